@@ -95,6 +95,98 @@ interface GoNativeBlobCompressor {
    * @return size of the compressed data in bytes
    */
   fun RawCompressedSize(data: ByteArray, data_len: Int): Int
+
+  /**
+   * WriteWithReason behaves like Write but tells why the block was discarded.
+   *
+   * @param data  bytes of the rlp encoded block
+   * @param data_len  number of bytes
+   * @return the code of the [RejectionReason], REJECTION_NONE if the block was appended,
+   * or -1 if an error occurred, in which case Error() returns its description
+   */
+  fun WriteWithReason(data: ByteArray, data_len: Int): Int
+
+  /**
+   * CanWriteWithReason behaves like WriteWithReason but does not actually write the data.
+   *
+   * @param data  bytes of the rlp encoded block
+   * @param data_len  number of bytes
+   * @return the code of the [RejectionReason], REJECTION_NONE if the block could be appended,
+   * or -1 if an error occurred
+   */
+  fun CanWriteWithReason(data: ByteArray, data_len: Int): Int
+
+  /**
+   * WriteBatch appends the blocks to the compressor as a new batch, atomically: either all the
+   * blocks are appended or the compressed data is left unchanged. There is no need to call
+   * StartNewBatch after it.
+   *
+   * @param data  concatenation of the rlp encoded blocks
+   * @param block_lengths  number of bytes of each block
+   * @param nb_blocks  number of blocks, must be positive
+   * @return the code of the [RejectionReason], REJECTION_NONE if the batch was appended,
+   * or -1 if an error occurred, in which case Error() returns its description
+   */
+  fun WriteBatch(data: ByteArray, block_lengths: IntArray, nb_blocks: Int): Int
+
+  /**
+   * CanWriteBatch behaves like WriteBatch but does not actually write the data.
+   *
+   * @param data  concatenation of the rlp encoded blocks
+   * @param block_lengths  number of bytes of each block
+   * @param nb_blocks  number of blocks, must be positive
+   * @return the code of the [RejectionReason], REJECTION_NONE if the batch could be appended,
+   * or -1 if an error occurred
+   */
+  fun CanWriteBatch(data: ByteArray, block_lengths: IntArray, nb_blocks: Int): Int
+
+  /**
+   * RemainingBytes returns the number of bytes left in the blob, that is dataLimit minus Len().
+   */
+  fun RemainingBytes(): Long
+
+  /**
+   * RemainingUncompressedBytes returns the number of uncompressed bytes the decompression
+   * circuit can still accept in the blob, the header included.
+   */
+  fun RemainingUncompressedBytes(): Long
+
+  /**
+   * RemainingBatches returns the number of batches that can still be started in the blob.
+   */
+  fun RemainingBatches(): Long
+}
+
+/**
+ * Reasons returned by WriteWithReason, CanWriteWithReason, WriteBatch and CanWriteBatch. They
+ * mirror the RejectionReason enum of the native library.
+ */
+enum class RejectionReason(val code: Int) {
+  // the block(s) were, or could have been, appended
+  REJECTION_NONE(0),
+
+  // the compressed data would exceed the data limit
+  REJECTION_BLOB_FULL(1),
+
+  // the uncompressed data would exceed what the decompression circuit handles
+  REJECTION_DECOMPRESSOR_LIMIT(2),
+
+  // the blob already holds the max number of batches of the decompression circuit
+  REJECTION_TOO_MANY_BATCHES(3);
+
+  companion object {
+    /**
+     * @return the reason of the code, or null for the error code -1
+     * @throws IllegalArgumentException for an unknown code
+     */
+    fun fromCode(code: Int): RejectionReason? {
+      if (code == -1) {
+        return null
+      }
+      return entries.firstOrNull { it.code == code }
+        ?: throw IllegalArgumentException("unknown rejection reason code: $code")
+    }
+  }
 }
 
 interface GoNativeBlobCompressorJnaLib : GoNativeBlobCompressor, Library
@@ -135,7 +135,7 @@ func makePiProof(cfg *config.Config, cf *CollectedFields) (plonk.Proof, witness.
 // for which circuit should the proof be generated.
 func makeDummyProof(cfg *config.Config, input string, circID circuits.MockCircuitID) string {
 	// TODO @gbotrel why do we do setup at run time here? we could factorize with other paths.
-	srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+	srsProvider, err := circuits.NewSRSProvider(cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -103,7 +103,7 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 	if cfg.BlobDecompression.ProverMode == config.ProverModeDev {
 		// create a dummy proof instead
 
-		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+		srsProvider, err := circuits.NewSRSProvider(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("could not create the SRS store: %w", err)
 		}
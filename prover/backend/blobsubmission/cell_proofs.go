@@ -0,0 +1,191 @@
+package blobsubmission
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+const (
+	// FieldElementsPerBlob is the number of field elements in a blob
+	FieldElementsPerBlob = 4096
+	// FieldElementsPerExtBlob is the number of field elements in a blob
+	// extended with the Reed-Solomon code of EIP-7594.
+	FieldElementsPerExtBlob = 2 * FieldElementsPerBlob
+	// FieldElementsPerCell is the number of field elements in a cell
+	FieldElementsPerCell = 64
+	// CellsPerExtBlob is the number of cells of an extended blob, and thus
+	// the number of cell proofs to attach to a blob in a sidecar.
+	CellsPerExtBlob = FieldElementsPerExtBlob / FieldElementsPerCell
+	// BytesPerCell is the size of a serialized cell
+	BytesPerCell = FieldElementsPerCell * fr381.Bytes
+
+	// CellProofsWrapperVersion is the version of the blob-transaction network
+	// wrapper carrying cell proofs instead of one proof per blob.
+	CellProofsWrapperVersion = 1
+)
+
+// trustedSetupG1Monomial holds the G1 points of the KZG ceremony in monomial
+// form, [τ^i]_1 for i < 4096, as hex-encoded compressed points, one per line.
+// The ones in Lagrange form embedded in go-ethereum can't be used for the cell
+// proofs as they require dividing the blob polynomial.
+//
+//go:embed trusted_setup_g1_monomial.txt
+var trustedSetupG1Monomial []byte
+
+var (
+	setupG1Monomial     []bls12381.G1Affine
+	setupG1MonomialErr  error
+	setupG1MonomialOnce sync.Once
+)
+
+// Cell is the evaluations of the blob polynomial over one of the cosets of the
+// extended domain, serialized in big-endian.
+type Cell [BytesPerCell]byte
+
+// ComputeCellsAndProofs computes the cells of the extended blob and their KZG
+// proofs as specified by EIP-7594. The proofs can be attached to a blob
+// sidecar of version [CellProofsWrapperVersion].
+//
+// The blob polynomial is extended over the domain of size 8192 whose elements
+// are taken in bit-reversed order. Cell i holds the evaluations on the coset
+// h_i.<w> of the subgroup <w> of size 64 found at positions [64i, 64i+64) of
+// this ordering and its proof is the commitment to the quotient of the blob
+// polynomial by the vanishing polynomial X^64 - h_i^64 of the coset.
+func ComputeCellsAndProofs(blob *kzg4844.Blob) (cells []Cell, proofs []kzg4844.Proof, err error) {
+
+	setup, err := getSetupG1Monomial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The blob contains the evaluations of the polynomial on the domain of
+	// size 4096 in bit-reversed order.
+	coeffs := make([]fr381.Element, FieldElementsPerExtBlob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if err := coeffs[i].SetBytesCanonical(blob[i*fr381.Bytes : (i+1)*fr381.Bytes]); err != nil {
+			return nil, nil, fmt.Errorf("invalid field element #%v in the blob: %w", i, err)
+		}
+	}
+
+	fft.NewDomain(FieldElementsPerBlob).FFTInverse(coeffs[:FieldElementsPerBlob], fft.DIT)
+
+	extDomain := fft.NewDomain(FieldElementsPerExtBlob)
+	extEvals := make([]fr381.Element, FieldElementsPerExtBlob)
+	copy(extEvals, coeffs)
+	extDomain.FFT(extEvals, fft.DIF)
+
+	// shifts lists the elements of the extended domain in bit-reversed order,
+	// the first element of each cell's chunk being the shift of its coset.
+	shifts := make([]fr381.Element, FieldElementsPerExtBlob)
+	shifts[0].SetOne()
+	for i := 1; i < len(shifts); i++ {
+		shifts[i].Mul(&shifts[i-1], &extDomain.Generator)
+	}
+	fft.BitReverse(shifts)
+
+	cells = make([]Cell, CellsPerExtBlob)
+	proofs = make([]kzg4844.Proof, CellsPerExtBlob)
+	proofErrs := make([]error, CellsPerExtBlob)
+
+	parallel.Execute(CellsPerExtBlob, func(start, stop int) {
+
+		quotient := make([]fr381.Element, FieldElementsPerBlob-FieldElementsPerCell)
+
+		for i := start; i < stop; i++ {
+
+			for j := 0; j < FieldElementsPerCell; j++ {
+				b := extEvals[i*FieldElementsPerCell+j].Bytes()
+				copy(cells[i][j*fr381.Bytes:], b[:])
+			}
+
+			var c fr381.Element
+			c.Exp(shifts[i*FieldElementsPerCell], bigFieldElementsPerCell)
+			divideByXnMinusC(quotient, coeffs[:FieldElementsPerBlob], FieldElementsPerCell, &c)
+
+			var proof bls12381.G1Affine
+			if _, err := proof.MultiExp(setup[:len(quotient)], quotient, ecc.MultiExpConfig{NbTasks: 1}); err != nil {
+				proofErrs[i] = fmt.Errorf("could not compute the proof of cell #%v: %w", i, err)
+				continue
+			}
+			proofs[i] = kzg4844.Proof(proof.Bytes())
+		}
+	})
+
+	for _, err := range proofErrs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cells, proofs, nil
+}
+
+// bigFieldElementsPerCell is the exponent mapping the shift of a coset to the
+// constant of its vanishing polynomial.
+var bigFieldElementsPerCell = big.NewInt(FieldElementsPerCell)
+
+// divideByXnMinusC sets quotient to the quotient of the euclidean division of
+// p by X^n - c. The remainder is dropped. The quotient must have len(p) - n
+// coefficients.
+func divideByXnMinusC(quotient, p []fr381.Element, n int, c *fr381.Element) {
+	// p = q.(X^n - c) + r gives p_{j+n} = q_j - c.q_{j+n} for all j >= 0.
+	for j := len(quotient) - 1; j >= 0; j-- {
+		quotient[j] = p[j+n]
+		if j+n < len(quotient) {
+			var tmp fr381.Element
+			tmp.Mul(c, &quotient[j+n])
+			quotient[j].Add(&quotient[j], &tmp)
+		}
+	}
+}
+
+// getSetupG1Monomial parses the embedded trusted setup the first time it is
+// called.
+func getSetupG1Monomial() ([]bls12381.G1Affine, error) {
+	setupG1MonomialOnce.Do(func() {
+		setupG1Monomial, setupG1MonomialErr = parseSetupG1Monomial(trustedSetupG1Monomial)
+	})
+	return setupG1Monomial, setupG1MonomialErr
+}
+
+func parseSetupG1Monomial(b []byte) ([]bls12381.G1Affine, error) {
+
+	var (
+		points  = make([]bls12381.G1Affine, 0, FieldElementsPerBlob)
+		scanner = bufio.NewScanner(bytes.NewReader(b))
+	)
+
+	for scanner.Scan() {
+		pointBytes, err := hex.DecodeString(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("trusted setup: point #%v is not hex: %w", len(points), err)
+		}
+		var point bls12381.G1Affine
+		if _, err := point.SetBytes(pointBytes); err != nil {
+			return nil, fmt.Errorf("trusted setup: invalid point #%v: %w", len(points), err)
+		}
+		points = append(points, point)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("trusted setup: %w", err)
+	}
+
+	if len(points) != FieldElementsPerBlob {
+		return nil, fmt.Errorf("trusted setup: expected %v points, got %v", FieldElementsPerBlob, len(points))
+	}
+
+	return points, nil
+}
@@ -0,0 +1,201 @@
+package blobsubmission
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// _cellProofsVectors holds reference vectors of the consensus-specs for
+// `compute_cells_and_kzg_proofs`. The cells are given by the sha256 of their
+// concatenation.
+const _cellProofsVectors = "./samples/cell-proofs-vectors.json"
+
+func TestComputeCellsAndProofsVectors(t *testing.T) {
+
+	var vectors []struct {
+		Name        string   `json:"name"`
+		Blob        string   `json:"blob"`
+		CellsSha256 string   `json:"cellsSha256"`
+		Proofs      []string `json:"proofs"`
+	}
+
+	readJSONFile(t, _cellProofsVectors, &vectors)
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+
+			var blob kzg4844.Blob
+			blobBytes, err := utils.HexDecodeString(v.Blob)
+			require.NoError(t, err)
+			copy(blob[:], blobBytes)
+
+			cells, proofs, err := ComputeCellsAndProofs(&blob)
+			require.NoError(t, err)
+			require.Len(t, proofs, CellsPerExtBlob)
+
+			h := sha256.New()
+			for i := range cells {
+				h.Write(cells[i][:])
+			}
+			assert.Equal(t, v.CellsSha256, utils.HexEncodeToString(h.Sum(nil)))
+
+			for i := range proofs {
+				assert.Equal(t, v.Proofs[i], utils.HexEncodeToString(proofs[i][:]), "proof #%v", i)
+			}
+		})
+	}
+}
+
+func TestComputeCellsAndProofsEmptyBlob(t *testing.T) {
+
+	var blob kzg4844.Blob
+	cells, proofs, err := ComputeCellsAndProofs(&blob)
+	require.NoError(t, err)
+
+	var infinity bls12381.G1Affine
+	for i := range proofs {
+		assert.Equal(t, kzg4844.Proof(infinity.Bytes()), proofs[i])
+		assert.Equal(t, Cell{}, cells[i])
+	}
+}
+
+func TestComputeCellsAndProofsInvalidBlob(t *testing.T) {
+	var blob kzg4844.Blob
+	for i := range blob[:fr381.Bytes] {
+		blob[i] = 0xff
+	}
+	_, _, err := ComputeCellsAndProofs(&blob)
+	assert.Error(t, err)
+}
+
+// The embedded setup must be the one of go-ethereum, in monomial form.
+func TestSetupG1MonomialMatchesKzg4844(t *testing.T) {
+
+	setup, err := getSetupG1Monomial()
+	require.NoError(t, err)
+
+	blob := randBlob()
+	expected, err := kzg4844.BlobToCommitment(&blob)
+	require.NoError(t, err)
+
+	coeffs := make([]fr381.Element, FieldElementsPerBlob)
+	for i := range coeffs {
+		require.NoError(t, coeffs[i].SetBytesCanonical(blob[i*fr381.Bytes:(i+1)*fr381.Bytes]))
+	}
+	fft.NewDomain(FieldElementsPerBlob).FFTInverse(coeffs, fft.DIT)
+
+	var commitment bls12381.G1Affine
+	_, err = commitment.MultiExp(setup, coeffs, ecc.MultiExpConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, kzg4844.Commitment(commitment.Bytes()))
+}
+
+func TestDivideByXnMinusC(t *testing.T) {
+
+	var (
+		n = 4
+		p = make([]fr381.Element, 11)
+		c fr381.Element
+		z fr381.Element
+	)
+
+	for i := range p {
+		p[i].SetRandom()
+	}
+	c.SetRandom()
+	z.SetRandom()
+
+	quotient := make([]fr381.Element, len(p)-n)
+	divideByXnMinusC(quotient, p, n, &c)
+
+	// p(z) - q(z).(z^n - c) must be the evaluation of a polynomial of degree
+	// < n whose coefficients are the low coefficients of p minus the ones of
+	// -c.q
+	var (
+		remainder = make([]fr381.Element, n)
+		tmp       fr381.Element
+	)
+	copy(remainder, p[:n])
+	for j := 0; j < n && j < len(quotient); j++ {
+		tmp.Mul(&c, &quotient[j])
+		remainder[j].Add(&remainder[j], &tmp)
+	}
+
+	var pz, qz, rz, zn fr381.Element
+	pz, qz, rz = evalPoly(p, &z), evalPoly(quotient, &z), evalPoly(remainder, &z)
+	zn.Exp(z, big.NewInt(int64(n)))
+	zn.Sub(&zn, &c)
+	qz.Mul(&qz, &zn)
+	qz.Add(&qz, &rz)
+
+	assert.Equal(t, pz, qz)
+}
+
+func evalPoly(p []fr381.Element, z *fr381.Element) (res fr381.Element) {
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, z)
+		res.Add(&res, &p[i])
+	}
+	return res
+}
+
+func TestBlobSubmissionEIP7594(t *testing.T) {
+
+	var (
+		req         Request
+		outExpected Response
+	)
+
+	readJSONFile(t, _inFileEIP4844, &req)
+	readJSONFile(t, _outFileEIP4844, &outExpected)
+
+	req.Eip7594Enabled = true
+	out, err := CraftResponse(&req)
+	require.NoError(t, err)
+
+	assert.Equal(t, CellProofsWrapperVersion, out.WrapperVersion)
+	require.Len(t, out.KzgCellProofsSidecar, CellsPerExtBlob)
+
+	// The other fields are unchanged
+	withoutCells := *out
+	withoutCells.WrapperVersion, withoutCells.KzgCellProofsSidecar = 0, nil
+	assert.Equal(t, outExpected, withoutCells)
+
+	// The proofs are the ones of the blob of the response, as computed by
+	// [ComputeCellsAndProofs] which is itself checked against the reference
+	// vectors in [TestComputeCellsAndProofsVectors].
+	compressedStream, err := base64.StdEncoding.DecodeString(req.CompressedData)
+	require.NoError(t, err)
+	blob, err := compressedStreamToBlob(compressedStream)
+	require.NoError(t, err)
+	_, expectedProofs, err := ComputeCellsAndProofs(&blob)
+	require.NoError(t, err)
+
+	for i := range out.KzgCellProofsSidecar {
+		proof, err := utils.HexDecodeString(out.KzgCellProofsSidecar[i])
+		require.NoError(t, err)
+		assert.Equal(t, expectedProofs[i][:], proof, "proof #%v", i)
+
+		var p bls12381.G1Affine
+		_, err = p.SetBytes(proof)
+		assert.NoError(t, err, "proof #%v is not a point of G1", i)
+	}
+
+	// The cell proofs require eip4844
+	req.Eip4844Enabled = false
+	_, err = CraftResponse(&req)
+	assert.Error(t, err)
+}
@@ -6,6 +6,10 @@ type Request struct {
 	// If true, eip4844. If false or not defined, legacy calldata
 	Eip4844Enabled bool `json:"eip4844Enabled"`
 
+	// If true, the response also contains the EIP-7594 cell proofs of the blob.
+	// Requires eip4844.
+	Eip7594Enabled bool `json:"eip7594Enabled,omitempty"`
+
 	// The compressed data in base64 string
 	CompressedData string `json:"compressedData"`
 
@@ -41,6 +45,13 @@ type Response struct {
 	// The KZG proof for the blob sidecar in the blob tx
 	KzgProofSidecar string `json:"kzgProofSidecar"` // kzg4844.Proof [48]byte
 
+	// The version of the blob sidecar wrapper. Set to 1 when the cell proofs
+	// are emitted, they then replace KzgProofSidecar in the sidecar.
+	WrapperVersion int `json:"wrapperVersion,omitempty"`
+	// The EIP-7594 KZG proofs of the cells of the extended blob, in order of
+	// the cell indices. Only set if eip7594 is enabled in the request.
+	KzgCellProofsSidecar []string `json:"kzgCellProofsSidecar,omitempty"` // [128]kzg4844.Proof
+
 	// The expected value of X and Y from the prover's perspective. In hexstring
 	// as a field element on the BLS12 field.
 	ExpectedX string `json:"expectedX"` //ExpectedX kzg4844.Point [32]byte
@@ -73,3 +84,67 @@ type ConflationOrder struct {
 func (order *ConflationOrder) Range() (start, end int) {
 	return order.StartingBlockNumber, order.UpperBoundaries[len(order.UpperBoundaries)-1]
 }
+
+// MaxBlobsPerSubmission is the maximal number of blobs that can be submitted
+// in a single L1 transaction and thus in a [MultiRequest].
+const MaxBlobsPerSubmission = 6
+
+// Input of the blob-submission for several blobs submitted in the same L1
+// transaction. The blobs are chained in order: each blob starts from the state
+// root hash, the data hash and the shnarf of the blob preceding it.
+type MultiRequest struct {
+
+	// Must be true, multi-blob submissions are only possible with EIP4844
+	Eip4844Enabled bool `json:"eip4844Enabled"`
+	// If true, the cell proofs of every blob are emitted. See [Request].
+	Eip7594Enabled bool `json:"eip7594Enabled,omitempty"`
+
+	// Parent data hash: the hash of the compressed data that were last
+	// submitted and following which we are submitting the first blob.
+	DataParentHash string `json:"dataParentHash"`
+	// The parent zkRootHash of the first blob. In hexstring.
+	ParentStateRootHash string `json:"parentStateRootHash"`
+	// The shnarf upon which we are towering the first blob.
+	PrevShnarf string `json:"prevShnarf"`
+
+	// The ordered list of the submitted blobs
+	Blobs []BlobRequest `json:"blobs"`
+}
+
+// A blob of a [MultiRequest]. The parent fields are implied by the previous
+// blob of the request.
+type BlobRequest struct {
+	// The compressed data in base64 string
+	CompressedData string `json:"compressedData"`
+	// Conflation order
+	ConflationOrder ConflationOrder `json:"conflationOrder"`
+	// The state root hash after executing all the blocks in the blob
+	FinalStateRootHash string `json:"finalStateRootHash"`
+}
+
+// Output of the blob-submission for a [MultiRequest]
+type MultiResponse struct {
+
+	// Always true
+	Eip4844Enabled bool `json:"eip4844Enabled"`
+
+	// The responses of every blob in the order of the request. Each of them is
+	// the response of the blob as if it was submitted alone.
+	Blobs []Response `json:"blobs"`
+
+	// The BlobHashes (VersionedHashes) of the blobs in order
+	DataHashes []string `json:"dataHashes"`
+	// The conflation orders of the blobs, concatenated
+	ConflationOrder ConflationOrder `json:"conflationOrder"`
+	// The parent zkRootHash of the first blob
+	ParentStateRootHash string `json:"parentStateRootHash"`
+	// The state root hash after executing all the blobs
+	FinalStateRootHash string `json:"finalStateRootHash"`
+	// The hash of the blob preceding the first blob
+	DataParentHash string `json:"parentDataHash"`
+	// The shnarf upon which we are towering the first blob
+	PrevShnarf string `json:"prevShnarf"`
+	// The shnarf of the last blob, which the contract is expected to recover
+	// after processing all the blobs.
+	ExpectedShnarf string `json:"expectedShnarf"`
+}
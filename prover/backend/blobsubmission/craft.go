@@ -22,6 +22,10 @@ func CraftResponseCalldata(req *Request) (*Response, error) {
 		return nil, errors.New("crafting response: request must not be nil")
 	}
 
+	if req.Eip7594Enabled {
+		return nil, errors.New("crafting response: eip7594 cell proofs require eip4844")
+	}
+
 	// Flat pass the request parameters to the response
 	var (
 		errs             [4]error
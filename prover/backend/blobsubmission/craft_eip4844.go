@@ -151,6 +151,19 @@ func CraftResponse(req *Request) (*Response, error) {
 	resp.ExpectedY = utils.HexEncodeToString(y)
 	resp.ExpectedShnarf = utils.HexEncodeToString(newShnarf)
 
+	// KZG cell proofs for the EIP-7594 sidecar
+	if req.Eip7594Enabled {
+		_, cellProofs, err := ComputeCellsAndProofs(&blobPadded)
+		if err != nil {
+			return nil, fmt.Errorf("crafting response: could not compute the cell proofs: %w", err)
+		}
+		resp.WrapperVersion = CellProofsWrapperVersion
+		resp.KzgCellProofsSidecar = make([]string, len(cellProofs))
+		for i := range cellProofs {
+			resp.KzgCellProofsSidecar[i] = utils.HexEncodeToString(cellProofs[i][:])
+		}
+	}
+
 	return resp, nil
 }
 
@@ -0,0 +1,148 @@
+package blobsubmission
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CraftMultiResponse prepares the response of a multi-blob submission. The
+// blobs are crafted one after the other with [CraftResponse], each blob being
+// towered on the shnarf, the data hash and the final state root hash of the
+// previous one.
+func CraftMultiResponse(req *MultiRequest) (*MultiResponse, error) {
+
+	if req == nil {
+		return nil, errors.New("crafting multi-blob response: request must not be nil")
+	}
+
+	if !req.Eip4844Enabled {
+		return nil, errors.New("crafting multi-blob response: multi-blob submissions require eip4844")
+	}
+
+	if len(req.Blobs) == 0 || len(req.Blobs) > MaxBlobsPerSubmission {
+		return nil, fmt.Errorf("crafting multi-blob response: got %v blobs, expected between 1 and %v", len(req.Blobs), MaxBlobsPerSubmission)
+	}
+
+	var (
+		resp = &MultiResponse{
+			Eip4844Enabled: true,
+			Blobs:          make([]Response, len(req.Blobs)),
+			DataHashes:     make([]string, len(req.Blobs)),
+		}
+		dataParentHash      = req.DataParentHash
+		parentStateRootHash = req.ParentStateRootHash
+		prevShnarf          = req.PrevShnarf
+	)
+
+	for i, blob := range req.Blobs {
+
+		blobResp, err := CraftResponse(&Request{
+			Eip4844Enabled:      true,
+			Eip7594Enabled:      req.Eip7594Enabled,
+			CompressedData:      blob.CompressedData,
+			DataParentHash:      dataParentHash,
+			ConflationOrder:     blob.ConflationOrder,
+			ParentStateRootHash: parentStateRootHash,
+			FinalStateRootHash:  blob.FinalStateRootHash,
+			PrevShnarf:          prevShnarf,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("crafting multi-blob response: blob #%v: %w", i, err)
+		}
+
+		resp.Blobs[i] = *blobResp
+		resp.DataHashes[i] = blobResp.DataHash
+
+		dataParentHash = blobResp.DataHash
+		parentStateRootHash = blobResp.FinalStateRootHash
+		prevShnarf = blobResp.ExpectedShnarf
+	}
+
+	if err := CheckResponseChain(resp.Blobs); err != nil {
+		return nil, fmt.Errorf("crafting multi-blob response: %w", err)
+	}
+
+	var (
+		first = &resp.Blobs[0]
+		last  = &resp.Blobs[len(resp.Blobs)-1]
+	)
+
+	resp.ParentStateRootHash = first.ParentStateRootHash
+	resp.DataParentHash = first.DataParentHash
+	resp.PrevShnarf = first.PrevShnarf
+	resp.FinalStateRootHash = last.FinalStateRootHash
+	resp.ExpectedShnarf = last.ExpectedShnarf
+	resp.ConflationOrder.StartingBlockNumber = first.ConflationOrder.StartingBlockNumber
+	for i := range resp.Blobs {
+		resp.ConflationOrder.UpperBoundaries = append(resp.ConflationOrder.UpperBoundaries, resp.Blobs[i].ConflationOrder.UpperBoundaries...)
+	}
+
+	return resp, nil
+}
+
+// CheckResponseChain checks that the responses form a chain of consecutive
+// blobs: every blob must be towered on the shnarf, the data hash and the final
+// state root hash of the previous one and its conflation order must start
+// right after the last block of the previous one. The conflation orders are
+// also checked individually.
+func CheckResponseChain(resps []Response) error {
+
+	var errs []error
+
+	for i := range resps {
+
+		curr := &resps[i]
+
+		if err := curr.ConflationOrder.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("blob #%v: %w", i, err))
+			continue
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := &resps[i-1]
+
+		if curr.PrevShnarf != prev.ExpectedShnarf {
+			errs = append(errs, fmt.Errorf("blob #%v: prev shnarf %v does not match the expected shnarf of the previous blob %v", i, curr.PrevShnarf, prev.ExpectedShnarf))
+		}
+
+		if curr.DataParentHash != prev.DataHash {
+			errs = append(errs, fmt.Errorf("blob #%v: parent data hash %v does not match the data hash of the previous blob %v", i, curr.DataParentHash, prev.DataHash))
+		}
+
+		if curr.ParentStateRootHash != prev.FinalStateRootHash {
+			errs = append(errs, fmt.Errorf("blob #%v: parent state root hash %v does not match the final state root hash of the previous blob %v", i, curr.ParentStateRootHash, prev.FinalStateRootHash))
+		}
+
+		// An empty conflation order of the previous blob is already reported
+		if len(prev.ConflationOrder.UpperBoundaries) > 0 {
+			if _, prevEnd := prev.ConflationOrder.Range(); curr.ConflationOrder.StartingBlockNumber != prevEnd+1 {
+				errs = append(errs, fmt.Errorf("blob #%v: starts at block %v but the previous blob ends at block %v", i, curr.ConflationOrder.StartingBlockNumber, prevEnd))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validate checks that the conflation order is non-empty and that the upper
+// boundaries of its batches are increasing.
+func (order *ConflationOrder) validate() error {
+
+	if len(order.UpperBoundaries) == 0 {
+		return errors.New("the conflation order has no batch")
+	}
+
+	prevEnd := order.StartingBlockNumber - 1
+	for i, end := range order.UpperBoundaries {
+		if end <= prevEnd {
+			return fmt.Errorf("the conflation order is not increasing: batch #%v ends at block %v but the previous one ends at block %v", i, end, prevEnd)
+		}
+		prevEnd = end
+	}
+
+	return nil
+}
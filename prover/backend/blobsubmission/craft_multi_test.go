@@ -0,0 +1,136 @@
+package blobsubmission
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiBlobSubmission(t *testing.T) {
+
+	var (
+		single         Request
+		singleExpected Response
+	)
+
+	readJSONFile(t, _inFileEIP4844, &single)
+	readJSONFile(t, _outFileEIP4844, &singleExpected)
+
+	req := MultiRequest{
+		Eip4844Enabled:      true,
+		DataParentHash:      single.DataParentHash,
+		ParentStateRootHash: single.ParentStateRootHash,
+		PrevShnarf:          single.PrevShnarf,
+		Blobs: []BlobRequest{
+			{CompressedData: single.CompressedData, ConflationOrder: single.ConflationOrder, FinalStateRootHash: single.FinalStateRootHash},
+			{CompressedData: single.CompressedData, ConflationOrder: ConflationOrder{StartingBlockNumber: 41, UpperBoundaries: []int{45}}, FinalStateRootHash: "0x" + strings.Repeat("ee", 32)},
+			{CompressedData: "", ConflationOrder: ConflationOrder{StartingBlockNumber: 46, UpperBoundaries: []int{50, 60}}, FinalStateRootHash: "0x" + strings.Repeat("ff", 32)},
+		},
+	}
+
+	resp, err := CraftMultiResponse(&req)
+	require.NoError(t, err)
+	require.Len(t, resp.Blobs, len(req.Blobs))
+
+	// The first blob is the same as when submitted alone
+	assert.Equal(t, singleExpected, resp.Blobs[0])
+	assert.Equal(t, singleExpected.DataHash, resp.DataHashes[0])
+
+	// The next blobs are the ones we would get by chaining single requests
+	for i := 1; i < len(req.Blobs); i++ {
+		prev := resp.Blobs[i-1]
+		expected, err := CraftResponse(&Request{
+			Eip4844Enabled:      true,
+			CompressedData:      req.Blobs[i].CompressedData,
+			DataParentHash:      prev.DataHash,
+			ConflationOrder:     req.Blobs[i].ConflationOrder,
+			ParentStateRootHash: prev.FinalStateRootHash,
+			FinalStateRootHash:  req.Blobs[i].FinalStateRootHash,
+			PrevShnarf:          prev.ExpectedShnarf,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, *expected, resp.Blobs[i], "blob #%v", i)
+		assert.Equal(t, expected.DataHash, resp.DataHashes[i])
+	}
+
+	last := resp.Blobs[len(resp.Blobs)-1]
+	assert.Equal(t, last.ExpectedShnarf, resp.ExpectedShnarf)
+	assert.Equal(t, last.FinalStateRootHash, resp.FinalStateRootHash)
+	assert.Equal(t, single.ParentStateRootHash, resp.ParentStateRootHash)
+	assert.Equal(t, single.PrevShnarf, resp.PrevShnarf)
+	assert.Equal(t, single.DataParentHash, resp.DataParentHash)
+	assert.Equal(t, ConflationOrder{StartingBlockNumber: 0, UpperBoundaries: []int{10, 20, 30, 40, 45, 50, 60}}, resp.ConflationOrder)
+
+	// Tampering with the chain is detected
+	tampered := append([]Response{}, resp.Blobs...)
+	tampered[1].PrevShnarf = tampered[0].PrevShnarf
+	assert.Error(t, CheckResponseChain(tampered))
+
+	tampered = append([]Response{}, resp.Blobs...)
+	tampered[2].ConflationOrder.StartingBlockNumber = 47
+	assert.Error(t, CheckResponseChain(tampered))
+}
+
+func TestMultiBlobSubmissionErrors(t *testing.T) {
+
+	var single Request
+	readJSONFile(t, _inFileEIP4844, &single)
+
+	var (
+		blob = BlobRequest{CompressedData: single.CompressedData, ConflationOrder: single.ConflationOrder, FinalStateRootHash: single.FinalStateRootHash}
+		base = MultiRequest{
+			Eip4844Enabled:      true,
+			DataParentHash:      single.DataParentHash,
+			ParentStateRootHash: single.ParentStateRootHash,
+			PrevShnarf:          single.PrevShnarf,
+		}
+		// nextBlob returns a blob coming after `blob` with the given conflation
+		nextBlob = func(start int, ends ...int) BlobRequest {
+			next := blob
+			next.ConflationOrder = ConflationOrder{StartingBlockNumber: start, UpperBoundaries: ends}
+			return next
+		}
+	)
+
+	testCases := map[string][]BlobRequest{
+		"no-blob":               nil,
+		"non-contiguous":        {blob, nextBlob(42, 50)},
+		"empty-conflation":      {blob, nextBlob(41)},
+		"decreasing-conflation": {blob, nextBlob(41, 50, 49)},
+		"bad-data":              {blob, {CompressedData: "not base64", ConflationOrder: ConflationOrder{StartingBlockNumber: 41, UpperBoundaries: []int{50}}}},
+	}
+
+	tooMany := []BlobRequest{blob}
+	for i := 0; i < MaxBlobsPerSubmission; i++ {
+		tooMany = append(tooMany, nextBlob(41+i, 41+i))
+	}
+	testCases["too-many-blobs"] = tooMany
+
+	for name, blobs := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := base
+			req.Blobs = blobs
+			_, err := CraftMultiResponse(&req)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		req := base
+		req.Eip4844Enabled = false
+		req.Blobs = []BlobRequest{blob}
+		_, err := CraftMultiResponse(&req)
+		assert.Error(t, err)
+	})
+}
+
+func readJSONFile(t *testing.T, fpath string, v any) {
+	f, err := os.Open(fpath)
+	require.NoErrorf(t, err, "could not open %s", fpath)
+	defer f.Close()
+	require.NoErrorf(t, json.NewDecoder(f).Decode(v), "could not decode %s", fpath)
+}
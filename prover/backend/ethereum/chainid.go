@@ -16,7 +16,7 @@ func GetSigner(tx *types.Transaction) types.Signer {
 
 // Get the signer
 func getSigner(chainID *big.Int) types.Signer {
-	return types.NewLondonSigner(chainID)
+	return types.NewPragueSigner(chainID)
 }
 
 // Get the unprotected signer
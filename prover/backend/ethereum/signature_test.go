@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -73,6 +74,24 @@ var testTxDatas = []types.TxData{
 			types.AccessTuple{Address: TEST_ADDRESS_A, StorageKeys: []common.Hash{TEST_HASH_A, TEST_HASH_F}},
 		},
 	},
+	// set-code transaction
+	&types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(CHAIN_ID),
+		Nonce:     2,
+		GasTipCap: uint256.NewInt(123543135),
+		GasFeeCap: uint256.NewInt(112121212),
+		Gas:       4531112,
+		To:        TEST_ADDRESS,
+		Value:     uint256.NewInt(845315452),
+		Data:      common.Hex2Bytes("0xdeed8745a20f"),
+		AccessList: types.AccessList{
+			types.AccessTuple{Address: TEST_ADDRESS_A, StorageKeys: []common.Hash{TEST_HASH_A, TEST_HASH_F}},
+		},
+		AuthList: []types.SetCodeAuthorization{
+			mustSignAuthorization(types.SetCodeAuthorization{ChainID: *uint256.MustFromBig(CHAIN_ID), Address: TEST_ADDRESS_A, Nonce: 5}),
+			mustSignAuthorization(types.SetCodeAuthorization{Address: TEST_ADDRESS, Nonce: 0}),
+		},
+	},
 }
 
 // Test signers
@@ -81,6 +100,7 @@ var testSigners = []types.Signer{
 	types.NewEIP155Signer(CHAIN_ID),
 	types.NewEIP2930Signer(CHAIN_ID),
 	types.NewLondonSigner(CHAIN_ID),
+	types.NewPragueSigner(CHAIN_ID),
 }
 
 // Test the consistency with ethereum signatures for unprotected legacy tx.
@@ -127,11 +147,32 @@ func TestTransactionSigning(t *testing.T) {
 			assert.Equal(t, tx.Data(), decodedTx.Data())
 			assert.Equal(t, tx.Value(), decodedTx.Value())
 			assert.Equal(t, tx.Cost(), decodedTx.Cost())
+			assert.Equal(t, tx.AccessList(), decodedTx.AccessList())
+			assert.Equal(t, tx.SetCodeAuthorizations(), decodedTx.SetCodeAuthorizations())
+
+			// The sender is recovered from the decoded transaction
+			decodedTx, err = decodedTx.WithSignature(signer, sig)
+			require.NoError(t, err)
+			assert.Equal(t, from, common.Address(GetFrom(decodedTx)))
 		})
 	}
 
 }
 
+// mustSignAuthorization signs the authorization of a set-code transaction with
+// a random key.
+func mustSignAuthorization(auth types.SetCodeAuthorization) types.SetCodeAuthorization {
+	privKey, err := ecdsa.GenerateKey(secp256k1.S256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	auth, err = types.SignSetCode(privKey, auth)
+	if err != nil {
+		panic(err)
+	}
+	return auth
+}
+
 // ecRecovebackend/ethereum/signature.gor the signature from properly encoded transaction
 func ecRecover(msg []byte, sig Signature) common.Address {
 
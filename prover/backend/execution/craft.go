@@ -14,6 +14,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/config"
 	blob "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
@@ -122,12 +123,12 @@ func inspectStateManagerTraces(
 			old, new, err := statemanager.CheckTraces(traces[i])
 			// The trace must have been validated
 			if err != nil {
-				utils.Panic("error parsing the state manager traces : %v", err)
+				exit.Panic(exit.KindStateMismatch, "error parsing the state manager traces of block #%v: %w", i, err)
 			}
 
 			// The "old of a block" must equal the parent
 			if old != parent {
-				utils.Panic("old does not match with parent root hash")
+				exit.Panic(exit.KindStateMismatch, "the old root hash of block #%v (%v) does not match with the parent root hash (%v)", i, old.Hex(), parent.Hex())
 			}
 
 			// Populate the prover's output with the recovered root hash
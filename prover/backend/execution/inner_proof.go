@@ -0,0 +1,214 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/serialization"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+// innerProofExt is the extension of the files storing an inner-proof
+const innerProofExt = ".inner-proof.bin"
+
+// InnerProofFingerprint returns the fingerprint of the compiled IOP of the
+// full zkEVM (see [wizard.CompiledIOP.Fingerprint]). The inner-proofs are tied
+// to it so that a proof is never checked against another IOP.
+func InnerProofFingerprint(comp *wizard.CompiledIOP) string {
+	return comp.Fingerprint()
+}
+
+// InnerProofPath returns the file where the inner-proof of the request is
+// dumped, or an empty string if [config.Execution.InnerProofDir] is not set.
+func InnerProofPath(cfg *config.Config, req *Request) string {
+	if len(cfg.Execution.InnerProofDir) == 0 {
+		return ""
+	}
+	return filepath.Join(cfg.Execution.InnerProofDir, req.tracesFileStem()+innerProofExt)
+}
+
+// ProverCheckpointDir returns the checkpointer of the inner prover of the
+// request for the compiled IOP, or nil if [config.Execution.CheckpointDir] is not
+// set. Every request has its own directory, named after its conflated traces
+// file.
+func ProverCheckpointDir(cfg *config.Config, req *Request, comp *wizard.CompiledIOP) *serialization.ProverCheckpointDir {
+	if len(cfg.Execution.CheckpointDir) == 0 {
+		return nil
+	}
+	return &serialization.ProverCheckpointDir{
+		Dir:         filepath.Join(cfg.Execution.CheckpointDir, req.tracesFileStem()),
+		Fingerprint: InnerProofFingerprint(comp),
+	}
+}
+
+// tracesFileStem returns the name of the conflated traces file of the request
+// without its extension. It identifies the request.
+func (req *Request) tracesFileStem() string {
+	base := path.Base(req.ConflatedExecutionTracesFile)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// WriteInnerProof serializes the inner-proof generated for the compiled IOP
+// into the file. The file is written atomically.
+func WriteInnerProof(filePath string, comp *wizard.CompiledIOP, proof wizard.Proof) error {
+
+	data, err := serialization.SerializeProof(proof, InnerProofFingerprint(comp))
+	if err != nil {
+		return fmt.Errorf("could not serialize the inner-proof: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("could not create the directory of %v: %w", filePath, err)
+	}
+
+	tmp := filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("could not write %v: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, filePath); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not rename %v into %v: %w", tmp, filePath, err)
+	}
+
+	return nil
+}
+
+// ReadInnerProof reads an inner-proof written by [WriteInnerProof] for the
+// same compiled IOP. A proof generated for other limits, other constraints or
+// by another version of the prover is reported as a [exit.KindSetupMismatch]
+// error, an unreadable proof as a [exit.KindInvalidRequest] one.
+func ReadInnerProof(filePath string, comp *wizard.CompiledIOP) (wizard.Proof, error) {
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return wizard.Proof{}, exit.Errorf(exit.KindInvalidRequest, "could not read the inner-proof: %w", err)
+	}
+
+	proof, err := serialization.DeserializeProof(data, InnerProofFingerprint(comp))
+	switch {
+	case errors.Is(err, serialization.ErrProofFingerprint), errors.Is(err, serialization.ErrProofFormat):
+		return wizard.Proof{}, exit.Errorf(exit.KindSetupMismatch, "could not use the inner-proof %v: %w", filePath, err)
+	case err != nil:
+		return wizard.Proof{}, exit.Errorf(exit.KindInvalidRequest, "could not decode the inner-proof %v: %w", filePath, err)
+	}
+
+	return proof, nil
+}
+
+// VerifyInner reads the inner-proof from the file and verifies it against
+// the full zkEVM for the normal or the large limits and the constraints of the
+// traces engine version (see [arithmetization.SelectZkevmBin]).
+func VerifyInner(cfg *config.Config, innerProofPath string, tracesEngineVersion string, large bool) (err error) {
+
+	defer exit.Recover(&err)
+
+	traces := tracesLimits(cfg, large)
+
+	bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, tracesEngineVersion)
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("Get Full IOP")
+	fullZkEvm := zkevm.FullZkEvm(traces, cfg, bin)
+
+	proof, err := ReadInnerProof(innerProofPath, fullZkEvm.WizardIOP)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Verifying the inner-proof %v", innerProofPath)
+	if err := fullZkEvm.VerifyInner(proof); err != nil {
+		return exit.Errorf(exit.KindInvalidRequest, "the inner-proof %v does not pass: %w", innerProofPath, err)
+	}
+
+	return nil
+}
+
+// ProveFromInner generates the execution proof of the request from its
+// inner-proof, read from the file, instead of running the inner prover. The
+// inner-proof is verified before being wrapped in the outer-proof. It is only
+// supported in full mode.
+func ProveFromInner(cfg *config.Config, req *Request, innerProofPath string, large bool) (_ *Response, err error) {
+
+	defer exit.Recover(&err)
+
+	if cfg.Execution.ProverMode != config.ProverModeFull {
+		return nil, fmt.Errorf("resuming from an inner-proof requires the %v prover mode, got %v", config.ProverModeFull, cfg.Execution.ProverMode)
+	}
+
+	traces := tracesLimits(cfg, large)
+
+	bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, req.TracesEngineVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Info("Get Full IOP")
+	fullZkEvm := zkevm.FullZkEvm(traces, cfg, bin)
+
+	proof, err := ReadInnerProof(innerProofPath, fullZkEvm.WizardIOP)
+	if err != nil {
+		return nil, err
+	}
+
+	// WARN: CraftProverOutput calls functions that can panic.
+	out := CraftProverOutput(cfg, req)
+	w := NewWitness(cfg, req, &out)
+
+	var (
+		setup       circuits.Setup
+		errSetup    error
+		chSetupDone = make(chan struct{})
+	)
+	go func() {
+		setup, errSetup = circuits.LoadVersionedSetup(cfg, circuits.ExecutionCircuitID, bin.Version)
+		close(chSetupDone)
+	}()
+
+	logrus.Infof("Verifying the inner-proof %v", innerProofPath)
+	if err := fullZkEvm.VerifyInner(proof); err != nil {
+		<-chSetupDone
+		return nil, exit.Errorf(exit.KindInvalidRequest, "the inner-proof %v does not pass: %w", innerProofPath, err)
+	}
+
+	<-chSetupDone
+	if errSetup != nil {
+		return nil, exit.Errorf(exit.KindSetupMismatch, "could not load setup: %w", errSetup)
+	}
+
+	out.Proof = mustProveOuter(traces, bin, setup, fullZkEvm, proof, w.FuncInp)
+	out.VerifyingKeyShaSum = setup.VerifyingKeyDigest()
+	out.Version = cfg.Version
+	out.ProverMode = cfg.Execution.ProverMode
+	out.VerifierIndex = uint(cfg.Aggregation.VerifierID) // TODO @gbotrel revisit
+
+	return &out, nil
+}
+
+// dumpInnerProof writes the inner-proof into the file unless the path is
+// empty. A failure is only logged as the dump must not fail the job.
+func dumpInnerProof(filePath string, comp *wizard.CompiledIOP, proof wizard.Proof) {
+
+	if len(filePath) == 0 {
+		return
+	}
+
+	if err := WriteInnerProof(filePath, comp, proof); err != nil {
+		logrus.Errorf("could not dump the inner-proof: %v", err)
+		return
+	}
+
+	logrus.Infof("dumped the inner-proof in %v", filePath)
+}
@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInnerProofPath(t *testing.T) {
+
+	cfg := &config.Config{}
+	req := &Request{ConflatedExecutionTracesFile: "1-5.conflated.v0.8.0.lt"}
+
+	assert.Empty(t, InnerProofPath(cfg, req))
+
+	cfg.Execution.InnerProofDir = "/data/inner-proofs"
+	assert.Equal(t, "/data/inner-proofs/1-5.conflated.v0.8.0.inner-proof.bin", InnerProofPath(cfg, req))
+}
+
+func TestInnerProofFile(t *testing.T) {
+
+	var (
+		compile = func(size int, constraintsHash string) *wizard.CompiledIOP {
+			define := func(b *wizard.Builder) {
+				b.RegisterCommit("P", size)
+			}
+			return wizard.Compile(define).BootstrapFiatShamir(wizard.VersionMetadata{ConstraintsHash: constraintsHash}, nil)
+		}
+		comp      = compile(4, "constraints")
+		compLarge = compile(8, "constraints")
+		compOther = compile(4, "other constraints")
+		filePath  = filepath.Join(t.TempDir(), "dir", "1-5"+innerProofExt)
+		proof     = wizard.Proof{
+			Messages:      collection.NewMapping[ifaces.ColID, ifaces.ColAssignment](),
+			QueriesParams: collection.NewMapping[ifaces.QueryID, ifaces.QueryParams](),
+		}
+	)
+
+	proof.Messages.InsertNew("P", smartvectors.ForTest(1, 2, 3, 4))
+	proof.QueriesParams.InsertNew("L", query.NewLocalOpeningParams(field.NewElement(1)))
+
+	require.NoError(t, WriteInnerProof(filePath, comp, proof))
+
+	read, err := ReadInnerProof(filePath, compile(4, "constraints"))
+	require.NoError(t, err)
+	assert.Equal(t, proof.QueriesParams.MustGet("L"), read.QueriesParams.MustGet("L"))
+
+	// the proof is tied to the limits
+	_, err = ReadInnerProof(filePath, compLarge)
+	e, ok := exit.As(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, exit.KindSetupMismatch, e.Kind)
+
+	// the proof is tied to the constraints
+	_, err = ReadInnerProof(filePath, compOther)
+	e, ok = exit.As(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, exit.KindSetupMismatch, e.Kind)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("not a proof"), 0644))
+	_, err = ReadInnerProof(filePath, comp)
+	e, ok = exit.As(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, exit.KindInvalidRequest, e.Kind)
+}
@@ -0,0 +1,178 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/bridge"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/accumulator"
+)
+
+// LimitsVerdict tells which prover can handle a request
+type LimitsVerdict string
+
+const (
+	// LimitsVerdictNormal means that the request fits the normal limits
+	LimitsVerdictNormal LimitsVerdict = "normal"
+	// LimitsVerdictLarge means that the request only fits the large limits
+	LimitsVerdictLarge LimitsVerdict = "large"
+	// LimitsVerdictReject means that the request overflows the large limits
+	LimitsVerdictReject LimitsVerdict = "reject"
+)
+
+// LimitsReportEntry compares a count with its limits
+type LimitsReportEntry struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	Limit      int    `json:"limit"`
+	LimitLarge int    `json:"limitLarge"`
+}
+
+// LimitsReport is the result of [CheckLimits]
+type LimitsReport struct {
+	TracesFile string              `json:"tracesFile"`
+	Entries    []LimitsReportEntry `json:"entries"`
+	Verdict    LimitsVerdict       `json:"verdict"`
+}
+
+// CheckLimits estimates what the prover will check against the traces limits
+// for the request without proving it: the number of rows of every module of
+// the arithmetization, the number of calls to the precompiles and the request
+// level counts (transactions, L2 to L1 logs, Merkle proofs). The counts are
+// compared to both the normal and the large limits of the configuration.
+//
+// The "BLOCK_L1_SIZE" limit and the number of modexp calls with large
+// operands are not estimated.
+func CheckLimits(cfg *config.Config, req *Request) (_ *LimitsReport, err error) {
+
+	// Request.Blocks panics on malformed blocks
+	defer exit.Recover(&err)
+
+	bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, req.TracesEngineVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, _, err := bin.Compile(&mir.DEFAULT_OPTIMISATION_LEVEL)
+	if err != nil {
+		return nil, exit.Errorf(exit.KindSetupMismatch, "could not read the zkevm.bin file: %w", err)
+	}
+
+	tracesFile := req.ConflatedExecTraceFilepath(cfg.Execution.ConflatedTracesDir)
+
+	counts, err := arithmetization.CountLtTraces(schema, tracesFile)
+	if err != nil {
+		return nil, exit.Errorf(exit.KindInvalidRequest, "could not count the traces: %w", err)
+	}
+
+	var (
+		blocks  = req.Blocks()
+		nbTx    = 0
+		nbLogs  = 0
+		nbProof = 0
+	)
+
+	for i := range blocks {
+		nbTx += len(blocks[i].Transactions())
+		nbLogs += len(bridge.L2L1MessageHashes(req.LogsForBlock(i), cfg.Layer2.MsgSvcContract))
+	}
+
+	for _, traces := range req.StateManagerTraces() {
+		nbProof += accumulator.NumProofsOf(traces)
+	}
+
+	counts["BLOCK_TRANSACTIONS"] = nbTx
+	counts["BLOCK_L2_L1_LOGS"] = nbLogs
+	counts["SHOMEI_MERKLE_PROOFS"] = nbProof
+
+	report := newLimitsReport(counts, &cfg.TracesLimits, &cfg.TracesLimitsLarge)
+	report.TracesFile = tracesFile
+	return report, nil
+}
+
+// newLimitsReport compares the counts with the limits. The entries are sorted
+// in the order of the configuration file. The counts that do not have a
+// limit in the configuration, or whose normal and large limits are both zero
+// (i.e. not configured), are skipped.
+func newLimitsReport(counts map[string]int, limits, limitsLarge *config.TracesLimits) *LimitsReport {
+
+	report := &LimitsReport{Verdict: LimitsVerdictNormal}
+
+	for _, name := range config.TracesLimitNames() {
+
+		count, ok := counts[name]
+		if !ok {
+			continue
+		}
+
+		var (
+			limit, _      = limits.Get(name)
+			limitLarge, _ = limitsLarge.Get(name)
+		)
+
+		if limit == 0 && limitLarge == 0 {
+			continue
+		}
+
+		report.Entries = append(report.Entries, LimitsReportEntry{
+			Name:       name,
+			Count:      count,
+			Limit:      limit,
+			LimitLarge: limitLarge,
+		})
+
+		switch {
+		case count > limitLarge:
+			report.Verdict = LimitsVerdictReject
+		case count > limit && report.Verdict == LimitsVerdictNormal:
+			report.Verdict = LimitsVerdictLarge
+		}
+	}
+
+	return report
+}
+
+// Err returns nil if the request fits the normal limits. Otherwise, it
+// returns a [exit.KindTraceLimit] error if the request fits the large limits
+// and a [exit.KindTraceLimitLarge] error if it does not. The error names the
+// entry with the largest overflow.
+func (r *LimitsReport) Err() error {
+
+	if r.Verdict == LimitsVerdictNormal {
+		return nil
+	}
+
+	var (
+		worst      *exit.Error
+		worstRatio float64
+	)
+
+	for _, e := range r.Entries {
+
+		limit := e.Limit
+		if r.Verdict == LimitsVerdictReject {
+			limit = e.LimitLarge
+		}
+
+		if e.Count <= limit {
+			continue
+		}
+
+		// A zero limit gives an infinite ratio, which is what we want
+		ratio := float64(e.Count) / float64(limit)
+		if worst == nil || ratio > worstRatio {
+			worst = exit.TraceLimitOverflow(e.Name, e.Count, limit)
+			worstRatio = ratio
+		}
+	}
+
+	if r.Verdict == LimitsVerdictReject {
+		worst.Kind = exit.KindTraceLimitLarge
+		worst.Err = fmt.Errorf("large %w", worst.Err)
+	}
+
+	return worst
+}
@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsReport(t *testing.T) {
+
+	var (
+		limits      = &config.TracesLimits{Hub: 16, Add: 8, BlockKeccak: 4}
+		limitsLarge = &config.TracesLimits{Hub: 32, Add: 16, BlockKeccak: 4}
+	)
+
+	testCases := []struct {
+		name    string
+		counts  map[string]int
+		verdict LimitsVerdict
+		kind    exit.Kind
+		module  string
+		// nbSkipped is the number of counts without a configured limit
+		nbSkipped int
+	}{
+		{
+			name:    "normal",
+			counts:  map[string]int{"HUB": 16, "ADD": 8, "BLOCK_KECCAK": 4},
+			verdict: LimitsVerdictNormal,
+		},
+		{
+			name:    "large",
+			counts:  map[string]int{"HUB": 17, "ADD": 15, "BLOCK_KECCAK": 0},
+			verdict: LimitsVerdictLarge,
+			kind:    exit.KindTraceLimit,
+			module:  "ADD",
+		},
+		{
+			name:    "reject",
+			counts:  map[string]int{"HUB": 17, "ADD": 8, "BLOCK_KECCAK": 5},
+			verdict: LimitsVerdictReject,
+			kind:    exit.KindTraceLimitLarge,
+			module:  "BLOCK_KECCAK",
+		},
+		{
+			name:      "unknown-module",
+			counts:    map[string]int{"HUB": 1, "NEWMODULE": 1},
+			verdict:   LimitsVerdictNormal,
+			nbSkipped: 1,
+		},
+		{
+			name:      "unset-limit",
+			counts:    map[string]int{"HUB": 1, "MUL": 3},
+			verdict:   LimitsVerdictNormal,
+			nbSkipped: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			report := newLimitsReport(tc.counts, limits, limitsLarge)
+			assert.Equal(t, tc.verdict, report.Verdict)
+			assert.Len(t, report.Entries, len(tc.counts)-tc.nbSkipped)
+
+			err := report.Err()
+			if len(tc.kind) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			e, ok := exit.As(err)
+			require.True(t, ok)
+			assert.Equal(t, tc.kind, e.Kind)
+			assert.Equal(t, tc.module, e.Module)
+		})
+	}
+}
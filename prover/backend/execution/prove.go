@@ -6,10 +6,14 @@ import (
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/serialization"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	public_input "github.com/consensys/linea-monorepo/prover/public-input"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,10 +22,18 @@ type Witness struct {
 	ZkEVM   *zkevm.Witness
 }
 
-func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
-	traces := &cfg.TracesLimits
-	if large {
-		traces = &cfg.TracesLimitsLarge
+// Prove generates the execution proof of the request. The failures of the
+// prover that are classified by the [exit] package are returned as errors, the
+// other ones panic.
+func Prove(cfg *config.Config, req *Request, large bool) (_ *Response, err error) {
+
+	defer exit.Recover(&err)
+
+	traces := tracesLimits(cfg, large)
+
+	bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, req.TracesEngineVersion)
+	if err != nil {
+		return nil, err
 	}
 
 	var resp Response
@@ -45,6 +57,8 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 				out.Proof, out.VerifyingKeyShaSum = mustProveAndPass(
 					cfg,
 					traces,
+					bin,
+					req,
 					NewWitness(cfg, req, &out),
 				)
 
@@ -65,16 +79,31 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 	return &resp, nil
 }
 
+// tracesLimits returns the limits of the normal or of the large prover
+func tracesLimits(cfg *config.Config, large bool) *config.TracesLimits {
+	if large {
+		return &cfg.TracesLimitsLarge
+	}
+	return &cfg.TracesLimits
+}
+
 // mustProveAndPass the prover (in the void). Does not takes a
 // prover-step function performing the assignment but a function
 // returning such a function. This is important to avoid side-effects
-// when calling it twice.
+// when calling it twice. The zkEVM is compiled for the limits and the
+// constraint binary. In full and bench mode, the inner prover is
+// checkpointed (see [ProverCheckpointDir]) and the inner-proof is dumped (see
+// [InnerProofPath]) when the configuration asks for it.
 func mustProveAndPass(
 	cfg *config.Config,
 	traces *config.TracesLimits,
+	bin *arithmetization.ZkevmBin,
+	req *Request,
 	w *Witness,
 ) (proofHexString string, vkeyShaSum string) {
 
+	innerProofPath := InnerProofPath(cfg, req)
+
 	switch cfg.Execution.ProverMode {
 	case config.ProverModeDev, config.ProverModePartial:
 		if cfg.Execution.ProverMode == config.ProverModePartial {
@@ -84,14 +113,14 @@ func mustProveAndPass(
 			// And run the partial-prover with only the main steps. The generated
 			// proof is sanity-checked to ensure that the prover never outputs
 			// invalid proofs.
-			partial := zkevm.FullZkEVMCheckOnly(traces, cfg)
+			partial := zkevm.FullZkEVMCheckOnly(traces, cfg, bin)
 			proof := partial.ProveInner(w.ZkEVM)
 			if err := partial.VerifyInner(proof); err != nil {
 				utils.Panic("The prover did not pass: %v", err)
 			}
 		}
 
-		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+		srsProvider, err := circuits.NewSRSProvider(cfg)
 		if err != nil {
 			utils.Panic(err.Error())
 		}
@@ -108,7 +137,7 @@ func mustProveAndPass(
 
 		// Run the full prover to obtain the intermediate proof
 		logrus.Info("Get Full IOP")
-		fullZkEvm := zkevm.FullZkEvm(traces, cfg)
+		fullZkEvm := zkevm.FullZkEvm(traces, cfg, bin)
 
 		var (
 			setup       circuits.Setup
@@ -116,62 +145,52 @@ func mustProveAndPass(
 			chSetupDone = make(chan struct{})
 		)
 		go func() {
-			setup, errSetup = circuits.LoadSetup(cfg, circuits.ExecutionCircuitID)
+			setup, errSetup = circuits.LoadVersionedSetup(cfg, circuits.ExecutionCircuitID, bin.Version)
 			close(chSetupDone)
 		}()
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
+		proof := mustProveInner(fullZkEvm, w.ZkEVM, ProverCheckpointDir(cfg, req, fullZkEvm.WizardIOP))
 
 		logrus.Info("Sanity-checking the inner-proof")
 		if err := fullZkEvm.VerifyInner(proof); err != nil {
 			utils.Panic("The prover did not pass: %v", err)
 		}
 
+		// Dump the inner-proof so that the outer-proof can be resumed from it
+		// if it fails.
+		dumpInnerProof(innerProofPath, fullZkEvm.WizardIOP, proof)
+
 		// wait for setup to be loaded
 		<-chSetupDone
 		if errSetup != nil {
-			utils.Panic("could not load setup: %v", errSetup)
-		}
-
-		// ensure the checksum for the traces in the setup matches the one in the config
-		setupCfgChecksum, err := setup.Manifest.GetString("cfg_checksum")
-		if err != nil {
-			utils.Panic("could not get the traces checksum from the setup manifest: %v", err)
+			exit.Panic(exit.KindSetupMismatch, "could not load setup: %w", errSetup)
 		}
 
-		if setupCfgChecksum != traces.Checksum() {
-			// This check is failing on prod but works locally.
-			// @alex: since this is a setup-related constraint, it would likely be
-			// more interesting to directly include that information in the setup
-			// instead of the config. That way we are guaranteed to not pass the
-			// wrong value at runtime.
-			utils.Panic("traces checksum in the setup manifest does not match the one in the config")
-		}
-
-		// TODO: implements the collection of the functional inputs from the prover response
-		return execution.MakeProof(traces, setup, fullZkEvm.WizardIOP, proof, *w.FuncInp), setup.VerifyingKeyDigest()
+		return mustProveOuter(traces, bin, setup, fullZkEvm, proof, w.FuncInp), setup.VerifyingKeyDigest()
 
 	case config.ProverModeBench:
 
 		// Run the full prover to obtain the intermediate proof
 		logrus.Info("Get Full IOP")
-		fullZkEvm := zkevm.FullZkEvm(traces, cfg)
+		fullZkEvm := zkevm.FullZkEvm(traces, cfg, bin)
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
+		proof := mustProveInner(fullZkEvm, w.ZkEVM, ProverCheckpointDir(cfg, req, fullZkEvm.WizardIOP))
 
 		logrus.Info("Sanity-checking the inner-proof")
 		if err := fullZkEvm.VerifyInner(proof); err != nil {
 			utils.Panic("The prover did not pass: %v", err)
 		}
+
+		dumpInnerProof(innerProofPath, fullZkEvm.WizardIOP, proof)
 		return "", ""
 
 	case config.ProverModeCheckOnly:
 
-		fullZkEvm := zkevm.FullZkEVMCheckOnly(traces, cfg)
+		fullZkEvm := zkevm.FullZkEVMCheckOnly(traces, cfg, bin)
 		// this will panic to alert errors, so there is no need to handle or
 		// sanity-check anything.
 		logrus.Infof("Prover starting the prover")
@@ -183,3 +202,58 @@ func mustProveAndPass(
 		panic("not implemented")
 	}
 }
+
+// mustProveInner runs the inner prover, checkpointing it with cp if it is not
+// nil. The checkpoint is removed once the inner-proof is generated.
+func mustProveInner(fullZkEvm *zkevm.ZkEvm, w *zkevm.Witness, cp *serialization.ProverCheckpointDir) wizard.Proof {
+
+	if cp == nil {
+		return fullZkEvm.ProveInner(w)
+	}
+
+	proof := fullZkEvm.ProveInnerWithCheckpoints(w, cp)
+	if err := cp.Clear(); err != nil {
+		logrus.Warnf("could not remove the prover checkpoint: %v", err)
+	}
+
+	return proof
+}
+
+// mustProveOuter checks that the setup was generated for the limits and the
+// constraint binary and wraps the inner-proof in the outer-proof.
+func mustProveOuter(
+	traces *config.TracesLimits,
+	bin *arithmetization.ZkevmBin,
+	setup circuits.Setup,
+	fullZkEvm *zkevm.ZkEvm,
+	proof wizard.Proof,
+	funcInp *public_input.Execution,
+) string {
+
+	// ensure the checksum for the traces in the setup matches the one in the config
+	setupCfgChecksum, err := setup.Manifest.GetString("cfg_checksum")
+	if err != nil {
+		exit.Panic(exit.KindSetupMismatch, "could not get the traces checksum from the setup manifest: %w", err)
+	}
+
+	if setupCfgChecksum != traces.Checksum() {
+		// This check is failing on prod but works locally.
+		// @alex: since this is a setup-related constraint, it would likely be
+		// more interesting to directly include that information in the setup
+		// instead of the config. That way we are guaranteed to not pass the
+		// wrong value at runtime.
+		exit.Panic(exit.KindSetupMismatch, "traces checksum in the setup manifest (%v) does not match the one in the config (%v)", setupCfgChecksum, traces.Checksum())
+	}
+
+	setupBinHash, err := setup.Manifest.GetString("zkevm_bin_hash")
+	if err != nil {
+		exit.Panic(exit.KindSetupMismatch, "could not get the constraints hash from the setup manifest: %w", err)
+	}
+
+	if setupBinHash != bin.Hash() {
+		exit.Panic(exit.KindSetupMismatch, "the setup was generated for the constraints %v, but the request uses %v (%v)", setupBinHash, bin.Hash(), bin.Source)
+	}
+
+	// TODO: implements the collection of the functional inputs from the prover response
+	return execution.MakeProof(traces, setup, fullZkEvm.WizardIOP, proof, *funcInp)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -40,14 +41,14 @@ func (req *Request) Blocks() []ethtypes.Block {
 		// Attempt to parse the block as an hexstring
 		blockRLPBytes, err := utils.HexDecodeString(blockdata.Rlp)
 		if err != nil {
-			utils.Panic("error while parsing the block RLP #%v : %v", i, err)
+			exit.Panic(exit.KindInvalidRequest, "error while parsing the block RLP #%v : %w", i, err)
 		}
 		buffer := bytes.NewReader(blockRLPBytes)
 
 		// Attempt to parse the RLP
 		err = rlp.Decode(buffer, &res[i])
 		if err != nil {
-			utils.Panic("Could not RLP decode the blockRLP 0x%x (block #%v)", blockRLPBytes, i)
+			exit.Panic(exit.KindInvalidRequest, "could not RLP decode the blockRLP 0x%x (block #%v): %w", blockRLPBytes, i, err)
 		}
 
 	}
@@ -106,7 +107,7 @@ func (req *Request) GetRawSignaturesVerificationInputs() (txHashes [][32]byte, p
 			sig := ethereum.GetJsonSignature(tx)
 			pubkey, encodedSig, err := ethereum.RecoverPublicKey(txhash, sig)
 			if err != nil {
-				utils.Panic("error recovering public key from transaction: %v", err)
+				exit.Panic(exit.KindInvalidRequest, "error recovering public key from transaction: %w", err)
 			}
 
 			// append the claims to the return arguments
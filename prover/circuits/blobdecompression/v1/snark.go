@@ -24,7 +24,7 @@ import (
 
 const (
 	checkSumSize   = 32
-	MaxNbBatches   = 100 // TODO ensure this is a reasonable maximum
+	MaxNbBatches   = blob.MaxNbBatches // TODO ensure this is a reasonable maximum
 	maxBlobNbBytes = 128 * 1024 * blob.PackingSizeU256 / 256
 )
 
@@ -0,0 +1,395 @@
+package circuits
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// srsChecksumsFileName is the name of the file listing the checksums of the
+// SRS files in the remote store, in the format of the sha256sum tool.
+const srsChecksumsFileName = "SHA256SUMS"
+
+// NewSRSProvider returns the SRS provider described by the config: a
+// [RemoteSRSProvider] if a remote store is configured and an [SRSStore]
+// reading the assets directory otherwise.
+func NewSRSProvider(cfg *config.Config) (SRSProvider, error) {
+	if len(cfg.RemoteAssets.URL) > 0 {
+		return NewRemoteSRSProvider(cfg)
+	}
+	return NewSRSStore(cfg.PathForSRS())
+}
+
+// RemoteSRSProvider is an [SRSProvider] that downloads the SRS files it needs
+// from the remote store into the SRS directory of the assets directory. The
+// files are checked against the checksums listed by the store. The files
+// already present in the directory are used as they are.
+type RemoteSRSProvider struct {
+	store *remoteStore
+	dir   string
+}
+
+// NewRemoteSRSProvider returns a [RemoteSRSProvider] for the remote store of
+// the config.
+func NewRemoteSRSProvider(cfg *config.Config) (*RemoteSRSProvider, error) {
+	store, err := newRemoteStore(&cfg.RemoteAssets)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSRSProvider{store: store, dir: cfg.PathForSRS()}, nil
+}
+
+func (p *RemoteSRSProvider) GetSRS(ctx context.Context, ccs constraint.ConstraintSystem) (kzg.SRS, kzg.SRS, error) {
+
+	checksums, err := p.store.readChecksums(ctx, path.Join("kzgsrs", srsChecksumsFileName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the checksums of the remote SRS: %w", err)
+	}
+
+	var (
+		sizeCanonical, sizeLagrange = plonk.SRSSize(ccs)
+		curveID                     = fieldToCurve(ccs.Field())
+		canonical, lagrange         string
+		canonicalSize               int
+	)
+
+	for name := range checksums {
+		entry, entryCurveID, ok := parseSRSFileName(name)
+		if !ok || entryCurveID != curveID {
+			continue
+		}
+		if entry.isCanonical && entry.size >= sizeCanonical && (len(canonical) == 0 || entry.size < canonicalSize) {
+			canonical, canonicalSize = name, entry.size
+		}
+		if !entry.isCanonical && entry.size == sizeLagrange {
+			lagrange = name
+		}
+	}
+
+	if len(canonical) == 0 {
+		return nil, nil, fmt.Errorf("the remote store has no canonical SRS for curve %s and size %d", curveID, sizeCanonical)
+	}
+
+	// The lagrange SRS is optional, the SRS store computes it from the
+	// canonical one if it is missing.
+	for _, name := range []string{canonical, lagrange} {
+		if len(name) == 0 {
+			continue
+		}
+		dst := filepath.Join(p.dir, name)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := p.store.fetch(ctx, path.Join("kzgsrs", name), dst, checksums[name]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	store, err := NewSRSStore(p.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return store.GetSRS(ctx, ccs)
+}
+
+// FetchSetup downloads the setup of the circuit for the version of the
+// constraints, if any, from the remote store of the config into the assets
+// directory, unless the setup directory already holds a manifest. The files
+// are checked against the checksums of the manifest, which is written last so
+// that an interrupted download is resumed the next time.
+func FetchSetup(ctx context.Context, cfg *config.Config, circuitID CircuitID, version string) error {
+
+	var (
+		rootDir      = cfg.PathForVersionedSetup(string(circuitID), version)
+		remoteDir    = path.Join(cfg.Version, cfg.Environment, string(circuitID), version)
+		manifestPath = filepath.Join(rootDir, config.ManifestFileName)
+	)
+
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil
+	}
+
+	store, err := newRemoteStore(&cfg.RemoteAssets)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("fetching the setup of %v from the remote store", circuitID)
+
+	manifestBytes, err := store.readAll(ctx, path.Join(remoteDir, config.ManifestFileName))
+	if err != nil {
+		return fmt.Errorf("reading the remote manifest: %w", err)
+	}
+
+	var manifest SetupManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("decoding the remote manifest: %w", err)
+	}
+
+	files := []struct {
+		name, checksum string
+	}{
+		{config.CircuitFileName, manifest.Checksums.Circuit},
+		{config.VerifyingKeyFileName, manifest.Checksums.VerifyingKey},
+		{config.VerifierContractFileName, manifest.Checksums.VerifierContract},
+	}
+
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return fmt.Errorf("creating directory %q: %w", rootDir, err)
+	}
+
+	for _, f := range files {
+
+		// The verifier contract is only generated for BN254 circuits
+		if f.name == config.VerifierContractFileName && len(f.checksum) == 0 {
+			continue
+		}
+
+		if len(f.checksum) == 0 {
+			return fmt.Errorf("the remote manifest has no checksum for %v", f.name)
+		}
+
+		dst := filepath.Join(rootDir, f.name)
+
+		// Left by an interrupted download
+		if err := checkFileChecksum(dst, f.checksum); err == nil {
+			continue
+		}
+
+		if err := store.fetch(ctx, path.Join(remoteDir, f.name), dst, f.checksum); err != nil {
+			return err
+		}
+	}
+
+	return writeFileAtomic(manifestPath, func(w io.Writer) error {
+		_, err := w.Write(manifestBytes)
+		return err
+	})
+}
+
+// remoteStore downloads files from the store described by a
+// [config.RemoteAssets].
+type remoteStore struct {
+	baseURL *url.URL
+	client  *http.Client
+	// signer is nil if the requests are not signed
+	signer *sigV4Signer
+}
+
+func newRemoteStore(cfg *config.RemoteAssets) (*remoteStore, error) {
+
+	if len(cfg.URL) == 0 {
+		return nil, errors.New("no remote store is configured")
+	}
+
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the URL of the remote store: %w", err)
+	}
+
+	store := &remoteStore{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+
+	if len(cfg.S3Region) > 0 {
+		store.signer = &sigV4Signer{
+			region:       cfg.S3Region,
+			service:      "s3",
+			accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+			now:          time.Now,
+		}
+	}
+
+	return store, nil
+}
+
+// get opens the file at relPath in the store. The caller must close it.
+func (s *remoteStore) get(ctx context.Context, relPath string) (io.ReadCloser, error) {
+
+	u := s.baseURL.JoinPath(relPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.signer != nil {
+		s.signer.sign(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %v: %w", u.Redacted(), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %v: unexpected status %v", u.Redacted(), resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+func (s *remoteStore) readAll(ctx context.Context, relPath string) ([]byte, error) {
+	body, err := s.get(ctx, relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// readChecksums reads a checksum file in the format of the sha256sum tool
+// and returns the checksums indexed by file name.
+func (s *remoteStore) readChecksums(ctx context.Context, relPath string) (map[string]string, error) {
+
+	body, err := s.get(ctx, relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var (
+		res     = make(map[string]string)
+		scanner = bufio.NewScanner(body)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum prefixes the name with '*' in binary mode
+		res[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+
+	return res, scanner.Err()
+}
+
+// fetch downloads the file at relPath into dst and checks that its SHA256
+// matches the checksum. The file is only moved to dst once checked.
+func (s *remoteStore) fetch(ctx context.Context, relPath, dst, checksum string) error {
+
+	body, err := s.get(ctx, relPath)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	start := time.Now()
+
+	err = writeFileAtomic(dst, func(w io.Writer) error {
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(w, h), body); err != nil {
+			return fmt.Errorf("downloading %v: %w", relPath, err)
+		}
+		return compareChecksums(relPath, h.Sum(nil), checksum)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("fetched %v from the remote store in %v", relPath, time.Since(start))
+	return nil
+}
+
+// checkFileChecksum returns an error if the file does not exist or if its
+// SHA256 does not match the checksum.
+func checkFileChecksum(filePath, checksum string) error {
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	return compareChecksums(filePath, h.Sum(nil), checksum)
+}
+
+// compareChecksums compares a digest with a hex checksum, optionally prefixed
+// with 0x as in the [SetupManifest].
+func compareChecksums(name string, digest []byte, checksum string) error {
+	expected := strings.TrimPrefix(strings.ToLower(checksum), "0x")
+	if got := hex.EncodeToString(digest); got != expected {
+		return fmt.Errorf("checksum mismatch for %v: expected %v, got %v", name, expected, got)
+	}
+	return nil
+}
+
+// writeFileAtomic writes a file through a temporary file that is renamed
+// into place if write succeeds and removed otherwise.
+func writeFileAtomic(dst string, write func(w io.Writer) error) error {
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.part")
+	if err != nil {
+		return fmt.Errorf("creating a temporary file for %q: %w", dst, err)
+	}
+
+	if err := errors.Join(write(tmp), tmp.Close()); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("renaming %q: %w", tmp.Name(), err)
+	}
+
+	return nil
+}
+
+// parseSRSFileName parses the name of an SRS file as generated by the
+// trusted setup ceremonies, e.g.
+// "kzg_srs_canonical_1024_bn254_aleo.memdump". The boolean is false if the
+// name does not match.
+func parseSRSFileName(name string) (fsEntry, ecc.ID, bool) {
+
+	matches := srsRegexp.FindStringSubmatch(name)
+	if matches == nil {
+		return fsEntry{}, ecc.UNKNOWN, false
+	}
+
+	var curveID ecc.ID
+	switch matches[4] {
+	case "bls12377":
+		curveID = ecc.BLS12_377
+	case "bn254":
+		curveID = ecc.BN254
+	case "bw6761":
+		curveID = ecc.BW6_761
+	}
+
+	size, _ := strconv.Atoi(matches[3])
+
+	return fsEntry{isCanonical: matches[2] == "canonical", size: size, path: name}, curveID, true
+}
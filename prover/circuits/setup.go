@@ -146,10 +146,41 @@ func (s *Setup) WriteTo(rootDir string) error {
 	return nil
 }
 
+// LoadSetup reads the setup of the circuit from the assets directory. If a
+// remote store is configured, the missing assets are first fetched from it
+// (see [FetchSetup]). If the setup cache is enabled (see [EnableSetupCache]),
+// the setup is only read the first time and is served from memory afterwards.
 func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
+	return LoadVersionedSetup(cfg, circuitID, "")
+}
+
+// LoadVersionedSetup works as [LoadSetup] but reads the setup generated for
+// the given version of the constraints, see [config.Config.PathForVersionedSetup].
+func LoadVersionedSetup(cfg *config.Config, circuitID CircuitID, version string) (Setup, error) {
+
+	rootDir := cfg.PathForVersionedSetup(string(circuitID), version)
+
+	load := func() (Setup, error) {
+		if len(cfg.RemoteAssets.URL) > 0 {
+			if err := FetchSetup(context.Background(), cfg, circuitID, version); err != nil {
+				return Setup{}, fmt.Errorf("fetching the setup of %v: %w", circuitID, err)
+			}
+		}
+		return loadSetupFromDir(cfg, rootDir)
+	}
+
+	if !setupCache.isEnabled() {
+		return load()
+	}
+
+	return setupCache.getOrLoad(rootDir, load)
+}
+
+// loadSetupFromDir reads the setup stored in rootDir and completes the proving
+// key with the SRS.
+func loadSetupFromDir(cfg *config.Config, rootDir string) (Setup, error) {
 	runtime.GC()
 
-	rootDir := cfg.PathForSetup(string(circuitID))
 	manifestPath := filepath.Join(rootDir, config.ManifestFileName)
 	manifest, err := ReadSetupManifest(manifestPath)
 	if err != nil {
@@ -183,7 +214,7 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 	}
 
 	// Load the proving key from the SRS provider
-	srsProvider, err := NewSRSStore(cfg.PathForSRS())
+	srsProvider, err := NewSRSProvider(cfg)
 	if err != nil {
 		return Setup{}, fmt.Errorf("creating SRS provider: %w", err)
 	}
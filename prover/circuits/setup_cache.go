@@ -0,0 +1,73 @@
+package circuits
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// setupCache memoizes the setups returned by [LoadSetup] for long-running
+// processes. It is disabled by default because a one-shot prover process
+// would only pay the memory cost of keeping the setups around.
+var setupCache = &memSetupCache{}
+
+type memSetupCache struct {
+	mu      sync.Mutex
+	enabled bool
+	// setups indexed by the directory they were read from. Each entry has its
+	// own lock so that loading one setup does not block the others.
+	setups map[string]*cachedSetup
+}
+
+type cachedSetup struct {
+	once  sync.Once
+	setup Setup
+	err   error
+}
+
+// EnableSetupCache makes [LoadSetup] keep every setup it reads in memory for
+// the rest of the process' lifetime. It is meant for the server mode of the
+// prover where the same setups are used for every request.
+func EnableSetupCache() {
+	setupCache.mu.Lock()
+	defer setupCache.mu.Unlock()
+	setupCache.enabled = true
+	if setupCache.setups == nil {
+		setupCache.setups = map[string]*cachedSetup{}
+	}
+}
+
+func (c *memSetupCache) isEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabled
+}
+
+// getOrLoad returns the setup cached for rootDir, calling load to populate the
+// entry if it is missing. Failed loads are not cached so that they can be
+// retried.
+func (c *memSetupCache) getOrLoad(rootDir string, load func() (Setup, error)) (Setup, error) {
+
+	c.mu.Lock()
+	entry, found := c.setups[rootDir]
+	if !found {
+		entry = &cachedSetup{}
+		c.setups[rootDir] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		logrus.Infof("loading the setup from %v in the setup cache", rootDir)
+		entry.setup, entry.err = load()
+	})
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.setups[rootDir] == entry {
+			delete(c.setups, rootDir)
+		}
+		c.mu.Unlock()
+	}
+
+	return entry.setup, entry.err
+}
@@ -3,13 +3,11 @@ package circuits
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
-	"strconv"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
@@ -22,6 +20,8 @@ import (
 	kzgbw6 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
 )
 
+var srsRegexp = regexp.MustCompile(`^(kzg_srs)_(canonical|lagrange)_(\d+)_(bls12377|bn254|bw6761)_(aleo|aztec|celo)\.memdump$`)
+
 type SRSStore struct {
 	entries map[ecc.ID][]fsEntry
 }
@@ -50,42 +50,18 @@ func NewSRSStore(rootDir string) (*SRSStore, error) {
 	srsStore.entries[ecc.BN254] = []fsEntry{}
 	srsStore.entries[ecc.BW6_761] = []fsEntry{}
 
-	srsRegexp := regexp.MustCompile(`^(kzg_srs)_(canonical|lagrange)_(\d+)_(bls12377|bn254|bw6761)_(aleo|aztec|celo)\.memdump$`)
-
 	for _, entry := range dir {
 		if entry.IsDir() {
 			continue
 		}
-		// parse the file name
-		// create a fsEntry
-		// store it in the map
 
-		fileName := entry.Name()
-		matches := srsRegexp.FindStringSubmatch(fileName)
-		if matches == nil {
+		fsEntry, curveID, ok := parseSRSFileName(entry.Name())
+		if !ok {
 			continue
 		}
 
-		isCanonical := matches[2] == "canonical"
-		size, _ := strconv.Atoi(matches[3])
-		var curveID ecc.ID
-		switch matches[4] {
-		case "bls12377":
-			curveID = ecc.BLS12_377
-		case "bn254":
-			curveID = ecc.BN254
-		case "bw6761":
-			curveID = ecc.BW6_761
-		default:
-			return nil, errors.New("curve not supported")
-		}
-
-		srsStore.entries[curveID] = append(srsStore.entries[curveID], fsEntry{
-			isCanonical: isCanonical,
-			size:        size,
-			path:        filepath.Join(rootDir, fileName),
-		})
-
+		fsEntry.path = filepath.Join(rootDir, entry.Name())
+		srsStore.entries[curveID] = append(srsStore.entries[curveID], fsEntry)
 	}
 
 	// sort the entries by size
@@ -2,14 +2,13 @@ package controller
 
 import (
 	"context"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
-	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,8 +16,11 @@ import (
 func runController(ctx context.Context, cfg *config.Config) {
 	var (
 		cLog          = cfg.Logger().WithField("component", "main-loop")
-		fsWatcher     = NewFsWatcher(cfg)
+		jobSource     = NewJobSource(cfg)
 		executor      = NewExecutor(cfg)
+		sched         = NewScheduler(cfg)
+		inFlight      sync.WaitGroup
+		jobDone       = make(chan struct{}, sched.MaxJobs)
 		numRetrySoFar int
 	)
 
@@ -43,10 +45,18 @@ func runController(ctx context.Context, cfg *config.Config) {
 		// SIGTERM is received, there would be no log entry about the signal
 		// until the proof completes.
 		<-ctx.Done()
-		cLog.Infoln("Received cancellation request, will exit as soon as possible or once current proof task is complete.")
+		cLog.Infoln("Received cancellation request, will exit as soon as possible or once the current proof tasks are complete.")
 	}()
 
 	for {
+
+		// When all the slots are taken, there is no point in looking for a
+		// new job: we wait for one of the running jobs to complete.
+		var nextPoll <-chan time.Time
+		if !sched.Full() {
+			nextPoll = retryDelay(cfg.Controller.RetryDelays, numRetrySoFar)
+		}
+
 		select {
 		case <-ctx.Done():
 			// Graceful shutdown.
@@ -56,14 +66,22 @@ func runController(ctx context.Context, cfg *config.Config) {
 			// allows the ctx.Done channel to be read multiple times, which, in
 			// our scenario, ensures cancellation requests are effectively
 			// detected and handled.
-			cLog.Infoln("Context canceled by caller or SIGTERM. Exiting")
+			cLog.Infof("Context canceled by caller or SIGTERM. Waiting for %v in-flight jobs before exiting", sched.Running())
+			inFlight.Wait()
+			cLog.Infoln("All jobs completed. Exiting")
 			metrics.ShutdownServer(ctx)
 			return
 
+		// A job completed and released its resources, we can look for a new
+		// one right away.
+		case <-jobDone:
+			numRetrySoFar = 0
+
 		// Processing a new job
-		case <-retryDelay(cfg.Controller.RetryDelays, numRetrySoFar):
-			// Fetch the best block we can fetch
-			job := fsWatcher.GetBest()
+		case <-nextPoll:
+			// Fetch the best block we can fetch, among the ones we have the
+			// capacity to run.
+			job := GetBest(jobSource, sched, cLog)
 
 			// No jobs, waiting a little before we retry
 			if job == nil {
@@ -80,111 +98,51 @@ func runController(ctx context.Context, cfg *config.Config) {
 			// Else, reset the retry counter
 			numRetrySoFar = 0
 
-			// Run the command (potentially retrying in large mode)
-			status := executor.Run(job)
-
-			// createColumns the job according to the status we got
-			switch {
-
-			// Success
-			case status.ExitCode == CodeSuccess:
-				// NB: we already check that the response filename can be
-				// generated prior to running the command. So this actually
-				// will not panic.
-				respFile, err := job.ResponseFile()
-				tmpRespFile := job.TmpResponseFile(cfg)
-				if err != nil {
-					formatStr := "Could not generate the response file: %v (original request file: %v)"
-					utils.Panic(formatStr, err, job.OriginalFile)
-				}
+			inFlight.Add(1)
+			go func() {
+				defer func() {
+					sched.Release(job)
+					inFlight.Done()
+					jobDone <- struct{}{}
+				}()
+				runJob(cfg, cLog, jobSource, executor, job)
+			}()
+		}
+	}
+}
 
-				logrus.Infof(
-					"Moving the response file from the tmp response file `%v`, to the final response file: `%v`",
-					tmpRespFile, respFile,
-				)
-
-				if err := os.Rename(tmpRespFile, respFile); err != nil {
-					// @Alex: it is unclear how the rename operation could fail
-					// here. If this happens, we prefer removing the tmp file.
-					// Note that the operation is an `mv -f`
-					os.Remove(tmpRespFile)
-
-					cLog.Errorf(
-						"Error renaming %v to %v: %v, removed the tmp file",
-						tmpRespFile, respFile, err,
-					)
-				}
+// Runs the job (potentially retrying in large mode) and reports its outcome to
+// the job source.
+func runJob(cfg *config.Config, cLog *logrus.Entry, jobSource JobSource, executor *Executor, job *Job) {
 
-				// Move the inprogress to the done directory
-				cLog.Infof(
-					"Moving %v to %v with the success prefix",
-					job.OriginalFile, job.Def.dirDone(),
-				)
-
-				jobDone := job.DoneFile(status)
-				if err := os.Rename(job.InProgressPath(), jobDone); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"Error renaming %v to %v: %v",
-						job.InProgressPath(), jobDone, err,
-					)
-				}
+	status := executor.Run(job)
 
-			// Defer to the large prover
-			case job.Def.Name == jobNameExecution && isIn(status.ExitCode, cfg.Controller.DeferToOtherLargeCodes):
-				cLog.Infof("Renaming %v for the large prover", job.OriginalFile)
-				// Move the inprogress file back in the from directory with
-				// the new suffix
-				toLargePath, err := job.DeferToLargeFile(status)
-				if err != nil {
-					// There are two possibilities of errors. (1), the status
-					// we success but the above cases prevents that. The other
-					// case is that the suffix was not provided. But, during
-					// the config validation, we check already that the suffix
-					// must be provided if the size of the list of
-					// deferToOtherLargeCodes is non-zero. If the size of the
-					// list was zero, then there would be no way to reach this
-					// portion of the code given that the current exit code
-					// cannot be part of the empty list. Thus, this section is
-					// unreachable.
-					cLog.Errorf(
-						"error deriving the to-large-name of %v: %v",
-						job.InProgressPath(), err,
-					)
-				}
+	// Report the outcome of the job to the source according to the status we
+	// got
+	var err error
+	switch {
 
-				if err := os.Rename(job.InProgressPath(), toLargePath); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"error renaming %v to %v: %v",
-						job.InProgressPath(), toLargePath, err,
-					)
-				}
+	// Success
+	case status.ExitCode == CodeSuccess:
+		err = jobSource.Ack(job, status)
 
-			// Failure case
-			default:
-				// Move the inprogress to the done directory
-				cLog.Infof(
-					"Moving %v with in %v with a failure suffix for code %v",
-					job.OriginalFile, job.Def.dirDone(), status.ExitCode,
-				)
-
-				jobFailed := job.DoneFile(status)
-				if err := os.Rename(job.InProgressPath(), jobFailed); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"Error renaming %v to %v: %v",
-						job.InProgressPath(), jobFailed, err,
-					)
-				}
-			}
-		}
+	// Defer to the large prover. The deterministic failures are never deferred
+	// since they would fail on the large prover as well.
+	case job.Def.Name == jobNameExecution && isIn(status.ExitCode, cfg.Controller.DeferToOtherLargeCodes) && !status.Kind().IsDeterministic():
+		err = jobSource.DeferToLarge(job, status)
+
+	// Failure case
+	default:
+		err = jobSource.Nack(job, status)
+	}
+
+	if err != nil {
+		// When that happens, the only thing left to do is to log the error.
+		// The job will likely require a human intervention.
+		cLog.Errorf(
+			"Could not report the status (code=%v) of job %v to the job source: %v",
+			status.ExitCode, job.OriginalFile, err,
+		)
 	}
 }
 
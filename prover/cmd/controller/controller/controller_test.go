@@ -115,8 +115,8 @@ func TestRunCommand(t *testing.T) {
 			Entries: []string{
 				"0-2-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.success",
 				"2-4-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.failure.code_2",
-				"4-6-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.failure.code_77",
-				"6-8-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.failure.code_137",
+				"4-6-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.failure.code_77_trace-limit",
+				"6-8-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json.failure.code_137_oom",
 			},
 		},
 		{
@@ -134,8 +134,8 @@ func TestRunCommand(t *testing.T) {
 			Entries: []string{
 				"0-2-deadbeef57-getZkAggregatedProof.json.success",
 				"2-4-deadbeef57-getZkAggregatedProof.json.failure.code_2",
-				"4-6-deadbeef57-getZkAggregatedProof.json.failure.code_77",
-				"6-8-deadbeef57-getZkAggregatedProof.json.failure.code_137",
+				"4-6-deadbeef57-getZkAggregatedProof.json.failure.code_77_trace-limit",
+				"6-8-deadbeef57-getZkAggregatedProof.json.failure.code_137_oom",
 			},
 		},
 		{
@@ -155,6 +155,64 @@ func TestRunCommand(t *testing.T) {
 
 }
 
+func TestRunConcurrentJobs(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.MaxConcurrentJobs = 3
+	confM.Controller.Resources = config.Resources{
+		Total:       config.ResourceBudget{MemoryGiB: 10},
+		Execution:   config.ResourceBudget{MemoryGiB: 4},
+		Aggregation: config.ResourceBudget{MemoryGiB: 4},
+	}
+
+	var (
+		eFrom = confM.Execution.DirFrom()
+		aFrom = confM.Aggregation.DirFrom()
+	)
+
+	// Each job takes 2 seconds, the budget only allows two of them to run
+	// at the same time.
+	for i, dir := range []string{eFrom, eFrom, aFrom} {
+		jobType := execJob
+		if dir == aFrom {
+			jobType = aggregationJob
+		}
+		fname := createTestInputFile(dir, i, i+1, jobType, 0)
+		err := os.WriteFile(path.Join(dir, fname), []byte("#!/bin/sh\nsleep 2\nexit 0"), 0600)
+		require.NoError(t, err)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		runController(ctx, confM)
+		close(finished)
+	}()
+
+	// Cancel while the first two jobs are still running. The controller
+	// should wait for them but not start the third one.
+	<-time.After(1 * time.Second)
+	stop()
+
+	select {
+	case <-finished:
+		t.Fatalf("the controller returned before the in-flight jobs completed")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	<-finished
+
+	countFiles := func(dir string) int {
+		ls, err := lsname(dir)
+		require.NoError(t, err)
+		return len(ls)
+	}
+
+	assert.Equal(t, 2, countFiles(confM.Execution.DirDone()), "both executions should have run concurrently")
+	assert.Equal(t, 0, countFiles(confM.Aggregation.DirDone()), "the aggregation should not have been started")
+	assert.Equal(t, 1, countFiles(aFrom), "the aggregation should not have been locked")
+}
+
 func TestFileWatcherM(t *testing.T) {
 
 	confM, _ := setupFsTest(t)
@@ -1,29 +1,38 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/sirupsen/logrus"
 )
 
 // List of the possible errors returned by the prover as an exit code. There are
 // other possibilities but the one listed here are the one of interests. Some of
 // these codes are generated by the executor and not the child process itself.
-// These includes, CodeFatal, CodeTooManyRetries and CodeCantRunCommand.
+// These includes, CodeFatal, CodeTooManyRetries and CodeCantRunCommand. The
+// codes are defined by the [exit] package which is shared with the prover.
 const (
-	CodeSuccess        int = 0   // Success code
-	CodeTraceLimit     int = 77  // The traces are overflown
-	CodeOom            int = 137 // When the process exits on OOM
-	CodeFatal          int = 14  // When the process could not start
-	CodeCantRunCommand int = 15  // When the controller could not run the command
+	CodeSuccess         int = exit.CodeSuccess         // Success code
+	CodeTraceLimit      int = exit.CodeTraceLimit      // The traces are overflown
+	CodeInvalidRequest  int = exit.CodeInvalidRequest  // The request is invalid
+	CodeStateMismatch   int = exit.CodeStateMismatch   // The state-manager traces are inconsistent
+	CodeSetupMismatch   int = exit.CodeSetupMismatch   // The setup does not match the config
+	CodeOomRisk         int = exit.CodeOomRisk         // The machine does not have enough memory
+	CodeTraceLimitLarge int = exit.CodeTraceLimitLarge // The traces are overflown for the large limits too
+	CodeOom             int = exit.CodeOom             // When the process exits on OOM
+	CodeFatal           int = exit.CodeFatal           // When the process could not start
+	CodeCantRunCommand  int = exit.CodeCantRunCommand  // When the controller could not run the command
 )
 
 // Status of a finished job
@@ -34,6 +43,17 @@ type Status struct {
 	What string
 	// Additional errors for context
 	Err error
+	// The failure report written by the prover, if any
+	Failure *exit.Failure
+}
+
+// Kind returns the classification of the failure. The failure report of the
+// prover takes precedence over the exit code when it is available.
+func (s Status) Kind() exit.Kind {
+	if s.Failure != nil && len(s.Failure.Kind) > 0 {
+		return s.Failure.Kind
+	}
+	return exit.KindOf(s.ExitCode)
 }
 
 // Resource collects all the informations about the job that can be used to
@@ -76,8 +96,18 @@ func (e *Executor) Run(job *Job) (status Status) {
 	// note: checking that locked job contains "large" is not super typesafe...
 	largeRun := job.Def.Name == jobNameExecution && e.Config.Execution.CanRunFullLarge && strings.Contains(job.LockedFile, config.LargeSuffix)
 
+	// Estimate the traces before proving, this tells whether the job can be
+	// directly run in large mode or rejected.
+	if !largeRun && job.Def.Name == jobNameExecution && e.Config.Controller.CheckLimitsCmdTmpl != nil {
+		checkStatus, done := e.checkLimits(job)
+		if done {
+			return checkStatus
+		}
+		largeRun = checkStatus.ExitCode != CodeSuccess
+	}
+
 	// First, run the job normally
-	cmd, err := e.buildCmd(job, largeRun)
+	cmd, err := e.buildCmd(job, e.workerCmdTmpl(largeRun), job.TmpResponseFile(e.Config))
 	if err != nil {
 		return Status{
 			ExitCode: CodeCantRunCommand,
@@ -86,7 +116,17 @@ func (e *Executor) Run(job *Job) (status Status) {
 		}
 	}
 
-	status = runCmd(cmd, job, false)
+	status = e.runCmd(cmd, job, job.TmpResponseFile(e.Config), false)
+
+	// An interrupted prover is run again with the same command so that it
+	// resumes from its last checkpoint instead of starting over in large mode.
+	if e.canResumeFromCheckpoint(job, status) {
+		e.Logger.Infof(
+			"resuming %v from its checkpoint after code %v (%v)",
+			job.OriginalFile, status.ExitCode, status.What,
+		)
+		status = e.runCmd(cmd, job, job.TmpResponseFile(e.Config), true)
+	}
 
 	// if it's a blob decompression or aggregation, we never retry with a large
 	// command. We can return the status as is.
@@ -102,15 +142,16 @@ func (e *Executor) Run(job *Job) (status Status) {
 
 	// Happy path, the job is successful and we can return the status. Testing
 	// for success separately from the other code ensures that we are not going
-	// to retry on success even if the controller is misconfigured.
-	if isSuccess || !isRetryableCode {
+	// to retry on success even if the controller is misconfigured. The
+	// deterministic failures are never retried as they would fail the same way.
+	if isSuccess || !isRetryableCode || status.Kind().IsDeterministic() {
 		return status
 	}
 
 	// At this point, we expect that all the required fields to fill the
 	// template have been passed in job args have been passed. This is enforced
 	// by the configuration validation rule.
-	cmd, err = e.buildCmd(job, true)
+	cmd, err = e.buildCmd(job, e.workerCmdTmpl(true), job.TmpResponseFile(e.Config))
 	if err != nil {
 		return Status{
 			ExitCode: CodeCantRunCommand,
@@ -120,15 +161,126 @@ func (e *Executor) Run(job *Job) (status Status) {
 	}
 
 	// And escalates the return whatever the return value is.
-	return runCmd(cmd, job, true)
+	return e.runCmd(cmd, job, job.TmpResponseFile(e.Config), true)
+}
+
+// Runs the check-limits command for an execution job. It returns done = true
+// if the job should not be proven by this executor: the status is then the
+// final status of the job. Otherwise, a status with an exit code other than
+// CodeSuccess indicates that the job should be directly proven in large mode.
+func (e *Executor) checkLimits(job *Job) (status Status, done bool) {
+
+	// The check has its own output file so that it never clobbers the
+	// response of the job.
+	outFile := job.TmpCheckLimitsFile(e.Config)
+	defer os.Remove(outFile)
+
+	cmd, err := e.buildCmd(job, e.Config.Controller.CheckLimitsCmdTmpl, outFile)
+	if err != nil {
+		return Status{
+			ExitCode: CodeCantRunCommand,
+			Err:      err,
+			What:     "can't format the command",
+		}, true
+	}
+
+	status = e.runCmd(cmd, job, outFile, false)
+
+	switch {
+	case status.ExitCode == CodeSuccess:
+		return status, false
+	case status.Kind().IsDeterministic():
+		e.keepCheckLimitsReport(job, outFile)
+		return status, true
+	case isIn(status.ExitCode, e.Config.Controller.RetryLocallyWithLargeCodes):
+		os.Remove(exit.FailureFile(outFile))
+		return status, false
+	case isIn(status.ExitCode, e.Config.Controller.DeferToOtherLargeCodes):
+		e.keepCheckLimitsReport(job, outFile)
+		return status, true
+	}
+
+	os.Remove(exit.FailureFile(outFile))
+
+	// The check is only an optimization, the job is proven normally when it
+	// fails for another reason.
+	e.Logger.Warnf(
+		"the limits check of %v failed with code %v (%v), proving it normally",
+		job.OriginalFile, status.ExitCode, status.What,
+	)
+	return Status{ExitCode: CodeSuccess}, false
 }
 
-// Builds a command from a template to run, returns a status if it failed
-func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
+// Moves the failure report of the limits check, if any, where the job source
+// expects the report of the job. This is used when the check is the final
+// status of the job.
+func (e *Executor) keepCheckLimitsReport(job *Job, outFile string) {
+	report := exit.FailureFile(outFile)
+	if _, err := os.Stat(report); err != nil {
+		return
+	}
+	if err := os.Rename(report, exit.FailureFile(job.TmpResponseFile(e.Config))); err != nil {
+		e.Logger.Warnf("could not move the failure report %v: %v", report, err)
+		os.Remove(report)
+	}
+}
 
-	// The generates a name for the output file. Also attempts to generate the
-	// name of the final response file so that we can be sure it will be
-	// not fail being generated after having run the command.
+// Returns true if the execution job failed in a way that the prover may
+// resume from its checkpoint. This requires the checkpoints to be enabled and
+// the failure to be neither a success nor deterministic.
+func (e *Executor) canResumeFromCheckpoint(job *Job, status Status) bool {
+	return job.Def.Name == jobNameExecution &&
+		len(e.Config.Execution.CheckpointDir) > 0 &&
+		status.ExitCode != CodeSuccess &&
+		!status.Kind().IsDeterministic() &&
+		isIn(status.ExitCode, e.Config.Controller.RetryLocallyFromCheckpointCodes)
+}
+
+// Returns the template of the worker command
+func (e *Executor) workerCmdTmpl(large bool) *template.Template {
+	if large {
+		return e.Config.Controller.WorkerCmdLargeTmpl
+	}
+	return e.Config.Controller.WorkerCmdTmpl
+}
+
+// Runs the command of the job and completes the status with the failure report
+// written by the prover next to the output file, if any.
+func (e *Executor) runCmd(cmd string, job *Job, outFile string, retry bool) Status {
+
+	// A report left by a previous attempt would be misattributed to this one
+	failureFile := exit.FailureFile(outFile)
+	os.Remove(failureFile)
+
+	status := runCmd(cmd, job, retry, e.env(job))
+	if status.ExitCode == CodeSuccess {
+		return status
+	}
+
+	failure, err := exit.ReadFailure(failureFile)
+	if err != nil {
+		// The prover may not have been able to write the report, for instance
+		// if it was killed. The exit code is then all we have.
+		if !errors.Is(err, os.ErrNotExist) {
+			e.Logger.Warnf("could not read the failure report %v: %v", failureFile, err)
+		}
+		return status
+	}
+
+	status.Failure = failure
+	if len(failure.Message) > 0 {
+		status.What = failure.Message
+	}
+
+	return status
+}
+
+// Builds a command from a template to run, returns a status if it failed. The
+// command writes its output into outFile.
+func (e *Executor) buildCmd(job *Job, tmpl *template.Template, outFile string) (cmd string, err error) {
+
+	// Attempts to generate the name of the final response file so that we can
+	// be sure it will not fail being generated after having run the command.
 	if _, err := job.ResponseFile(); err != nil {
 		logrus.Errorf(
 			"could not generate the tmp response filename for %s: %v",
@@ -136,12 +288,6 @@ func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 		)
 		return "", err
 	}
-	outFile := job.TmpResponseFile(e.Config)
-
-	tmpl := e.Config.Controller.WorkerCmdTmpl
-	if large {
-		tmpl = e.Config.Controller.WorkerCmdLargeTmpl
-	}
 
 	// use the template to generate the command
 	resource := Resource{
@@ -166,9 +312,21 @@ func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 	return w.String(), nil
 }
 
+// Returns the environment of the worker process for the job. When the job
+// type has a CPU budget, the worker inherits GOMAXPROCS accordingly so that
+// concurrent jobs do not compete for the same cores.
+func (e *Executor) env(job *Job) []string {
+	env := os.Environ()
+	budget := jobBudget(e.Config.Controller.Resources, job.Def.Name)
+	if budget.CPUs > 0 {
+		env = append(env, fmt.Sprintf("GOMAXPROCS=%v", budget.CPUs))
+	}
+	return env
+}
+
 // Run a command and returns the status. Retry gives an indication on whether
-// this is a local retry or not.
-func runCmd(cmd string, job *Job, retry bool) Status {
+// this is a local retry or not. The env is passed to the process.
+func runCmd(cmd string, job *Job, retry bool, env []string) Status {
 
 	// Split the command into a list of argvs that can be passed to the os
 	// package.
@@ -200,6 +358,7 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 		argvs[0], argvs,
 		// Pipe the child process's stdin/stdout/stderr into the current process
 		&os.ProcAttr{
+			Env: env,
 			Files: []*os.File{
 				os.Stdin,
 				os.Stdout,
@@ -261,6 +420,16 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 		status.What = "out of memory error"
 	case CodeTraceLimit:
 		status.What = "trace limit overflow"
+	case CodeInvalidRequest:
+		status.What = "invalid request"
+	case CodeStateMismatch:
+		status.What = "state-manager traces mismatch"
+	case CodeSetupMismatch:
+		status.What = "setup mismatch"
+	case CodeOomRisk:
+		status.What = "not enough memory to run the job"
+	case CodeTraceLimitLarge:
+		status.What = "trace limit overflow in large mode"
 	}
 
 	metrics.CollectPostProcess(job.Def.Name, status.ExitCode, processingTime, retry)
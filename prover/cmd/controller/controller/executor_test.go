@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"path/filepath"
 	"testing"
 	"text/template"
 
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -97,3 +99,230 @@ func TestRetryWithLarge(t *testing.T) {
 		assert.Equalf(t, jobs[i].ExpCode, status.ExitCode, "got status %++v", status)
 	}
 }
+
+func TestNoRetryOnDeterministicFailures(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+		FailureSuffix:   matchFailureSuffix(config.FailSuffix),
+	}
+
+	job := Job{
+		Def:          &testDefinition,
+		OriginalFile: "exit-78.sh",
+		LockedFile:   "exit-78.sh",
+	}
+
+	e := NewExecutor(&config.Config{
+		Controller: config.Controller{
+			WorkerCmdTmpl: template.Must(
+				template.New("test-cmd").
+					Parse("/bin/sh {{.InFile}}"),
+			),
+			WorkerCmdLargeTmpl: template.Must(
+				template.New("test-cmd-large").
+					Parse(`/bin/sh -c "/bin/sh {{.InFile}}"; exit $(($? + 10))`),
+			),
+			// The invalid-request code is listed on purpose: a misconfigured
+			// controller should still not retry a deterministic failure.
+			RetryLocallyWithLargeCodes: []int{CodeTraceLimit, CodeInvalidRequest},
+		},
+	})
+
+	status := e.Run(&job)
+	assert.Equalf(t, CodeInvalidRequest, status.ExitCode, "got status %++v", status)
+	assert.Equal(t, exit.KindInvalidRequest, status.Kind())
+	assert.Equal(t,
+		"testdata/requests-done/exit-78.sh.failure.code_78_invalid-request",
+		job.DoneFile(status),
+	)
+}
+
+func TestCheckLimitsBeforeProving(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+		FailureSuffix:   matchFailureSuffix(config.FailSuffix),
+	}
+
+	testCases := []struct {
+		checkCmd string
+		expCode  int
+	}{
+		{
+			// Fits the normal limits: the normal command is run
+			checkCmd: "exit 0",
+			expCode:  1,
+		},
+		{
+			// Needs the large limits: the large command is directly run
+			checkCmd: "exit 77",
+			expCode:  0,
+		},
+		{
+			// Overflows the large limits: nothing is run
+			checkCmd: "exit 82",
+			expCode:  CodeTraceLimitLarge,
+		},
+		{
+			// The check itself failed: the normal command is run
+			checkCmd: "exit 2",
+			expCode:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.checkCmd, func(t *testing.T) {
+
+			job := Job{
+				Def:          &testDefinition,
+				OriginalFile: "exit-0.sh",
+				LockedFile:   "exit-0.sh",
+			}
+
+			e := NewExecutor(&config.Config{
+				Controller: config.Controller{
+					WorkerCmdTmpl: template.Must(
+						template.New("test-cmd").
+							Parse("exit 1"),
+					),
+					WorkerCmdLargeTmpl: template.Must(
+						template.New("test-cmd-large").
+							Parse("/bin/sh {{.InFile}}"),
+					),
+					CheckLimitsCmdTmpl: template.Must(
+						template.New("test-check-limits").
+							Parse(tc.checkCmd),
+					),
+					RetryLocallyWithLargeCodes: []int{CodeTraceLimit},
+				},
+			})
+
+			status := e.Run(&job)
+			assert.Equalf(t, tc.expCode, status.ExitCode, "got status %++v", status)
+		})
+	}
+}
+
+func TestCheckLimitsOutputFile(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+		FailureSuffix:   matchFailureSuffix(config.FailSuffix),
+	}
+
+	job := Job{
+		Def:          &testDefinition,
+		OriginalFile: "exit-0.sh",
+		LockedFile:   "exit-0.sh",
+	}
+
+	cfg := &config.Config{
+		Controller: config.Controller{
+			WorkerCmdTmpl: template.Must(
+				template.New("test-cmd").
+					Parse("exit 1"),
+			),
+			WorkerCmdLargeTmpl: template.Must(
+				template.New("test-cmd-large").
+					Parse("/bin/sh {{.InFile}}"),
+			),
+			RetryLocallyWithLargeCodes: []int{CodeTraceLimit},
+		},
+	}
+
+	// The check requires the large limits only if it is given its own output
+	// file, the job then succeeds with the large command.
+	checkFile := job.TmpCheckLimitsFile(cfg)
+	assert.NotEqual(t, job.TmpResponseFile(cfg), checkFile)
+	cfg.Controller.CheckLimitsCmdTmpl = template.Must(
+		template.New("test-check-limits").
+			Parse(`test "{{.OutFile}}" = "` + checkFile + `" && exit 77`),
+	)
+
+	status := NewExecutor(cfg).Run(&job)
+	assert.Equalf(t, 0, status.ExitCode, "got status %++v", status)
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+		FailureSuffix:   matchFailureSuffix(config.FailSuffix),
+	}
+
+	testCases := []struct {
+		name          string
+		checkpointDir bool
+		expCode       int
+	}{
+		{
+			// The interrupted command is run again and succeeds
+			name:          "enabled",
+			checkpointDir: true,
+			expCode:       0,
+		},
+		{
+			// Without checkpoints, the job falls back to the large command
+			name:          "disabled",
+			checkpointDir: false,
+			expCode:       0 + 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			job := Job{
+				Def:          &testDefinition,
+				OriginalFile: "exit-0.sh",
+				LockedFile:   "exit-0.sh",
+			}
+
+			// The command is interrupted the first time it is run and leaves
+			// a marker standing for the checkpoint.
+			marker := filepath.Join(t.TempDir(), "checkpoint")
+			cfg := &config.Config{
+				Controller: config.Controller{
+					WorkerCmdTmpl: template.Must(
+						template.New("test-cmd").
+							Parse("test -f " + marker + " || { touch " + marker + "; exit 137; }"),
+					),
+					WorkerCmdLargeTmpl: template.Must(
+						template.New("test-cmd-large").
+							Parse(`/bin/sh -c "/bin/sh {{.InFile}}"; exit $(($? + 10))`),
+					),
+					RetryLocallyWithLargeCodes:      []int{137},
+					RetryLocallyFromCheckpointCodes: []int{137},
+				},
+			}
+
+			if tc.checkpointDir {
+				cfg.Execution.CheckpointDir = t.TempDir()
+			}
+
+			status := NewExecutor(cfg).Run(&job)
+			assert.Equalf(t, tc.expCode, status.ExitCode, "got status %++v", status)
+		})
+	}
+}
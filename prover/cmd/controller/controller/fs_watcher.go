@@ -2,6 +2,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -11,13 +12,16 @@ import (
 
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/exp/slices"
 )
 
 // FsWatcher is a struct who will watch the filesystem and return files as if
 // it were a message queue.
 type FsWatcher struct {
+	// Configuration of the controller, used to derive the response files
+	Config *config.Config
 	// Unique ID of the container. Used to identify the owner of a locked file
 	LocalID string
 	// List of jobs that we are currently matching
@@ -30,41 +34,58 @@ type FsWatcher struct {
 
 func NewFsWatcher(conf *config.Config) *FsWatcher {
 	fs := &FsWatcher{
+		Config:     conf,
 		LocalID:    conf.Controller.LocalID,
 		InProgress: config.InProgressSufix,
+		JobToWatch: enabledJobDefinitions(conf),
 		Logger:     conf.Logger().WithField("component", "filesystem-watcher"),
 	}
 
+	return fs
+}
+
+// Returns the definitions of the jobs that are enabled in the config
+func enabledJobDefinitions(conf *config.Config) []JobDefinition {
+
+	jdefs := []JobDefinition{}
+
 	if conf.Controller.EnableExecution {
-		fs.JobToWatch = append(fs.JobToWatch, ExecutionDefinition(conf))
+		jdefs = append(jdefs, ExecutionDefinition(conf))
 	}
 
 	if conf.Controller.EnableBlobDecompression {
-		fs.JobToWatch = append(fs.JobToWatch, CompressionDefinition(conf))
+		jdefs = append(jdefs, CompressionDefinition(conf))
 	}
 
 	if conf.Controller.EnableAggregation {
-		fs.JobToWatch = append(fs.JobToWatch, AggregatedDefinition(conf))
+		jdefs = append(jdefs, AggregatedDefinition(conf))
 	}
 
-	return fs
+	return jdefs
 }
 
-// Returns the list of jobs to perform by priorities. If no
+// Returns the best job that we could lock among all the jobs found in the
+// watched directories. Returns nil if no job could be found or locked.
 func (fs *FsWatcher) GetBest() (job *Job) {
+	return GetBest(fs, nil, fs.Logger)
+}
 
-	// Fetches the full job list from all three directories. The fetching
-	// operation will not ignore files if they are not in the expected
-	// directory. For instance, if an aggregation file is in the directory
-	// supposed to contain only aggregation jobs.
-	jobs := []*Job{}
+// Fetch lists all the jobs present in the watched directories. The fetching
+// operation will not ignore files if they are not in the expected directory.
+// For instance, if an aggregation file is in the directory supposed to
+// contain only aggregation jobs. Fetch implements [JobSource].
+func (fs *FsWatcher) Fetch() ([]*Job, error) {
 
 	// If there are no jobs definition to watch
 	if len(fs.JobToWatch) == 0 {
-		fs.Logger.Errorf("No job definition to watch")
-		return nil
+		return nil, errors.New("no job definition to watch")
 	}
 
+	var (
+		jobs = []*Job{}
+		errs []error
+	)
+
 	for i := range fs.JobToWatch {
 		// Don't try to pass &jdef, where jdef is a loop variable as
 		// `for i, jdef := range f.JobToWatch {...}`
@@ -73,46 +94,154 @@ func (fs *FsWatcher) GetBest() (job *Job) {
 		// last job definition.
 		jdef := &fs.JobToWatch[i]
 		if err := fs.appendJobFromDef(jdef, &jobs); err != nil {
-			fs.Logger.Errorf(
-				"Got an error trying to fetch job `%v` from dir %v: %v",
+			errs = append(errs, fmt.Errorf(
+				"could not fetch job `%v` from dir %v: %w",
 				jdef.Name, jdef.dirFrom(), err,
-			)
+			))
 		}
 	}
 
-	if len(jobs) == 0 {
-		fs.Logger.Debugf("The queue is empty")
-		return nil
+	return jobs, errors.Join(errs...)
+}
+
+// Lock attempts to lock the job by renaming its file. Lock implements
+// [JobSource].
+func (fs *FsWatcher) Lock(job *Job) bool {
+	return fs.tryLockFile(job)
+}
+
+// Ack moves the temporary response file to its final location and the
+// in-progress request file to the done directory with the success suffix. Ack
+// implements [JobSource].
+func (fs *FsWatcher) Ack(job *Job, status Status) error {
+
+	// NB: we already check that the response filename can be generated prior
+	// to running the command. So this actually will not panic.
+	respFile, err := job.ResponseFile()
+	tmpRespFile := job.TmpResponseFile(fs.Config)
+	if err != nil {
+		formatStr := "Could not generate the response file: %v (original request file: %v)"
+		utils.Panic(formatStr, err, job.OriginalFile)
+	}
+
+	fs.Logger.Infof(
+		"Moving the response file from the tmp response file `%v`, to the final response file: `%v`",
+		tmpRespFile, respFile,
+	)
+
+	var errs []error
+
+	if err := os.Rename(tmpRespFile, respFile); err != nil {
+		// @Alex: it is unclear how the rename operation could fail here. If
+		// this happens, we prefer removing the tmp file. Note that the
+		// operation is an `mv -f`
+		os.Remove(tmpRespFile)
+		errs = append(errs, fmt.Errorf(
+			"error renaming %v to %v: %w, removed the tmp file",
+			tmpRespFile, respFile, err,
+		))
+	}
+
+	// Move the inprogress to the done directory
+	fs.Logger.Infof(
+		"Moving %v to %v with the success prefix",
+		job.OriginalFile, job.Def.dirDone(),
+	)
+
+	jobDone := job.DoneFile(status)
+	if err := os.Rename(job.InProgressPath(), jobDone); err != nil {
+		// When that happens, the only thing left to do is to report the
+		// error and let the inprogress file where it is. It will likely
+		// require a human intervention.
+		errs = append(errs, fmt.Errorf(
+			"error renaming %v to %v: %w",
+			job.InProgressPath(), jobDone, err,
+		))
 	}
 
-	// Sort the jobs by scores in ascending order. Lower scores mean more
-	// priority.
-	slices.SortStableFunc(jobs, func(a, b *Job) int {
-		return a.Score() - b.Score()
-	})
+	return errors.Join(errs...)
+}
 
-	best, success := fs.lockBest(jobs)
-	if !success {
-		fs.Logger.Infof(
-			"Found %v jobs in the queue. They were all locked before we could pick one",
-			len(jobs),
+// Nack moves the in-progress request file to the done directory with a
+// failure suffix carrying the exit code. Nack implements [JobSource].
+func (fs *FsWatcher) Nack(job *Job, status Status) error {
+
+	fs.Logger.Infof(
+		"Moving %v with in %v with a failure suffix for code %v",
+		job.OriginalFile, job.Def.dirDone(), status.ExitCode,
+	)
+
+	fs.moveFailureReport(job)
+
+	jobFailed := job.DoneFile(status)
+	if err := os.Rename(job.InProgressPath(), jobFailed); err != nil {
+		// When that happens, the only thing left to do is to report the
+		// error and let the inprogress file where it is. It will likely
+		// require a human intervention.
+		return fmt.Errorf(
+			"error renaming %v to %v: %w",
+			job.InProgressPath(), jobFailed, err,
 		)
-		return nil
 	}
 
-	return jobs[best]
+	return nil
 }
 
-// Returns the best file that we could lock and its position in the slice. If
-// everything failed returns 0, false.
-func (f *FsWatcher) lockBest(jobs []*Job) (pos int, success bool) {
-	for pos := range jobs {
-		if f.tryLockFile(jobs[pos]) {
-			return pos, true
-		}
+// Moves the failure report written by the prover, if any, next to where the
+// response file of the job would have been written. Failing to do so is only
+// logged as the report is informative.
+func (fs *FsWatcher) moveFailureReport(job *Job) {
+
+	tmpFailureFile := exit.FailureFile(job.TmpResponseFile(fs.Config))
+	if _, err := os.Stat(tmpFailureFile); err != nil {
+		return
+	}
+
+	respFile, err := job.ResponseFile()
+	if err != nil {
+		fs.Logger.Errorf("could not generate the response file of %v: %v", job.OriginalFile, err)
+		os.Remove(tmpFailureFile)
+		return
+	}
+
+	if err := os.Rename(tmpFailureFile, exit.FailureFile(respFile)); err != nil {
+		fs.Logger.Errorf("could not move the failure report %v: %v", tmpFailureFile, err)
+		os.Remove(tmpFailureFile)
+	}
+}
+
+// DeferToLarge moves the in-progress file back in the requests directory with
+// the large suffix so that it is picked up by a large prover. DeferToLarge
+// implements [JobSource].
+func (fs *FsWatcher) DeferToLarge(job *Job, status Status) error {
+
+	fs.Logger.Infof("Renaming %v for the large prover", job.OriginalFile)
+
+	toLargePath, err := job.DeferToLargeFile(status)
+	if err != nil {
+		// There are two possibilities of errors. (1), the status was success
+		// but the controller never defers successful jobs. The other case is
+		// that the suffix was not provided. But, during the config
+		// validation, we check already that the suffix must be provided if
+		// the size of the list of deferToOtherLargeCodes is non-zero. Thus,
+		// this section is unreachable in practice.
+		return fmt.Errorf(
+			"error deriving the to-large-name of %v: %w",
+			job.InProgressPath(), err,
+		)
 	}
 
-	return 0, false
+	if err := os.Rename(job.InProgressPath(), toLargePath); err != nil {
+		// When that happens, the only thing left to do is to report the
+		// error and let the inprogress file where it is. It will likely
+		// require a human intervention.
+		return fmt.Errorf(
+			"error renaming %v to %v: %w",
+			job.InProgressPath(), toLargePath, err,
+		)
+	}
+
+	return nil
 }
 
 // Try appending a list of jobs that are parsed from a given directory. An error
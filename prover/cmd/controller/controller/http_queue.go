@@ -0,0 +1,336 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
+)
+
+// HTTPQueue is a [JobSource] delegating the discovery and the locking of the
+// jobs to a queue service reachable over HTTP. The protocol is the following:
+//
+//   - GET  <url>/jobs returns the pending jobs as a JSON list of
+//     [QueuedJob].
+//   - POST <url>/jobs/<id>/lock with a [QueueLockRequest] locks the job for
+//     the caller. It answers 200 with the content of the request file in the
+//     body, or 409 if the job is already locked by another worker.
+//   - POST <url>/jobs/<id>/ack, <url>/jobs/<id>/nack and
+//     <url>/jobs/<id>/defer-to-large with a [QueueReport] close the job.
+//
+// The file naming conventions of the filesystem backend are preserved: the
+// queue is expected to serve the same request filenames and the reports
+// carry the names that the filesystem backend would have given to the done,
+// deferred and response files. The request and response files are staged
+// locally in the requests directories so that the executor can run the
+// worker command on them.
+type HTTPQueue struct {
+	// Configuration of the controller, used to derive the response files
+	Config *config.Config
+	// Unique ID of the container. Used to identify the owner of a lock
+	LocalID string
+	// Base URL of the queue service
+	URL string
+	// List of jobs that we are currently matching
+	JobToWatch []JobDefinition
+	// Suffix to append to the name of the locally staged request file
+	InProgress string
+	// Client used to talk to the queue service
+	Client *http.Client
+	// Logger specific to the queue
+	Logger *logrus.Entry
+}
+
+// QueuedJob is an entry of the job list returned by the queue service
+type QueuedJob struct {
+	// ID is the identifier of the job in the queue
+	ID string `json:"id"`
+	// Name is the name of the job definition the job belongs to. For
+	// instance, "execution".
+	Name string `json:"name"`
+	// File is the name of the request file. It must match the input regexp
+	// of the job definition.
+	File string `json:"file"`
+}
+
+// QueueLockRequest is the body of a lock request sent to the queue service
+type QueueLockRequest struct {
+	Owner string `json:"owner"`
+}
+
+// QueueReport is the body sent to the queue service to close a job
+type QueueReport struct {
+	Owner    string `json:"owner"`
+	ExitCode int    `json:"exitCode"`
+	What     string `json:"what,omitempty"`
+	// File is the new name of the request file. The done file for "ack" and
+	// "nack" and the large-prover request file for "defer-to-large".
+	File string `json:"file"`
+	// ResponseFile and Response are only set for "ack"
+	ResponseFile string `json:"responseFile,omitempty"`
+	Response     []byte `json:"response,omitempty"`
+	// Failure is the failure report of the prover, if any
+	Failure *exit.Failure `json:"failure,omitempty"`
+}
+
+// NewHTTPQueue returns an [HTTPQueue] pointing to the queue service
+// configured in the controller's config.
+func NewHTTPQueue(conf *config.Config) *HTTPQueue {
+
+	timeout := time.Duration(conf.Controller.JobSource.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &HTTPQueue{
+		Config:     conf,
+		LocalID:    conf.Controller.LocalID,
+		URL:        strings.TrimSuffix(conf.Controller.JobSource.URL, "/"),
+		JobToWatch: enabledJobDefinitions(conf),
+		InProgress: config.InProgressSufix,
+		Client:     &http.Client{Timeout: timeout},
+		Logger:     conf.Logger().WithField("component", "http-queue"),
+	}
+}
+
+// Fetch lists the pending jobs of the queue and parses the ones matching one
+// of the watched job definitions. Fetch implements [JobSource].
+func (q *HTTPQueue) Fetch() ([]*Job, error) {
+
+	if len(q.JobToWatch) == 0 {
+		return nil, errors.New("no job definition to watch")
+	}
+
+	resp, err := q.Client.Get(q.URL + "/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("could not list the jobs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not list the jobs: got status %v", resp.Status)
+	}
+
+	var queued []QueuedJob
+	if err := json.NewDecoder(resp.Body).Decode(&queued); err != nil {
+		return nil, fmt.Errorf("could not decode the job list: %w", err)
+	}
+
+	var (
+		jobs       = []*Job{}
+		numEntries = map[string]int{}
+		numMatched = map[string]int{}
+	)
+
+	for _, qj := range queued {
+
+		jdef := q.jobDefinition(qj.Name)
+		if jdef == nil {
+			q.Logger.Tracef("Ignoring job `%v` of unwatched type `%v`", qj.ID, qj.Name)
+			continue
+		}
+
+		numEntries[jdef.Name]++
+
+		// The job is parsed exactly as if we found its file in the requests
+		// directory. That way, the large-prover filtering and the priority
+		// scores are unchanged.
+		job, err := NewJob(jdef, qj.File)
+		if err != nil {
+			q.Logger.Debugf("Found invalid job `%v` : %v", qj.ID, err)
+			continue
+		}
+
+		job.QueueID = qj.ID
+		jobs = append(jobs, job)
+		numMatched[jdef.Name]++
+	}
+
+	for i := range q.JobToWatch {
+		name := q.JobToWatch[i].Name
+		metrics.CollectFS(name, numEntries[name], numMatched[name])
+	}
+
+	return jobs, nil
+}
+
+// Lock requests the lock of the job to the queue service and stages the
+// content of the request file locally. Lock implements [JobSource].
+func (q *HTTPQueue) Lock(job *Job) bool {
+
+	body, err := json.Marshal(QueueLockRequest{Owner: q.LocalID})
+	if err != nil {
+		q.Logger.Errorf("could not marshal the lock request: %v", err)
+		return false
+	}
+
+	resp, err := q.Client.Post(q.jobURL(job, "lock"), "application/json", bytes.NewReader(body))
+	if err != nil {
+		q.Logger.Errorf("could not lock job `%v`: %v", job.QueueID, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		q.Logger.Tracef("could not lock job `%v`: got status %v", job.QueueID, resp.Status)
+		return false
+	}
+
+	job.LockedFile = strings.Join(
+		[]string{
+			job.OriginalFile,
+			q.InProgress,
+			q.LocalID,
+		}, ".")
+
+	if err := q.stage(job, resp.Body); err != nil {
+		// The job is locked on the queue side but we cannot process it. We
+		// return it to the queue so that it is not lost.
+		q.Logger.Errorf("could not stage the request file of job `%v`: %v", job.QueueID, err)
+		status := Status{ExitCode: CodeCantRunCommand, What: "could not stage the request file", Err: err}
+		if err := q.report(job, "nack", status, job.DoneFile(status), nil); err != nil {
+			q.Logger.Errorf("could not release job `%v`: %v", job.QueueID, err)
+		}
+		job.LockedFile = ""
+		return false
+	}
+
+	return true
+}
+
+// Ack uploads the response of the job to the queue service and cleans the
+// locally staged files. Ack implements [JobSource].
+func (q *HTTPQueue) Ack(job *Job, status Status) error {
+
+	// NB: we already check that the response filename can be generated prior
+	// to running the command.
+	respFile, err := job.ResponseFile()
+	if err != nil {
+		return fmt.Errorf("could not generate the response file of %v: %w", job.OriginalFile, err)
+	}
+
+	tmpRespFile := job.TmpResponseFile(q.Config)
+	response, err := os.ReadFile(tmpRespFile)
+	if err != nil {
+		return fmt.Errorf("could not read the tmp response file %v: %w", tmpRespFile, err)
+	}
+
+	q.Logger.Infof("Sending the response `%v` of job `%v` to the queue", respFile, job.QueueID)
+
+	report := &QueueReport{
+		ResponseFile: filepath.Base(respFile),
+		Response:     response,
+	}
+
+	return q.report(job, "ack", status, job.DoneFile(status), report)
+}
+
+// Nack reports the failure of the job to the queue service. Nack implements
+// [JobSource].
+func (q *HTTPQueue) Nack(job *Job, status Status) error {
+	q.Logger.Infof("Reporting the failure of job `%v` with code %v", job.QueueID, status.ExitCode)
+	return q.report(job, "nack", status, job.DoneFile(status), nil)
+}
+
+// DeferToLarge asks the queue service to hand the job over to the large
+// provers. DeferToLarge implements [JobSource].
+func (q *HTTPQueue) DeferToLarge(job *Job, status Status) error {
+
+	q.Logger.Infof("Deferring job `%v` to the large prover", job.QueueID)
+
+	toLargePath, err := job.DeferToLargeFile(status)
+	if err != nil {
+		return fmt.Errorf("error deriving the to-large-name of %v: %w", job.OriginalFile, err)
+	}
+
+	return q.report(job, "defer-to-large", status, toLargePath, nil)
+}
+
+// Sends a report closing the job to the queue service and removes the locally
+// staged files. The report is optional and is completed with the status.
+func (q *HTTPQueue) report(job *Job, action string, status Status, newFile string, report *QueueReport) error {
+
+	if report == nil {
+		report = &QueueReport{}
+	}
+
+	report.Owner = q.LocalID
+	report.ExitCode = status.ExitCode
+	report.What = status.What
+	report.File = filepath.Base(newFile)
+	report.Failure = status.Failure
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("could not marshal the report: %w", err)
+	}
+
+	// The local files are not needed anymore, whatever the outcome of the
+	// request is.
+	defer func() {
+		os.Remove(job.InProgressPath())
+		os.Remove(job.TmpResponseFile(q.Config))
+		os.Remove(exit.FailureFile(job.TmpResponseFile(q.Config)))
+	}()
+
+	resp, err := q.Client.Post(q.jobURL(job, action), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not %v job `%v`: %w", action, job.QueueID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("could not %v job `%v`: got status %v: %s", action, job.QueueID, resp.Status, msg)
+	}
+
+	return nil
+}
+
+// Writes the content of the request file at the in-progress path of the job
+func (q *HTTPQueue) stage(job *Job, r io.Reader) error {
+
+	if err := os.MkdirAll(job.Def.dirFrom(), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(job.InProgressPath())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Returns the job definition with the given name or nil if we are not watching
+// it.
+func (q *HTTPQueue) jobDefinition(name string) *JobDefinition {
+	for i := range q.JobToWatch {
+		if q.JobToWatch[i].Name == name {
+			return &q.JobToWatch[i]
+		}
+	}
+	return nil
+}
+
+// Returns the URL of an action on a job
+func (q *HTTPQueue) jobURL(job *Job, action string) string {
+	return fmt.Sprintf("%v/jobs/%v/%v", q.URL, url.PathEscape(job.QueueID), action)
+}
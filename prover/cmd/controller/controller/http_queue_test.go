@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testQueue is an in-process stand-in for the queue service
+type testQueue struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   map[string]*testQueueEntry
+}
+
+type testQueueEntry struct {
+	QueuedJob
+	Content string
+	Owner   string
+	// Action is the last closing action received for the job, "" if the job
+	// is still pending.
+	Action string
+	Report QueueReport
+}
+
+func newTestQueue(t *testing.T) (*testQueue, *httptest.Server) {
+	q := &testQueue{jobs: map[string]*testQueueEntry{}}
+	srv := httptest.NewServer(q)
+	t.Cleanup(srv.Close)
+	return q, srv
+}
+
+func (q *testQueue) push(name, file, content string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	id := fmt.Sprintf("job-%v", q.nextID)
+	q.jobs[id] = &testQueueEntry{
+		QueuedJob: QueuedJob{ID: id, Name: name, File: file},
+		Content:   content,
+	}
+}
+
+// Returns the entry whose original file is `file`
+func (q *testQueue) find(file string) *testQueueEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, e := range q.jobs {
+		if e.File == file {
+			return e
+		}
+	}
+	return nil
+}
+
+func (q *testQueue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if r.Method == http.MethodGet && r.URL.Path == "/jobs" {
+		pending := []QueuedJob{}
+		for _, e := range q.jobs {
+			if len(e.Owner) == 0 {
+				pending = append(pending, e.QueuedJob)
+			}
+		}
+		json.NewEncoder(w).Encode(pending)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+	if r.Method != http.MethodPost || len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	e, ok := q.jobs[parts[0]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch action := parts[1]; action {
+	case "lock":
+		var req QueueLockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(e.Owner) > 0 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		e.Owner = req.Owner
+		w.Write([]byte(e.Content))
+	case "ack", "nack", "defer-to-large":
+		var rep QueueReport
+		if err := json.NewDecoder(r.Body).Decode(&rep); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if rep.Owner != e.Owner {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		e.Action, e.Report = action, rep
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestHTTPQueueGetBest(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	q, srv := newTestQueue(t)
+	confM.Controller.JobSource = config.JobSource{Backend: config.JobSourceHTTP, URL: srv.URL}
+
+	// Pushed in reverse order of priority
+	fAgg := createTestInputFile(t.TempDir(), 0, 2, aggregationJob, 0)
+	fComp := createTestInputFile(t.TempDir(), 0, 2, compressionJob, 0)
+	fExec := createTestInputFile(t.TempDir(), 0, 1, execJob, 0)
+	fLarge := createTestInputFile(t.TempDir(), 1, 2, execJob, 0, forLarge)
+
+	q.push(jobNameAggregation, fAgg, "agg")
+	q.push(jobNameBlobDecompression, fComp, "comp")
+	q.push(jobNameExecution, fExec, "exec")
+	q.push(jobNameExecution, fLarge, "large") // only for the large prover
+	q.push("unknown", "some-file.json", "")
+
+	src := NewJobSource(confM)
+	logger := confM.Logger().WithField("component", "test")
+
+	for _, exp := range []string{fExec, fComp, fAgg} {
+		job := GetBest(src, nil, logger)
+		require.NotNil(t, job, "did not find the job")
+		assert.Equal(t, exp, job.OriginalFile)
+		assert.FileExists(t, job.InProgressPath())
+		assert.Equal(t, confM.Controller.LocalID, q.find(exp).Owner)
+	}
+
+	assert.Nil(t, GetBest(src, nil, logger), "the queue should be empty now")
+}
+
+func TestHTTPQueueRunController(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	q, srv := newTestQueue(t)
+	confM.Controller.JobSource = config.JobSource{Backend: config.JobSourceHTTP, URL: srv.URL}
+
+	var (
+		tmp     = t.TempDir()
+		fOk     = createTestInputFile(tmp, 0, 1, execJob, 0)
+		fLarge  = createTestInputFile(tmp, 1, 2, execJob, 137)
+		fFail   = createTestInputFile(tmp, 2, 3, execJob, 2)
+		fCompOk = createTestInputFile(tmp, 0, 2, compressionJob, 0)
+	)
+
+	// The content of the request is the script returning the exit code, as
+	// for the filesystem tests.
+	for _, f := range []struct {
+		name, file string
+		code       int
+	}{
+		{jobNameExecution, fOk, 0},
+		{jobNameExecution, fLarge, 137},
+		{jobNameExecution, fFail, 2},
+		{jobNameBlobDecompression, fCompOk, 0},
+	} {
+		q.push(f.name, f.file, fmt.Sprintf("#!/bin/sh\nexit %v", f.code))
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	go runController(ctx, confM)
+	<-time.After(2 * time.Second)
+	stop()
+
+	expected := []struct {
+		File, Action, NewFile, RespFile string
+	}{
+		{fOk, "ack", fOk + ".success", "0-1-getZkProof.json"},
+		{fLarge, "defer-to-large", fLarge + ".large.failure.code_137", ""},
+		{fFail, "nack", fFail + ".failure.code_2", ""},
+		{fCompOk, "ack", fCompOk + ".success", "0-2-getZkBlobCompressionProof.json"},
+	}
+
+	for _, exp := range expected {
+		e := q.find(exp.File)
+		assert.Equalf(t, exp.Action, e.Action, "file %v", exp.File)
+		assert.Equalf(t, exp.NewFile, e.Report.File, "file %v", exp.File)
+		assert.Equalf(t, exp.RespFile, e.Report.ResponseFile, "file %v", exp.File)
+	}
+
+	// The staged files must have been cleaned up
+	for _, dir := range []string{confM.Execution.DirFrom(), confM.BlobDecompression.DirFrom()} {
+		ls, err := lsname(dir)
+		require.NoError(t, err)
+		assert.Emptyf(t, ls, "dir %v", dir)
+	}
+}
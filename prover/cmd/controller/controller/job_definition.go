@@ -85,7 +85,7 @@ func ExecutionDefinition(conf *config.Config) JobDefinition {
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
 			fmt.Sprintf(
-				`^[0-9]+-[0-9]+(-etv[0-9\.]+)?(-stv[0-9\.]+)?-getZkProof\.json%v(\.failure\.%v_[0-9]+)*$`,
+				`^[0-9]+-[0-9]+(-etv[0-9\.]+)?(-stv[0-9\.]+)?-getZkProof\.json%v(\.failure\.%v_[0-9]+(_[a-z\-]+)?)*$`,
 				inpFileExt,
 				config.FailSuffix,
 			),
@@ -138,7 +138,7 @@ func CompressionDefinition(conf *config.Config) JobDefinition {
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
 			fmt.Sprintf(
-				`^[0-9]+-[0-9]+(-bcv[0-9\.]+)?(-ccv[0-9\.]+)?-((0x)?[0-9a-zA-Z]*-)?getZkBlobCompressionProof\.json(\.failure\.%v_[0-9]+)*$`,
+				`^[0-9]+-[0-9]+(-bcv[0-9\.]+)?(-ccv[0-9\.]+)?-((0x)?[0-9a-zA-Z]*-)?getZkBlobCompressionProof\.json(\.failure\.%v_[0-9]+(_[a-z\-]+)?)*$`,
 				config.FailSuffix,
 			),
 			regexp2.None,
@@ -190,7 +190,7 @@ func AggregatedDefinition(conf *config.Config) JobDefinition {
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
 			fmt.Sprintf(
-				`^[0-9]+-[0-9]+(-[a-fA-F0-9]+)?-getZkAggregatedProof\.json(\.failure\.%v_[0-9]+)*$`,
+				`^[0-9]+-[0-9]+(-[a-fA-F0-9]+)?-getZkAggregatedProof\.json(\.failure\.%v_[0-9]+(_[a-z\-]+)?)*$`,
 				config.FailSuffix,
 			),
 			regexp2.None,
@@ -237,11 +237,11 @@ func matchVersionWithPrefix(pre string) *regexp2.Regexp {
 }
 
 // Match the failure code suffix. This string will essentially match all the
-// substrints of the form `.failure.code_<X>` so that they can be replaced with
-// the empty string.
+// substrints of the form `.failure.code_<X>` or `.failure.code_<X>_<kind>` so
+// that they can be replaced with the empty string.
 func matchFailureSuffix(pre string) *regexp2.Regexp {
 	return regexp2.MustCompile(
-		fmt.Sprintf(`\.failure\.%v_[0-9]+`, pre),
+		fmt.Sprintf(`\.failure\.%v_[0-9]+(_[a-z\-]+)?`, pre),
 		regexp2.None,
 	)
 }
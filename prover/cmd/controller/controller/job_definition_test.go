@@ -83,6 +83,12 @@ func TestExecutionInFileRegexp(t *testing.T) {
 		failWith2FailsL = "requests-done/102-103-etv0.2.3-stv1.2.3-getZkProof.json.large.failure.code_2"
 	)
 
+	// The files carrying the kind of a previous failure
+	var (
+		correctWithKindM = "102-103-etv0.2.3-stv1.2.3-getZkProof.json.failure.code_77.failure.code_77_trace-limit"
+		correctWithKindL = "102-103-etv0.2.3-stv1.2.3-getZkProof.json.large.failure.code_77.failure.code_137_oom"
+	)
+
 	testcase := []inpFileNamesCases{
 		{
 			Ext: "", Fail: "code", ShouldMatch: true,
@@ -121,6 +127,33 @@ func TestExecutionInFileRegexp(t *testing.T) {
 			Fnames:    []string{missingEtv, missingStv, notAPoint, badName},
 			Explainer: "L does not pick obviously invalid files",
 		},
+		{
+			Ext: "", Fail: "code", ShouldMatch: true,
+			Fnames:         []string{correctWithKindM},
+			Explainer:      "failure kinds, case M",
+			ExpectedOutput: []string{respWith2FailsM},
+			ExpToLarge:     []string{toLargeWith2FailsM},
+			ExpSuccess:     []string{successWith2FailsM},
+			ExpFailW2:      []string{failWith2FailsM},
+		},
+		{
+			Ext: "large", Fail: "code", ShouldMatch: true,
+			Fnames:         []string{correctWithKindL},
+			Explainer:      "failure kinds, case L",
+			ExpectedOutput: []string{respWith2FailsL},
+			ExpSuccess:     []string{successWith2FailsL},
+			ExpFailW2:      []string{failWith2FailsL},
+		},
+		{
+			Ext: "", Fail: "code", ShouldMatch: false,
+			Fnames:    []string{correctWithKindL},
+			Explainer: "failure kinds, M does not pick the files reserved for L",
+		},
+		{
+			Ext: "large", Fail: "code", ShouldMatch: false,
+			Fnames:    []string{correctWithKindM},
+			Explainer: "failure kinds, L does not pick the files reserved for M",
+		},
 	}
 
 	for _, c := range testcase {
@@ -312,6 +345,8 @@ func TestFailSuffixMatching(t *testing.T) {
 	}{
 		{s: "abds.failure.code_1.failure.code_2", ncodes: 2},
 		{s: "abds.failure.code_1", ncodes: 1},
+		{s: "abds.failure.code_77_trace-limit", ncodes: 1},
+		{s: "abds.failure.code_77_trace-limit.failure.code_2", ncodes: 2},
 		{s: "abds.failure.code1", ncodes: 0},
 		{s: "abds.failure.code__1", ncodes: 0},
 		{s: "abds", ncodes: 0},
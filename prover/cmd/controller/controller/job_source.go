@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+)
+
+// JobSource abstracts the queue from which the controller pulls its jobs. The
+// controller only ever interacts with the queue through this interface, which
+// means that it does not need to know whether the jobs are discovered by
+// listing directories or by querying a remote service.
+type JobSource interface {
+	// Fetch returns the list of the jobs that are currently pending in the
+	// queue. The jobs are returned unlocked and in no particular order.
+	Fetch() ([]*Job, error)
+	// Lock attempts to acquire exclusive ownership over a job returned by
+	// Fetch. It returns false if another worker locked it first. When it
+	// succeeds, the job's input is available at [Job.InProgressPath].
+	Lock(job *Job) bool
+	// Ack reports that the job completed successfully. The response of the
+	// job is expected to be found in [Job.TmpResponseFile].
+	Ack(job *Job, status Status) error
+	// Nack reports that the job failed with the given status and should not
+	// be retried by this worker.
+	Nack(job *Job, status Status) error
+	// DeferToLarge hands the job back to the queue so that it can be picked
+	// up by a large prover.
+	DeferToLarge(job *Job, status Status) error
+}
+
+// NewJobSource returns the job source selected in the configuration. It panics
+// if the configured backend is unknown; this is normally caught earlier by the
+// validation of the config.
+func NewJobSource(cfg *config.Config) JobSource {
+	switch cfg.Controller.JobSource.Backend {
+	case "", config.JobSourceFilesystem:
+		return NewFsWatcher(cfg)
+	case config.JobSourceHTTP:
+		return NewHTTPQueue(cfg)
+	default:
+		utils.Panic("unknown job source backend: %q", cfg.Controller.JobSource.Backend)
+	}
+	return nil // unreachable
+}
+
+// GetBest fetches the pending jobs from the source and returns the one with
+// the best priority that we could lock. It returns nil if the queue is empty
+// or if every job was locked by another worker before we could get to it.
+//
+// If a scheduler is provided, the jobs for which the scheduler has no capacity
+// are skipped without being locked and the returned job has its resources
+// reserved in the scheduler. The caller is responsible for releasing them.
+func GetBest(src JobSource, sched *Scheduler, logger *logrus.Entry) *Job {
+
+	jobs, err := src.Fetch()
+	if err != nil {
+		logger.Errorf("Got an error trying to fetch the jobs: %v", err)
+	}
+
+	if len(jobs) == 0 {
+		logger.Debugf("The queue is empty")
+		return nil
+	}
+
+	// Sort the jobs by scores in ascending order. Lower scores mean more
+	// priority.
+	slices.SortStableFunc(jobs, func(a, b *Job) int {
+		return a.Score() - b.Score()
+	})
+
+	numSkipped := 0
+	for _, job := range jobs {
+
+		if sched != nil && !sched.TryReserve(job) {
+			numSkipped++
+			continue
+		}
+
+		if src.Lock(job) {
+			return job
+		}
+
+		if sched != nil {
+			sched.Release(job)
+		}
+	}
+
+	if numSkipped > 0 {
+		logger.Debugf(
+			"Found %v jobs in the queue. %v were skipped for lack of resources and the rest was locked before we could pick one",
+			len(jobs), numSkipped,
+		)
+		return nil
+	}
+
+	logger.Infof(
+		"Found %v jobs in the queue. They were all locked before we could pick one",
+		len(jobs),
+	)
+	return nil
+}
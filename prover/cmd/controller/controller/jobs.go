@@ -31,6 +31,10 @@ type Job struct {
 
 	// The hex string of the content hash
 	ContentHash string
+
+	// Identifier of the job in the remote queue. Only set when the job was
+	// fetched through a [HTTPQueue].
+	QueueID string
 }
 
 // OutputFileRessouce collects all the data needed to fill the output template
@@ -98,9 +102,18 @@ func (j *Job) ResponseFile() (s string, err error) {
 	return s, nil
 }
 
-// Returns the name of the output file for the job
+// Returns the name of the temporary file in which the worker writes the
+// response. The name embeds the original request file so that concurrent jobs
+// never share the same temporary file.
 func (j *Job) TmpResponseFile(c *config.Config) (s string) {
-	return path.Join(j.Def.dirTo(), "tmp-response-file."+c.Controller.LocalID+".json")
+	return path.Join(j.Def.dirTo(), "tmp-response-file."+c.Controller.LocalID+"."+j.OriginalFile)
+}
+
+// Returns the name of the temporary file passed as the output of the limits
+// check of the job. It is distinct from [Job.TmpResponseFile] so that the check
+// never overwrites the response nor the failure report of the proving.
+func (j *Job) TmpCheckLimitsFile(c *config.Config) (s string) {
+	return path.Join(j.Def.dirTo(), "tmp-check-limits-file."+c.Controller.LocalID+"."+j.OriginalFile)
 }
 
 // Returns the name of the input file modified so that it is retried in
@@ -148,6 +161,8 @@ func (j *Job) DeferToLargeFile(status Status) (s string, err error) {
 		panic(err)
 	}
 
+	// The kind of the failure is not appended: the file is a request and it
+	// keeps the naming that the large provers already parse.
 	return fmt.Sprintf(
 		"%v/%v.%v.failure.%v_%v",
 		j.Def.dirFrom(), origFile,
@@ -156,7 +171,9 @@ func (j *Job) DeferToLargeFile(status Status) (s string, err error) {
 	), nil
 }
 
-// Returns the done file following the jobs status
+// Returns the done file following the jobs status. The name of a failed job
+// carries the exit code and the kind of the failure, e.g.
+// `.failure.code_77_trace-limit`.
 func (j *Job) DoneFile(status Status) string {
 
 	// Remove the suffix .failure.code_[0-9]+ from all the strings
@@ -173,7 +190,7 @@ func (j *Job) DoneFile(status Status) string {
 	if status.ExitCode == CodeSuccess {
 		return fmt.Sprintf("%v/%v.%v", j.Def.dirDone(), origFile, config.SuccessSuffix)
 	} else {
-		return fmt.Sprintf("%v/%v.failure.%v_%v", j.Def.dirDone(), origFile, config.FailSuffix, status.ExitCode)
+		return fmt.Sprintf("%v/%v.failure.%v_%v%v", j.Def.dirDone(), origFile, config.FailSuffix, status.ExitCode, status.Kind().Suffix())
 	}
 }
 
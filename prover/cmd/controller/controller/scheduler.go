@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+)
+
+// Scheduler keeps track of the jobs running concurrently in the controller and
+// of the resources they reserved. It decides whether a job can be started
+// given the number of free slots and the remaining resources. It is safe for
+// concurrent use.
+type Scheduler struct {
+	// Maximal number of jobs running at the same time
+	MaxJobs int
+	// Declared resources of the machine and per-job-type budgets
+	Resources config.Resources
+
+	mu      sync.Mutex
+	running int
+	inUse   config.ResourceBudget
+}
+
+// NewScheduler returns a scheduler following the controller configuration.
+// A non-positive MaxConcurrentJobs is understood as 1.
+func NewScheduler(cfg *config.Config) *Scheduler {
+	maxJobs := cfg.Controller.MaxConcurrentJobs
+	if maxJobs < 1 {
+		maxJobs = 1
+	}
+	return &Scheduler{
+		MaxJobs:   maxJobs,
+		Resources: cfg.Controller.Resources,
+	}
+}
+
+// TryReserve reserves a slot and the budget of the job if they are available.
+// It returns false and reserves nothing otherwise.
+func (s *Scheduler) TryReserve(job *Job) bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running >= s.MaxJobs {
+		return false
+	}
+
+	var (
+		budget = jobBudget(s.Resources, job.Def.Name)
+		total  = s.Resources.Total
+	)
+
+	if total.MemoryGiB > 0 && s.inUse.MemoryGiB+budget.MemoryGiB > total.MemoryGiB {
+		return false
+	}
+
+	if total.CPUs > 0 && s.inUse.CPUs+budget.CPUs > total.CPUs {
+		return false
+	}
+
+	s.running++
+	s.inUse.MemoryGiB += budget.MemoryGiB
+	s.inUse.CPUs += budget.CPUs
+	return true
+}
+
+// Release frees the slot and the budget reserved for the job
+func (s *Scheduler) Release(job *Job) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget := jobBudget(s.Resources, job.Def.Name)
+	s.running--
+	s.inUse.MemoryGiB -= budget.MemoryGiB
+	s.inUse.CPUs -= budget.CPUs
+}
+
+// Full returns true if all the slots are taken. Note that a scheduler that is
+// not full may still be unable to start a job if the resources are lacking.
+func (s *Scheduler) Full() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running >= s.MaxJobs
+}
+
+// Running returns the number of jobs currently running
+func (s *Scheduler) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Returns the budget declared for a type of job
+func jobBudget(res config.Resources, jobName string) config.ResourceBudget {
+	switch jobName {
+	case jobNameExecution:
+		return res.Execution
+	case jobNameBlobDecompression:
+		return res.BlobDecompression
+	case jobNameAggregation:
+		return res.Aggregation
+	default:
+		return config.ResourceBudget{}
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerReserve(t *testing.T) {
+
+	var (
+		execDef = &JobDefinition{Name: jobNameExecution}
+		compDef = &JobDefinition{Name: jobNameBlobDecompression}
+		aggDef  = &JobDefinition{Name: jobNameAggregation}
+		exec    = &Job{Def: execDef}
+		comp    = &Job{Def: compDef}
+		agg     = &Job{Def: aggDef}
+	)
+
+	sched := &Scheduler{
+		MaxJobs: 3,
+		Resources: config.Resources{
+			Total:             config.ResourceBudget{MemoryGiB: 512, CPUs: 64},
+			Execution:         config.ResourceBudget{MemoryGiB: 400, CPUs: 32},
+			BlobDecompression: config.ResourceBudget{MemoryGiB: 64, CPUs: 16},
+			Aggregation:       config.ResourceBudget{MemoryGiB: 150, CPUs: 32},
+		},
+	}
+
+	assert.True(t, sched.TryReserve(exec), "execution should fit on an empty machine")
+	assert.False(t, sched.TryReserve(exec), "a second execution exceeds the memory")
+	assert.False(t, sched.TryReserve(agg), "the aggregation exceeds the memory")
+	assert.True(t, sched.TryReserve(comp), "compression fits next to execution")
+	assert.False(t, sched.TryReserve(comp), "a second compression exceeds the memory")
+	assert.Equal(t, 2, sched.Running())
+
+	sched.Release(exec)
+	assert.True(t, sched.TryReserve(agg), "the aggregation fits once execution is done")
+	assert.True(t, sched.TryReserve(comp), "the second compression now fits")
+	assert.True(t, sched.Full())
+	assert.False(t, sched.TryReserve(comp), "no slots left")
+}
+
+func TestSchedulerDefault(t *testing.T) {
+
+	// The zero config runs one job at a time without any resource limit
+	sched := NewScheduler(&config.Config{})
+	job := &Job{Def: &JobDefinition{Name: jobNameExecution}}
+
+	assert.True(t, sched.TryReserve(job))
+	assert.True(t, sched.Full())
+	assert.False(t, sched.TryReserve(job))
+	sched.Release(job)
+	assert.True(t, sched.TryReserve(job))
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// dictPaths is a flag that can be repeated or given as a comma-separated list
+type dictPaths []string
+
+func (d *dictPaths) String() string {
+	return strings.Join(*d, ",")
+}
+
+func (d *dictPaths) Set(s string) error {
+	for _, path := range strings.Split(s, ",") {
+		if path = strings.TrimSpace(path); len(path) > 0 {
+			*d = append(*d, path)
+		}
+	}
+	return nil
+}
+
+var (
+	inFPathCLI    string
+	dictPathsCLI  dictPaths
+	jsonCLI       bool
+	roundTripCLI  bool
+	chainIDCLI    uint64
+	outFPathCLI   string
+	verboseTxsCLI bool
+)
+
+func init() {
+	flag.StringVar(&inFPathCLI, "in", "", "path to the blob: raw bytes, 0x-prefixed hex or a JSON file with a base64 `compressedData` field")
+	flag.Var(&dictPathsCLI, "dict", "path to a compression dictionary, can be repeated or comma-separated")
+	flag.BoolVar(&jsonCLI, "json", false, "print the decoded blob as JSON")
+	flag.BoolVar(&roundTripCLI, "round-trip", false, "re-encode every block and check it matches the blob byte for byte (version 1 only)")
+	flag.Uint64Var(&chainIDCLI, "chain-id", 0, "chain ID used to re-encode the EIP-155 legacy transactions (default: inferred from the typed transactions)")
+	flag.StringVar(&outFPathCLI, "out", "", "file where the output is written (default: stdout)")
+	flag.BoolVar(&verboseTxsCLI, "txs", false, "list the transactions of every block in the text output")
+}
+
+func main() {
+
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Printf("FATAL\n")
+		fmt.Printf("err = %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	if len(inFPathCLI) == 0 {
+		return fmt.Errorf("the --in flag is required")
+	}
+
+	if len(dictPathsCLI) == 0 {
+		return fmt.Errorf("at least one --dict is required")
+	}
+
+	dictStore := dictionary.NewStore()
+	if err := dictStore.Load(dictPathsCLI...); err != nil {
+		return fmt.Errorf("could not load the dictionaries: %w", err)
+	}
+
+	in, err := os.ReadFile(inFPathCLI)
+	if err != nil {
+		return fmt.Errorf("could not read %v: %w", inFPathCLI, err)
+	}
+
+	b, err := parseBlob(in)
+	if err != nil {
+		return fmt.Errorf("could not parse %v: %w", inFPathCLI, err)
+	}
+
+	inspection, err := blob.Inspect(b, dictStore)
+	if err != nil {
+		return err
+	}
+
+	if roundTripCLI {
+		chainID := inspection.ChainID()
+		if chainIDCLI != 0 {
+			chainID = new(big.Int).SetUint64(chainIDCLI)
+		}
+		for i, batch := range inspection.Batches {
+			for j := range batch {
+				if err := batch[j].CheckRoundTrip(inspection.Version, chainID); err != nil {
+					return fmt.Errorf("round-trip of block #%v of batch #%v: %w", j, i, err)
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if jsonCLI {
+		enc := json.NewEncoder(&out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(inspection); err != nil {
+			return fmt.Errorf("could not marshal the output: %w", err)
+		}
+	} else {
+		printInspection(&out, inspection, len(b))
+	}
+
+	if roundTripCLI && !jsonCLI {
+		fmt.Fprintf(&out, "round-trip: OK, all %v blocks re-encode to the bytes of the blob\n", inspection.NbBlocks())
+	}
+
+	if len(outFPathCLI) == 0 {
+		_, err = os.Stdout.Write(out.Bytes())
+		return err
+	}
+
+	return os.WriteFile(outFPathCLI, out.Bytes(), 0600)
+}
+
+// parseBlob detects the format of the input. A JSON object is expected to be
+// a blob-submission or a prover response, carrying the blob in base64 in its
+// `compressedData` field. Text starting with 0x is read as hex. Anything else
+// is taken as the raw blob. The padding of a 4844 blob needs no special
+// treatment as the decompressor ignores the trailing zeroes.
+func parseBlob(in []byte) ([]byte, error) {
+
+	trimmed := bytes.TrimSpace(in)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var resp struct {
+			CompressedData string `json:"compressedData"`
+		}
+		if err := json.Unmarshal(trimmed, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.CompressedData) == 0 {
+			return nil, fmt.Errorf("no `compressedData` field in the JSON input")
+		}
+		return base64.StdEncoding.DecodeString(resp.CompressedData)
+	case bytes.HasPrefix(trimmed, []byte("0x")):
+		return utils.HexDecodeString(string(trimmed))
+	default:
+		return in, nil
+	}
+}
+
+func printInspection(w io.Writer, in *blob.Inspection, blobSize int) {
+
+	fmt.Fprintf(w, "version: %v\n", in.Version)
+	fmt.Fprintf(w, "dictionary checksum: %v\n", in.DictChecksum)
+	fmt.Fprintf(w, "blob size: %v bytes\n", blobSize)
+	fmt.Fprintf(w, "batches: %v, blocks: %v\n", len(in.Batches), in.NbBlocks())
+
+	for i, batch := range in.Batches {
+
+		nbBytes := 0
+		for j := range batch {
+			nbBytes += batch[j].RawSize
+		}
+		fmt.Fprintf(w, "batch #%v: %v blocks, %v bytes uncompressed\n", i, len(batch), nbBytes)
+
+		for j, block := range batch {
+			fmt.Fprintf(w, "  block #%v: timestamp=%v txs=%v size=%v", j, block.Timestamp, len(block.Txs), block.RawSize)
+			if block.Hash != nil {
+				fmt.Fprintf(w, " hash=%v", block.Hash.Hex())
+			}
+			fmt.Fprintln(w)
+
+			if !verboseTxsCLI {
+				continue
+			}
+
+			for k, tx := range block.Txs {
+				to := "<contract creation>"
+				if tx.To != nil {
+					to = tx.To.Hex()
+				}
+				fmt.Fprintf(w, "    tx #%v: type=%v from=%v to=%v nonce=%v value=%v gas=%v data=%vB", k, tx.Type, tx.From.Hex(), to, tx.Nonce, tx.Value.ToInt(), tx.Gas, tx.DataSize)
+				if tx.AuthListSize > 0 {
+					fmt.Fprintf(w, " authorizations=%v", tx.AuthListSize)
+				}
+				fmt.Fprintln(w)
+			}
+		}
+	}
+}
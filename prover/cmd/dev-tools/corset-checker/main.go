@@ -25,7 +25,10 @@ func main() {
 	}
 
 	suite := []func(*wizard.CompiledIOP){
-		dummy.Compile,
+		dummy.CompileWithReport(dummy.ReportSettings{
+			Dir:     reportDirCLI,
+			MaxRows: maxRowsCLI,
+		}),
 	}
 
 	if cfg.Execution.ProverMode == config.ProverModeBench {
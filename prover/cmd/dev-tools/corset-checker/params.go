@@ -12,12 +12,16 @@ var (
 	configFPathCLI       string
 	traceFPathCLI        string
 	optimisationLevelCLI uint
+	reportDirCLI         string
+	maxRowsCLI           int
 )
 
 func init() {
 	flag.StringVar(&configFPathCLI, "config", "", "path to the config file. Only the trace limits are read")
 	flag.StringVar(&traceFPathCLI, "trace-file", "", "path to the `.lt` trace file")
 	flag.UintVar(&optimisationLevelCLI, "opt", 1, "set go-corset optimisation level to apply")
+	flag.StringVar(&reportDirCLI, "report-dir", "", "directory where the unsatisfied constraints are reported, as a JSON file and one CSV file per constraint")
+	flag.IntVar(&maxRowsCLI, "max-rows", 0, "number of offending rows reported per constraint, -1 for all of them (default 100)")
 	flag.Parse()
 }
 
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/compress/lzss"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// corpusBlock is a block of the corpus, both RLP encoded as fed to the blob
+// maker and encoded as it is compressed.
+type corpusBlock struct {
+	rlp     []byte
+	encoded []byte
+}
+
+// evaluation is the performance of a dictionary over the evaluation set
+type evaluation struct {
+	Path string `json:"path"`
+	// Size of the dictionary, once augmented by the compressor
+	Size     int           `json:"size"`
+	Checksum hexutil.Bytes `json:"checksum"`
+	NbBlocks int           `json:"nbBlocks"`
+	// NbBlobs counts the last blob in proportion of its filling
+	NbBlobs       float64 `json:"nbBlobs"`
+	BlocksPerBlob float64 `json:"blocksPerBlob"`
+	// Ratio is the size of the blocks as encoded for compression over the
+	// size of the blobs
+	Ratio float64 `json:"ratio"`
+	// WorstCaseRatio is the ratio obtained when compressing every block on
+	// its own, as estimated by WorstCompressedBlockSize.
+	WorstCaseRatio              float64 `json:"worstCaseRatio"`
+	MaxWorstCompressedBlockSize int     `json:"maxWorstCompressedBlockSize"`
+}
+
+// evaluate fills blobs with the blocks, in order and in a single batch per
+// blob, using the dictionary.
+func evaluate(dictPath string, blocks []corpusBlock) (evaluation, error) {
+
+	res := evaluation{Path: dictPath, NbBlocks: len(blocks)}
+
+	dict, err := os.ReadFile(dictPath)
+	if err != nil {
+		return res, err
+	}
+	dict = lzss.AugmentDict(dict)
+	res.Size = len(dict)
+	if res.Checksum, err = dictionary.Checksum(dict, 1); err != nil {
+		return res, err
+	}
+
+	bm, err := v1.NewBlobMaker(v1.MaxUsableBytes, dictPath)
+	if err != nil {
+		return res, err
+	}
+
+	var (
+		nbFullBlobs    int
+		compressedSize int
+		encodedSize    int
+		worstCaseSize  int
+	)
+
+	for i := range blocks {
+
+		encodedSize += len(blocks[i].encoded)
+
+		_, n, err := bm.WorstCompressedBlockSize(blocks[i].rlp)
+		if err != nil {
+			return res, fmt.Errorf("block #%v: %w", i, err)
+		}
+		worstCaseSize += n
+		res.MaxWorstCompressedBlockSize = max(res.MaxWorstCompressedBlockSize, n)
+
+		ok, err := bm.Write(blocks[i].rlp, false)
+		if err != nil {
+			return res, fmt.Errorf("block #%v: %w", i, err)
+		}
+		if ok {
+			continue
+		}
+
+		// the blob is full, start a new one
+		nbFullBlobs++
+		compressedSize += bm.Len()
+		bm.Reset()
+		if ok, err = bm.Write(blocks[i].rlp, false); err != nil || !ok {
+			return res, fmt.Errorf("block #%v does not fit in an empty blob: %w", i, err)
+		}
+	}
+
+	compressedSize += bm.Len()
+
+	res.NbBlobs = float64(nbFullBlobs) + float64(bm.Len())/float64(v1.MaxUsableBytes)
+	res.BlocksPerBlob = float64(len(blocks)) / res.NbBlobs
+	res.Ratio = float64(encodedSize) / float64(compressedSize)
+	res.WorstCaseRatio = float64(encodedSize) / float64(worstCaseSize)
+
+	return res, nil
+}
+
+// loadCorpus reads the blocks of the files, walking the directories
+func loadCorpus(paths []string) ([]corpusBlock, error) {
+
+	var blocks []corpusBlock
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rlpBlocks, err := readRlpBlocks(path)
+			if err != nil {
+				return fmt.Errorf("could not read the blocks of %v: %w", path, err)
+			}
+			for i := range rlpBlocks {
+				encoded, err := encodeForCompression(rlpBlocks[i])
+				if err != nil {
+					return fmt.Errorf("block #%v of %v: %w", i, path, err)
+				}
+				blocks = append(blocks, corpusBlock{rlp: rlpBlocks[i], encoded: encoded})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return blocks, nil
+}
+
+func readRlpBlocks(path string) ([][]byte, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var res [][]byte
+
+	switch trimmed := bytes.TrimSpace(b); {
+	case strings.HasSuffix(path, ".json"):
+		var req execution.Request
+		if err := json.Unmarshal(b, &req); err != nil {
+			return nil, err
+		}
+		for i := range req.BlocksData {
+			block, err := utils.HexDecodeString(req.BlocksData[i].Rlp)
+			if err != nil {
+				return nil, fmt.Errorf("block #%v: %w", i, err)
+			}
+			res = append(res, block)
+		}
+	case bytes.HasPrefix(trimmed, []byte("0x")):
+		for i, line := range strings.Split(string(trimmed), "\n") {
+			if line = strings.TrimSpace(line); len(line) == 0 {
+				continue
+			}
+			block, err := utils.HexDecodeString(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: %w", i+1, err)
+			}
+			res = append(res, block)
+		}
+	default:
+		s := rlp.NewStream(bytes.NewReader(b), 0)
+		for {
+			block, err := s.Raw()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("block #%v: %w", len(res), err)
+			}
+			res = append(res, block)
+		}
+	}
+
+	return res, nil
+}
+
+func encodeForCompression(rlpBlock []byte) ([]byte, error) {
+	var block types.Block
+	if err := rlp.DecodeBytes(rlpBlock, &block); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := v1.EncodeBlockForCompression(&block, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	"github.com/sirupsen/logrus"
+)
+
+// listFlag is a flag that can be repeated or given as a comma-separated list
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+var (
+	corpusCLI       listFlag
+	baselinesCLI    listFlag
+	maxSizeCLI      int
+	sizesCLI        listFlag
+	segmentSizesCLI listFlag
+	dmerSizeCLI     int
+	evalEveryCLI    int
+	outDirCLI       string
+	jsonCLI         bool
+)
+
+func init() {
+	flag.Var(&corpusCLI, "corpus", "file or directory of RLP blocks: execution requests (.json), 0x-prefixed hex (one block per line) or concatenated raw RLP blocks. Can be repeated")
+	flag.Var(&baselinesCLI, "baseline", "existing dictionary to evaluate alongside the candidates, e.g. the current compressor_dict.bin. Can be repeated")
+	flag.IntVar(&maxSizeCLI, "max-size", 65536, "size in bytes of the dictionary the decompression circuit is set up with (see the --dict-size flag of the prover setup)")
+	flag.Var(&sizesCLI, "sizes", "sizes of the candidate dictionaries (default: max-size/4, max-size/2 and max-size)")
+	flag.Var(&segmentSizesCLI, "segment-sizes", "sizes of the segments the candidate dictionaries are made of (default: 256,1024)")
+	flag.IntVar(&dmerSizeCLI, "dmer-size", 8, "length of the substrings used to score the segments, in [4, 8]")
+	flag.IntVar(&evalEveryCLI, "eval-every", 5, "every n-th block is kept out of the training set and used for the evaluation. 0 evaluates on the training set")
+	flag.StringVar(&outDirCLI, "out-dir", ".", "directory where the candidate dictionaries are written")
+	flag.BoolVar(&jsonCLI, "json", false, "print the report as JSON")
+}
+
+func main() {
+
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Printf("FATAL\n")
+		fmt.Printf("err = %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	if len(corpusCLI) == 0 {
+		return fmt.Errorf("the --corpus flag is required")
+	}
+	if evalEveryCLI == 1 || evalEveryCLI < 0 {
+		return fmt.Errorf("--eval-every must be 0 or at least 2, got %v", evalEveryCLI)
+	}
+
+	sizes, err := parseInts(sizesCLI, []int{maxSizeCLI / 4, maxSizeCLI / 2, maxSizeCLI})
+	if err != nil {
+		return fmt.Errorf("invalid --sizes: %w", err)
+	}
+	for _, size := range sizes {
+		if size > maxSizeCLI {
+			return fmt.Errorf("candidate size %v exceeds the max size %v", size, maxSizeCLI)
+		}
+	}
+
+	segmentSizes, err := parseInts(segmentSizesCLI, []int{256, 1024})
+	if err != nil {
+		return fmt.Errorf("invalid --segment-sizes: %w", err)
+	}
+
+	blocks, err := loadCorpus(corpusCLI)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("no block found in the corpus")
+	}
+
+	// split the corpus
+	var trainSet, evalSet []corpusBlock
+	for i := range blocks {
+		if evalEveryCLI > 0 && i%evalEveryCLI == evalEveryCLI-1 {
+			evalSet = append(evalSet, blocks[i])
+		} else {
+			trainSet = append(trainSet, blocks[i])
+		}
+	}
+	if evalEveryCLI == 0 {
+		evalSet = trainSet
+	}
+	if len(evalSet) == 0 {
+		return fmt.Errorf("not enough blocks in the corpus to evaluate the dictionaries: %v", len(blocks))
+	}
+
+	logrus.Infof("loaded %v blocks: %v for training and %v for the evaluation", len(blocks), len(trainSet), len(evalSet))
+
+	samples := make([][]byte, len(trainSet))
+	for i := range trainSet {
+		samples[i] = trainSet[i].encoded
+	}
+
+	if err := os.MkdirAll(outDirCLI, 0755); err != nil {
+		return err
+	}
+
+	dictPaths := append([]string{}, baselinesCLI...)
+	for _, size := range sizes {
+		for _, segmentSize := range segmentSizes {
+
+			dict, err := dictionary.Train(samples, dictionary.TrainingConfig{
+				Size:        size,
+				SegmentSize: segmentSize,
+				DmerSize:    dmerSizeCLI,
+			})
+			if err != nil {
+				return fmt.Errorf("could not train the dictionary of size %v with segments of %v bytes: %w", size, segmentSize, err)
+			}
+
+			dictPath := filepath.Join(outDirCLI, fmt.Sprintf("dict-%v-k%v.bin", size, segmentSize))
+			if err := os.WriteFile(dictPath, dict, 0600); err != nil {
+				return err
+			}
+			logrus.Infof("wrote %v (%v bytes)", dictPath, len(dict))
+			dictPaths = append(dictPaths, dictPath)
+		}
+	}
+
+	evaluations := make([]evaluation, len(dictPaths))
+	for i, dictPath := range dictPaths {
+		if evaluations[i], err = evaluate(dictPath, evalSet); err != nil {
+			return fmt.Errorf("could not evaluate %v: %w", dictPath, err)
+		}
+	}
+
+	if jsonCLI {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(evaluations)
+	}
+
+	printEvaluations(os.Stdout, evaluations)
+	return nil
+}
+
+func parseInts(list []string, defaults []int) ([]int, error) {
+	if len(list) == 0 {
+		return defaults, nil
+	}
+	res := make([]int, len(list))
+	for i := range list {
+		var err error
+		if res[i], err = strconv.Atoi(list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func printEvaluations(w io.Writer, evaluations []evaluation) {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "dictionary\tsize\tblocks\tblobs\tblocks/blob\tratio\tworst-case ratio\tmax worst-case block size\t")
+	for _, e := range evaluations {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%.2f\t%.2f\t%.3f\t%.3f\t%v\t\n",
+			e.Path, e.Size, e.NbBlocks, e.NbBlobs, e.BlocksPerBlob, e.Ratio, e.WorstCaseRatio, e.MaxWorstCompressedBlockSize)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The decompression circuit must be set up with --dict-size equal to the size of the dictionary,")
+	fmt.Fprintln(w, "and the dictionary must be registered under its checksum:")
+	for _, e := range evaluations {
+		fmt.Fprintf(w, "  %v: %v\n", e.Path, e.Checksum)
+	}
+}
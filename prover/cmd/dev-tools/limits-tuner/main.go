@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	configFPathCLI string
+	tracesGlobCLI  string
+	coverageCLI    float64
+	largeCLI       bool
+	outFPathCLI    string
+	estimateCLI    bool
+)
+
+func init() {
+	flag.StringVar(&configFPathCLI, "config", "", "path to the config file. The current limits are read from it")
+	flag.StringVar(&tracesGlobCLI, "traces", "", "glob matching the conflated .lt trace files of the corpus")
+	flag.Float64Var(&coverageCLI, "coverage", 99, "percentage of the corpus that each limit should cover")
+	flag.BoolVar(&largeCLI, "large", false, "tune the large limits instead of the normal ones")
+	flag.StringVar(&outFPathCLI, "out", "", "file where the proposed limits are written as a config section (default: stdout)")
+	flag.BoolVar(&estimateCLI, "estimate", true, "compile the zkEVM to estimate the committed cells and the memory of the prover")
+}
+
+func main() {
+
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Printf("FATAL\n")
+		fmt.Printf("err = %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	if len(configFPathCLI) == 0 || len(tracesGlobCLI) == 0 {
+		return fmt.Errorf("the --config and --traces flags are required")
+	}
+
+	if coverageCLI <= 0 || coverageCLI > 100 {
+		return fmt.Errorf("the coverage must be in (0, 100], got %v", coverageCLI)
+	}
+
+	cfg, err := config.NewConfigFromFile(configFPathCLI)
+	if err != nil {
+		return fmt.Errorf("could not parse the config: %w", err)
+	}
+
+	traceFiles, err := filepath.Glob(tracesGlobCLI)
+	if err != nil || len(traceFiles) == 0 {
+		return fmt.Errorf("no trace file matches %v (err=%v)", tracesGlobCLI, err)
+	}
+
+	bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, "")
+	if err != nil {
+		return fmt.Errorf("could not load the constraints: %w", err)
+	}
+
+	schema, _, err := bin.Compile(&mir.DEFAULT_OPTIMISATION_LEVEL)
+	if err != nil {
+		return fmt.Errorf("could not read the zkevm.bin file: %w", err)
+	}
+
+	corpus := newCorpus()
+	for i, traceFile := range traceFiles {
+		counts, err := arithmetization.CountLtTraces(schema, traceFile)
+		if err != nil {
+			logrus.Warnf("skipping %v: %v", traceFile, err)
+			continue
+		}
+		corpus.add(counts)
+		logrus.Infof("counted %v (%v/%v)", traceFile, i+1, len(traceFiles))
+	}
+
+	if corpus.nbFiles == 0 {
+		return fmt.Errorf("could not count any of the trace files")
+	}
+
+	var (
+		current = &cfg.TracesLimits
+		section = "traces_limits"
+	)
+
+	if largeCLI {
+		current, section = &cfg.TracesLimitsLarge, "traces_limits_large"
+	}
+
+	proposals := corpus.propose(current, coverageCLI)
+
+	fmt.Printf("corpus: %v trace files, target coverage per limit: %v%%\n\n", corpus.nbFiles, coverageCLI)
+	writeTable(os.Stdout, proposals)
+	fmt.Printf("\nfraction of the corpus fitting all the proposed limits: %.2f%%\n", 100*corpus.jointCoverage(proposals))
+
+	if estimateCLI {
+		fmt.Printf("\n")
+		printEstimate(cfg, bin, "current", current)
+		printEstimate(cfg, bin, "proposed", asLimits(proposals))
+	}
+
+	out := os.Stdout
+	if len(outFPathCLI) > 0 {
+		if out, err = os.Create(outFPathCLI); err != nil {
+			return fmt.Errorf("could not create %v: %w", outFPathCLI, err)
+		}
+		defer out.Close()
+	} else {
+		fmt.Printf("\n")
+	}
+
+	writeToml(out, section, proposals)
+	return nil
+}
+
+// printEstimate prints the number of cells committed by the full prover for
+// the limits and a lower bound on the memory it needs to hold them.
+func printEstimate(cfg *config.Config, bin *arithmetization.ZkevmBin, name string, tl *config.TracesLimits) {
+
+	var (
+		stats    = zkevm.FullZkEvmCommitmentStats(tl, cfg, bin)
+		cells    = stats.NbCells + stats.NbPrecomputedCells
+		memBytes = float64(cells) * float64(field.Bytes) * float64(1+stats.VortexBlowUp)
+	)
+
+	fmt.Printf(
+		"%v limits: %v committed columns, %v committed cells (+%v precomputed), estimated memory >= %.1f GiB\n",
+		name, stats.NbColumns, stats.NbCells, stats.NbPrecomputedCells, memBytes/(1<<30),
+	)
+}
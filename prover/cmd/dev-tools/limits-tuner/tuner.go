@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// corpus collects the counts of a set of trace files keyed by limit name
+type corpus struct {
+	nbFiles int
+	// counts[name][i] is the count of the i-th file. Files that miss a
+	// count are counted as zero.
+	counts map[string][]int
+}
+
+func newCorpus() *corpus {
+	return &corpus{counts: map[string][]int{}}
+}
+
+// add records the counts of a trace file
+func (c *corpus) add(counts map[string]int) {
+
+	for name := range counts {
+		if _, ok := c.counts[name]; !ok {
+			c.counts[name] = make([]int, c.nbFiles)
+		}
+	}
+
+	for name := range c.counts {
+		c.counts[name] = append(c.counts[name], counts[name])
+	}
+
+	c.nbFiles++
+}
+
+// proposal is the proposed value of a limit
+type proposal struct {
+	Name             string
+	Current          int
+	Median, Max      int
+	AtCoverage       int
+	Proposed         int
+	ProposedCoverage float64
+}
+
+// propose computes, for every limit, the value at the target coverage (in
+// percent) and rounds it up to the next power of two for the limits that are
+// required to be powers of two. The limits that are not observed in the
+// corpus (e.g. the request-level limits) or whose count at the target coverage
+// is zero keep their current value.
+func (c *corpus) propose(current *config.TracesLimits, coverage float64) []proposal {
+
+	var (
+		res  = []proposal{}
+		typ  = reflect.TypeOf(config.TracesLimits{})
+		pow2 = map[string]bool{}
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		pow2[f.Tag.Get("mapstructure")] = strings.Contains(f.Tag.Get("validate"), "power_of_2")
+	}
+
+	for _, name := range config.TracesLimitNames() {
+
+		curr, _ := current.Get(name)
+		p := proposal{Name: name, Current: curr, Proposed: curr}
+
+		counts, ok := c.counts[name]
+		if !ok || len(counts) == 0 {
+			res = append(res, p)
+			continue
+		}
+
+		sorted := append([]int{}, counts...)
+		sort.Ints(sorted)
+
+		p.Median = percentile(sorted, 50)
+		p.Max = sorted[len(sorted)-1]
+		p.AtCoverage = percentile(sorted, coverage)
+		if p.AtCoverage > 0 {
+			p.Proposed = p.AtCoverage
+			if pow2[name] {
+				p.Proposed = utils.NextPowerOfTwo(p.Proposed)
+			}
+		}
+		p.ProposedCoverage = fractionAtMost(sorted, p.Proposed)
+
+		res = append(res, p)
+	}
+
+	return res
+}
+
+// jointCoverage returns the fraction of the files for which all the counts
+// fit the proposed limits.
+func (c *corpus) jointCoverage(proposals []proposal) float64 {
+
+	if c.nbFiles == 0 {
+		return 1
+	}
+
+	fits := make([]bool, c.nbFiles)
+	for i := range fits {
+		fits[i] = true
+	}
+
+	for _, p := range proposals {
+		for i, count := range c.counts[p.Name] {
+			if count > p.Proposed {
+				fits[i] = false
+			}
+		}
+	}
+
+	nbFit := 0
+	for _, f := range fits {
+		if f {
+			nbFit++
+		}
+	}
+
+	return float64(nbFit) / float64(c.nbFiles)
+}
+
+// percentile returns the nearest-rank percentile of a sorted slice
+func percentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// fractionAtMost returns the fraction of the entries of a sorted slice that
+// are at most x.
+func fractionAtMost(sorted []int, x int) float64 {
+	n := sort.Search(len(sorted), func(i int) bool { return sorted[i] > x })
+	return float64(n) / float64(len(sorted))
+}
+
+// writeTable writes the proposals as a human-readable table
+func writeTable(w io.Writer, proposals []proposal) {
+	fmt.Fprintf(w, "%-45s %10s %10s %10s %10s %10s %9s\n", "LIMIT", "CURRENT", "MEDIAN", "AT-COV", "MAX", "PROPOSED", "COVERAGE")
+	for _, p := range proposals {
+		fmt.Fprintf(w, "%-45s %10d %10d %10d %10d %10d %8.2f%%\n",
+			p.Name, p.Current, p.Median, p.AtCoverage, p.Max, p.Proposed, 100*p.ProposedCoverage)
+	}
+}
+
+// writeToml writes the proposals as a section of the configuration file
+func writeToml(w io.Writer, section string, proposals []proposal) {
+	fmt.Fprintf(w, "[%v]\n", section)
+	for _, p := range proposals {
+		fmt.Fprintf(w, "%v = %v\n", p.Name, p.Proposed)
+	}
+}
+
+// asLimits returns the proposals as a [config.TracesLimits]
+func asLimits(proposals []proposal) *config.TracesLimits {
+
+	var (
+		res = &config.TracesLimits{}
+		val = reflect.ValueOf(res).Elem()
+		typ = val.Type()
+		idx = map[string]int{}
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		idx[typ.Field(i).Tag.Get("mapstructure")] = i
+	}
+
+	for _, p := range proposals {
+		val.Field(idx[p.Name]).SetInt(int64(p.Proposed))
+	}
+
+	return res
+}
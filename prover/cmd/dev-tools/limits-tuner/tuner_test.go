@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropose(t *testing.T) {
+
+	c := newCorpus()
+	for i := 1; i <= 100; i++ {
+		c.add(map[string]int{"HUB": 1000 * i, "PRECOMPILE_SHA2_BLOCKS": i})
+	}
+	// A module only seen in the last file
+	c.add(map[string]int{"HUB": 10, "PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS": 3})
+
+	var (
+		current   = &config.TracesLimits{Hub: 1 << 16, BlockTransactions: 200, PrecompileEcrecoverEffectiveCalls: 128}
+		proposals = c.propose(current, 90)
+		byName    = map[string]proposal{}
+	)
+
+	for _, p := range proposals {
+		byName[p.Name] = p
+	}
+
+	// HUB: 91 files out of 101 are <= 90000, rounded to 2^17
+	assert.Equal(t, 90000, byName["HUB"].AtCoverage)
+	assert.Equal(t, 1<<17, byName["HUB"].Proposed)
+	assert.Equal(t, 1.0, byName["HUB"].ProposedCoverage)
+
+	// Not a power of two
+	assert.Equal(t, 90, byName["PRECOMPILE_SHA2_BLOCKS"].Proposed)
+
+	// Zero in all but one file, keeps the current value
+	assert.Equal(t, 0, byName["PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS"].AtCoverage)
+	assert.Equal(t, 128, byName["PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS"].Proposed)
+
+	// Not observed, keeps the current value
+	assert.Equal(t, 200, byName["BLOCK_TRANSACTIONS"].Proposed)
+
+	// 10 files overflow the SHA2 limit
+	assert.InDelta(t, 91.0/101, c.jointCoverage(proposals), 1e-9)
+	assert.Equal(t, 1<<17, asLimits(proposals).Hub)
+}
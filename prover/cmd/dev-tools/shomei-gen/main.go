@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/mock"
+	"github.com/sirupsen/logrus"
+)
+
+// spec is the input of the tool: the world state before the first block and
+// the state diffs of the blocks.
+type spec struct {
+	FirstBlock   int                   `json:"firstBlock"`
+	InitialState mock.WorldStateSpec   `json:"initialState"`
+	Blocks       []mock.BlockStateDiff `json:"blocks"`
+}
+
+var (
+	specFPathCLI    string
+	requestFPathCLI string
+	outFPathCLI     string
+)
+
+func init() {
+	flag.StringVar(&specFPathCLI, "spec", "", "path to the JSON file with the initial state and the state diffs of the blocks")
+	flag.StringVar(&requestFPathCLI, "request", "", "optional execution request whose state-manager traces are replaced by the generated ones")
+	flag.StringVar(&outFPathCLI, "out", "", "file where the output is written (default: stdout)")
+}
+
+func main() {
+
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Printf("FATAL\n")
+		fmt.Printf("err = %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	if len(specFPathCLI) == 0 {
+		return fmt.Errorf("the --spec flag is required")
+	}
+
+	var s spec
+	if err := readJSON(specFPathCLI, &s); err != nil {
+		return err
+	}
+
+	state, err := s.InitialState.State()
+	if err != nil {
+		return fmt.Errorf("invalid initial state: %w", err)
+	}
+
+	parentRoot, traces, err := mock.GenerateShomeiTraces(state, s.FirstBlock, s.Blocks)
+	if err != nil {
+		return fmt.Errorf("could not generate the traces: %w", err)
+	}
+
+	logrus.Infof("generated the traces of %v blocks from the parent state root hash %v", len(traces), parentRoot.Hex())
+
+	var out any
+
+	if len(requestFPathCLI) == 0 {
+		var shomeiOut statemanager.ShomeiOutput
+		shomeiOut.Result.ZkParentStateRootHash = parentRoot
+		shomeiOut.Result.ZkStateMerkleProof = traces
+		out = shomeiOut
+	} else {
+		// The request is patched as a raw JSON object so that the fields
+		// unknown to the prover are kept as is.
+		var req map[string]json.RawMessage
+		if err := readJSON(requestFPathCLI, &req); err != nil {
+			return err
+		}
+		if req["zkParentStateRootHash"], err = json.Marshal(parentRoot); err != nil {
+			return err
+		}
+		if req["zkStateMerkleProof"], err = json.Marshal(traces); err != nil {
+			return err
+		}
+		out = req
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal the output: %w", err)
+	}
+
+	if len(outFPathCLI) == 0 {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+
+	return os.WriteFile(outFPathCLI, b, 0600)
+}
+
+func readJSON(fPath string, v any) error {
+	b, err := os.ReadFile(fPath)
+	if err != nil {
+		return fmt.Errorf("could not read %v: %w", fPath, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not parse %v: %w", fPath, err)
+	}
+	return nil
+}
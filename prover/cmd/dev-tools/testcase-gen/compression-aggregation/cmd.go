@@ -358,7 +358,7 @@ func dumpVerifierContract(odir string, circID circuits.MockCircuitID) {
 	f := files.MustOverwrite(filepath)
 	defer f.Close()
 
-	srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+	srsProvider, err := circuits.NewSRSProvider(cfg)
 	if err != nil {
 		printlnAndExit("could not create SRS provider: %v", err)
 	}
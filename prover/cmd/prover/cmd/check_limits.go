@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
+)
+
+type CheckLimitsArgs struct {
+	Input string
+	// Output is the response file the job would be proven into. It is only
+	// used to locate the failure report, nothing is written to it.
+	Output string
+	// Report is the file where the JSON report is written. The report is
+	// written on the standard output if empty.
+	Report     string
+	ConfigFile string
+}
+
+// CheckLimits estimates the traces of an execution request against the
+// normal and the large limits, without proving it. The returned error is nil
+// if the request fits the normal limits, a [exit.KindTraceLimit] error if it
+// needs the large prover and a [exit.KindTraceLimitLarge] error if it cannot
+// be proven at all. As for [Prove], a failure report is written next to the
+// output file.
+func CheckLimits(args CheckLimitsArgs) (err error) {
+
+	if len(args.Output) > 0 {
+		failureFile := exit.FailureFile(args.Output)
+		os.Remove(failureFile)
+
+		defer func() {
+			if err != nil {
+				if werr := exit.WriteFailure(failureFile, err); werr != nil {
+					logrus.Errorf("could not write the failure report: %v", werr)
+				}
+			}
+		}()
+	}
+
+	return checkLimits(args)
+}
+
+func checkLimits(args CheckLimitsArgs) error {
+	const cmdName = "check-limits"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	req := &execution.Request{}
+	if err := readRequest(args.Input, req); err != nil {
+		return exit.Errorf(exit.KindInvalidRequest, "could not read the input file (%v): %w", args.Input, err)
+	}
+
+	report, err := execution.CheckLimits(cfg, req)
+	if err != nil {
+		return fmt.Errorf("could not check the limits: %w", err)
+	}
+
+	for _, e := range report.Entries {
+		level := logrus.InfoLevel
+		if e.Count > e.Limit {
+			level = logrus.WarnLevel
+		}
+		logrus.StandardLogger().Logf(level, "limit check name=%v count=%v limit=%v limit-large=%v", e.Name, e.Count, e.Limit, e.LimitLarge)
+	}
+
+	logrus.Infof("limit check verdict=%v", report.Verdict)
+
+	if len(args.Report) > 0 {
+		if err := writeResponse(args.Report, report); err != nil {
+			return err
+		}
+	} else if err := writeReport(report); err != nil {
+		return err
+	}
+
+	return report.Err()
+}
+
+func writeReport(report *execution.LimitsReport) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("could not encode the report: %w", err)
+	}
+	return nil
+}
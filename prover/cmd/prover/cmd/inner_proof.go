@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
+)
+
+type VerifyInnerArgs struct {
+	// Inner is the file storing the inner-proof
+	Inner string
+	// TracesEngineVersion selects the constraints of the zkEVM, as the field
+	// of the request the inner-proof was generated for.
+	TracesEngineVersion string
+	Large               bool
+	ConfigFile          string
+}
+
+// VerifyInner verifies an inner-proof dumped by the execution prover against
+// the full zkEVM compiled for the normal or the large limits.
+func VerifyInner(args VerifyInnerArgs) error {
+	const cmdName = "verify-inner"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	if err := execution.VerifyInner(cfg, args.Inner, args.TracesEngineVersion, args.Large); err != nil {
+		return err
+	}
+
+	logrus.Infof("the inner-proof %v is valid", args.Inner)
+	return nil
+}
+
+type OuterFromInnerArgs struct {
+	Input  string
+	Output string
+	// Inner is the file storing the inner-proof of the request. It defaults
+	// to the file the prover dumps it into (see [config.Execution]).
+	Inner      string
+	Large      bool
+	ConfigFile string
+}
+
+// OuterFromInner generates the response of an execution request from the
+// inner-proof dumped by a previous attempt, skipping the inner prover. As for
+// [Prove], a failure report is written next to the output file.
+func OuterFromInner(args OuterFromInnerArgs) (err error) {
+
+	failureFile := exit.FailureFile(args.Output)
+	os.Remove(failureFile)
+
+	defer func() {
+		if err != nil {
+			if werr := exit.WriteFailure(failureFile, err); werr != nil {
+				logrus.Errorf("could not write the failure report: %v", werr)
+			}
+		}
+	}()
+
+	return outerFromInner(args)
+}
+
+func outerFromInner(args OuterFromInnerArgs) error {
+	const cmdName = "outer-from-inner"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	req := &execution.Request{}
+	if err := readRequest(args.Input, req); err != nil {
+		return exit.Errorf(exit.KindInvalidRequest, "could not read the input file (%v): %w", args.Input, err)
+	}
+
+	inner := args.Inner
+	if len(inner) == 0 {
+		inner = execution.InnerProofPath(cfg, req)
+	}
+
+	if len(inner) == 0 {
+		return exit.Errorf(exit.KindInvalidRequest, "no inner-proof file was given and execution.inner_proof_dir is not set")
+	}
+
+	// Same rule as for the prove command
+	large := args.Large || (strings.Contains(args.Input, "large") && cfg.Execution.CanRunFullLarge)
+
+	resp, err := execution.ProveFromInner(cfg, req, inner, large)
+	if err != nil {
+		return fmt.Errorf("could not prove the execution from the inner-proof: %w", err)
+	}
+
+	return writeResponse(args.Output, resp)
+}
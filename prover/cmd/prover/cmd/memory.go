@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
+)
+
+// checkMemory returns an [exit.KindOomRisk] error if the machine has less
+// available memory than the job requires. The check is skipped when the
+// requirement is not configured or when the available memory cannot be
+// determined (e.g. on non-Linux systems).
+func checkMemory(requiredGiB int) error {
+
+	if requiredGiB <= 0 {
+		return nil
+	}
+
+	availableGiB, err := availableMemoryGiB("/proc/meminfo")
+	if err != nil {
+		logrus.Warnf("could not determine the available memory, skipping the memory check: %v", err)
+		return nil
+	}
+
+	if availableGiB < float64(requiredGiB) {
+		return exit.Errorf(
+			exit.KindOomRisk,
+			"the job requires %v GiB of memory but only %.1f GiB are available",
+			requiredGiB, availableGiB,
+		)
+	}
+
+	return nil
+}
+
+// availableMemoryGiB parses the MemAvailable entry of a meminfo file
+func availableMemoryGiB(meminfo string) (float64, error) {
+
+	f, err := os.Open(meminfo)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// The line is formatted as "MemAvailable:   12345678 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse %q: %w", scanner.Text(), err)
+		}
+
+		return float64(kb) / (1 << 20), nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("no MemAvailable entry in %v", meminfo)
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -12,6 +11,8 @@ import (
 	"github.com/consensys/linea-monorepo/prover/backend/execution"
 	"github.com/consensys/linea-monorepo/prover/backend/files"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
 )
 
 type ProverArgs struct {
@@ -21,7 +22,28 @@ type ProverArgs struct {
 	ConfigFile string
 }
 
-func Prove(args ProverArgs) error {
+// Prove runs the prover on the request. When it fails, a failure report is
+// written next to the output file (see [exit.FailureFile]) and the returned
+// error carries the classification of the failure.
+func Prove(args ProverArgs) (err error) {
+
+	// Remove the report of a previous attempt so that the controller does not
+	// misattribute it.
+	failureFile := exit.FailureFile(args.Output)
+	os.Remove(failureFile)
+
+	defer func() {
+		if err != nil {
+			if werr := exit.WriteFailure(failureFile, err); werr != nil {
+				logrus.Errorf("could not write the failure report: %v", werr)
+			}
+		}
+	}()
+
+	return prove(args)
+}
+
+func prove(args ProverArgs) error {
 	const cmdName = "prove"
 	// TODO @gbotrel with a specific flag, we could compile the circuit and compare with the checksum of the
 	// asset we deserialize, to make sure we are using the circuit associated with the compiled binary and the setup.
@@ -40,7 +62,7 @@ func Prove(args ProverArgs) error {
 	if jobExecution {
 		req := &execution.Request{}
 		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+			return exit.Errorf(exit.KindInvalidRequest, "could not read the input file (%v): %w", args.Input, err)
 		}
 
 		// we use the large traces in 2 cases;
@@ -48,6 +70,10 @@ func Prove(args ProverArgs) error {
 		// 2. the job contains the large suffix and we are a large machine (cfg.Execution.CanRunLarge)
 		large := args.Large || (strings.Contains(args.Input, "large") && cfg.Execution.CanRunFullLarge)
 
+		if err := checkMemory(cfg.Controller.Resources.Execution.MemoryGiB); err != nil {
+			return err
+		}
+
 		resp, err := execution.Prove(cfg, req, large)
 		if err != nil {
 			return fmt.Errorf("could not prove the execution: %w", err)
@@ -59,7 +85,7 @@ func Prove(args ProverArgs) error {
 	if jobBlobDecompression {
 		req := &blobdecompression.Request{}
 		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+			return exit.Errorf(exit.KindInvalidRequest, "could not read the input file (%v): %w", args.Input, err)
 		}
 
 		resp, err := blobdecompression.Prove(cfg, req)
@@ -73,7 +99,7 @@ func Prove(args ProverArgs) error {
 	if jobAggregation {
 		req := &aggregation.Request{}
 		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+			return exit.Errorf(exit.KindInvalidRequest, "could not read the input file (%v): %w", args.Input, err)
 		}
 
 		resp, err := aggregation.Prove(cfg, req)
@@ -84,7 +110,7 @@ func Prove(args ProverArgs) error {
 		return writeResponse(args.Output, resp)
 	}
 
-	return errors.New("unknown job type")
+	return exit.Errorf(exit.KindInvalidRequest, "unknown job type for input file %v", args.Input)
 }
 
 func readRequest(path string, into any) error {
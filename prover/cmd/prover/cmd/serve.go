@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+// Routes served by the proving server
+const (
+	RouteProveExecution         = "/prove/execution"
+	RouteProveBlobDecompression = "/prove/blob-decompression"
+	RouteProveAggregation       = "/prove/aggregation"
+	RouteHealth                 = "/health"
+)
+
+// Kinds of events streamed back by the proving server
+const (
+	EventQueued   = "queued"
+	EventStarted  = "started"
+	EventProgress = "progress"
+	EventResponse = "response"
+	EventError    = "error"
+)
+
+type ServeArgs struct {
+	ConfigFile string
+	Addr       string
+	Large      bool
+	WarmUp     bool
+}
+
+// ServerEvent is an event streamed back to the client of the proving server.
+// The events are sent as newline-delimited JSON. The stream of a request
+// always terminates with either a "response" or an "error" event.
+type ServerEvent struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	Message  string    `json:"message,omitempty"`
+	Response any       `json:"response,omitempty"`
+	// Failure is the classification of the failure of an "error" event
+	Failure *exit.Failure `json:"failure,omitempty"`
+}
+
+// Serve starts the proving server and blocks until the context is cancelled
+// or the server fails. On cancellation, the server stops accepting requests
+// and waits for the in-flight ones to complete.
+func Serve(ctx context.Context, args ServeArgs) error {
+	const cmdName = "serve"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	// The whole point of the server is to not pay the loading cost of the
+	// setups for every request.
+	circuits.EnableSetupCache()
+
+	srv := NewProvingServer(cfg, args.Large)
+	if args.WarmUp {
+		srv.WarmUp()
+	}
+
+	httpSrv := &http.Server{
+		Addr:              args.Addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logrus.Infof("shutting down the proving server")
+		httpSrv.Shutdown(context.Background())
+	}()
+
+	logrus.Infof("proving server listening on %v", args.Addr)
+	if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s failed to serve: %w", cmdName, err)
+	}
+
+	return nil
+}
+
+// ProvingServer serves the proving requests over HTTP. It keeps the compiled
+// wizard IOP and the setups in memory between requests. The proofs are
+// generated one at a time as a single proof already uses all the resources of
+// the machine.
+type ProvingServer struct {
+	Config *config.Config
+	// Large indicates whether execution requests are proven with the large
+	// traces limits. The compiled zkEVM is memoized for the lifetime of the
+	// process so a server instance can only serve one of the two.
+	Large bool
+
+	mux *http.ServeMux
+	// Held for the whole duration of a proof
+	proving sync.Mutex
+	// Forwards the logs of the proof being generated to its client
+	hook *progressHook
+}
+
+// NewProvingServer returns a [ProvingServer] and registers its logging hook
+// in the standard logger.
+func NewProvingServer(cfg *config.Config, large bool) *ProvingServer {
+
+	s := &ProvingServer{
+		Config: cfg,
+		Large:  large,
+		mux:    http.NewServeMux(),
+		hook:   &progressHook{},
+	}
+
+	logrus.AddHook(s.hook)
+
+	s.mux.HandleFunc(RouteProveExecution, proveHandler(s, func(req *execution.Request) (any, error) {
+		return execution.Prove(s.Config, req, s.Large)
+	}))
+
+	s.mux.HandleFunc(RouteProveBlobDecompression, proveHandler(s, func(req *blobdecompression.Request) (any, error) {
+		return blobdecompression.Prove(s.Config, req)
+	}))
+
+	s.mux.HandleFunc(RouteProveAggregation, proveHandler(s, func(req *aggregation.Request) (any, error) {
+		return aggregation.Prove(s.Config, req)
+	}))
+
+	s.mux.HandleFunc(RouteHealth, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": "ok",
+			"large":  s.Large,
+		})
+	})
+
+	return s
+}
+
+func (s *ProvingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// WarmUp compiles the zkEVM and loads the setups that the enabled jobs are
+// going to need so that the first request does not pay for it. Failing to load
+// a setup is not fatal: the error will be returned to the first request
+// needing it.
+func (s *ProvingServer) WarmUp() {
+
+	cfg := s.Config
+	traces := &cfg.TracesLimits
+	if s.Large {
+		traces = &cfg.TracesLimitsLarge
+	}
+
+	loadSetup := func(circuitID circuits.CircuitID) {
+		if _, err := circuits.LoadSetup(cfg, circuitID); err != nil {
+			logrus.Warnf("warm-up: could not load the setup of %v: %v", circuitID, err)
+		}
+	}
+
+	if cfg.Controller.EnableExecution {
+		// The zkEVM is compiled for the default constraints, the ones of the
+		// other versions of the traces engine are compiled on demand.
+		bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, "")
+		if err != nil {
+			logrus.Warnf("warm-up: could not load the constraints: %v", err)
+		} else {
+			switch cfg.Execution.ProverMode {
+			case config.ProverModeFull:
+				logrus.Info("warm-up: compiling the full zkEVM")
+				zkevm.FullZkEvm(traces, cfg, bin)
+				loadSetup(circuits.ExecutionCircuitID)
+			case config.ProverModeBench:
+				logrus.Info("warm-up: compiling the full zkEVM")
+				zkevm.FullZkEvm(traces, cfg, bin)
+			case config.ProverModePartial, config.ProverModeCheckOnly:
+				logrus.Info("warm-up: compiling the check-only zkEVM")
+				zkevm.FullZkEVMCheckOnly(traces, cfg, bin)
+			}
+		}
+	}
+
+	if cfg.Controller.EnableBlobDecompression && cfg.BlobDecompression.ProverMode == config.ProverModeFull {
+		loadSetup(circuits.BlobDecompressionV1CircuitID)
+	}
+
+	if cfg.Controller.EnableAggregation && cfg.Aggregation.ProverMode == config.ProverModeFull {
+		loadSetup(circuits.PublicInputInterconnectionCircuitID)
+		loadSetup(circuits.EmulationCircuitID)
+	}
+
+	logrus.Info("warm-up: done")
+}
+
+// proveHandler returns an HTTP handler decoding a request of type T from the
+// body, proving it with the provided function and streaming back the events.
+func proveHandler[T any](s *ProvingServer, prove func(req *T) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		req := new(T)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, fmt.Sprintf("could not decode the request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		stream := newEventStream(w)
+		stream.send(ServerEvent{Event: EventQueued})
+
+		s.proving.Lock()
+		defer s.proving.Unlock()
+
+		s.hook.setSink(stream.send)
+		defer s.hook.setSink(nil)
+
+		stream.send(ServerEvent{Event: EventStarted})
+		start := time.Now()
+
+		resp, err := runRecovered(func() (any, error) { return prove(req) })
+		if err != nil {
+			stream.send(ServerEvent{Event: EventError, Message: err.Error(), Failure: exit.NewFailure(err)})
+			return
+		}
+
+		stream.send(ServerEvent{
+			Event:    EventResponse,
+			Message:  fmt.Sprintf("proof generated in %v", time.Since(start)),
+			Response: resp,
+		})
+	}
+}
+
+// Runs the function and converts a panic into an error. The provers panic on
+// many invalid inputs and a request should never crash the server. The panics
+// carrying an error are wrapped so that their classification (see
+// [exit.Error]) is kept.
+func runRecovered(f func() (any, error)) (res any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rerr, ok := r.(error); ok {
+				err = fmt.Errorf("the prover panicked: %w", rerr)
+				return
+			}
+			err = fmt.Errorf("the prover panicked: %v", r)
+		}
+	}()
+	return f()
+}
+
+// eventStream writes newline-delimited JSON events to an HTTP response and
+// flushes them immediately.
+type eventStream struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	flusher http.Flusher
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &eventStream{enc: json.NewEncoder(w), flusher: flusher}
+}
+
+func (e *eventStream) send(ev ServerEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	// If the client went away, there is nothing we can do about it. The
+	// proof still completes so that the setups remain consistent.
+	if err := e.enc.Encode(ev); err != nil {
+		return
+	}
+
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+// progressHook is a logrus hook forwarding the info-level logs emitted during
+// a proof as progress events.
+type progressHook struct {
+	mu   sync.Mutex
+	sink func(ServerEvent)
+}
+
+func (h *progressHook) setSink(sink func(ServerEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sink = sink
+}
+
+func (h *progressHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.PanicLevel,
+		logrus.FatalLevel,
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+		logrus.InfoLevel,
+	}
+}
+
+func (h *progressHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	sink := h.sink
+	h.mu.Unlock()
+
+	if sink != nil {
+		sink(ServerEvent{Event: EventProgress, Time: entry.Time.UTC(), Message: entry.Message})
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Value int `json:"value"`
+}
+
+func TestProveHandlerStream(t *testing.T) {
+
+	s := &ProvingServer{hook: &progressHook{}}
+	logrus.AddHook(s.hook)
+
+	handler := proveHandler(s, func(req *testRequest) (any, error) {
+		logrus.Infof("proving value %v", req.Value)
+		if req.Value < 0 {
+			panic("negative value")
+		}
+		exit.PanicOnLimitOverflow("HUB", req.Value, 1000)
+		return map[string]int{"double": 2 * req.Value}, nil
+	})
+
+	run := func(body string) (int, []ServerEvent) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, RouteProveExecution, strings.NewReader(body)))
+
+		events := []ServerEvent{}
+		if rec.Header().Get("Content-Type") != "application/x-ndjson" {
+			return rec.Code, events
+		}
+
+		sc := bufio.NewScanner(rec.Body)
+		for sc.Scan() {
+			var ev ServerEvent
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+			events = append(events, ev)
+		}
+		return rec.Code, events
+	}
+
+	eventKinds := func(events []ServerEvent) []string {
+		res := make([]string, len(events))
+		for i := range events {
+			res[i] = events[i].Event
+		}
+		return res
+	}
+
+	code, events := run(`{"value": 21}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{EventQueued, EventStarted, EventProgress, EventResponse}, eventKinds(events))
+	assert.Equal(t, "proving value 21", events[2].Message)
+	assert.Equal(t, map[string]any{"double": 42.0}, events[3].Response)
+
+	code, events = run(`{"value": -1}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{EventQueued, EventStarted, EventProgress, EventError}, eventKinds(events))
+	assert.Contains(t, events[3].Message, "negative value")
+	assert.Equal(t, exit.KindUnclassified, events[3].Failure.Kind)
+
+	// The typed failures keep their classification
+	code, events = run(`{"value": 1001}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{EventQueued, EventStarted, EventProgress, EventError}, eventKinds(events))
+	assert.Equal(t, exit.KindTraceLimit, events[3].Failure.Kind)
+	assert.Equal(t, exit.CodeTraceLimit, events[3].Failure.ExitCode)
+	assert.Equal(t, "HUB", events[3].Failure.Module)
+
+	code, _ = run(`not-json`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// The logs emitted outside of a request are not forwarded anywhere
+	logrus.Infof("not part of any request")
+}
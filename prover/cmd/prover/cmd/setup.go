@@ -28,6 +28,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 )
 
 type SetupArgs struct {
@@ -37,6 +38,9 @@ type SetupArgs struct {
 	DictSize   int
 	AssetsDir  string
 	ConfigFile string
+	// TracesEngineVersion selects the constraints of the execution circuits,
+	// see [arithmetization.SelectZkevmBin].
+	TracesEngineVersion string
 }
 
 var AllCircuits = []circuits.CircuitID{
@@ -83,7 +87,7 @@ func Setup(context context.Context, args SetupArgs) error {
 
 	// srs provider
 	var srsProvider circuits.SRSProvider
-	srsProvider, err = circuits.NewSRSStore(cfg.PathForSRS())
+	srsProvider, err = circuits.NewSRSProvider(cfg)
 	if err != nil {
 		return fmt.Errorf("%s failed to create SRS provider: %w", cmdName, err)
 	}
@@ -101,8 +105,13 @@ func Setup(context context.Context, args SetupArgs) error {
 		}
 		logrus.Infof("setting up %s", c)
 
-		var builder circuits.Builder
-		extraFlags := make(map[string]any)
+		var (
+			builder circuits.Builder
+			// version is the version of the constraints of the execution
+			// circuits, their setups are stored per version.
+			version    string
+			extraFlags = make(map[string]any)
+		)
 
 		// let's compile the circuit.
 		switch c {
@@ -111,8 +120,15 @@ func Setup(context context.Context, args SetupArgs) error {
 			if c == circuits.ExecutionLargeCircuitID {
 				limits = cfg.TracesLimitsLarge
 			}
+			bin, err := arithmetization.SelectZkevmBin(&cfg.Execution, args.TracesEngineVersion)
+			if err != nil {
+				return fmt.Errorf("%s failed to load the constraints: %w", cmdName, err)
+			}
+			version = bin.Version
 			extraFlags["cfg_checksum"] = limits.Checksum()
-			zkEvm := zkevm.FullZkEvm(&limits, cfg)
+			extraFlags["zkevm_bin_hash"] = bin.Hash()
+			extraFlags["zkevm_bin_version"] = bin.Version
+			zkEvm := zkevm.FullZkEvm(&limits, cfg, bin)
 			builder = execution.NewBuilder(zkEvm)
 
 		case circuits.BlobDecompressionV0CircuitID:
@@ -138,7 +154,7 @@ func Setup(context context.Context, args SetupArgs) error {
 			continue // dummy, aggregation, emulation or public input circuits are handled later
 		}
 
-		if err := updateSetup(context, cfg, args.Force, srsProvider, c, builder, extraFlags); err != nil {
+		if err := updateSetup(context, cfg, args.Force, srsProvider, c, version, builder, extraFlags); err != nil {
 			return err
 		}
 	}
@@ -212,7 +228,7 @@ func Setup(context context.Context, args SetupArgs) error {
 		logrus.Infof("setting up %s (numProofs=%d)", c, numProofs)
 
 		builder := aggregation.NewBuilder(numProofs, cfg.Aggregation.AllowedInputs, piSetup, allowedVkForAggregation)
-		if err := updateSetup(context, cfg, args.Force, srsProvider, c, builder, extraFlagsForAggregationCircuit); err != nil {
+		if err := updateSetup(context, cfg, args.Force, srsProvider, c, "", builder, extraFlagsForAggregationCircuit); err != nil {
 			return err
 		}
 
@@ -231,7 +247,7 @@ func Setup(context context.Context, args SetupArgs) error {
 	c := circuits.EmulationCircuitID
 	logrus.Infof("setting up %s", c)
 	builder := emulation.NewBuilder(allowedVkForEmulation)
-	return updateSetup(context, cfg, args.Force, srsProvider, c, builder, nil)
+	return updateSetup(context, cfg, args.Force, srsProvider, c, "", builder, nil)
 
 }
 
@@ -261,10 +277,11 @@ func getDummyCircuitVK(ctx context.Context, cfg *config.Config, srsProvider circ
 
 // updateSetup runs the setup for the given circuit if needed.
 // it first compiles the circuit, then checks if the files already exist,
-// and if so, if the checksums match.
+// and if so, if the checksums match. The setup is stored in the directory of
+// the version of the constraints if it is not empty.
 // if the files already exist and the checksums match, it skips the setup.
 // else it does the setup and writes the assets to disk.
-func updateSetup(ctx context.Context, cfg *config.Config, force bool, srsProvider circuits.SRSProvider, circuit circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error {
+func updateSetup(ctx context.Context, cfg *config.Config, force bool, srsProvider circuits.SRSProvider, circuit circuits.CircuitID, version string, builder circuits.Builder, extraFlags map[string]any) error {
 	if extraFlags == nil {
 		extraFlags = make(map[string]any)
 	}
@@ -277,7 +294,7 @@ func updateSetup(ctx context.Context, cfg *config.Config, force bool, srsProvide
 	}
 
 	// derive the asset paths
-	setupPath := cfg.PathForSetup(string(circuit))
+	setupPath := cfg.PathForVersionedSetup(string(circuit), version)
 	manifestPath := filepath.Join(setupPath, config.ManifestFileName)
 
 	if !force {
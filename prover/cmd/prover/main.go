@@ -2,10 +2,13 @@ package main
 
 import (
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/consensys/gnark/logger"
 	"github.com/consensys/linea-monorepo/prover/cmd/prover/cmd"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -34,12 +37,46 @@ var (
 		RunE:  cmdProve,
 	}
 	proverArgs cmd.ProverArgs
+
+	// checkLimitsCmd represents the check-limits command
+	checkLimitsCmd = &cobra.Command{
+		Use:   "check-limits",
+		Short: "estimate the traces of an execution request against the normal and the large limits without proving it",
+		RunE:  cmdCheckLimits,
+	}
+	checkLimitsArgs cmd.CheckLimitsArgs
+
+	// verifyInnerCmd represents the verify-inner command
+	verifyInnerCmd = &cobra.Command{
+		Use:   "verify-inner",
+		Short: "verify an inner-proof dumped by the execution prover",
+		RunE:  cmdVerifyInner,
+	}
+	verifyInnerArgs cmd.VerifyInnerArgs
+
+	// outerFromInnerCmd represents the outer-from-inner command
+	outerFromInnerCmd = &cobra.Command{
+		Use:   "outer-from-inner",
+		Short: "resume an execution request from its dumped inner-proof, verifies it and creates the outer proof",
+		RunE:  cmdOuterFromInner,
+	}
+	outerFromInnerArgs cmd.OuterFromInnerArgs
+
+	// serveCmd represents the serve command
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "serve proving requests over HTTP, keeping the compiled circuits and the setups in memory between requests",
+		RunE:  cmdServe,
+	}
+	serveArgs cmd.ServeArgs
 )
 
 func main() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		// The exit code reflects the classification of the error so that
+		// the controller can act on it.
+		os.Exit(exit.CodeOf(err))
 	}
 }
 
@@ -58,6 +95,7 @@ func init() {
 	setupCmd.Flags().StringVar(&setupArgs.DictPath, "dict", "", "path to the dictionary file used in blob (de)compression (for v0 only)")
 	setupCmd.Flags().IntVar(&setupArgs.DictSize, "dict-size", 65536, "size in bytes of the dictionary used in blob (de)compression")
 	setupCmd.Flags().StringVar(&setupArgs.AssetsDir, "assets-dir", "", "path to the directory where the assets are stored (override conf)")
+	setupCmd.Flags().StringVar(&setupArgs.TracesEngineVersion, "traces-engine-version", "", "traces engine version selecting the constraints of the execution circuits (default: execution.zkevm_bin)")
 
 	viper.BindPFlag("assets_dir", setupCmd.Flags().Lookup("assets-dir"))
 
@@ -66,6 +104,31 @@ func init() {
 	proveCmd.Flags().StringVar(&proverArgs.Input, "in", "", "input file")
 	proveCmd.Flags().StringVar(&proverArgs.Output, "out", "", "output file")
 	proveCmd.Flags().BoolVar(&proverArgs.Large, "large", false, "run the large execution circuit")
+
+	rootCmd.AddCommand(checkLimitsCmd)
+
+	checkLimitsCmd.Flags().StringVar(&checkLimitsArgs.Input, "in", "", "input file")
+	checkLimitsCmd.Flags().StringVar(&checkLimitsArgs.Output, "out", "", "output file of the job, the failure report is written next to it")
+	checkLimitsCmd.Flags().StringVar(&checkLimitsArgs.Report, "report", "", "file where the JSON report is written (default: stdout)")
+
+	rootCmd.AddCommand(verifyInnerCmd)
+
+	verifyInnerCmd.Flags().StringVar(&verifyInnerArgs.Inner, "inner", "", "inner-proof file")
+	verifyInnerCmd.Flags().BoolVar(&verifyInnerArgs.Large, "large", false, "verify against the large execution circuit")
+	verifyInnerCmd.Flags().StringVar(&verifyInnerArgs.TracesEngineVersion, "traces-engine-version", "", "traces engine version of the request (default: execution.zkevm_bin)")
+
+	rootCmd.AddCommand(outerFromInnerCmd)
+
+	outerFromInnerCmd.Flags().StringVar(&outerFromInnerArgs.Input, "in", "", "input file")
+	outerFromInnerCmd.Flags().StringVar(&outerFromInnerArgs.Output, "out", "", "output file")
+	outerFromInnerCmd.Flags().StringVar(&outerFromInnerArgs.Inner, "inner", "", "inner-proof file (default: the file dumped in execution.inner_proof_dir)")
+	outerFromInnerCmd.Flags().BoolVar(&outerFromInnerArgs.Large, "large", false, "run the large execution circuit")
+
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveArgs.Addr, "addr", ":8080", "address on which the server listens")
+	serveCmd.Flags().BoolVar(&serveArgs.Large, "large", false, "prove the execution requests with the large execution circuit")
+	serveCmd.Flags().BoolVar(&serveArgs.WarmUp, "warm-up", true, "compile the circuits and load the setups before accepting requests")
 }
 
 func cmdSetup(_cmd *cobra.Command, _ []string) error {
@@ -78,6 +141,28 @@ func cmdProve(*cobra.Command, []string) error {
 	return cmd.Prove(proverArgs)
 }
 
+func cmdCheckLimits(*cobra.Command, []string) error {
+	checkLimitsArgs.ConfigFile = fConfigFile
+	return cmd.CheckLimits(checkLimitsArgs)
+}
+
+func cmdVerifyInner(*cobra.Command, []string) error {
+	verifyInnerArgs.ConfigFile = fConfigFile
+	return cmd.VerifyInner(verifyInnerArgs)
+}
+
+func cmdOuterFromInner(*cobra.Command, []string) error {
+	outerFromInnerArgs.ConfigFile = fConfigFile
+	return cmd.OuterFromInner(outerFromInnerArgs)
+}
+
+func cmdServe(_cmd *cobra.Command, _ []string) error {
+	serveArgs.ConfigFile = fConfigFile
+	ctx, stop := signal.NotifyContext(_cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return cmd.Serve(ctx, serveArgs)
+}
+
 // allCircuitList returns the list [cmd.AllCircuits] where the circuit id
 // are converted into strings.
 func allCircuitList() []string {
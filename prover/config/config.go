@@ -56,6 +56,12 @@ func NewConfigFromFile(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse worker_cmd_large template: %w", err)
 	}
+	if len(cfg.Controller.CheckLimitsCmd) > 0 {
+		cfg.Controller.CheckLimitsCmdTmpl, err = template.New("check_limits_cmd").Parse(cfg.Controller.CheckLimitsCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse check_limits_cmd template: %w", err)
+		}
+	}
 
 	// Set the logging level
 	logrus.SetLevel(logrus.Level(cfg.LogLevel)) // #nosec G115 -- overflow not possible (uint8 -> uint32)
@@ -67,9 +73,15 @@ func NewConfigFromFile(path string) (*Config, error) {
 	}
 	cfg.Layer2.MsgSvcContract = addr.Address()
 
-	// ensure that asset dir / kzgsrs exists using os.Stat
+	// ensure that asset dir / kzgsrs exists using os.Stat. When the assets
+	// are fetched from a remote store, the directory is only a cache and is
+	// created as needed.
 	srsDir := cfg.PathForSRS()
-	if _, err := os.Stat(srsDir); os.IsNotExist(err) {
+	if len(cfg.RemoteAssets.URL) > 0 {
+		if err := os.MkdirAll(srsDir, 0755); err != nil {
+			return nil, fmt.Errorf("could not create the kzgsrs directory (%s): %w", srsDir, err)
+		}
+	} else if _, err := os.Stat(srsDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("kzgsrs directory (%s) does not exist: %w", srsDir, err)
 	}
 
@@ -107,6 +119,10 @@ type Config struct {
 	// accessed (prover). The file structure is described in TODO @gbotrel.
 	AssetsDir string `mapstructure:"assets_dir" validate:"required,dir"`
 
+	// RemoteAssets optionally configures a remote store from which the SRS
+	// and the setups are fetched into AssetsDir when they are missing.
+	RemoteAssets RemoteAssets `mapstructure:"remote_assets"`
+
 	Controller                 Controller
 	Execution                  Execution
 	BlobDecompression          BlobDecompression `mapstructure:"blob_decompression"`
@@ -152,11 +168,45 @@ func (cfg *Config) PathForSetup(circuitID string) string {
 	return path.Join(cfg.AssetsDir, cfg.Version, cfg.Environment, circuitID)
 }
 
+// PathForVersionedSetup returns the path to the setup directory for the given
+// circuitID and version of the constraints, e.g.
+// .../prover-assets/0.1.0/mainnet/execution/v1.2.3. The version is the traces
+// engine version selecting the constraints in [Execution.ZkevmBinDir]. Without
+// a version, this is the path of [Config.PathForSetup].
+func (cfg *Config) PathForVersionedSetup(circuitID, version string) string {
+	return path.Join(cfg.PathForSetup(circuitID), version)
+}
+
 // PathForSRS returns the path to the SRS directory.
 func (cfg *Config) PathForSRS() string {
 	return path.Join(cfg.AssetsDir, "kzgsrs")
 }
 
+// RemoteAssets describes a remote store holding the assets of the prover. The
+// store mirrors the layout of the assets directory: the setup of a circuit is
+// found at "<url>/<version>/<environment>/<circuit>/" and the SRS files at
+// "<url>/kzgsrs/", along with a "SHA256SUMS" file listing their checksums. The
+// setups generated for a version of the constraints are in a subdirectory of
+// the circuit named after the version.
+// The files of the setups are checked against the checksums of their
+// manifest.
+type RemoteAssets struct {
+	// URL is the base URL of the store, e.g. "https://assets.example.com/prover"
+	// or, for an S3-compatible store, the path-style URL of the bucket:
+	// "https://s3.eu-west-1.amazonaws.com/my-bucket/prover". Empty disables
+	// the remote store.
+	URL string `mapstructure:"url" validate:"omitempty,url"`
+
+	// S3Region is the region used to sign the requests with AWS signature
+	// V4. The credentials are read from the AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+	// Empty means that the requests are not signed.
+	S3Region string `mapstructure:"s3_region"`
+
+	// Timeout of a single download in seconds. 0 means no timeout.
+	Timeout int `mapstructure:"timeout" validate:"gte=0"`
+}
+
 type Controller struct {
 	// The unique id of this process. Must be unique between all workers. This
 	// field is not to be populated by the toml configuration file. It is to be
@@ -166,6 +216,10 @@ type Controller struct {
 	// Prometheus stores the configuration for the Prometheus metrics server.
 	Prometheus Prometheus
 
+	// JobSource stores the configuration of the backend through which the
+	// controller discovers and locks the jobs to process.
+	JobSource JobSource `mapstructure:"job_source"`
+
 	// The delays at which we retry when we find no files in the queue. If this
 	// is set to [0, 1, 2, 3, 4, 5]. It will retry after 0 sec the first time it
 	// cannot find a file in the queue, 1 sec the second time and so on. Once it
@@ -173,11 +227,28 @@ type Controller struct {
 	RetryDelays []int `mapstructure:"retry_delays"`
 
 	// List of exit codes for which the job will put back the job to be reexecuted in large mode.
+	// The code 81 (oom-risk) is not deferred by default, it can be added to
+	// hand over to a large prover the jobs that a machine refused to start for
+	// lack of memory.
 	DeferToOtherLargeCodes []int `mapstructure:"defer_to_other_large_codes"`
 
 	// List of exit codes for which the job will retry in large mode
 	RetryLocallyWithLargeCodes []int `mapstructure:"retry_locally_with_large_codes"`
 
+	// List of exit codes for which an execution job is run again with the same
+	// command, before any retry in large mode, so that the prover resumes from
+	// its last checkpoint. Only used when [Execution.CheckpointDir] is set.
+	RetryLocallyFromCheckpointCodes []int `mapstructure:"retry_locally_from_checkpoint_codes"`
+
+	// MaxConcurrentJobs is the maximal number of jobs that the controller runs
+	// in parallel. Defaults to 1.
+	MaxConcurrentJobs int `mapstructure:"max_concurrent_jobs" validate:"gte=0"`
+
+	// Resources declares the resources that the controller can hand out to
+	// its jobs and the share reserved by each type of job. A job is only
+	// locked if the resources it requires are available.
+	Resources Resources `mapstructure:"resources"`
+
 	// defaults to true; the controller will not pick associated jobs if false.
 	EnableExecution         bool `mapstructure:"enable_execution"`
 	EnableBlobDecompression bool `mapstructure:"enable_blob_decompression"`
@@ -189,6 +260,54 @@ type Controller struct {
 	WorkerCmdLarge     string             `mapstructure:"worker_cmd_large_tmpl"`
 	WorkerCmdTmpl      *template.Template `mapstructure:"-"`
 	WorkerCmdLargeTmpl *template.Template `mapstructure:"-"`
+
+	// CheckLimitsCmd is the command estimating the traces of an execution job
+	// against the limits before proving it, for instance "prover check-limits
+	// --config {{.ConfFile}} --in {{.InFile}} --out {{.OutFile}}". The job is
+	// then directly proven in large mode or rejected depending on the exit
+	// code. The check is skipped if empty, which is the default.
+	CheckLimitsCmd     string             `mapstructure:"check_limits_cmd_tmpl"`
+	CheckLimitsCmdTmpl *template.Template `mapstructure:"-"`
+}
+
+type Resources struct {
+	// Total amount of resources available on the machine for the jobs. A zero
+	// field means that the corresponding resource is not limited.
+	Total ResourceBudget `mapstructure:"total"`
+
+	// Resources reserved by each type of job for the whole duration of the
+	// job. For execution, the budget should account for a local retry in
+	// large mode.
+	Execution         ResourceBudget `mapstructure:"execution"`
+	BlobDecompression ResourceBudget `mapstructure:"blob_decompression"`
+	Aggregation       ResourceBudget `mapstructure:"aggregation"`
+}
+
+type ResourceBudget struct {
+	// MemoryGiB is an amount of memory in GiB
+	MemoryGiB int `mapstructure:"memory_gib" validate:"gte=0"`
+	// CPUs is a number of cores. When set in a job budget, it is also passed
+	// to the worker process as GOMAXPROCS.
+	CPUs int `mapstructure:"cpus" validate:"gte=0"`
+}
+
+// Names of the supported job-source backends
+const (
+	JobSourceFilesystem = "fs"
+	JobSourceHTTP       = "http"
+)
+
+type JobSource struct {
+	// Backend selects how the jobs are discovered. "fs" (the default) polls
+	// the requests directories and locks the files by renaming them. "http"
+	// delegates the listing and the locking to a queue service.
+	Backend string `mapstructure:"backend" validate:"omitempty,oneof=fs http"`
+	// URL is the base URL of the queue service. Only used by the "http"
+	// backend.
+	URL string `mapstructure:"url" validate:"required_if=Backend http"`
+	// Timeout, in seconds, applied to every request made to the queue
+	// service. Defaults to 30 seconds.
+	Timeout int `mapstructure:"timeout" validate:"gte=0"`
 }
 
 type Prometheus struct {
@@ -218,6 +337,42 @@ type Execution struct {
 	// used within the prover was generated from the same commit of linea-constraints as the generated lt trace file.
 	// Set this to true to disable compatibility checks (default: false).
 	IgnoreCompatibilityCheck bool `mapstructure:"ignore_compatibility_check"`
+
+	// ZkevmBin is the path of the constraint binary ("zkevm.bin") compiled
+	// into the arithmetization. Empty means that the prover uses the binary
+	// embedded at build time.
+	ZkevmBin string `mapstructure:"zkevm_bin"`
+
+	// ZkevmBinDir is a directory holding one constraint binary per version of
+	// the traces engine, laid out as "<dir>/<tracesEngineVersion>/zkevm.bin".
+	// When set, the binary is selected from the "tracesEngineVersion" field of
+	// the request, and [Execution.ZkevmBin] is only used for the requests not
+	// specifying it.
+	ZkevmBinDir string `mapstructure:"zkevm_bin_dir"`
+
+	// InnerProofDir is an optional directory where the full and the bench
+	// provers dump the inner-proof of every request, so that the outer-proof
+	// can be resumed with the `outer-from-inner` command if it fails. The
+	// files are named after the conflated traces file of the request. Leave
+	// empty to disable.
+	InnerProofDir string `mapstructure:"inner_proof_dir"`
+
+	// CheckpointDir is an optional directory where the full and the bench
+	// provers checkpoint the inner prover at the end of every round. A job
+	// that is interrupted and run again resumes from its last checkpoint
+	// instead of restarting from scratch. The checkpoint of a job is removed
+	// once its inner-proof is generated. The directory should be on a volume
+	// that survives the machine for the checkpoints to be useful on spot
+	// instances. Leave empty to disable.
+	CheckpointDir string `mapstructure:"checkpoint_dir"`
+
+	// ViolationReportDir is an optional directory where the check-only and
+	// the partial provers report the constraints that a rejected trace does
+	// not satisfy: the offending rows and the values of the columns involved,
+	// as a JSON file and as CSV files readable by the csvtraces package. The
+	// report of a rejected trace replaces the previous one. Leave empty to
+	// only report in the error.
+	ViolationReportDir string `mapstructure:"violation_report_dir"`
 }
 
 type BlobDecompression struct {
@@ -3,8 +3,9 @@ package config
 import "github.com/spf13/viper"
 
 var (
-	DefaultDeferToOtherLargeCodes     = []int{137}     // List of exit codes for which the job will put back the job to be reexecuted in large mode.
-	DefaultRetryLocallyWithLargeCodes = []int{77, 333} // List of exit codes for which the job will retry in large mode
+	DefaultDeferToOtherLargeCodes          = []int{137}     // List of exit codes for which the job will put back the job to be reexecuted in large mode.
+	DefaultRetryLocallyWithLargeCodes      = []int{77, 333} // List of exit codes for which the job will retry in large mode
+	DefaultRetryLocallyFromCheckpointCodes = []int{}        // List of exit codes for which the job will resume from its checkpoint, none by default
 )
 
 func setDefaultValues() {
@@ -18,10 +19,16 @@ func setDefaultValues() {
 	viper.SetDefault("controller.enable_blob_decompression", true)
 	viper.SetDefault("controller.enable_aggregation", true)
 
+	viper.SetDefault("controller.max_concurrent_jobs", 1)
+
+	viper.SetDefault("controller.job_source.backend", JobSourceFilesystem)
+	viper.SetDefault("controller.job_source.timeout", 30)
+
 	// Set the default values for the retry delays
 	viper.SetDefault("controller.retry_delays", []int{0, 1, 2, 3, 5, 8, 13, 21, 44, 85})
 	viper.SetDefault("controller.defer_to_other_large_codes", DefaultDeferToOtherLargeCodes)
 	viper.SetDefault("controller.retry_locally_with_large_codes", DefaultRetryLocallyWithLargeCodes)
+	viper.SetDefault("controller.retry_locally_from_checkpoint_codes", DefaultRetryLocallyFromCheckpointCodes)
 
 	// Set default for cmdTmpl and cmdLargeTmpl
 	// TODO @gbotrel binary to run prover is hardcoded here.
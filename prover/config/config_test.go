@@ -58,3 +58,24 @@ func TestEnvironment(t *testing.T) {
 
 	assert.NotEqual(0, count, "no config file found")
 }
+
+func TestTracesLimitsGet(t *testing.T) {
+	assert := require.New(t)
+
+	tl := TracesLimits{Hub: 1 << 21, BlockKeccak: 8192}
+	names := TracesLimitNames()
+
+	assert.Equal("ADD", names[0])
+	assert.Contains(names, "SHOMEI_MERKLE_PROOFS")
+
+	hub, ok := tl.Get("HUB")
+	assert.True(ok)
+	assert.Equal(1<<21, hub)
+
+	keccak, ok := tl.Get("BLOCK_KECCAK")
+	assert.True(ok)
+	assert.Equal(8192, keccak)
+
+	_, ok = tl.Get("hub")
+	assert.False(ok)
+}
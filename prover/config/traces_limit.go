@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
 
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
@@ -82,3 +83,37 @@ func (tl *TracesLimits) Checksum() string {
 
 	return digest
 }
+
+// TracesLimitNames returns the names of the limits as they appear in the
+// configuration file (e.g. "PRECOMPILE_SHA2_BLOCKS"), in declaration order.
+func TracesLimitNames() []string {
+
+	var (
+		typ = reflect.TypeOf(TracesLimits{})
+		res = make([]string, typ.NumField())
+	)
+
+	for i := range res {
+		res[i] = typ.Field(i).Tag.Get("mapstructure")
+	}
+
+	return res
+}
+
+// Get returns the limit from its name in the configuration file. The boolean
+// is false if there is no such limit.
+func (tl *TracesLimits) Get(name string) (int, bool) {
+
+	var (
+		val = reflect.ValueOf(tl).Elem()
+		typ = val.Type()
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("mapstructure") == name {
+			return val.Field(i).Interface().(int), true
+		}
+	}
+
+	return 0, false
+}
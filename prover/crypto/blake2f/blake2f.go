@@ -0,0 +1,153 @@
+// Package blake2f implements the BLAKE2b compression function F as specified
+// in EIP-152. Unlike the standard library, the package exposes the
+// intermediate states of the compression function so that they can be used to
+// generate the witness of the BLAKE2f module of the zkEVM.
+package blake2f
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// InputSizeByte is the size of the input of the BLAKE2f precompile
+	InputSizeByte = 213
+	// OutputSizeByte is the size of the output of the BLAKE2f precompile
+	OutputSizeByte = 64
+)
+
+// IV is the initialization vector of BLAKE2b
+var IV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b,
+	0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f,
+	0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// Sigma lists the permutations of the message words used by the rounds of
+// BLAKE2b. Round i uses Sigma[i % 10].
+var Sigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// Input represents a call to the BLAKE2f precompile
+type Input struct {
+	Rounds uint32
+	H      [8]uint64
+	M      [16]uint64
+	T      [2]uint64
+	F      bool
+}
+
+// State is the working vector of the compression function
+type State = [16]uint64
+
+// ParseInput parses the 213 bytes input of the precompile. It returns false
+// if the input is malformed: i.e. if it does not have the right size or if the
+// final block indicator is not 0 or 1.
+func ParseInput(b []byte) (Input, bool) {
+
+	var in Input
+
+	if len(b) != InputSizeByte || b[212] > 1 {
+		return in, false
+	}
+
+	in.Rounds = binary.BigEndian.Uint32(b[0:4])
+	for i := range in.H {
+		in.H[i] = binary.LittleEndian.Uint64(b[4+8*i:])
+	}
+	for i := range in.M {
+		in.M[i] = binary.LittleEndian.Uint64(b[68+8*i:])
+	}
+	in.T[0] = binary.LittleEndian.Uint64(b[196:])
+	in.T[1] = binary.LittleEndian.Uint64(b[204:])
+	in.F = b[212] == 1
+
+	return in, true
+}
+
+// Init returns the working vector before the first round
+func Init(h [8]uint64, t [2]uint64, f bool) (v State) {
+
+	copy(v[:8], h[:])
+	copy(v[8:], IV[:])
+	v[12] ^= t[0]
+	v[13] ^= t[1]
+	if f {
+		v[14] = ^v[14]
+	}
+
+	return v
+}
+
+// Round applies the i-th round of the compression function on the working
+// vector.
+func Round(v *State, m *[16]uint64, i int) {
+
+	s := &Sigma[i%10]
+
+	g(v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+	g(v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+	g(v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+	g(v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+	g(v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+	g(v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+	g(v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+	g(v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+}
+
+// Finalize returns the new state of the hasher from the previous state and the
+// working vector after the last round.
+func Finalize(h [8]uint64, v State) (res [8]uint64) {
+	for i := range res {
+		res[i] = h[i] ^ v[i] ^ v[i+8]
+	}
+	return res
+}
+
+// Compress runs the compression function F. If the optional tracer is
+// provided, the function appends to it the working vectors obtained after
+// each round.
+func Compress(in Input, optTracer *[]State) [8]uint64 {
+
+	v := Init(in.H, in.T, in.F)
+
+	for i := 0; i < int(in.Rounds); i++ {
+		Round(&v, &in.M, i)
+		if optTracer != nil {
+			*optTracer = append(*optTracer, v)
+		}
+	}
+
+	return Finalize(in.H, v)
+}
+
+// EncodeOutput returns the output of the precompile for the given hasher state
+func EncodeOutput(h [8]uint64) (res [OutputSizeByte]byte) {
+	for i := range h {
+		binary.LittleEndian.PutUint64(res[8*i:], h[i])
+	}
+	return res
+}
+
+// g is the mixing function of BLAKE2b
+func g(v *State, a, b, c, d int, x, y uint64) {
+	v[a] = v[a] + v[b] + x
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = v[a] + v[b] + y
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
@@ -0,0 +1,95 @@
+package blake2f
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// TestEIP152Vectors checks the test vectors 4 to 7 of EIP-152
+func TestEIP152Vectors(t *testing.T) {
+
+	const (
+		h = "48c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b"
+		m = "616263" + "0000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000000000000000000000000000000000000000"
+		tt = "03000000000000000000000000000000"
+	)
+
+	testCases := []struct {
+		rounds, f, expected string
+	}{
+		{
+			rounds:   "00000000",
+			f:        "01",
+			expected: "08c9bcf367e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d282e6ad7f520e511f6c3e2b8c68059b9442be0454267ce079217e1319cde05b",
+		},
+		{
+			rounds:   "0000000c",
+			f:        "01",
+			expected: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		},
+		{
+			rounds:   "0000000c",
+			f:        "00",
+			expected: "75ab69d3190a562c51aef8d88f1c2775876944407270c42c9844252c26d2875298743e7f6d5ea2f2d3e8d226039cd31b4e426ac4f2d3d666a610c2116fde4735",
+		},
+		{
+			rounds:   "00000001",
+			f:        "01",
+			expected: "b63a380cb2897d521994a85234ee2c181b5f844d2c624c002677e9703449d2fba551b3a8333bcdf5f2f7e08993d53923de3d64fcc68c034e717b9293fed7a421",
+		},
+	}
+
+	for _, tc := range testCases {
+
+		b, err := hex.DecodeString(tc.rounds + h + m + tt + tc.f)
+		require.NoError(t, err)
+
+		in, ok := ParseInput(b)
+		require.True(t, ok)
+
+		out := EncodeOutput(Compress(in, nil))
+		assert.Equal(t, tc.expected, hex.EncodeToString(out[:]), "rounds=%v f=%v", tc.rounds, tc.f)
+	}
+}
+
+// TestAgainstBlake2b checks that 12 rounds of F over a single final block
+// computes BLAKE2b-512.
+func TestAgainstBlake2b(t *testing.T) {
+
+	// #nosec G404 -- we don't need a cryptographic PRNG for testing purposes
+	rng := rand.New(rand.NewChaCha8([32]byte{}))
+
+	for size := 1; size <= 128; size++ {
+
+		var (
+			msg    = make([]byte, size)
+			block  = [128]byte{}
+			traces = []State{}
+			in     = Input{Rounds: 12, H: IV, T: [2]uint64{uint64(size), 0}, F: true}
+		)
+
+		utils.ReadPseudoRand(rng, msg)
+		copy(block[:], msg)
+
+		// parameter block: digest length = 64, key length = 0, fanout = 1,
+		// depth = 1
+		in.H[0] ^= 0x01010040
+		for i := range in.M {
+			in.M[i] = binary.LittleEndian.Uint64(block[8*i:])
+		}
+
+		out := EncodeOutput(Compress(in, &traces))
+		assert.Equal(t, blake2b.Sum512(msg), out, "size=%v", size)
+		assert.Len(t, traces, 12)
+	}
+}
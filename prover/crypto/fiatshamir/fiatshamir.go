@@ -5,6 +5,7 @@ import (
 
 	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
@@ -14,7 +15,8 @@ import (
 // providing field elements and can be consumed to generate either field
 // elements or sequences of small integers. The Fiat-Shamir instantiation relies
 // on the MiMC hash function and uses the following strategy for generating
-// random public coins:
+// random public coins (the Poseidon2 hash function can be used instead, see
+// [NewPoseidon2FiatShamir]):
 //
 //   - The messages are appended to the hasher as it is received by the Fiat-
 //     Shamir state, field element by field element
@@ -43,8 +45,16 @@ func NewMiMCFiatShamir() *State {
 	}
 }
 
+// NewPoseidon2FiatShamir constructs a fresh and empty Fiat-Shamir state using
+// the Poseidon2 hash function in place of MiMC.
+func NewPoseidon2FiatShamir() *State {
+	return &State{
+		hasher: poseidon2.NewPoseidon2(),
+	}
+}
+
 // State returns the internal state of the Fiat-Shamir hasher. Only works for
-// MiMC.
+// MiMC and Poseidon2.
 func (s *State) State() []field.Element {
 	_ = s.hasher.Sum(nil)
 	b := s.hasher.State()
@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/gnark/std/hash"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc/gkrmimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 )
@@ -52,6 +53,15 @@ func NewGnarkFiatShamir(api frontend.API, factory *gkrmimc.HasherFactory) *Gnark
 	}
 }
 
+// NewGnarkFiatShamirPoseidon2 creates a [GnarkFiatShamir] object mirroring
+// the [State] returned by [NewPoseidon2FiatShamir].
+func NewGnarkFiatShamirPoseidon2(api frontend.API) *GnarkFiatShamir {
+	return &GnarkFiatShamir{
+		hasher: poseidon2.NewGnarkHasher(api),
+		api:    api,
+	}
+}
+
 // SetState mutates the fiat-shamir state of
 func (fs *GnarkFiatShamir) SetState(state []frontend.Variable) {
 
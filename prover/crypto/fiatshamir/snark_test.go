@@ -82,3 +82,31 @@ func TestGnarkUpdateVec(t *testing.T) {
 
 	gnarkutil.AssertCircuitSolved(t, f)
 }
+
+func TestGnarkFiatShamirPoseidon2(t *testing.T) {
+
+	f := func(api frontend.API) error {
+		fs := NewPoseidon2FiatShamir()
+		fs.UpdateVec(vector.ForTest(2, 2, 1, 2))
+		y1 := fs.RandomField()
+		a1 := fs.RandomManyIntegers(8, 1<<10)
+
+		fs2 := NewGnarkFiatShamirPoseidon2(api)
+		fs2.UpdateVec([]frontend.Variable{2, 2, 1, 2})
+		y2 := fs2.RandomField()
+		a2 := fs2.RandomManyIntegers(8, 1<<10)
+
+		api.AssertIsEqual(y1, y2)
+		for i := range a1 {
+			api.AssertIsEqual(a1[i], a2[i])
+		}
+
+		// The transcript differs from the one of MiMC
+		fs3 := NewMiMCFiatShamir()
+		fs3.UpdateVec(vector.ForTest(2, 2, 1, 2))
+		api.AssertIsDifferent(y1, fs3.RandomField())
+		return nil
+	}
+
+	gnarkutil.AssertCircuitSolved(t, f)
+}
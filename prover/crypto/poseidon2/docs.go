@@ -0,0 +1,24 @@
+// poseidon2 implements the Poseidon2 permutation over the scalar field of
+// BLS12-377 with a state of two field elements, and a compression function
+// built on top of it which is a drop-in replacement of the MiMC block
+// compression of the [github.com/consensys/linea-monorepo/prover/crypto/mimc]
+// package. The package provides a native hasher, gnark gadgets and low-level
+// utility methods that are used in the wizard package to arithmetize the
+// compression function.
+//
+// The instance has a width t=2, rF=6 full rounds, rP=26 partial rounds and
+// the s-box x^17. The round keys are derived from the seed
+// "Poseidon2-BLS12_377[t=2,rF=6,rP=26,d=17]" by a Keccak chain. A width of two
+// is what allows the compression function to take the same (state, block)
+// inputs as MiMC.
+//
+// The package does not use the poseidon2 package of gnark-crypto and its
+// outputs differ from it, so the two must not be mixed. In the version of
+// gnark-crypto used by the prover, InitRC writes the round keys of the
+// partial rounds and of the last full rounds from index 0, overwriting the
+// first rounds and leaving the last ones empty, and the external matrix is
+// wrongly computed for t > 4. The keys are derived here with the same Keccak
+// chain but each key is stored at its own round.
+//
+// https://eprint.iacr.org/2023/323.pdf
+package poseidon2
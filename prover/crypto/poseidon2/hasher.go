@@ -0,0 +1,163 @@
+package poseidon2
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// blockSize is the number of bytes of a block of the hasher
+const blockSize = field.Bytes
+
+// digest implements [hash.StateStorer] by applying [BlockCompression] to the
+// written field elements in Merkle-Damgård mode, starting from a zero state.
+// It mirrors the MiMC hasher of gnark-crypto.
+type digest struct {
+	h    field.Element
+	data []field.Element
+}
+
+// NewPoseidon2 returns a Poseidon2 hasher. The hasher expects the written
+// bytes to be a sequence of big-endian encoded field elements.
+func NewPoseidon2() hash.StateStorer {
+	return &digest{}
+}
+
+// Reset resets the hasher to its initial state
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+	d.h = field.Zero()
+}
+
+// Sum appends the current hash to b and returns the resulting slice. As for
+// MiMC, the written data is flushed in the state of the hasher.
+func (d *digest) Sum(b []byte) []byte {
+	d.flush()
+	h := d.h.Bytes()
+	return append(b, h[:]...)
+}
+
+// Size returns the number of bytes returned by Sum
+func (d *digest) Size() int {
+	return blockSize
+}
+
+// BlockSize returns the block size of the hasher
+func (d *digest) BlockSize() int {
+	return blockSize
+}
+
+// Write adds more data to the running hash. Each block of [field.Bytes] bytes
+// must be the canonical big-endian encoding of a field element. As for MiMC,
+// inputs shorter than a block are left-padded.
+func (d *digest) Write(p []byte) (int, error) {
+
+	if len(p) > 0 && len(p) < blockSize {
+		pp := make([]byte, blockSize)
+		copy(pp[blockSize-len(p):], p)
+		p = pp
+	}
+
+	if len(p)%blockSize != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+
+	for start := 0; start < len(p); start += blockSize {
+		var x field.Element
+		if err := x.SetBytesCanonical(p[start : start+blockSize]); err != nil {
+			return 0, err
+		}
+		d.data = append(d.data, x)
+	}
+
+	return len(p), nil
+}
+
+// State returns the internal state of the hasher after flushing the written
+// data.
+func (d *digest) State() []byte {
+	d.flush()
+	b := d.h.Bytes()
+	return b[:]
+}
+
+// SetState sets the state of the hasher. It expects the 32 bytes encoding of
+// a field element.
+func (d *digest) SetState(newState []byte) error {
+
+	if len(newState) != blockSize {
+		return errors.New("the poseidon2 state expects a state of 32 bytes")
+	}
+
+	if err := d.h.SetBytesCanonical(newState); err != nil {
+		return errors.New("the provided newState does not represent a valid state")
+	}
+
+	d.data = nil
+	return nil
+}
+
+// flush compresses the written data in the state
+func (d *digest) flush() {
+	for i := range d.data {
+		d.h = BlockCompression(d.h, d.data[i])
+	}
+	d.data = nil
+}
+
+// GnarkHasher mirrors the hasher returned by [NewPoseidon2] in a gnark
+// circuit. It implements the [github.com/consensys/gnark/std/hash.StateStorer]
+// interface.
+type GnarkHasher struct {
+	api  frontend.API
+	h    frontend.Variable
+	data []frontend.Variable
+}
+
+// NewGnarkHasher returns a [GnarkHasher] in its initial state
+func NewGnarkHasher(api frontend.API) *GnarkHasher {
+	return &GnarkHasher{api: api, h: 0}
+}
+
+// Write adds more data to the running hash
+func (h *GnarkHasher) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the hasher to its initial state
+func (h *GnarkHasher) Reset() {
+	h.data = nil
+	h.h = 0
+}
+
+// Sum flushes the written data in the state of the hasher and returns it
+func (h *GnarkHasher) Sum() frontend.Variable {
+	for _, x := range h.data {
+		h.h = GnarkBlockCompression(h.api, h.h, x)
+	}
+	h.data = nil
+	return h.h
+}
+
+// State returns the internal state of the hasher after flushing the written
+// data.
+func (h *GnarkHasher) State() []frontend.Variable {
+	return []frontend.Variable{h.Sum()}
+}
+
+// SetState sets the state of the hasher. It expects a single variable.
+func (h *GnarkHasher) SetState(newState []frontend.Variable) error {
+
+	if len(h.data) > 0 {
+		return errors.New("the hasher is not in an initial state")
+	}
+
+	if len(newState) != 1 {
+		return errors.New("the Poseidon2 hasher expects a single field element to represent the state")
+	}
+
+	h.h = newState[0]
+	return nil
+}
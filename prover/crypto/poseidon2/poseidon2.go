@@ -0,0 +1,165 @@
+package poseidon2
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Width is the number of field elements in the state of the permutation
+	Width = 2
+	// NbFullRounds is the number of full rounds of the permutation. Half of
+	// them are applied before the partial rounds and the other half after.
+	NbFullRounds = 6
+	// NbPartialRounds is the number of partial rounds of the permutation
+	NbPartialRounds = 26
+	// seed is hashed with Keccak to derive the round keys
+	seed = "Poseidon2-BLS12_377[t=2,rF=6,rP=26,d=17]"
+)
+
+var (
+	// ExternalMatrix is the matrix of the linear layer of the full rounds. It
+	// is also applied on the input of the permutation.
+	ExternalMatrix = [Width][Width]int{{2, 1}, {1, 2}}
+	// InternalMatrix is the matrix of the linear layer of the partial rounds
+	InternalMatrix = [Width][Width]int{{2, 1}, {1, 3}}
+)
+
+// RoundKeys collects the round keys of the permutation, round by round. The
+// full rounds have one key per element of the state and the partial rounds
+// have a single key, added to the first element of the state.
+var RoundKeys [][]field.Element = func() [][]field.Element {
+
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write([]byte(seed))
+	rnd := hasher.Sum(nil)
+
+	res := make([][]field.Element, NbFullRounds+NbPartialRounds)
+	for r := range res {
+		numKeys := Width
+		if IsPartialRound(r) {
+			numKeys = 1
+		}
+
+		res[r] = make([]field.Element, numKeys)
+		for i := range res[r] {
+			hasher.Reset()
+			hasher.Write(rnd)
+			rnd = hasher.Sum(nil)
+			res[r][i].SetBytes(rnd)
+		}
+	}
+
+	return res
+}()
+
+// IsPartialRound returns true if the round r of the permutation is a partial
+// round.
+func IsPartialRound(r int) bool {
+	return r >= NbFullRounds/2 && r < NbFullRounds/2+NbPartialRounds
+}
+
+// Permutation applies the Poseidon2 permutation to the state
+func Permutation(state [Width]field.Element) [Width]field.Element {
+
+	matMulExternal(&state)
+
+	for r := range RoundKeys {
+		for i := range RoundKeys[r] {
+			state[i].Add(&state[i], &RoundKeys[r][i])
+			state[i] = SBox(state[i])
+		}
+
+		if IsPartialRound(r) {
+			matMulInternal(&state)
+		} else {
+			matMulExternal(&state)
+		}
+	}
+
+	return state
+}
+
+// BlockCompression applies the Poseidon2 compression function to a given
+// block over a given state. The compression is obtained by permuting the pair
+// (oldState, block) and by adding the block to the second element of the
+// result (feed-forward). It mirrors the signature of the MiMC block
+// compression function so that the two can be swapped.
+func BlockCompression(oldState, block field.Element) (newState field.Element) {
+	res := Permutation([Width]field.Element{oldState, block})
+	return *res[1].Add(&res[1], &block)
+}
+
+// HashVec hashes a vector of field elements
+func HashVec(v []field.Element) (h field.Element) {
+	for i := range v {
+		h = BlockCompression(h, v[i])
+	}
+	return h
+}
+
+// SBox returns x^17
+func SBox(x field.Element) field.Element {
+	var res field.Element
+	res.Square(&x).Square(&res).Square(&res).Square(&res).Mul(&res, &x)
+	return res
+}
+
+// matMulExternal multiplies the state by [ExternalMatrix]
+func matMulExternal(state *[Width]field.Element) {
+	var sum field.Element
+	sum.Add(&state[0], &state[1])
+	state[0].Add(&state[0], &sum)
+	state[1].Add(&state[1], &sum)
+}
+
+// matMulInternal multiplies the state by [InternalMatrix]
+func matMulInternal(state *[Width]field.Element) {
+	var sum field.Element
+	sum.Add(&state[0], &state[1])
+	state[0].Add(&state[0], &sum)
+	state[1].Double(&state[1]).Add(&state[1], &sum)
+}
+
+// GnarkPermutation applies the Poseidon2 permutation within a gnark circuit
+// and mirrors exactly [Permutation].
+func GnarkPermutation(api frontend.API, state [Width]frontend.Variable) [Width]frontend.Variable {
+
+	state = gnarkMatMul(api, ExternalMatrix, state)
+
+	for r := range RoundKeys {
+		for i := range RoundKeys[r] {
+			x := api.Add(state[i], RoundKeys[r][i])
+			x2 := api.Mul(x, x)
+			x4 := api.Mul(x2, x2)
+			x8 := api.Mul(x4, x4)
+			x16 := api.Mul(x8, x8)
+			state[i] = api.Mul(x16, x)
+		}
+
+		if IsPartialRound(r) {
+			state = gnarkMatMul(api, InternalMatrix, state)
+		} else {
+			state = gnarkMatMul(api, ExternalMatrix, state)
+		}
+	}
+
+	return state
+}
+
+// GnarkBlockCompression applies the Poseidon2 compression function to a given
+// block within a gnark circuit and mirrors exactly [BlockCompression].
+func GnarkBlockCompression(api frontend.API, oldState, block frontend.Variable) (newState frontend.Variable) {
+	res := GnarkPermutation(api, [Width]frontend.Variable{oldState, block})
+	return api.Add(res[1], block)
+}
+
+// gnarkMatMul multiplies the state by a matrix within a gnark circuit
+func gnarkMatMul(api frontend.API, m [Width][Width]int, state [Width]frontend.Variable) [Width]frontend.Variable {
+	var res [Width]frontend.Variable
+	for i := range m {
+		res[i] = api.Add(api.Mul(m[i][0], state[0]), api.Mul(m[i][1], state[1]))
+	}
+	return res
+}
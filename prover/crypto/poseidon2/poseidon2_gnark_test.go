@@ -0,0 +1,57 @@
+package poseidon2_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/require"
+)
+
+// circuit
+type Circuit struct {
+	Block frontend.Variable
+	Old   frontend.Variable
+	New   frontend.Variable
+	Vec   [3]frontend.Variable
+	Hash  frontend.Variable
+}
+
+func (circuit *Circuit) Define(api frontend.API) error {
+	res := poseidon2.GnarkBlockCompression(api, circuit.Old, circuit.Block)
+	api.AssertIsEqual(res, circuit.New)
+
+	hasher := poseidon2.NewGnarkHasher(api)
+	hasher.Write(circuit.Vec[:]...)
+	api.AssertIsEqual(hasher.Sum(), circuit.Hash)
+	return nil
+}
+
+func TestGnarkCompression(t *testing.T) {
+
+	r1cs, err := frontend.Compile(
+		ecc.BLS12_377.ScalarField(),
+		r1cs.NewBuilder,
+		&Circuit{},
+	)
+	require.NoError(t, err)
+
+	vec := []field.Element{field.NewElement(3), field.NewElement(4), field.NewElement(5)}
+
+	assignment := Circuit{
+		Block: 1,
+		Old:   2,
+		New:   poseidon2.BlockCompression(field.NewElement(2), field.NewElement(1)),
+		Vec:   [3]frontend.Variable{vec[0], vec[1], vec[2]},
+		Hash:  poseidon2.HashVec(vec),
+	}
+
+	witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+	require.NoError(t, err)
+
+	err = r1cs.IsSolved(witness)
+	require.NoError(t, err)
+}
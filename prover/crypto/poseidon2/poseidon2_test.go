@@ -0,0 +1,83 @@
+package poseidon2_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoseidon2Block(t *testing.T) {
+
+	for i := 0; i < 100; i++ {
+
+		hasher := poseidon2.NewPoseidon2()
+
+		// old is set to zero
+		var x, old field.Element
+
+		// s is set to a random value. Each run of the test will
+		// generate a different value.
+		x.SetRandom()
+		xBytes := x.Bytes()
+
+		newState := poseidon2.BlockCompression(old, x)
+
+		hasher.Write(xBytes[:])
+		newBytes := hasher.Sum(nil)
+		var newFromHasher field.Element
+		newFromHasher.SetBytes(newBytes)
+
+		require.Equal(t, newFromHasher.String(), newState.String())
+	}
+}
+
+func TestPoseidon2HasherState(t *testing.T) {
+
+	v := []field.Element{field.NewElement(1), field.NewElement(2), field.NewElement(3)}
+
+	hasher := poseidon2.NewPoseidon2()
+	for i := range v[:2] {
+		b := v[i].Bytes()
+		hasher.Write(b[:])
+	}
+
+	// The state is restored in a fresh hasher, which then completes the hash
+	resumed := poseidon2.NewPoseidon2()
+	require.NoError(t, resumed.SetState(hasher.State()))
+	b := v[2].Bytes()
+	resumed.Write(b[:])
+
+	var h field.Element
+	h.SetBytes(resumed.Sum(nil))
+	require.Equal(t, poseidon2.HashVec(v), h)
+
+	// Non-canonical inputs are rejected
+	_, err := hasher.Write(field.Modulus().Bytes())
+	require.Error(t, err)
+}
+
+func TestPoseidon2Permutation(t *testing.T) {
+
+	var a, b field.Element
+	a.SetRandom()
+	b.SetRandom()
+
+	// The permutation is a bijection and does not commute its inputs
+	require.NotEqual(t,
+		poseidon2.Permutation([2]field.Element{a, b}),
+		poseidon2.Permutation([2]field.Element{b, a}),
+	)
+	require.NotEqual(t, poseidon2.BlockCompression(a, b), poseidon2.BlockCompression(b, a))
+
+	// The round keys are well-formed
+	require.Len(t, poseidon2.RoundKeys, poseidon2.NbFullRounds+poseidon2.NbPartialRounds)
+	for r := range poseidon2.RoundKeys {
+		if poseidon2.IsPartialRound(r) {
+			require.Len(t, poseidon2.RoundKeys[r], 1)
+		} else {
+			require.Len(t, poseidon2.RoundKeys[r], poseidon2.Width)
+		}
+	}
+}
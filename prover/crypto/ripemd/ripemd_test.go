@@ -0,0 +1,69 @@
+package ripemd
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // used as a reference implementation
+)
+
+type testCase struct {
+	ExpectedHash Digest
+	Stream       []byte
+}
+
+func TestHash(t *testing.T) {
+
+	var (
+		maxSizeByte = 1000
+		// #nosec G404 -- we don't need a cryptographic PRNG for testing purposes
+		rng = rand.New(rand.NewChaCha8([32]byte{}))
+	)
+
+	for sizeByte := 0; sizeByte < maxSizeByte; sizeByte++ {
+
+		var (
+			testCase                = genTestCase(rng, sizeByte)
+			recoveredHashWoTraces   = Hash(testCase.Stream, nil)
+			recoveredHashWithTraces = Hash(testCase.Stream, &HashTraces{})
+		)
+
+		assert.Equalf(t, testCase.ExpectedHash, recoveredHashWoTraces, "(without trace) for input of size: %v", sizeByte)
+		assert.Equalf(t, testCase.ExpectedHash, recoveredHashWithTraces, "(with trace) for input of size: %v", sizeByte)
+	}
+}
+
+func TestCompressMatchesTraces(t *testing.T) {
+
+	var (
+		traces = &HashTraces{}
+		stream = make([]byte, 200)
+	)
+
+	Hash(stream, traces)
+
+	for i := range traces.Blocks {
+		assert.Equal(t, traces.BlockNewStates[i], Compress(traces.BlockOldStates[i], traces.Blocks[i]))
+	}
+
+	assert.Equal(t, IV(), traces.BlockOldStates[0])
+}
+
+func genTestCase(rng *rand.Rand, sizeByte int) testCase {
+
+	stream := make([]byte, sizeByte)
+	utils.ReadPseudoRand(rng, stream)
+
+	h := ripemd160.New()
+	h.Write(stream)
+
+	var expected Digest
+	copy(expected[:], h.Sum(nil))
+
+	return testCase{
+		Stream:       stream,
+		ExpectedHash: expected,
+	}
+}
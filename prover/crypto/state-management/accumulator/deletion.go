@@ -52,10 +52,10 @@ func (p *ProverState[K, V]) DeleteAndProve(key K) (trace DeletionTrace[K, V]) {
 	}
 
 	// No need to look for the sandwich, we can find it in the leafopening
-	tuple := p.Data.MustGet(i)
+	tuple := p.mustGetTuple(i)
 	iMinus, iPlus := tuple.LeafOpening.Prev, tuple.LeafOpening.Next
-	tupleMinus := p.Data.MustGet(iMinus)
-	tuplePlus := p.Data.MustGet(iPlus)
+	tupleMinus := p.mustGetTuple(iMinus)
+	tuplePlus := p.mustGetTuple(iPlus)
 
 	trace = DeletionTrace[K, V]{
 		Location:        p.Location,
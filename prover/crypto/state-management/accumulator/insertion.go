@@ -47,8 +47,8 @@ func (p *ProverState[K, V]) InsertAndProve(key K, val V) (trace InsertionTrace[K
 
 	// Fetch the leaf openings and add them in the trace
 	iMinus, iPlus := p.findSandwich(key)
-	tupleMinus := p.Data.MustGet(iMinus)
-	tuplePlus := p.Data.MustGet(iPlus)
+	tupleMinus := p.mustGetTuple(iMinus)
+	tuplePlus := p.mustGetTuple(iPlus)
 
 	trace = InsertionTrace[K, V]{
 		Location: p.Location,
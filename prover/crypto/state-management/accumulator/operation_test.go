@@ -169,3 +169,70 @@ func TestDeletion(t *testing.T) {
 	assert.Equal(t, acc.NextFreeNode, ver.NextFreeNode)
 	assert.Equal(t, acc.SubTreeRoot(), ver.SubTreeRoot)
 }
+
+func TestProverStateWithStorage(t *testing.T) {
+
+	config := &smt.Config{
+		HashFunc: hashtypes.Keccak,
+		Depth:    40,
+	}
+
+	storage, err := smt.OpenPebbleStorage(t.TempDir())
+	require.NoError(t, err)
+	defer storage.Close()
+
+	acc := newTestAccumulatorKeccak()
+	accStored, err := accumulator.InitializeProverStateWithStorage[DummyKey, DummyVal](config, locationTesting, storage)
+	require.NoError(t, err)
+
+	for i := 0; i < numRepetion; i++ {
+		trace := acc.InsertAndProve(dumkey(i), dumval(i))
+		traceStored := accStored.InsertAndProve(dumkey(i), dumval(i))
+		require.Equal(t, trace, traceStored)
+	}
+
+	for i := 0; i < numRepetion; i += 3 {
+		trace := acc.DeleteAndProve(dumkey(i))
+		traceStored := accStored.DeleteAndProve(dumkey(i))
+		require.Equal(t, trace, traceStored)
+	}
+
+	assert.Equal(t, acc.TopRoot(), accStored.TopRoot())
+
+	// The accumulator is reopened from its storage and continues identically
+	reopened, err := accumulator.InitializeProverStateWithStorage[DummyKey, DummyVal](config, locationTesting, storage)
+	require.NoError(t, err)
+	assert.Equal(t, acc.TopRoot(), reopened.TopRoot())
+	assert.Equal(t, acc.NextFreeNode, reopened.NextFreeNode)
+
+	for i := 0; i < numRepetion; i += 3 {
+		trace := acc.InsertAndProve(dumkey(i), dumval(i+1))
+		traceStored := reopened.InsertAndProve(dumkey(i), dumval(i+1))
+		require.Equal(t, trace, traceStored)
+	}
+
+	for i := 1; i < numRepetion; i += 3 {
+		trace := acc.UpdateAndProve(dumkey(i), dumval(i+2))
+		traceStored := reopened.UpdateAndProve(dumkey(i), dumval(i+2))
+		require.Equal(t, trace, traceStored)
+	}
+
+	// The tuples are read from the storage, the updates are persisted too
+	reopened, err = accumulator.InitializeProverStateWithStorage[DummyKey, DummyVal](config, locationTesting, storage)
+	require.NoError(t, err)
+	assert.Empty(t, reopened.Data.InnerMap())
+	assert.ElementsMatch(t, acc.ListAllKeys(), reopened.ListAllKeys())
+
+	for i := 0; i < numRepetion; i++ {
+		require.Equal(t, acc.ReadNonZeroAndProve(dumkey(i)), reopened.ReadNonZeroAndProve(dumkey(i)))
+	}
+
+	for i := 0; i < numRepetion; i += 2 {
+		require.Equal(t, acc.DeleteAndProve(dumkey(i)), reopened.DeleteAndProve(dumkey(i)))
+		require.Equal(t, acc.ReadZeroAndProve(dumkey(i)), reopened.ReadZeroAndProve(dumkey(i)))
+	}
+
+	// The storage holds another accumulator
+	_, err = accumulator.InitializeProverStateWithStorage[DummyKey, DummyVal](config, "other", storage)
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package accumulator
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
@@ -21,8 +22,12 @@ type ProverState[K, V io.WriterTo] struct {
 	NextFreeNode int64
 	// Internal tree
 	Tree *smt.Tree
-	// Keys associated to the leaf #i
+	// Keys associated to the leaf #i. It is left empty when the accumulator
+	// is backed by a storage: the tuples are then read from the storage.
 	Data collection.Mapping[int64, KVOpeningTuple[K, V]]
+	// readTuple reads a tuple from the storage, it is nil if the accumulator
+	// is not backed by a storage.
+	readTuple func(i int64) (KVOpeningTuple[K, V], bool, error)
 }
 
 // InitializeProverState returns an initialized empty accumulator state
@@ -46,6 +51,83 @@ func InitializeProverState[K, V io.WriterTo](conf *smt.Config, location string)
 	}
 }
 
+// InitializeProverStateWithStorage works as [InitializeProverState] but keeps
+// the accumulator in the storage, which can be on disk for the accumulators
+// that do not fit in memory. The data of the accumulator is stored next to its
+// tree so that an accumulator can be reopened from its storage. An empty
+// storage is initialized with an empty accumulator.
+//
+// The tuples are not kept in memory: they are read from the storage when they
+// are accessed, and the keys are looked up through an index of the tuples
+// sorted by HKey, also kept in the storage.
+func InitializeProverStateWithStorage[K, V io.WriterTo, PK readerFrom[K], PV readerFrom[V]](conf *smt.Config, location string, storage smt.Storage) (*ProverState[K, V], error) {
+
+	tree, err := smt.OpenTree(conf, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	storedLocation, found, err := storage.GetRecord(locationRecordKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the location of the accumulator: %w", err)
+	}
+
+	if found {
+
+		if string(storedLocation) != location {
+			return nil, fmt.Errorf("the storage holds the accumulator %v, not %v", string(storedLocation), location)
+		}
+
+		nextFreeNode, err := loadNextFreeNode[K, V, PK, PV](tree)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the accumulator %v: %w", location, err)
+		}
+
+		return &ProverState[K, V]{
+			Location:     location,
+			NextFreeNode: nextFreeNode,
+			Tree:         tree,
+			Data:         collection.NewMapping[int64, KVOpeningTuple[K, V]](),
+			readTuple:    readTupleFrom[K, V, PK, PV](tree.Storage),
+		}, nil
+	}
+
+	if tree.Root != smt.NewEmptyTree(conf).Root {
+		return nil, fmt.Errorf("the storage holds a tree but no accumulator")
+	}
+
+	head, tail := Head(), Tail(conf)
+
+	// The records are written along with the head and the tail
+	tree.SetRecord(locationRecordKey, []byte(location))
+	tree.SetRecord(nextFreeNodeRecordKey, encodeInt64(2))
+	tree.SetRecord(dataRecordKey(0), encodeTuple(KVOpeningTuple[K, V]{LeafOpening: head}))
+	tree.SetRecord(dataRecordKey(1), encodeTuple(KVOpeningTuple[K, V]{LeafOpening: tail}))
+	tree.SetRecord(hkeyRecordKey(head.HKey), encodeInt64(0))
+	tree.SetRecord(hkeyRecordKey(tail.HKey), encodeInt64(1))
+
+	tree.UpdateBatch([]smt.LeafUpdate{
+		{Pos: 0, Leaf: head.Hash(conf)},
+		{Pos: 1, Leaf: tail.Hash(conf)},
+	})
+
+	return &ProverState[K, V]{
+		Location:     location,
+		NextFreeNode: 2, // because we inserted head and tail
+		Tree:         tree,
+		Data:         collection.NewMapping[int64, KVOpeningTuple[K, V]](),
+		readTuple:    readTupleFrom[K, V, PK, PV](tree.Storage),
+	}, nil
+}
+
+// readTupleFrom returns the function reading the tuples of an accumulator from
+// its storage.
+func readTupleFrom[K, V io.WriterTo, PK readerFrom[K], PV readerFrom[V]](storage smt.Storage) func(i int64) (KVOpeningTuple[K, V], bool, error) {
+	return func(i int64) (KVOpeningTuple[K, V], bool, error) {
+		return readTuple[K, V, PK, PV](storage, i)
+	}
+}
+
 // Config returns the configuration of the accumulator.
 func (s *ProverState[K, V]) Config() *smt.Config {
 	return s.Tree.Config
@@ -60,8 +142,17 @@ func (s *ProverState[K, V]) SubTreeRoot() Bytes32 {
 // it returns 0, false. The returned position corresponds to the position in the
 // tree.
 func (s *ProverState[K, V]) FindKey(k K) (int64, bool) {
-	// We do so with a linear scan to simplify (since it is only for testing)
 	hkey := hash(s.Config(), k)
+
+	if s.readTuple != nil {
+		i, found, err := findPosition(s.Tree.Storage, hkey)
+		if err != nil {
+			utils.Panic("could not look up the key %v: %v", k, err)
+		}
+		return i, found
+	}
+
+	// We do so with a linear scan to simplify (since it is only for testing)
 	for _, i := range s.Data.ListAllKeys() {
 		leafOpening := s.Data.MustGet(i).LeafOpening
 		if hkey == leafOpening.HKey {
@@ -78,8 +169,8 @@ func (s *ProverState[K, V]) ListAllKeys() []K {
 	// We compute the two keys that are used as bounds, to be able to ignore them later
 	lowerBound := Bytes32{}
 	upperBound := s.Config().HashFunc().MaxBytes32()
-	for _, i := range s.Data.ListAllKeys() {
-		tuple := s.Data.MustGet(i)
+	for _, i := range s.listPositions() {
+		tuple := s.mustGetTuple(i)
 		if !(Bytes32Cmp(tuple.LeafOpening.HKey, lowerBound) == 0 || Bytes32Cmp(tuple.LeafOpening.HKey, upperBound) == 0) {
 			containedKeys = append(containedKeys, tuple.Key)
 		}
@@ -90,8 +181,17 @@ func (s *ProverState[K, V]) ListAllKeys() []K {
 // findSandwich finds the position of the two leaves sandwhich the queries leaf.
 // It assumes that "k" is not stored in the tree.
 func (s *ProverState[K, V]) findSandwich(k K) (int64, int64) {
-	// We do so with a linear scanning to simplify (since it is only for testing)
 	hkey := hash(s.Config(), k)
+
+	if s.readTuple != nil {
+		iminus, iplus, err := findSandwichInStorage(s.Tree.Storage, hkey)
+		if err != nil {
+			utils.Panic("could not find the sandwich of %v: %v", k, err)
+		}
+		return iminus, iplus
+	}
+
+	// We do so with a linear scanning to simplify (since it is only for testing)
 	hminus, iminus := Bytes32{}, int64(0)                        // corresponds to head
 	hplus, iplus := s.Config().HashFunc().MaxBytes32(), int64(1) // corresponds to tail
 
@@ -134,7 +234,7 @@ func (s *ProverState[K, V]) upsertTuple(i int64, tuple KVOpeningTuple[K, V]) smt
 		utils.Panic("illegal tuple : %v", err)
 	}
 
-	if old, found := s.Data.TryGet(i); found {
+	if old, found := s.tryGetTuple(i); found {
 		// consistency-check of the old tuple
 		_, err = old.CheckAndLeaf(s.Config())
 		if err != nil {
@@ -170,8 +270,13 @@ func (s *ProverState[K, V]) upsertTuple(i int64, tuple KVOpeningTuple[K, V]) smt
 
 	oldRoot := s.SubTreeRoot()
 
+	// Inserting at the next free node also moves it
+	if s.readTuple != nil && i >= s.NextFreeNode {
+		s.Tree.SetRecord(nextFreeNodeRecordKey, encodeInt64(i+1))
+	}
+
 	// Perform the update
-	s.Data.Update(i, tuple)
+	s.setTuple(i, tuple)
 	s.Tree.Update(int(i), leaf)
 	newRoot := s.SubTreeRoot()
 
@@ -184,8 +289,7 @@ func (s *ProverState[K, V]) rmTuple(i int64) smt.Proof {
 	oldRoot := s.SubTreeRoot()
 
 	// Update the tree with an empty leaf
-	s.Data.MustExists(i)
-	s.Data.Del(i)
+	s.delTuple(i)
 	s.Tree.Update(int(i), smt.EmptyLeaf())
 	newRoot := s.SubTreeRoot()
 
@@ -203,3 +307,73 @@ func (s *ProverState[K, V]) TopRoot() Bytes32 {
 	Bytes32 := hasher.Sum(nil)
 	return AsBytes32(Bytes32)
 }
+
+// tryGetTuple returns the tuple at position i, reading it from the storage if
+// the accumulator is backed by one.
+func (s *ProverState[K, V]) tryGetTuple(i int64) (KVOpeningTuple[K, V], bool) {
+	if s.readTuple == nil {
+		return s.Data.TryGet(i)
+	}
+
+	tuple, found, err := s.readTuple(i)
+	if err != nil {
+		utils.Panic("could not read the tuple at position %v: %v", i, err)
+	}
+	return tuple, found
+}
+
+// mustGetTuple works as [ProverState.tryGetTuple] but panics if there is no
+// tuple at position i.
+func (s *ProverState[K, V]) mustGetTuple(i int64) KVOpeningTuple[K, V] {
+	tuple, found := s.tryGetTuple(i)
+	if !found {
+		utils.Panic("no tuple at position %v", i)
+	}
+	return tuple
+}
+
+// setTuple sets the tuple at position i. For an accumulator backed by a
+// storage, the tuple and its entry in the HKey index are flushed along with
+// the next update of the tree.
+func (s *ProverState[K, V]) setTuple(i int64, tuple KVOpeningTuple[K, V]) {
+	if s.readTuple == nil {
+		s.Data.Update(i, tuple)
+		return
+	}
+
+	s.Tree.SetRecord(dataRecordKey(i), encodeTuple(tuple))
+	s.Tree.SetRecord(hkeyRecordKey(tuple.LeafOpening.HKey), encodeInt64(i))
+}
+
+// delTuple deletes the tuple at position i, it panics if there is none. As for
+// [ProverState.setTuple], the deletion is flushed with the next update of the
+// tree.
+func (s *ProverState[K, V]) delTuple(i int64) {
+	if s.readTuple == nil {
+		s.Data.MustExists(i)
+		s.Data.Del(i)
+		return
+	}
+
+	tuple := s.mustGetTuple(i)
+	s.Tree.DeleteRecord(dataRecordKey(i))
+	s.Tree.DeleteRecord(hkeyRecordKey(tuple.LeafOpening.HKey))
+}
+
+// listPositions returns the positions of all the tuples of the accumulator
+func (s *ProverState[K, V]) listPositions() []int64 {
+	if s.readTuple == nil {
+		return s.Data.ListAllKeys()
+	}
+
+	var positions []int64
+	err := s.Tree.Storage.RangeRecords(hkeyRecordPrefix, func(_, value []byte) error {
+		i, err := decodeInt64(value)
+		positions = append(positions, i)
+		return err
+	})
+	if err != nil {
+		utils.Panic("could not list the tuples: %v", err)
+	}
+	return positions
+}
@@ -37,7 +37,7 @@ func (p *ProverState[K, V]) ReadNonZeroAndProve(key K) ReadNonZeroTrace[K, V] {
 		utils.Panic("called read-non-zero, but the key was not present")
 	}
 
-	tuple := p.Data.MustGet(i)
+	tuple := p.mustGetTuple(i)
 
 	if hash(p.Config(), key) != hash(p.Config(), tuple.Key) {
 		utils.Panic("sanity-check : the key mismatched")
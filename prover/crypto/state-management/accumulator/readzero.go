@@ -37,8 +37,8 @@ func (p *ProverState[K, V]) ReadZeroAndProve(key K) ReadZeroTrace[K, V] {
 	}
 
 	iMinus, iPlus := p.findSandwich(key)
-	dataMinus := p.Data.MustGet(iMinus)
-	dataPlus := p.Data.MustGet(iPlus)
+	dataMinus := p.mustGetTuple(iMinus)
+	dataPlus := p.mustGetTuple(iPlus)
 
 	return ReadZeroTrace[K, V]{
 		Location:     p.Location,
@@ -0,0 +1,268 @@
+package accumulator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+
+	//lint:ignore ST1001 -- the package contains a list of standard types for this repo
+	. "github.com/consensys/linea-monorepo/prover/utils/types"
+)
+
+// The accumulators backed by a storage persist their data as records of the
+// storage of their tree (see [smt.Storage]). The records are written along with
+// the nodes of the tree so that the two are always consistent.
+var (
+	// locationRecordKey holds the location of the accumulator
+	locationRecordKey = []byte("acc/location")
+	// nextFreeNodeRecordKey holds the next free node of the accumulator
+	nextFreeNodeRecordKey = []byte("acc/next-free-node")
+	// dataRecordPrefix prefixes the records holding the tuples of the
+	// accumulator. It is followed by the position of the tuple in big-endian.
+	dataRecordPrefix = []byte("acc/data/")
+	// hkeyRecordPrefix prefixes the records indexing the tuples by the hash of
+	// their key. It is followed by the HKey and the record holds the position
+	// of the tuple. As the records are ranged in the order of their keys, the
+	// index is sorted by HKey.
+	hkeyRecordPrefix = []byte("acc/hkey/")
+)
+
+// readerFrom is satisfied by the pointers to the keys and values of the
+// accumulators. It is used to decode them from the storage.
+type readerFrom[T any] interface {
+	*T
+	io.ReaderFrom
+}
+
+// dataRecordKey returns the key of the record holding the tuple at position i
+func dataRecordKey(i int64) []byte {
+	return binary.BigEndian.AppendUint64(bytes.Clone(dataRecordPrefix), uint64(i))
+}
+
+// hkeyRecordKey returns the key of the record indexing the tuple whose key
+// hashes to hkey
+func hkeyRecordKey(hkey Bytes32) []byte {
+	return append(bytes.Clone(hkeyRecordPrefix), hkey[:]...)
+}
+
+// encodeInt64 encodes an int64 in big-endian
+func encodeInt64(x int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(x))
+}
+
+// decodeInt64 is the reverse of [encodeInt64]
+func decodeInt64(b []byte) (int64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("expected 8 bytes, got %v", len(b))
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// encodeTuple serializes a tuple: the leaf opening followed by the key,
+// prefixed by its length, and the value.
+func encodeTuple[K, V io.WriterTo](tuple KVOpeningTuple[K, V]) []byte {
+
+	var (
+		buf bytes.Buffer
+		key bytes.Buffer
+	)
+
+	tuple.LeafOpening.WriteTo(&buf)
+
+	if _, err := tuple.Key.WriteTo(&key); err != nil {
+		panic(err)
+	}
+
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(key.Len())))
+	buf.Write(key.Bytes())
+
+	if _, err := tuple.Value.WriteTo(&buf); err != nil {
+		panic(err)
+	}
+
+	return buf.Bytes()
+}
+
+// decodeTuple is the reverse of [encodeTuple]
+func decodeTuple[K, V io.WriterTo, PK readerFrom[K], PV readerFrom[V]](b []byte) (KVOpeningTuple[K, V], error) {
+
+	var (
+		tuple KVOpeningTuple[K, V]
+		r     = bytes.NewReader(b)
+	)
+
+	prev, _, err := ReadInt64On32Bytes(r)
+	if err != nil {
+		return tuple, fmt.Errorf("could not read the prev: %w", err)
+	}
+
+	next, _, err := ReadInt64On32Bytes(r)
+	if err != nil {
+		return tuple, fmt.Errorf("could not read the next: %w", err)
+	}
+
+	tuple.LeafOpening.Prev, tuple.LeafOpening.Next = prev, next
+
+	if _, err := io.ReadFull(r, tuple.LeafOpening.HKey[:]); err != nil {
+		return tuple, fmt.Errorf("could not read the hkey: %w", err)
+	}
+
+	if _, err := io.ReadFull(r, tuple.LeafOpening.HVal[:]); err != nil {
+		return tuple, fmt.Errorf("could not read the hval: %w", err)
+	}
+
+	var keyLen [4]byte
+	if _, err := io.ReadFull(r, keyLen[:]); err != nil {
+		return tuple, fmt.Errorf("could not read the length of the key: %w", err)
+	}
+
+	key := make([]byte, binary.BigEndian.Uint32(keyLen[:]))
+	if _, err := io.ReadFull(r, key); err != nil {
+		return tuple, fmt.Errorf("could not read the key: %w", err)
+	}
+
+	if _, err := PK(&tuple.Key).ReadFrom(bytes.NewReader(key)); err != nil {
+		return tuple, fmt.Errorf("could not decode the key: %w", err)
+	}
+
+	if _, err := PV(&tuple.Value).ReadFrom(r); err != nil {
+		return tuple, fmt.Errorf("could not decode the value: %w", err)
+	}
+
+	return tuple, nil
+}
+
+// loadNextFreeNode reads the next free node of the accumulator stored in the
+// storage and checks the head and the tail against the leaves of the tree. The
+// other tuples are left in the storage and read when they are accessed.
+func loadNextFreeNode[K, V io.WriterTo, PK readerFrom[K], PV readerFrom[V]](tree *smt.Tree) (int64, error) {
+
+	b, found, err := tree.Storage.GetRecord(nextFreeNodeRecordKey)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("the next free node is missing")
+	}
+
+	nextFreeNode, err := decodeInt64(b)
+	if err != nil {
+		return 0, fmt.Errorf("could not decode the next free node: %w", err)
+	}
+
+	for _, i := range []int64{0, 1} {
+
+		tuple, found, err := readTuple[K, V, PK, PV](tree.Storage, i)
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, fmt.Errorf("the tuple at position %v is missing", i)
+		}
+
+		leaf, err := tuple.CheckAndLeaf(tree.Config)
+		if err != nil {
+			return 0, fmt.Errorf("illegal tuple at position %v: %w", i, err)
+		}
+
+		stored, err := tree.GetLeaf(int(i))
+		if err != nil {
+			return 0, err
+		}
+
+		if leaf != stored {
+			return 0, fmt.Errorf("the tuple at position %v does not match the leaf of the tree", i)
+		}
+	}
+
+	return nextFreeNode, nil
+}
+
+// readTuple reads the tuple at position i from the storage
+func readTuple[K, V io.WriterTo, PK readerFrom[K], PV readerFrom[V]](storage smt.Storage, i int64) (KVOpeningTuple[K, V], bool, error) {
+
+	b, found, err := storage.GetRecord(dataRecordKey(i))
+	if err != nil || !found {
+		return KVOpeningTuple[K, V]{}, found, err
+	}
+
+	tuple, err := decodeTuple[K, V, PK, PV](b)
+	if err != nil {
+		return tuple, false, fmt.Errorf("could not decode the tuple at position %v: %w", i, err)
+	}
+
+	return tuple, true, nil
+}
+
+// findPosition returns the position of the tuple whose key hashes to hkey, as
+// recorded in the HKey index of the storage.
+func findPosition(storage smt.Storage, hkey Bytes32) (int64, bool, error) {
+
+	b, found, err := storage.GetRecord(hkeyRecordKey(hkey))
+	if err != nil || !found {
+		return 0, found, err
+	}
+
+	i, err := decodeInt64(b)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not decode the position of the hkey %x: %w", hkey, err)
+	}
+
+	return i, true, nil
+}
+
+// errStopRange stops the ranging over the records once the result is found
+var errStopRange = errors.New("stop ranging")
+
+// findSandwichInStorage returns the positions of the largest HKey smaller than
+// hkey and of the smallest HKey larger than hkey in the HKey index. Rather than
+// ranging over the whole index, it ranges over the HKeys sharing the longest
+// prefix with hkey that has both: all the HKeys in between the two share the
+// same prefix. As the HKeys are uniformly distributed, this is a few records on
+// average. The head and the tail are always found with the empty prefix.
+func findSandwichInStorage(storage smt.Storage, hkey Bytes32) (iMinus, iPlus int64, err error) {
+
+	for l := len(hkey); l >= 0; l-- {
+
+		var foundMinus, foundPlus bool
+		prefix := append(bytes.Clone(hkeyRecordPrefix), hkey[:l]...)
+
+		err := storage.RangeRecords(prefix, func(key, value []byte) error {
+
+			cur := key[len(hkeyRecordPrefix):]
+			cmp := bytes.Compare(cur, hkey[:])
+			if cmp == 0 {
+				return fmt.Errorf("found a perfect match for the hkey %x", hkey)
+			}
+
+			i, err := decodeInt64(value)
+			if err != nil {
+				return fmt.Errorf("could not decode the position of the hkey %x: %w", cur, err)
+			}
+
+			// The records are ranged in increasing order, the last HKey
+			// smaller than hkey is followed by the first larger one.
+			if cmp < 0 {
+				iMinus, foundMinus = i, true
+				return nil
+			}
+
+			iPlus, foundPlus = i, true
+			return errStopRange
+		})
+
+		if err != nil && !errors.Is(err, errStopRange) {
+			return 0, 0, err
+		}
+
+		if foundMinus && foundPlus {
+			return iMinus, iPlus, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("the head or the tail is missing from the index")
+}
@@ -40,7 +40,7 @@ func (p *ProverState[K, V]) UpdateAndProve(key K, newVal V) UpdateTrace[K, V] {
 		utils.Panic("called update, but the key was not present")
 	}
 
-	tuple := p.Data.MustGet(i)
+	tuple := p.mustGetTuple(i)
 
 	if hash(p.Config(), key) != hash(p.Config(), tuple.Key) {
 		utils.Panic("sanity-check : the key mismatched")
@@ -53,7 +53,7 @@ func (p *ProverState[K, V]) UpdateAndProve(key K, newVal V) UpdateTrace[K, V] {
 	// Compute the new value and update the tree
 	tuple.Value = newVal
 	tuple.LeafOpening.HVal = hash(p.Config(), tuple.Value)
-	p.Data.Update(i, tuple)
+	p.setTuple(i, tuple)
 
 	newLeaf := tuple.LeafOpening.Hash(p.Config())
 	p.Tree.Update(int(i), newLeaf)
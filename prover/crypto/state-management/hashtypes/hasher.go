@@ -4,6 +4,7 @@ import (
 	"hash"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	. "github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -45,3 +46,12 @@ func MiMC() Hasher {
 		maxValue: maxVal.Bytes(),
 	}
 }
+
+// Create a new Poseidon2 hasher
+func Poseidon2() Hasher {
+	maxVal := field.NewFromString("-1")
+	return Hasher{
+		Hash:     poseidon2.NewPoseidon2(),
+		maxValue: maxVal.Bytes(),
+	}
+}
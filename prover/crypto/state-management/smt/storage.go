@@ -0,0 +1,243 @@
+package smt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+)
+
+// ErrReadOnlyStorage is returned when writing into a snapshot of a storage.
+var ErrReadOnlyStorage = errors.New("the storage is read-only")
+
+// Storage stores the nodes of a [Tree] whose value was set. The leaves are at
+// level 0 and the intermediate nodes at levels 1 to Depth-1. The root and the
+// depth of the tree are stored on a reserved level above the root. The nodes that
+// were never written are read as empty nodes by the tree.
+//
+// The storage also holds records: opaque key-value entries written by the
+// users of the tree alongside its nodes, see [Tree.SetRecord]. This is how the
+// accumulator persists its data next to its tree.
+//
+// The storage does not need to be safe for concurrent writes but must allow
+// reading while a snapshot is taken.
+type Storage interface {
+	// GetNode returns the node at the given level and position and false if
+	// it was never written.
+	GetNode(level, pos int) (types.Bytes32, bool, error)
+	// GetRecord returns the record stored under the key and false if there
+	// is none.
+	GetRecord(key []byte) ([]byte, bool, error)
+	// RangeRecords calls f on every record whose key starts with the prefix,
+	// in the lexicographic order of the keys. It stops at the first error.
+	RangeRecords(prefix []byte, f func(key, value []byte) error) error
+	// Write applies all the writes of the batch atomically.
+	Write(batch *Batch) error
+	// Snapshot returns a read-only view of the storage in its current state.
+	// The subsequent writes into the storage are not visible in the snapshot
+	// and writing into the snapshot returns [ErrReadOnlyStorage].
+	Snapshot() (Storage, error)
+	// Close releases the resources held by the storage.
+	Close() error
+}
+
+const (
+	// metaLevel is the level reserved to store the metadata of the tree. It
+	// is above any level of a tree.
+	metaLevel = 255
+	// metaRootPos and metaDepthPos are the positions of the root and of the
+	// depth of the tree in the metadata level.
+	metaRootPos  = 0
+	metaDepthPos = 1
+	// recordPrefix prefixes the keys of the records in a key-value store. It
+	// is distinct from the first byte of the keys of the nodes, which is
+	// their level.
+	recordPrefix = 254
+)
+
+// nodeID identifies a node of the tree in a storage
+type nodeID struct {
+	Level, Pos int
+}
+
+// key returns the key of the node in a key-value store: the level followed
+// by the position in big-endian so that the nodes of a level are contiguous
+// and sorted.
+func (id nodeID) key() []byte {
+	var k [9]byte
+	k[0] = byte(id.Level)
+	binary.BigEndian.PutUint64(k[1:], uint64(id.Pos))
+	return k[:]
+}
+
+// encodeDepth encodes the depth of the tree as a node of the metadata level
+func encodeDepth(depth int) (res types.Bytes32) {
+	binary.BigEndian.PutUint64(res[24:], uint64(depth))
+	return res
+}
+
+// decodeDepth is the reverse of [encodeDepth]
+func decodeDepth(node types.Bytes32) int {
+	return int(binary.BigEndian.Uint64(node[24:]))
+}
+
+// recordKey returns the key of a record in a key-value store
+func recordKey(key []byte) []byte {
+	return append([]byte{recordPrefix}, key...)
+}
+
+// Batch collects writes into a [Storage]. The last write of a node or of a
+// record wins.
+type Batch struct {
+	nodes map[nodeID]types.Bytes32
+	// records maps the keys of the records to their values, nil for the
+	// records to delete.
+	records map[string][]byte
+}
+
+// NewBatch returns an empty batch
+func NewBatch() *Batch {
+	return &Batch{nodes: map[nodeID]types.Bytes32{}, records: map[string][]byte{}}
+}
+
+// SetRecord records the write of a record. The value must not be nil.
+func (b *Batch) SetRecord(key, value []byte) {
+	b.records[string(key)] = value
+}
+
+// DeleteRecord records the deletion of a record
+func (b *Batch) DeleteRecord(key []byte) {
+	b.records[string(key)] = nil
+}
+
+// Set records the write of the node at the given level and position.
+func (b *Batch) Set(level, pos int, node types.Bytes32) {
+	b.nodes[nodeID{Level: level, Pos: pos}] = node
+}
+
+// Get returns the node written in the batch at the given level and position
+// and false if there is none.
+func (b *Batch) Get(level, pos int) (types.Bytes32, bool) {
+	node, ok := b.nodes[nodeID{Level: level, Pos: pos}]
+	return node, ok
+}
+
+// Len returns the number of nodes written in the batch
+func (b *Batch) Len() int {
+	return len(b.nodes)
+}
+
+// Range calls f on every node of the batch in no particular order.
+func (b *Batch) Range(f func(level, pos int, node types.Bytes32)) {
+	for id, node := range b.nodes {
+		f(id.Level, id.Pos, node)
+	}
+}
+
+// reset empties the batch
+func (b *Batch) reset() {
+	clear(b.nodes)
+	clear(b.records)
+}
+
+// MemoryStorage is a [Storage] holding the nodes in a map. Unlike the default
+// representation of the [Tree], it only stores the nodes that were written so
+// it is suited for sparse trees. Its snapshots are full copies.
+type MemoryStorage struct {
+	mu       sync.RWMutex
+	nodes    map[nodeID]types.Bytes32
+	records  map[string][]byte
+	readOnly bool
+}
+
+// NewMemoryStorage returns an empty [MemoryStorage]
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{nodes: map[nodeID]types.Bytes32{}, records: map[string][]byte{}}
+}
+
+// GetNode implements [Storage]
+func (s *MemoryStorage) GetNode(level, pos int) (types.Bytes32, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	node, ok := s.nodes[nodeID{Level: level, Pos: pos}]
+	return node, ok, nil
+}
+
+// GetRecord implements [Storage]
+func (s *MemoryStorage) GetRecord(key []byte) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.records[string(key)]
+	return value, ok, nil
+}
+
+// RangeRecords implements [Storage]
+func (s *MemoryStorage) RangeRecords(prefix []byte, f func(key, value []byte) error) error {
+
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.records))
+	for key := range s.records {
+		if strings.HasPrefix(key, string(prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok, _ := s.GetRecord([]byte(key))
+		if !ok {
+			continue
+		}
+		if err := f([]byte(key), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Write implements [Storage]
+func (s *MemoryStorage) Write(batch *Batch) error {
+	if s.readOnly {
+		return ErrReadOnlyStorage
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, node := range batch.nodes {
+		s.nodes[id] = node
+	}
+	for key, value := range batch.records {
+		if value == nil {
+			delete(s.records, key)
+			continue
+		}
+		s.records[key] = bytes.Clone(value)
+	}
+	return nil
+}
+
+// Snapshot implements [Storage]
+func (s *MemoryStorage) Snapshot() (Storage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	nodes := make(map[nodeID]types.Bytes32, len(s.nodes))
+	for id, node := range s.nodes {
+		nodes[id] = node
+	}
+	records := make(map[string][]byte, len(s.records))
+	for key, value := range s.records {
+		records[key] = value
+	}
+	return &MemoryStorage{nodes: nodes, records: records, readOnly: true}, nil
+}
+
+// Close implements [Storage]
+func (s *MemoryStorage) Close() error {
+	return nil
+}
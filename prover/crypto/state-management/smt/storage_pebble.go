@@ -0,0 +1,155 @@
+package smt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/sirupsen/logrus"
+)
+
+// PebbleStorage is a [Storage] persisting the nodes in an embedded pebble
+// database. It is meant for the trees that do not fit in memory, such as the
+// full world state. Its snapshots are pebble snapshots and are cheap.
+type PebbleStorage struct {
+	// db is the database, nil for a snapshot
+	db *pebble.DB
+	// reader is the database or the snapshot
+	reader pebble.Reader
+	// snapshot is set for the snapshots only
+	snapshot *pebble.Snapshot
+}
+
+// OpenPebbleStorage opens, or creates, the pebble database in dir.
+func OpenPebbleStorage(dir string) (*PebbleStorage, error) {
+	db, err := pebble.Open(dir, &pebble.Options{Logger: logrus.StandardLogger()})
+	if err != nil {
+		return nil, fmt.Errorf("could not open the pebble database in %v: %w", dir, err)
+	}
+	return &PebbleStorage{db: db, reader: db}, nil
+}
+
+// GetNode implements [Storage]
+func (s *PebbleStorage) GetNode(level, pos int) (types.Bytes32, bool, error) {
+
+	value, closer, err := s.reader.Get(nodeID{Level: level, Pos: pos}.key())
+	if errors.Is(err, pebble.ErrNotFound) {
+		return types.Bytes32{}, false, nil
+	}
+	if err != nil {
+		return types.Bytes32{}, false, fmt.Errorf("could not read the node (%v, %v): %w", level, pos, err)
+	}
+	defer closer.Close()
+
+	if len(value) != len(types.Bytes32{}) {
+		return types.Bytes32{}, false, fmt.Errorf("the node (%v, %v) has %v bytes", level, pos, len(value))
+	}
+
+	return types.AsBytes32(value), true, nil
+}
+
+// GetRecord implements [Storage]
+func (s *PebbleStorage) GetRecord(key []byte) ([]byte, bool, error) {
+
+	value, closer, err := s.reader.Get(recordKey(key))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read the record %x: %w", key, err)
+	}
+	defer closer.Close()
+
+	return bytes.Clone(value), true, nil
+}
+
+// RangeRecords implements [Storage]
+func (s *PebbleStorage) RangeRecords(prefix []byte, f func(key, value []byte) error) error {
+
+	lower := recordKey(prefix)
+	iter, err := s.reader.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: prefixUpperBound(lower),
+	})
+	if err != nil {
+		return fmt.Errorf("could not iterate over the records: %w", err)
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := f(bytes.Clone(iter.Key()[1:]), bytes.Clone(iter.Value())); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+// prefixUpperBound returns the smallest key larger than all the keys starting
+// with the prefix. The prefix starts with [recordPrefix] so it cannot be all
+// 0xff bytes.
+func prefixUpperBound(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// Write implements [Storage]. The batch is synced on disk before returning.
+func (s *PebbleStorage) Write(batch *Batch) error {
+
+	if s.db == nil {
+		return ErrReadOnlyStorage
+	}
+
+	b := s.db.NewBatch()
+	defer b.Close()
+
+	for id, node := range batch.nodes {
+		if err := b.Set(id.key(), node[:], nil); err != nil {
+			return fmt.Errorf("could not write the node (%v, %v): %w", id.Level, id.Pos, err)
+		}
+	}
+
+	for key, value := range batch.records {
+		var err error
+		if value == nil {
+			err = b.Delete(recordKey([]byte(key)), nil)
+		} else {
+			err = b.Set(recordKey([]byte(key)), value, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("could not write the record %x: %w", key, err)
+		}
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("could not commit the batch: %w", err)
+	}
+
+	return nil
+}
+
+// Snapshot implements [Storage]
+func (s *PebbleStorage) Snapshot() (Storage, error) {
+	if s.db == nil {
+		return nil, ErrReadOnlyStorage
+	}
+	snapshot := s.db.NewSnapshot()
+	return &PebbleStorage{reader: snapshot, snapshot: snapshot}, nil
+}
+
+// Close implements [Storage]. The snapshots of the storage must be closed
+// before it.
+func (s *PebbleStorage) Close() error {
+	if s.snapshot != nil {
+		return s.snapshot.Close()
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,192 @@
+package smt_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Runs the test against every implementation of the storage
+func forEachStorage(t *testing.T, test func(t *testing.T, open func() smt.Storage)) {
+
+	t.Run("memory", func(t *testing.T) {
+		storage := smt.NewMemoryStorage()
+		test(t, func() smt.Storage { return storage })
+	})
+
+	t.Run("pebble", func(t *testing.T) {
+		dir := t.TempDir()
+		test(t, func() smt.Storage {
+			storage, err := smt.OpenPebbleStorage(dir)
+			require.NoError(t, err)
+			return storage
+		})
+	})
+}
+
+func TestStorageMatchesInMemoryTree(t *testing.T) {
+
+	config := &smt.Config{
+		HashFunc: hashtypes.Keccak,
+		Depth:    40,
+	}
+
+	forEachStorage(t, func(t *testing.T, open func() smt.Storage) {
+
+		reference := smt.NewEmptyTree(config)
+		tree, err := smt.OpenTree(config, open())
+		require.NoError(t, err)
+		require.Equal(t, reference.Root, tree.Root)
+
+		for pos := 0; pos < 100; pos++ {
+			reference.Update(pos*pos, RandBytes32(pos))
+			tree.Update(pos*pos, RandBytes32(pos))
+			require.Equal(t, reference.Root, tree.Root)
+		}
+
+		for pos := 0; pos < 200; pos++ {
+			require.Equal(t, reference.MustGetLeaf(pos), tree.MustGetLeaf(pos))
+			require.Equal(t, reference.MustProve(pos), tree.MustProve(pos))
+		}
+
+		// The tree is persisted in the storage and can be reopened
+		require.NoError(t, tree.Close())
+		reopened, err := smt.OpenTree(config, open())
+		require.NoError(t, err)
+		defer reopened.Close()
+
+		assert.Equal(t, reference.Root, reopened.Root)
+		assert.Equal(t, reference.MustProve(81), reopened.MustProve(81))
+
+		// But not with another depth
+		_, err = smt.OpenTree(&smt.Config{HashFunc: hashtypes.Keccak, Depth: 20}, reopened.Storage)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateBatch(t *testing.T) {
+
+	config := &smt.Config{
+		HashFunc: hashtypes.Keccak,
+		Depth:    40,
+	}
+
+	forEachStorage(t, func(t *testing.T, open func() smt.Storage) {
+
+		var (
+			reference = smt.NewEmptyTree(config)
+			inMemory  = smt.NewEmptyTree(config)
+			updates   []smt.LeafUpdate
+		)
+
+		// Includes neighbouring leaves and a leaf written twice
+		for _, pos := range []int{0, 1, 2, 7, 1000, 1001, 1 << 16, 2} {
+			leaf := RandBytes32(len(updates) + 1)
+			reference.Update(pos, leaf)
+			updates = append(updates, smt.LeafUpdate{Pos: pos, Leaf: leaf})
+		}
+
+		inMemory.UpdateBatch(updates)
+		assert.Equal(t, reference.Root, inMemory.Root)
+
+		tree, err := smt.OpenTree(config, open())
+		require.NoError(t, err)
+		defer tree.Close()
+
+		tree.UpdateBatch(updates)
+		assert.Equal(t, reference.Root, tree.Root)
+		assert.Equal(t, reference.MustProve(2), tree.MustProve(2))
+		assert.Equal(t, reference.MustProve(1<<16), tree.MustProve(1<<16))
+	})
+}
+
+func TestTreeSnapshot(t *testing.T) {
+
+	config := &smt.Config{
+		HashFunc: hashtypes.Keccak,
+		Depth:    40,
+	}
+
+	forEachStorage(t, func(t *testing.T, open func() smt.Storage) {
+
+		tree, err := smt.OpenTree(config, open())
+		require.NoError(t, err)
+		defer tree.Close()
+
+		tree.Update(3, RandBytes32(3))
+
+		snapshot, err := tree.Snapshot()
+		require.NoError(t, err)
+		defer snapshot.Close()
+
+		var (
+			root  = tree.Root
+			proof = tree.MustProve(3)
+		)
+
+		tree.Update(3, RandBytes32(4))
+		tree.Update(4, RandBytes32(5))
+		require.NotEqual(t, root, tree.Root)
+
+		// The snapshot is not affected by the updates
+		assert.Equal(t, root, snapshot.Root)
+		assert.Equal(t, RandBytes32(3), snapshot.MustGetLeaf(3))
+		assert.Equal(t, proof, snapshot.MustProve(3))
+		assert.True(t, proof.Verify(config, RandBytes32(3), snapshot.Root))
+
+		// And cannot be updated
+		assert.Panics(t, func() { snapshot.Update(3, RandBytes32(6)) })
+	})
+}
+
+func TestTreeRecords(t *testing.T) {
+
+	config := &smt.Config{
+		HashFunc: hashtypes.Keccak,
+		Depth:    40,
+	}
+
+	forEachStorage(t, func(t *testing.T, open func() smt.Storage) {
+
+		tree, err := smt.OpenTree(config, open())
+		require.NoError(t, err)
+
+		tree.SetRecord([]byte("a/2"), []byte("two"))
+		tree.SetRecord([]byte("a/1"), []byte("one"))
+		tree.SetRecord([]byte("b/1"), []byte("other"))
+
+		// The records are only written by the next update of the tree
+		_, found, err := tree.Storage.GetRecord([]byte("a/1"))
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		tree.Update(1, RandBytes32(1))
+
+		tree.DeleteRecord([]byte("a/2"))
+		tree.SetRecord([]byte("a/3"), []byte("three"))
+		tree.Update(2, RandBytes32(2))
+		require.NoError(t, tree.Close())
+
+		// The records are persisted along with the tree
+		storage := open()
+		defer storage.Close()
+
+		value, found, err := storage.GetRecord([]byte("b/1"))
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []byte("other"), value)
+
+		var keys, values []string
+		err = storage.RangeRecords([]byte("a/"), func(key, value []byte) error {
+			keys = append(keys, string(key))
+			values = append(values, string(value))
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a/1", "a/3"}, keys)
+		assert.Equal(t, []string{"one", "three"}, values)
+	})
+}
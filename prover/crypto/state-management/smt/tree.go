@@ -41,6 +41,13 @@ type Tree struct {
 	// So there are 39, and not 40 levels. That way, the indexing stays
 	// consistent with "OccupiedNode"
 	EmptyNodes []types.Bytes32
+	// Storage, when set, stores the leaves and the nodes of the tree in place
+	// of OccupiedLeaves and OccupiedNodes, which are then left empty. See
+	// [OpenTree].
+	Storage Storage
+	// pending collects the nodes updated in the storage until they are written
+	// at the end of the update.
+	pending *Batch
 }
 
 // EmptyLeaf returns an empty leaf (e.g. the zero bytes value).
@@ -82,6 +89,102 @@ func NewEmptyTree(conf *Config) *Tree {
 	}
 }
 
+// OpenTree returns the tree stored in the storage, or a new empty tree if the
+// storage is empty. It returns an error if the storage holds a tree with
+// another depth. The hash function is not recorded in the storage and must
+// be the one the tree was built with.
+func OpenTree(conf *Config, storage Storage) (*Tree, error) {
+
+	tree := NewEmptyTree(conf)
+	tree.OccupiedNodes = nil
+	tree.Storage = storage
+	tree.pending = NewBatch()
+
+	depth, found, err := storage.GetNode(metaLevel, metaDepthPos)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the depth of the tree: %w", err)
+	}
+
+	// The storage is empty, the empty tree is written into it so that it is
+	// not reopened with another depth.
+	if !found {
+		tree.flush()
+		return tree, nil
+	}
+
+	if d := decodeDepth(depth); d != conf.Depth {
+		return nil, fmt.Errorf("the storage holds a tree of depth %v, expected %v", d, conf.Depth)
+	}
+
+	root, found, err := storage.GetNode(metaLevel, metaRootPos)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the root of the tree: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("the storage holds a tree without root")
+	}
+
+	tree.Root = root
+	return tree, nil
+}
+
+// Snapshot returns a read-only copy of the tree in its current state. The
+// subsequent updates of the tree do not affect the snapshot. For a tree with
+// a [Storage], the snapshot is taken by the storage and must be closed with
+// [Tree.Close]; updating it panics.
+func (t *Tree) Snapshot() (*Tree, error) {
+
+	snapshot := &Tree{
+		Config:     t.Config,
+		Root:       t.Root,
+		EmptyNodes: t.EmptyNodes,
+	}
+
+	if t.Storage == nil {
+		snapshot.OccupiedLeaves = append([]types.Bytes32{}, t.OccupiedLeaves...)
+		snapshot.OccupiedNodes = make([][]types.Bytes32, len(t.OccupiedNodes))
+		for i := range t.OccupiedNodes {
+			snapshot.OccupiedNodes[i] = append([]types.Bytes32{}, t.OccupiedNodes[i]...)
+		}
+		return snapshot, nil
+	}
+
+	storage, err := t.Storage.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("could not snapshot the storage: %w", err)
+	}
+
+	snapshot.Storage = storage
+	snapshot.pending = NewBatch()
+	return snapshot, nil
+}
+
+// Close closes the storage of the tree, if any.
+func (t *Tree) Close() error {
+	if t.Storage == nil {
+		return nil
+	}
+	return t.Storage.Close()
+}
+
+// SetRecord sets a record in the storage of the tree (see [Storage]). The
+// record is written along with the nodes at the next update of the tree so
+// that the two stay consistent. It panics if the tree has no storage.
+func (t *Tree) SetRecord(key, value []byte) {
+	if t.Storage == nil {
+		utils.Panic("the tree has no storage to hold the record %x", key)
+	}
+	t.pending.SetRecord(key, value)
+}
+
+// DeleteRecord works as [Tree.SetRecord] but deletes the record
+func (t *Tree) DeleteRecord(key []byte) {
+	if t.Storage == nil {
+		utils.Panic("the tree has no storage to hold the record %x", key)
+	}
+	t.pending.DeleteRecord(key)
+}
+
 // GetLeaf returns a leaf by position or an error if the leaf is out of bounds.
 func (t *Tree) GetLeaf(pos int) (types.Bytes32, error) {
 	// Check that the accessed node is within the bounds of the SMT
@@ -93,6 +196,9 @@ func (t *Tree) GetLeaf(pos int) (types.Bytes32, error) {
 	if pos < 0 {
 		return types.Bytes32{}, fmt.Errorf("negative position: %v", pos)
 	}
+	if t.Storage != nil {
+		return t.getStoredNode(0, pos), nil
+	}
 	// Check if this is an empty leaf
 	if pos >= len(t.OccupiedLeaves) {
 		return EmptyLeaf(), nil
@@ -135,6 +241,9 @@ func (t *Tree) getNode(level, posInLevel int) types.Bytes32 {
 		if posInLevel >= maxPos {
 			utils.Panic("nodeID is out of bound")
 		}
+		if t.Storage != nil {
+			return t.getStoredNode(level, posInLevel)
+		}
 		// Check if this is an empty node
 		if posInLevel >= len(t.OccupiedNodes[level-1]) {
 			return t.EmptyNodes[level-1]
@@ -182,6 +291,10 @@ func (t *Tree) updateNode(level, posInLevel int, newVal types.Bytes32) {
 		if posInLevel >= maxPos {
 			utils.Panic("node is out of bound level %v (maxPos %v), pos=%v", level, maxPos, posInLevel)
 		}
+		if t.Storage != nil {
+			t.pending.Set(level, posInLevel, newVal)
+			return
+		}
 		// Check if this is an empty node : and reserve if necessary
 		if posInLevel >= len(t.OccupiedNodes[level-1]) {
 			t.reserveLevel(level, posInLevel+1)
@@ -194,6 +307,10 @@ func (t *Tree) updateNode(level, posInLevel int, newVal types.Bytes32) {
 		if posInLevel >= maxPos {
 			utils.Panic("nodeID is out of bound")
 		}
+		if t.Storage != nil {
+			t.pending.Set(0, posInLevel, newVal)
+			return
+		}
 		// Check if this is an empty leaf
 		if posInLevel >= len(t.OccupiedLeaves) {
 			t.reserveLevel(0, posInLevel+1)
@@ -205,6 +322,51 @@ func (t *Tree) updateNode(level, posInLevel int, newVal types.Bytes32) {
 	}
 }
 
+// getStoredNode returns a leaf or an intermediate node of a tree with a
+// storage. The nodes updated but not yet written into the storage take
+// precedence.
+func (t *Tree) getStoredNode(level, posInLevel int) types.Bytes32 {
+
+	if node, ok := t.pending.Get(level, posInLevel); ok {
+		return node
+	}
+
+	node, found, err := t.Storage.GetNode(level, posInLevel)
+	if err != nil {
+		utils.Panic("could not read the node (%v, %v): %v", level, posInLevel, err)
+	}
+
+	switch {
+	case !found && level == 0:
+		return EmptyLeaf()
+	case !found:
+		return t.EmptyNodes[level-1]
+	case level > 0 && node == (types.Bytes32{}):
+		utils.Panic("sanity-check : intermediary node is 0")
+	}
+
+	return node
+}
+
+// flush writes the nodes updated since the last flush, along with the root
+// and the depth, into the storage of the tree. It does nothing if the tree
+// has no storage.
+func (t *Tree) flush() {
+
+	if t.Storage == nil {
+		return
+	}
+
+	t.pending.Set(metaLevel, metaRootPos, t.Root)
+	t.pending.Set(metaLevel, metaDepthPos, encodeDepth(t.Config.Depth))
+
+	if err := t.Storage.Write(t.pending); err != nil {
+		utils.Panic("could not write the updated nodes: %v", err)
+	}
+
+	t.pending.reset()
+}
+
 // reserveLevel extends the `OccupiedLeaves` and `OccupiedNodes` fields of the
 // tree by appending `trivial nodes` to the specified level/
 //
@@ -33,4 +33,56 @@ func (t *Tree) Update(pos int, newVal types.Bytes32) {
 	}
 
 	t.Root = current
+	t.flush()
+}
+
+// LeafUpdate is a leaf to overwrite in [Tree.UpdateBatch]
+type LeafUpdate struct {
+	Pos  int
+	Leaf types.Bytes32
+}
+
+// UpdateBatch overwrites several leaves in the tree and updates the parent
+// nodes. It yields the same tree as calling [Tree.Update] on each leaf in
+// order, but every parent node is only hashed once and, for a tree with a
+// [Storage], all the nodes are written at once.
+func (t *Tree) UpdateBatch(updates []LeafUpdate) {
+
+	depth := t.Config.Depth
+	dirty := make(map[int]struct{}, len(updates))
+
+	for _, u := range updates {
+		if u.Pos < 0 || u.Pos >= 1<<depth {
+			utils.Panic("out of bound %v", u.Pos)
+		}
+		t.updateNode(0, u.Pos, u.Leaf)
+		dirty[u.Pos] = struct{}{}
+	}
+
+	if len(dirty) == 0 {
+		return
+	}
+
+	// Recomputes the parents of the dirty nodes level by level. The root is
+	// set separately as it is not a node of the storage.
+	for level := 0; level < depth; level++ {
+
+		parents := make(map[int]struct{}, (len(dirty)+1)/2)
+		for idx := range dirty {
+			parents[idx>>1] = struct{}{}
+		}
+
+		for parent := range parents {
+			current := hashLR(t.Config, t.getNode(level, 2*parent), t.getNode(level, 2*parent+1))
+			if level+1 == depth {
+				t.Root = current
+				continue
+			}
+			t.updateNode(level+1, parent, current)
+		}
+
+		dirty = parents
+	}
+
+	t.flush()
 }
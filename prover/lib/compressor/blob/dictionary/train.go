@@ -0,0 +1,233 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/compress/lzss"
+)
+
+// minDmerSize is the length under which a substring is hardly worth a
+// back-reference.
+const minDmerSize = 4
+
+// TrainingConfig parametrizes [Train]
+type TrainingConfig struct {
+	// Size is the maximum size of the dictionary in bytes. It must match the
+	// dictionary size the decompression circuit is compiled with.
+	Size int
+	// SegmentSize is the size of the segments of the corpus the dictionary
+	// is made of.
+	SegmentSize int
+	// DmerSize is the size of the substrings whose frequencies are used to
+	// score the segments. It must be in [minDmerSize, 8].
+	DmerSize int
+}
+
+// DefaultTrainingConfig returns a config building dictionaries of the given
+// size.
+func DefaultTrainingConfig(size int) TrainingConfig {
+	return TrainingConfig{
+		Size:        size,
+		SegmentSize: 512,
+		DmerSize:    8,
+	}
+}
+
+// Train builds a dictionary out of the samples. The dictionary is a
+// concatenation of segments of the samples, chosen greedily so as to cover
+// the substrings of length DmerSize found in the largest numbers of samples.
+// Substrings repeated within a single sample are not favored since the
+// compressor can already refer to their previous occurrence.
+//
+// The corpus is divided in epochs in each of which one segment is picked at a
+// time, so that the dictionary does not overfit to a portion of the corpus.
+//
+// The returned dictionary already contains the special symbols of the
+// compressor (see [lzss.AugmentDict]), so that it is stored and checksummed as
+// used by the compressor. Its size is at most Size.
+func Train(samples [][]byte, cfg TrainingConfig) ([]byte, error) {
+
+	if cfg.Size <= 2 {
+		return nil, fmt.Errorf("dictionary size %v too small", cfg.Size)
+	}
+	if cfg.DmerSize < minDmerSize || cfg.DmerSize > 8 {
+		return nil, fmt.Errorf("dmer size must be in [%v, 8], got %v", minDmerSize, cfg.DmerSize)
+	}
+	if cfg.SegmentSize < cfg.DmerSize {
+		return nil, fmt.Errorf("segment size %v smaller than the dmer size %v", cfg.SegmentSize, cfg.DmerSize)
+	}
+
+	t := newTrainer(samples, cfg)
+	if len(t.corpus) == 0 {
+		return nil, errors.New("empty corpus")
+	}
+
+	var (
+		dict      = make([]byte, 0, cfg.Size)
+		nbEpochs  = max(1, min((cfg.Size+cfg.SegmentSize-1)/cfg.SegmentSize, len(t.corpus)/cfg.SegmentSize))
+		epochSize = (len(t.corpus) + nbEpochs - 1) / nbEpochs
+		exhausted = make([]bool, nbEpochs)
+	)
+
+	for nbExhausted := 0; len(dict) < cfg.Size && nbExhausted < nbEpochs; {
+		for e := 0; e < nbEpochs && len(dict) < cfg.Size; e++ {
+			if exhausted[e] {
+				continue
+			}
+
+			begin, end := t.bestSegment(e*epochSize, min((e+1)*epochSize, len(t.corpus)))
+			if begin == end {
+				exhausted[e] = true
+				nbExhausted++
+				continue
+			}
+
+			t.cover(begin, end)
+			end = min(end, begin+cfg.Size-len(dict))
+			dict = append(dict, t.corpus[begin:end]...)
+		}
+	}
+
+	// make room for the special symbols if needed
+	if len(lzss.AugmentDict(dict[:len(dict):len(dict)])) > cfg.Size {
+		dict = dict[:cfg.Size-2]
+	}
+
+	return lzss.AugmentDict(dict), nil
+}
+
+type trainer struct {
+	corpus []byte
+	d, k   int
+	// ids[i] identifies the dmer starting at position i of the corpus, or is
+	// -1 if it crosses the end of a sample.
+	ids []int32
+	// freqs[id] is the number of samples containing the dmer, or 0 once it
+	// is covered by the dictionary.
+	freqs []uint32
+	// active is the number of occurrences of every dmer in the current window
+	active []uint32
+}
+
+func newTrainer(samples [][]byte, cfg TrainingConfig) *trainer {
+
+	t := &trainer{d: cfg.DmerSize, k: cfg.SegmentSize}
+
+	size := 0
+	for i := range samples {
+		size += len(samples[i])
+	}
+	t.corpus = make([]byte, 0, size)
+	t.ids = make([]int32, 0, size)
+
+	var (
+		dmerIds    = make(map[uint64]int32)
+		lastSample []int
+	)
+
+	for s, sample := range samples {
+		t.corpus = append(t.corpus, sample...)
+		for i := range sample {
+			if i+t.d > len(sample) {
+				t.ids = append(t.ids, -1)
+				continue
+			}
+
+			var key uint64
+			for _, b := range sample[i : i+t.d] {
+				key = key<<8 | uint64(b)
+			}
+
+			id, ok := dmerIds[key]
+			if !ok {
+				id = int32(len(t.freqs))
+				dmerIds[key] = id
+				t.freqs = append(t.freqs, 0)
+				lastSample = append(lastSample, -1)
+			}
+			if lastSample[id] != s {
+				lastSample[id] = s
+				t.freqs[id]++
+			}
+			t.ids = append(t.ids, id)
+		}
+	}
+
+	// a dmer found in a single sample is of no use to compress other data
+	for id := range t.freqs {
+		if t.freqs[id] < 2 {
+			t.freqs[id] = 0
+		}
+	}
+
+	t.active = make([]uint32, len(t.freqs))
+	return t
+}
+
+// bestSegment returns the segment of at most k bytes of corpus[begin:end]
+// covering the dmers of highest total frequency, trimmed of its uncovered
+// ends. The segment is empty if no dmer of positive frequency remains.
+func (t *trainer) bestSegment(begin, end int) (segBegin, segEnd int) {
+
+	var (
+		nbDmers         = t.k - t.d + 1
+		score, maxScore uint64
+	)
+
+	// the window holds the dmers starting in [i-nbDmers+1, i]
+	for i := begin; i < end; i++ {
+		if id := t.ids[i]; id >= 0 {
+			if t.active[id] == 0 {
+				score += uint64(t.freqs[id])
+			}
+			t.active[id]++
+		}
+		if j := i - nbDmers; j >= begin {
+			if id := t.ids[j]; id >= 0 {
+				t.active[id]--
+				if t.active[id] == 0 {
+					score -= uint64(t.freqs[id])
+				}
+			}
+		}
+		if score > maxScore {
+			maxScore = score
+			segBegin, segEnd = max(begin, i-nbDmers+1), i+1
+		}
+	}
+
+	for i := max(begin, end-nbDmers); i < end; i++ {
+		if id := t.ids[i]; id >= 0 {
+			t.active[id]--
+		}
+	}
+
+	// trim the dmers of zero frequency, segEnd still bounding the dmer starts
+	for segBegin < segEnd && !t.isUseful(segBegin) {
+		segBegin++
+	}
+	for segEnd > segBegin && !t.isUseful(segEnd-1) {
+		segEnd--
+	}
+	if segBegin == segEnd {
+		return 0, 0
+	}
+
+	return segBegin, segEnd - 1 + t.d
+}
+
+func (t *trainer) isUseful(i int) bool {
+	id := t.ids[i]
+	return id >= 0 && t.freqs[id] > 0
+}
+
+// cover zeroes the frequencies of the dmers of the segment, so that they are
+// not counted again for the next segments.
+func (t *trainer) cover(begin, end int) {
+	for i := begin; i+t.d <= end; i++ {
+		if id := t.ids[i]; id >= 0 {
+			t.freqs[id] = 0
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package dictionary
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/consensys/compress/lzss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrain(t *testing.T) {
+
+	var (
+		rng     = rand.New(rand.NewChaCha8([32]byte{}))
+		pattern = randBytes(rng, 100)
+		samples = make([][]byte, 50)
+	)
+
+	// every sample contains the pattern among random bytes
+	for i := range samples {
+		samples[i] = append(randBytes(rng, rng.IntN(300)), pattern...)
+		samples[i] = append(samples[i], randBytes(rng, rng.IntN(300))...)
+	}
+
+	cfg := TrainingConfig{Size: 1024, SegmentSize: 128, DmerSize: 8}
+	dict, err := Train(samples, cfg)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, len(dict), cfg.Size)
+	assert.True(t, bytes.Contains(dict, pattern), "the pattern common to all the samples must be in the dictionary")
+	assert.Equal(t, dict, lzss.AugmentDict(bytes.Clone(dict)), "the dictionary must contain the special symbols")
+
+	// the random bytes are not worth including
+	assert.Less(t, len(dict), 2*len(pattern))
+
+	// deterministic
+	dict2, err := Train(samples, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, dict, dict2)
+
+	// a dictionary smaller than the pattern is filled up
+	cfg.Size = 40
+	dict, err = Train(samples, cfg)
+	require.NoError(t, err)
+	assert.Len(t, dict, cfg.Size)
+	// a few bytes around the pattern may be shared by chance by two samples
+	assert.True(t, bytes.Contains(dict, pattern[:cfg.Size/2]))
+}
+
+func TestTrainErrors(t *testing.T) {
+	samples := [][]byte{[]byte("some sample"), []byte("another sample")}
+
+	for name, cfg := range map[string]TrainingConfig{
+		"size":         {Size: 2, SegmentSize: 64, DmerSize: 8},
+		"dmer-small":   {Size: 1024, SegmentSize: 64, DmerSize: 3},
+		"dmer-large":   {Size: 1024, SegmentSize: 64, DmerSize: 9},
+		"segment-size": {Size: 1024, SegmentSize: 4, DmerSize: 8},
+	} {
+		_, err := Train(samples, cfg)
+		assert.Error(t, err, name)
+	}
+
+	_, err := Train(nil, DefaultTrainingConfig(1024))
+	assert.Error(t, err)
+}
+
+func randBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(rng.Uint32())
+	}
+	return b
+}
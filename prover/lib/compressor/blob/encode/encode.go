@@ -11,6 +11,7 @@ import (
 	typesLinea "github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
 	"github.com/icza/bitio"
 	"io"
 	"math/big"
@@ -253,6 +254,12 @@ func InjectFromAddressIntoR(txData types.TxData, from *common.Address) *types.Tr
 		tx.R.SetBytes(from[:])
 		tx.S = big.NewInt(1)
 		return types.NewTx(&tx)
+	case *types.SetCodeTx:
+		tx := *txData
+		tx.R = new(uint256.Int)
+		tx.R.SetBytes(from[:])
+		tx.S = uint256.NewInt(1)
+		return types.NewTx(&tx)
 	default:
 		panic("unexpected transaction type")
 	}
@@ -0,0 +1,247 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/encode"
+	v0 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Inspection is the content of a blob as decoded by [Inspect]. It is meant to
+// audit the data submitted on L1.
+type Inspection struct {
+	// Version of the blob as returned by [GetVersion]
+	Version uint16 `json:"version"`
+	// DictChecksum is the checksum of the dictionary used to compress the blob
+	DictChecksum hexutil.Bytes `json:"dictChecksum"`
+	// Batches lists the blocks of every batch of the blob
+	Batches [][]InspectedBlock `json:"batches"`
+}
+
+// InspectedBlock is a block of an [Inspection]
+type InspectedBlock struct {
+	// Raw is the block as encoded in the uncompressed payload of the blob
+	Raw hexutil.Bytes `json:"-"`
+	// Data is the decoded block
+	Data encode.DecodedBlockData `json:"-"`
+
+	// The hash is only encoded in the blobs of version 1
+	Hash      *common.Hash  `json:"hash,omitempty"`
+	Timestamp uint64        `json:"timestamp"`
+	Txs       []InspectedTx `json:"txs"`
+	RawSize   int           `json:"rawSize"`
+}
+
+// InspectedTx is a summary of a transaction of an [InspectedBlock]
+type InspectedTx struct {
+	From     common.Address  `json:"from"`
+	Type     uint8           `json:"type"`
+	ChainID  *hexutil.Big    `json:"chainId,omitempty"`
+	Nonce    uint64          `json:"nonce"`
+	To       *common.Address `json:"to"`
+	Value    *hexutil.Big    `json:"value"`
+	Gas      uint64          `json:"gas"`
+	DataSize int             `json:"dataSize"`
+	// AuthListSize is the number of authorizations of a set-code transaction
+	AuthListSize int `json:"authListSize,omitempty"`
+}
+
+// Inspect decompresses the blob and decodes all its blocks. The dictionary
+// used to compress the blob must be in the store.
+func Inspect(blob []byte, dictStore dictionary.Store) (*Inspection, error) {
+
+	var (
+		res = &Inspection{Version: GetVersion(blob)}
+		// blocks lists the raw blocks of every batch
+		blocks  [][][]byte
+		decoder func(*bytes.Reader) (encode.DecodedBlockData, error)
+	)
+
+	switch res.Version {
+	case 0:
+		header, _, flat, err := v0.DecompressBlob(blob, dictStore)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress the blob: %w", err)
+		}
+		res.DictChecksum = header.DictChecksum[:]
+		for i := 0; i < header.NbBatches(); i++ {
+			n := header.NbBlocksInBatch(i)
+			blocks = append(blocks, flat[:n])
+			flat = flat[n:]
+		}
+		decoder = v0.DecodeBlockFromUncompressed
+	case 1:
+		r, err := v1.DecompressBlob(blob, dictStore)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress the blob: %w", err)
+		}
+		res.DictChecksum = r.Header.DictChecksum[:]
+		flat := r.Blocks
+		for _, batchSize := range r.Header.BatchSizes {
+			var batch [][]byte
+			for ; batchSize > 0; flat = flat[1:] {
+				batch = append(batch, flat[0])
+				batchSize -= len(flat[0])
+			}
+			blocks = append(blocks, batch)
+		}
+		decoder = v1.DecodeBlockFromUncompressed
+	default:
+		return nil, errors.New("unrecognized blob version")
+	}
+
+	res.Batches = make([][]InspectedBlock, len(blocks))
+	for i := range blocks {
+		res.Batches[i] = make([]InspectedBlock, len(blocks[i]))
+		for j, raw := range blocks[i] {
+			data, err := decoder(bytes.NewReader(raw))
+			if err != nil {
+				return nil, fmt.Errorf("could not decode block #%v of batch #%v: %w", j, i, err)
+			}
+			res.Batches[i][j] = inspectBlock(raw, data, res.Version)
+		}
+	}
+
+	return res, nil
+}
+
+func inspectBlock(raw []byte, data encode.DecodedBlockData, version uint16) InspectedBlock {
+
+	block := InspectedBlock{
+		Raw:       raw,
+		Data:      data,
+		Timestamp: data.Timestamp,
+		Txs:       make([]InspectedTx, len(data.Txs)),
+		RawSize:   len(raw),
+	}
+
+	if version >= 1 {
+		block.Hash = &data.BlockHash
+	}
+
+	for i := range data.Txs {
+		tx := types.NewTx(data.Txs[i])
+		block.Txs[i] = InspectedTx{
+			From:         data.Froms[i],
+			Type:         tx.Type(),
+			Nonce:        tx.Nonce(),
+			To:           tx.To(),
+			Value:        (*hexutil.Big)(tx.Value()),
+			Gas:          tx.Gas(),
+			DataSize:     len(tx.Data()),
+			AuthListSize: len(tx.SetCodeAuthorizations()),
+		}
+		if tx.Type() != types.LegacyTxType {
+			block.Txs[i].ChainID = (*hexutil.Big)(tx.ChainId())
+		}
+	}
+
+	return block
+}
+
+// NbBlocks returns the number of blocks in the blob
+func (in *Inspection) NbBlocks() int {
+	n := 0
+	for i := range in.Batches {
+		n += len(in.Batches[i])
+	}
+	return n
+}
+
+// ChainID returns the chain ID of the first typed transaction of the blob and
+// nil if there is none. The legacy transactions do not encode their chain ID
+// once decoded.
+func (in *Inspection) ChainID() *big.Int {
+	for i := range in.Batches {
+		for j := range in.Batches[i] {
+			for _, tx := range in.Batches[i][j].Txs {
+				if tx.ChainID != nil {
+					return tx.ChainID.ToInt()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRoundTrip re-encodes the decoded block with the encoder of the blob
+// maker, see [v1.EncodeBlockForCompression], and checks that it matches the raw
+// block byte for byte. Only the blocks of the blobs of version 1 are
+// supported.
+//
+// The decoding of a legacy transaction drops its chain ID, so both its
+// EIP-155 and its pre-EIP-155 encodings are tried. The EIP-155 one requires
+// the chain ID of the network, it is only tried if chainID is not nil.
+func (b *InspectedBlock) CheckRoundTrip(version uint16, chainID *big.Int) error {
+
+	if version != 1 {
+		return fmt.Errorf("the round-trip check is not supported for blobs of version %v", version)
+	}
+
+	var (
+		d   = &b.Data
+		buf = &bytes.Buffer{}
+	)
+
+	v1.EncodeBlockHeaderForCompression(uint16(len(d.Txs)), uint32(d.Timestamp), d.BlockHash, buf)
+
+	for i := range d.Txs {
+
+		matched := false
+		for _, candidate := range txCandidates(d.Txs[i], chainID) {
+
+			encoded := &bytes.Buffer{}
+			if err := v1.EncodeTxWithSenderForCompression(d.Froms[i], candidate, encoded); err != nil {
+				return fmt.Errorf("could not re-encode transaction #%v: %w", i, err)
+			}
+
+			if bytes.HasPrefix(b.Raw[buf.Len():], encoded.Bytes()) {
+				buf.Write(encoded.Bytes())
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("transaction #%v does not re-encode into the bytes of the blob", i)
+		}
+	}
+
+	if !bytes.Equal(buf.Bytes(), b.Raw) {
+		return fmt.Errorf("the re-encoded block does not match the bytes of the blob: %v bytes vs %v bytes", buf.Len(), len(b.Raw))
+	}
+
+	return nil
+}
+
+// txCandidates returns the transactions that a decoded transaction may have
+// been before its encoding. Only the legacy transactions are ambiguous.
+func txCandidates(txData types.TxData, chainID *big.Int) (candidates []*types.Transaction) {
+
+	legacy, ok := txData.(*types.LegacyTx)
+	if !ok {
+		return []*types.Transaction{types.NewTx(txData)}
+	}
+
+	// Without V, the transaction is not protected
+	unprotected := *legacy
+	unprotected.V, unprotected.R, unprotected.S = nil, nil, nil
+	candidates = append(candidates, types.NewTx(&unprotected))
+
+	if chainID != nil {
+		// The smallest EIP-155 V for the chain ID
+		protected := unprotected
+		protected.V = new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(35))
+		candidates = append(candidates, types.NewTx(&protected))
+	}
+
+	return candidates
+}
@@ -0,0 +1,106 @@
+package blob_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	blobv1testing "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/test_utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectV0(t *testing.T) {
+	dictStore := dictionary.NewStore()
+	require.NoError(t, dictStore.Load(dictPath))
+
+	files, err := filepath.Glob("testdata/v0/*.bin")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		blobData := withNoError(t, os.ReadFile, file)
+		inspection, err := blob.Inspect(blobData, dictStore)
+		require.NoError(t, err, file)
+
+		assert.Equal(t, uint16(0), inspection.Version)
+		assert.NotZero(t, inspection.NbBlocks())
+		for _, batch := range inspection.Batches {
+			for _, block := range batch {
+				assert.Nil(t, block.Hash, "the blobs of version 0 do not encode the block hash")
+				assert.Error(t, block.CheckRoundTrip(inspection.Version, nil))
+			}
+		}
+	}
+}
+
+func TestInspectV1RoundTrip(t *testing.T) {
+	dictStore := dictionary.NewStore()
+	require.NoError(t, dictStore.Load(dictPath))
+
+	files, err := filepath.Glob("testdata/v1/prover-responses/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		var response blobdecompression.Response
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		require.NoError(t, json.NewDecoder(f).Decode(&response))
+		require.NoError(t, f.Close())
+
+		blobData, err := base64.StdEncoding.DecodeString(response.CompressedData)
+		require.NoError(t, err)
+
+		inspection, err := blob.Inspect(blobData, dictStore)
+		require.NoError(t, err, file)
+		assert.Equal(t, uint16(1), inspection.Version)
+
+		assertRoundTrip(t, inspection)
+	}
+}
+
+func TestInspectTinyTwoBatchBlob(t *testing.T) {
+	dictStore, err := dictionary.SingletonStore(blobv1testing.GetDict(t), 1)
+	require.NoError(t, err)
+
+	blobData := blobv1testing.TinyTwoBatchBlob(t)
+	inspection, err := blob.Inspect(blobData, dictStore)
+	require.NoError(t, err)
+	require.Len(t, inspection.Batches, 2)
+
+	expected, err := v1.DecompressBlob(blobData, dictStore)
+	require.NoError(t, err)
+	assert.Equal(t, len(expected.Blocks), inspection.NbBlocks())
+	assert.Equal(t, expected.Header.DictChecksum[:], []byte(inspection.DictChecksum))
+
+	assertRoundTrip(t, inspection)
+
+	// Padding the blob to the size of a 4844 blob does not change its content
+	padded := make([]byte, v1.MaxUsableBytes)
+	copy(padded, blobData)
+	inspectionPadded, err := blob.Inspect(padded, dictStore)
+	require.NoError(t, err)
+	assert.Equal(t, inspection, inspectionPadded)
+
+	// Tampering with a block is detected by the round-trip check
+	block := inspection.Batches[0][0]
+	block.Raw = append([]byte{}, block.Raw...)
+	block.Raw[len(block.Raw)-1] ^= 1
+	assert.Error(t, block.CheckRoundTrip(inspection.Version, inspection.ChainID()))
+}
+
+func assertRoundTrip(t *testing.T, inspection *blob.Inspection) {
+	chainID := inspection.ChainID()
+	for i, batch := range inspection.Batches {
+		for j, block := range batch {
+			assert.NoError(t, block.CheckRoundTrip(inspection.Version, chainID), "block #%v of batch #%v", j, i)
+		}
+	}
+}
@@ -26,6 +26,7 @@ const (
 	// These also impact the circuit constraints (compile / setup time)
 	MaxUncompressedBytes = 756240    // ~738.5KB defines the max size we can handle for a blob (uncompressed) input
 	MaxUsableBytes       = 32 * 4096 // defines the number of bytes available in a blob
+	MaxNbBatches         = 100       // defines the number of batches the decompression circuit accepts in a blob
 )
 
 // BlobMaker is a bm for RLP encoded blocks (see EIP-4844).
@@ -139,18 +140,30 @@ func (bm *BlobMaker) Bytes() []byte {
 // Write attempts to append the RLP block to the current batch.
 // if forceReset is set; this will NOT append the bytes but still returns true if the chunk could have been appended
 func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error) {
+	ok, _, err = bm.write(rlpBlock, forceReset)
+	return ok, err
+}
+
+// write implements Write and tells why the block was rejected, if it was
+// rejected without error.
+func (bm *BlobMaker) write(rlpBlock []byte, forceReset bool) (ok bool, reason RejectionReason, err error) {
 	prevLen := bm.compressor.Written()
 
 	// decode the RLP block.
 	var block types.Block
 	if err = rlp.Decode(bytes.NewReader(rlpBlock), &block); err != nil {
-		return false, fmt.Errorf("when decoding input RLP block: %w", err)
+		return false, NotRejected, fmt.Errorf("when decoding input RLP block: %w", err)
+	}
+
+	// the block would start a new batch
+	if len(bm.header.CurrBatchBlocksLen) == 0 && len(bm.header.BatchSizes) >= MaxNbBatches {
+		return false, RejectedTooManyBatches, nil
 	}
 
 	// re-encode it for compression
 	bm.buf.Reset()
 	if err = EncodeBlockForCompression(&block, &bm.buf); err != nil {
-		return false, fmt.Errorf("when re-encoding block for compression: %w", err)
+		return false, NotRejected, fmt.Errorf("when re-encoding block for compression: %w", err)
 	}
 	blockLen := bm.buf.Len()
 
@@ -166,9 +179,9 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 		// 2. we exceed the maximum input size of 2Mb (shouldn't happen either)
 		// In both cases, we can't do anything, so we reset the state.
 		if innerErr := bm.compressor.Revert(); innerErr != nil {
-			return false, fmt.Errorf("when reverting compressor because writing failed: %w\noriginal error: %w", innerErr, err)
+			return false, NotRejected, fmt.Errorf("when reverting compressor because writing failed: %w\noriginal error: %w", innerErr, err)
 		}
-		return false, fmt.Errorf("when writing block to compressor: %w", err)
+		return false, NotRejected, fmt.Errorf("when writing block to compressor: %w", err)
 	}
 
 	// increment length of the current batch
@@ -178,7 +191,7 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 	if _, err = bm.header.WriteTo(&bm.buf); err != nil {
 		// only possible error is an underlying writer error (shouldn't happen we use a simple in-memory buffer)
 		bm.header.removeLastBlock()
-		return false, fmt.Errorf("when writing header to buffer: %w", err)
+		return false, NotRejected, fmt.Errorf("when writing header to buffer: %w", err)
 	}
 
 	// check that the header + the uncompressed data is "decompressable" in the circuit
@@ -187,10 +200,10 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 		// and our decompression circuit is not able to handle the uncompressed data.
 		// we should reset the state.
 		if err := bm.compressor.Revert(); err != nil {
-			return false, fmt.Errorf("when reverting compressor because uncompressed blob is > maxUncompressedSize: %w", err)
+			return false, NotRejected, fmt.Errorf("when reverting compressor because uncompressed blob is > maxUncompressedSize: %w", err)
 		}
 		bm.header.removeLastBlock()
-		return false, nil
+		return false, RejectedDecompressorLimit, nil
 	}
 
 	fitsInBlob := func() bool {
@@ -224,7 +237,7 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 				err = fmt.Errorf("%w\n\tto recover from write failure: %w", innerErr, err)
 			}
 
-			return false, err
+			return false, NotRejected, err
 		}
 		if fitsInBlob() {
 			goto bypass
@@ -241,17 +254,17 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 
 		// discard.
 		if err = revert(); err != nil {
-			return false, fmt.Errorf("when reverting compressor because blob is full: %w", err)
+			return false, NotRejected, fmt.Errorf("when reverting compressor because blob is full: %w", err)
 		}
-		return false, nil
+		return false, RejectedBlobFull, nil
 	}
 bypass:
 	if forceReset {
 		// we don't want to append the data, but we could have.
 		if err = revert(); err != nil {
-			return false, fmt.Errorf("%w\nreverting because forceReset == true even though the blob isn't full", err)
+			return false, NotRejected, fmt.Errorf("%w\nreverting because forceReset == true even though the blob isn't full", err)
 		}
-		return true, nil
+		return true, NotRejected, nil
 	}
 
 	// copy the compressed data to the blob
@@ -263,12 +276,12 @@ bypass:
 		if innerErr != nil {
 			err = fmt.Errorf("%w\n\twhen attempting to recover from: %w", innerErr, err)
 		}
-		return false, fmt.Errorf("when packing blob: %w", err)
+		return false, NotRejected, fmt.Errorf("when packing blob: %w", err)
 	}
 	bm.currentBlobLength = int(n2)
 	copy(bm.currentBlob[:bm.currentBlobLength], bm.packBuffer.Bytes())
 
-	return true, nil
+	return true, NotRejected, nil
 }
 
 // Clone returns a (almost) deep copy of the bm -- this is used for test purposes.
@@ -38,9 +38,7 @@ func EncodeBlockForCompression(block *types.Block, w io.Writer) error {
 		numTxs = uint16(len(transactions))
 	)
 
-	binary.Write(w, binary.BigEndian, numTxs)
-	binary.Write(w, binary.BigEndian, timestamp)
-	w.Write(blockHash[:])
+	EncodeBlockHeaderForCompression(numTxs, timestamp, blockHash, w)
 
 	for i, tx := range transactions {
 		if err := EncodeTxForCompression(tx, w); err != nil {
@@ -51,14 +49,30 @@ func EncodeBlockForCompression(block *types.Block, w io.Writer) error {
 	return nil
 }
 
+// EncodeBlockHeaderForCompression encodes the fields of a block that precede
+// its transactions, as done by [EncodeBlockForCompression].
+func EncodeBlockHeaderForCompression(numTxs uint16, timestamp uint32, blockHash common.Hash, w io.Writer) {
+	binary.Write(w, binary.BigEndian, numTxs)
+	binary.Write(w, binary.BigEndian, timestamp)
+	w.Write(blockHash[:])
+}
+
 // encodeTransaction encodes a single transaction
 func EncodeTxForCompression(tx *types.Transaction, w io.Writer) error {
 	if tx == nil {
 		return fmt.Errorf("transactions is nil")
 	}
 
+	return EncodeTxWithSenderForCompression(common.Address(ethereum.GetFrom(tx)), tx, w)
+}
+
+// EncodeTxWithSenderForCompression encodes a transaction as done by
+// [EncodeTxForCompression] but takes the sender address instead of recovering
+// it from the signature. This allows encoding decoded transactions, whose
+// signature is dropped.
+func EncodeTxWithSenderForCompression(from common.Address, tx *types.Transaction, w io.Writer) error {
+
 	var (
-		from    = ethereum.GetFrom(tx)
 		txRlp   = ethereum.EncodeTxForSigning(tx)
 		_, err1 = w.Write(from[:])
 		_, err2 = w.Write(txRlp[:])
@@ -108,7 +122,7 @@ func ScanBlockByteLen(b []byte) (int, error) {
 		// Pass the tx from address
 		r.Seek(int64(len(common.Address{})), io.SeekCurrent)
 
-		// The transaction of type dynamicFee and accessList have a prefix ahead
+		// The transaction of type setCode, dynamicFee and accessList have a prefix ahead
 		// the RLP encoding. When that is the case, we need to skip it before
 		// scanning the RLP prefix of the transaction.
 		prefix, err := r.ReadByte()
@@ -119,7 +133,7 @@ func ScanBlockByteLen(b []byte) (int, error) {
 		// When the prefix does not match a transaction type, this indicates a
 		// legacy transaction and the prefix was in fact the RLP prefix.
 		// We unread it, so that [passRlpTx] can access it.
-		if int(prefix) > types.DynamicFeeTxType {
+		if int(prefix) > types.SetCodeTxType {
 			r.UnreadByte()
 		}
 
@@ -15,12 +15,16 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
 	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	typesLinea "github.com/consensys/linea-monorepo/prover/utils/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,7 +36,7 @@ func TestEncodeDecodeTx(t *testing.T) {
 	var (
 		privKey, _ = ecdsa.GenerateKey(secp256k1.S256(), rand.Reader)
 		chainID    = big.NewInt(51)
-		signer     = types.NewLondonSigner(chainID)
+		signer     = types.NewPragueSigner(chainID)
 	)
 
 	testTx := []struct {
@@ -122,6 +126,22 @@ func TestEncodeDecodeTx(t *testing.T) {
 				ChainID:   chainID,
 			},
 		},
+		{
+			Name: "set-code",
+			Tx:   setCodeTxData(t, chainID),
+		},
+		{
+			Name: "set-code-empty-auth-list",
+			Tx: &types.SetCodeTx{
+				Nonce:     3,
+				GasTipCap: uint256.NewInt(10002),
+				GasFeeCap: uint256.NewInt(33333),
+				Gas:       7000007,
+				To:        common.Address{12, 24},
+				Value:     uint256.NewInt(66666666),
+				ChainID:   uint256.MustFromBig(chainID),
+			},
+		},
 	}
 
 	for _, tc := range testTx {
@@ -152,6 +172,9 @@ func TestEncodeDecodeTx(t *testing.T) {
 			tx2 := types.NewTx(txData2)
 
 			assert.Equal(t, tx.To(), tx2.To(), "field `to` mismatches")
+			assert.Equal(t, tx.Type(), tx2.Type(), "field `type` mismatches")
+			assert.Equal(t, tx.SetCodeAuthorizations(), tx2.SetCodeAuthorizations(), "field `authorizationList` mismatches")
+			assert.Equal(t, ethereum.GetFrom(tx), typesLinea.EthAddress(*addr), "field `from` mismatches")
 
 		})
 
@@ -159,6 +182,83 @@ func TestEncodeDecodeTx(t *testing.T) {
 
 }
 
+func TestScanBlockWithSetCodeTx(t *testing.T) {
+
+	var (
+		privKey, _ = ecdsa.GenerateKey(secp256k1.S256(), rand.Reader)
+		chainID    = big.NewInt(51)
+		signer     = types.NewPragueSigner(chainID)
+		txs        = []*types.Transaction{
+			types.MustSignNewTx(privKey, signer, &types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     3,
+				GasTipCap: big.NewInt(10002),
+				GasFeeCap: big.NewInt(33333),
+				Gas:       7000007,
+				To:        &common.Address{12, 24},
+				Value:     big.NewInt(66666666),
+			}),
+			types.MustSignNewTx(privKey, signer, setCodeTxData(t, chainID)),
+		}
+		block = types.NewBlock(&types.Header{Time: 1234}, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+		buf   = &bytes.Buffer{}
+	)
+
+	require.NoError(t, v1.EncodeBlockForCompression(block, buf))
+
+	// Trailing bytes must not be counted in the block
+	encoded := append(buf.Bytes(), 0xff, 0xff)
+	n, err := v1.ScanBlockByteLen(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+
+	decoded, err := v1.DecodeBlockFromUncompressed(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, decoded.Txs, len(txs))
+	assert.Equal(t, block.Time(), decoded.Timestamp)
+	assert.Equal(t, block.Hash(), decoded.BlockHash)
+
+	for i := range txs {
+		tx := types.NewTx(decoded.Txs[i])
+		assert.Equal(t, txs[i].Type(), tx.Type())
+		assert.Equal(t, ethereum.GetTxHash(txs[i]), ethereum.GetSigner(txs[i]).Hash(tx), "tx #%v: the signing hash mismatches", i)
+		assert.Equal(t, common.Address(ethereum.GetFrom(txs[i])), decoded.Froms[i])
+	}
+}
+
+// setCodeTxData returns a set-code transaction whose authorization list
+// contains a signed authorization for the given chain and one valid on any
+// chain.
+func setCodeTxData(t *testing.T, chainID *big.Int) *types.SetCodeTx {
+
+	authority, err := ecdsa.GenerateKey(secp256k1.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	authList := make([]types.SetCodeAuthorization, 2)
+	for i, auth := range []types.SetCodeAuthorization{
+		{ChainID: *uint256.MustFromBig(chainID), Address: common.Address{0xde, 0xad}, Nonce: 1},
+		{Address: common.Address{0xbe, 0xef}, Nonce: 0},
+	} {
+		authList[i], err = types.SignSetCode(authority, auth)
+		require.NoError(t, err)
+	}
+
+	return &types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(chainID),
+		Nonce:     3,
+		GasTipCap: uint256.NewInt(10002),
+		GasFeeCap: uint256.NewInt(33333),
+		Gas:       7000007,
+		To:        common.Address{12, 24},
+		Value:     uint256.NewInt(66666666),
+		Data:      hexutil.MustDecode("0xdeadbeafbeefbeef12345689"),
+		AccessList: types.AccessList{
+			{Address: common.Address{12, 24}, StorageKeys: []common.Hash{{1}, {2}}},
+		},
+		AuthList: authList,
+	}
+}
+
 func TestEncodeDecodeFromResponse(t *testing.T) {
 
 	var (
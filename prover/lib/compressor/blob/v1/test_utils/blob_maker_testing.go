@@ -61,9 +61,10 @@ func LoadTestBlocks(testDataDir string) (testBlocks [][]byte, err error) {
 			return nil, err
 		}
 
-		for _, block := range proverInput.Blocks() {
+		blocks := proverInput.Blocks()
+		for i := range blocks {
 			var bb bytes.Buffer
-			if err = block.EncodeRLP(&bb); err != nil {
+			if err = blocks[i].EncodeRLP(&bb); err != nil {
 				return nil, err
 			}
 			testBlocks = append(testBlocks, bb.Bytes())
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// RejectionReason tells why a block was not appended to the blob
+type RejectionReason uint8
+
+const (
+	// NotRejected is returned when the block was appended, or when the
+	// write failed with an error.
+	NotRejected RejectionReason = iota
+	// RejectedBlobFull is returned when the compressed data would exceed the
+	// Limit of the blob maker.
+	RejectedBlobFull
+	// RejectedDecompressorLimit is returned when the uncompressed data would
+	// exceed what the decompression circuit can handle, MaxUncompressedBytes.
+	RejectedDecompressorLimit
+	// RejectedTooManyBatches is returned when the block would start a batch
+	// beyond the MaxNbBatches the decompression circuit accepts.
+	RejectedTooManyBatches
+)
+
+func (r RejectionReason) String() string {
+	switch r {
+	case NotRejected:
+		return "not rejected"
+	case RejectedBlobFull:
+		return "blob full"
+	case RejectedDecompressorLimit:
+		return "decompressor limit"
+	case RejectedTooManyBatches:
+		return "too many batches"
+	default:
+		return fmt.Sprintf("unknown rejection reason %d", r)
+	}
+}
+
+// Capacity is the room left in a blob
+type Capacity struct {
+	// Bytes is the number of bytes left in the blob, that is Limit - Len()
+	Bytes int
+	// UncompressedBytes is the number of bytes the decompression circuit can
+	// still accept, the header included.
+	UncompressedBytes int
+	// Batches is the number of batches that can still be started
+	Batches int
+}
+
+// WriteResult is the outcome of WriteWithReason or WriteBatch
+type WriteResult struct {
+	// Appended is true if the block(s) were, or could have been if forceReset
+	// was set, appended.
+	Appended bool
+	// Reason tells why the block(s) were rejected, if they were
+	Reason RejectionReason
+	// Remaining is the capacity of the blob after the write
+	Remaining Capacity
+}
+
+// Remaining returns the capacity left in the blob. The numbers of bytes are
+// exact for the current content of the blob; they do not tell the size of the
+// next block that would fit as it depends on how well it compresses.
+func (bm *BlobMaker) Remaining() Capacity {
+	nbBatches := len(bm.header.BatchSizes)
+	if len(bm.header.CurrBatchBlocksLen) != 0 {
+		nbBatches++
+	}
+	return Capacity{
+		Bytes:             bm.Limit - bm.currentBlobLength,
+		UncompressedBytes: MaxUncompressedBytes - bm.header.ByteSize() - bm.compressor.Written(),
+		Batches:           MaxNbBatches - nbBatches,
+	}
+}
+
+// WriteWithReason behaves as Write, but also tells why the block was rejected
+// and the capacity left in the blob after the write.
+func (bm *BlobMaker) WriteWithReason(rlpBlock []byte, forceReset bool) (WriteResult, error) {
+	ok, reason, err := bm.write(rlpBlock, forceReset)
+	return WriteResult{Appended: ok, Reason: reason, Remaining: bm.Remaining()}, err
+}
+
+// WriteBatch appends the RLP blocks to the blob as a new batch, atomically:
+// either all the blocks are appended or the blob is left unchanged. The batch
+// is sealed once appended, so that the next write starts a new one.
+//
+// If forceReset is set, the blob is left unchanged in any case and the result
+// tells whether the batch could have been appended. The capacity is then the
+// one of the unchanged blob.
+//
+// Rolling back more than one block requires recompressing the content of the
+// blob, which is more expensive than a regular Write.
+func (bm *BlobMaker) WriteBatch(rlpBlocks [][]byte, forceReset bool) (WriteResult, error) {
+
+	if len(rlpBlocks) == 0 {
+		return WriteResult{Remaining: bm.Remaining()}, errors.New("empty batch")
+	}
+
+	// sealing the current batch is part of what must be rolled back
+	snapshot := bm.snapshot(len(rlpBlocks) > 1)
+	bm.StartNewBatch()
+
+	// a single block can be reverted by the compressor itself
+	if len(rlpBlocks) == 1 {
+		ok, reason, err := bm.write(rlpBlocks[0], forceReset)
+		if ok && !forceReset {
+			bm.StartNewBatch()
+		} else {
+			bm.header = snapshot.header
+		}
+		return WriteResult{Appended: ok, Reason: reason, Remaining: bm.Remaining()}, err
+	}
+
+	for i := range rlpBlocks {
+		ok, reason, err := bm.write(rlpBlocks[i], false)
+		if err != nil || !ok {
+			if err != nil {
+				err = fmt.Errorf("block #%d of the batch: %w", i, err)
+			}
+			if i > 0 {
+				err = errors.Join(err, bm.restore(snapshot))
+			} else {
+				bm.header = snapshot.header
+			}
+			return WriteResult{Reason: reason, Remaining: bm.Remaining()}, err
+		}
+	}
+
+	if forceReset {
+		err := bm.restore(snapshot)
+		return WriteResult{Appended: err == nil, Remaining: bm.Remaining()}, err
+	}
+
+	bm.StartNewBatch()
+	return WriteResult{Appended: true, Remaining: bm.Remaining()}, nil
+}
+
+// blobMakerSnapshot is the state of a blob maker WriteBatch can roll back to
+type blobMakerSnapshot struct {
+	header  Header
+	blob    []byte
+	payload []byte // uncompressed data, only needed to restore the compressor
+}
+
+func (bm *BlobMaker) snapshot(withPayload bool) blobMakerSnapshot {
+	s := blobMakerSnapshot{
+		header: bm.header,
+		blob:   bytes.Clone(bm.currentBlob[:bm.currentBlobLength]),
+	}
+	s.header.BatchSizes = slices.Clone(bm.header.BatchSizes)
+	s.header.CurrBatchBlocksLen = slices.Clone(bm.header.CurrBatchBlocksLen)
+	if withPayload {
+		s.payload = bytes.Clone(bm.compressor.WrittenBytes())
+	}
+	return s
+}
+
+// restore rolls the blob maker back to the snapshot. The payload is compressed
+// again in one go, as the compressor can only revert its last write.
+func (bm *BlobMaker) restore(s blobMakerSnapshot) error {
+	bm.header = s.header
+	bm.currentBlobLength = copy(bm.currentBlob[:], s.blob)
+	bm.compressor.Reset()
+	if len(s.payload) == 0 {
+		return nil
+	}
+	_, err := bm.compressor.Write(s.payload)
+	return wrapError(err, "restoring the compressor")
+}
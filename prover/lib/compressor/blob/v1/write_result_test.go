@@ -0,0 +1,163 @@
+//go:build !fuzzlight
+
+package v1_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteWithReasonBlobFull(t *testing.T) {
+	assert := require.New(t)
+
+	bm, err := v1.NewBlobMaker(16*1024, testDictPath)
+	assert.NoError(err)
+	// the header of an empty blob holds the version, the checksum and the number of batches
+	assert.Equal(v1.Capacity{Bytes: bm.Limit, UncompressedBytes: v1.MaxUncompressedBytes - 36, Batches: v1.MaxNbBatches}, bm.Remaining())
+
+	for _, block := range testBlocks {
+		before, remainingBefore := bm.Clone(), bm.Remaining()
+		res, err := bm.WriteWithReason(block, false)
+		assert.NoError(err)
+		assert.Equal(bm.Remaining(), res.Remaining)
+		assert.Equal(bm.Limit-bm.Len(), res.Remaining.Bytes)
+
+		if !res.Appended {
+			assert.Equal(v1.RejectedBlobFull, res.Reason)
+			assert.True(bm.Equals(before), "a rejected block must not mutate the blob maker")
+			assert.Equal(remainingBefore, res.Remaining)
+			return
+		}
+		assert.Equal(v1.NotRejected, res.Reason)
+		assert.Less(res.Remaining.UncompressedBytes, remainingBefore.UncompressedBytes)
+	}
+	t.Fatal("the blob should have been filled")
+}
+
+func TestWriteWithReasonDecompressorLimit(t *testing.T) {
+	assert := require.New(t)
+
+	bm, err := v1.NewBlobMaker(120*1024, testDictPath)
+	assert.NoError(err)
+
+	// a block compressing so well that the decompressor limit is hit first
+	block, err := rlp.EncodeToBytes(makeFakeBlock(v1.MaxUncompressedBytes / 8))
+	assert.NoError(err)
+
+	for i := 0; ; i++ {
+		res, err := bm.WriteWithReason(block, false)
+		assert.NoError(err)
+		if !res.Appended {
+			assert.Equal(v1.RejectedDecompressorLimit, res.Reason)
+			assert.Equal(7, i)
+			assert.Less(res.Remaining.UncompressedBytes, v1.MaxUncompressedBytes/8)
+			assert.Greater(res.Remaining.Bytes, 100*1024)
+			return
+		}
+	}
+}
+
+func TestWriteWithReasonTooManyBatches(t *testing.T) {
+	assert := require.New(t)
+
+	bm, err := v1.NewBlobMaker(120*1024, testDictPath)
+	assert.NoError(err)
+
+	block, err := rlp.EncodeToBytes(makeFakeBlock(10))
+	assert.NoError(err)
+
+	for i := 0; i < v1.MaxNbBatches; i++ {
+		res, err := bm.WriteWithReason(block, false)
+		assert.NoError(err)
+		assert.True(res.Appended)
+		assert.Equal(v1.MaxNbBatches-i-1, res.Remaining.Batches)
+		bm.StartNewBatch()
+	}
+
+	res, err := bm.WriteWithReason(block, true)
+	assert.NoError(err)
+	assert.False(res.Appended)
+	assert.Equal(v1.RejectedTooManyBatches, res.Reason)
+	assert.Zero(res.Remaining.Batches)
+
+	ok, err := bm.Write(block, false)
+	assert.NoError(err)
+	assert.False(ok)
+
+	_, err = v1.DecompressBlob(bm.Bytes(), mustDictStore(t))
+	assert.NoError(err)
+}
+
+func TestWriteBatch(t *testing.T) {
+	assert := require.New(t)
+
+	bm, err := v1.NewBlobMaker(32*1024, testDictPath)
+	assert.NoError(err)
+
+	// a first batch fits
+	res, err := bm.WriteBatch(testBlocks[:2], false)
+	assert.NoError(err)
+	assert.True(res.Appended)
+	assert.Equal(v1.MaxNbBatches-1, res.Remaining.Batches)
+
+	before := bytes.Clone(bm.Bytes())
+	remainingBefore := bm.Remaining()
+
+	// a batch that can't fit is rolled back entirely, even after some of its
+	// blocks were written
+	res, err = bm.WriteBatch(testBlocks[2:], false)
+	assert.NoError(err)
+	assert.False(res.Appended)
+	assert.Equal(v1.RejectedBlobFull, res.Reason)
+	assert.Equal(before, bm.Bytes())
+	assert.Equal(remainingBefore.Bytes, res.Remaining.Bytes)
+	assert.Equal(remainingBefore.Batches, res.Remaining.Batches)
+
+	// dry runs leave the blob unchanged
+	for _, batch := range [][][]byte{testBlocks[2:3], testBlocks[2:4]} {
+		res, err = bm.WriteBatch(batch, true)
+		assert.NoError(err)
+		assert.True(res.Appended)
+		assert.Equal(before, bm.Bytes())
+		assert.Equal(remainingBefore.Batches, res.Remaining.Batches)
+	}
+
+	// the blob maker is still usable
+	res, err = bm.WriteBatch(testBlocks[2:4], false)
+	assert.NoError(err)
+	assert.True(res.Appended)
+	res, err = bm.WriteBatch(testBlocks[4:5], false)
+	assert.NoError(err)
+	assert.True(res.Appended)
+
+	r, err := v1.DecompressBlob(bm.Bytes(), mustDictStore(t))
+	assert.NoError(err)
+	assert.Len(r.Header.BatchSizes, 3)
+	assert.Len(r.Blocks, 5)
+	for i := range r.Blocks {
+		assert.Equal(encodeForCompression(t, testBlocks[i]), r.Blocks[i], "block #%d", i)
+	}
+
+	_, err = bm.WriteBatch(nil, false)
+	assert.Error(err)
+}
+
+func mustDictStore(t *testing.T) dictionary.Store {
+	dictStore := dictionary.NewStore()
+	require.NoError(t, dictStore.Load(testDictPath))
+	return dictStore
+}
+
+func encodeForCompression(t *testing.T, rlpBlock []byte) []byte {
+	var block types.Block
+	require.NoError(t, rlp.DecodeBytes(rlpBlock, &block))
+	var buf bytes.Buffer
+	require.NoError(t, v1.EncodeBlockForCompression(&block, &buf))
+	return buf.Bytes()
+}
@@ -1,9 +1,20 @@
 package main
 
+/*
+// Codes returned by WriteWithReason, CanWriteWithReason, WriteBatch and CanWriteBatch.
+// A negative code means that an error occurred, see Error().
+enum RejectionReason {
+	REJECTION_NONE = 0,               // the block(s) were, or could have been, appended
+	REJECTION_BLOB_FULL = 1,          // the compressed data would exceed the data limit
+	REJECTION_DECOMPRESSOR_LIMIT = 2, // the uncompressed data would exceed what the decompression circuit handles
+	REJECTION_TOO_MANY_BATCHES = 3,   // the blob already holds the max number of batches of the decompression circuit
+};
+*/
 import "C"
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"unsafe"
 
@@ -207,3 +218,149 @@ func RawCompressedSize(input *C.char, inputLength C.int) C.int {
 	}
 	return C.int(n)
 }
+
+// WriteWithReason behaves as Write, but tells why the block was discarded.
+// Returns REJECTION_NONE if the block was appended, the reason why it was discarded otherwise,
+// or -1 if an error occurred. User must call Error() to get the error message.
+//
+//export WriteWithReason
+func WriteWithReason(input *C.char, inputLength C.int) C.int {
+	return writeWithReason(input, inputLength, false)
+}
+
+// CanWriteWithReason behaves as WriteWithReason, except that it doesn't append the input to the compressed data
+// (but returns REJECTION_NONE if it could)
+//
+//export CanWriteWithReason
+func CanWriteWithReason(input *C.char, inputLength C.int) C.int {
+	return writeWithReason(input, inputLength, true)
+}
+
+func writeWithReason(input *C.char, inputLength C.int, forceReset bool) C.int {
+	lock.Lock()
+	defer lock.Unlock()
+	if inputLength < 0 || (input == nil && inputLength > 0) {
+		lastError = fmt.Errorf("invalid input: pointer %p with length %d", input, inputLength)
+		return -1
+	}
+	rlpBlock := unsafe.Slice((*byte)(unsafe.Pointer(input)), inputLength)
+	res, err := compressor.WriteWithReason(rlpBlock, forceReset)
+	return rejectionCode(res, err)
+}
+
+// WriteBatch appends the given blocks to the compressed data as a new batch, atomically:
+// either all the blocks are appended or the compressed data is left unchanged.
+// The batch is sealed once appended; there is no need to call StartNewBatch.
+// The input is the concatenation of the nbBlocks RLP encoded blocks, the length of each being given by blockLengths.
+// The Go code doesn't keep a pointer to the inputs and the caller is free to modify them.
+// nbBlocks must be positive and the lengths non-negative, otherwise the inputs are rejected as an error.
+// Returns REJECTION_NONE if the batch was appended, the reason why it was discarded otherwise,
+// or -1 if an error occurred. User must call Error() to get the error message.
+//
+//export WriteBatch
+func WriteBatch(input *C.char, blockLengths *C.int, nbBlocks C.int) C.int {
+	return writeBatch(input, blockLengths, nbBlocks, false)
+}
+
+// CanWriteBatch behaves as WriteBatch, except that it doesn't append the blocks to the compressed data
+// (but returns REJECTION_NONE if it could)
+//
+//export CanWriteBatch
+func CanWriteBatch(input *C.char, blockLengths *C.int, nbBlocks C.int) C.int {
+	return writeBatch(input, blockLengths, nbBlocks, true)
+}
+
+func writeBatch(input *C.char, blockLengths *C.int, nbBlocks C.int, forceReset bool) C.int {
+	lock.Lock()
+	defer lock.Unlock()
+
+	rlpBlocks, err := splitBlocks(input, blockLengths, nbBlocks)
+	if err != nil {
+		lastError = err
+		return -1
+	}
+
+	res, err := compressor.WriteBatch(rlpBlocks, forceReset)
+	return rejectionCode(res, err)
+}
+
+// splitBlocks validates the inputs of WriteBatch and CanWriteBatch coming from C
+// and splits the concatenated blocks. The blocks are views on the input.
+func splitBlocks(input *C.char, blockLengths *C.int, nbBlocks C.int) ([][]byte, error) {
+
+	if nbBlocks <= 0 {
+		return nil, fmt.Errorf("invalid number of blocks: %d", nbBlocks)
+	}
+	if blockLengths == nil {
+		return nil, errors.New("the block lengths are missing")
+	}
+
+	lengths := unsafe.Slice(blockLengths, nbBlocks)
+	totalLength := 0
+	for i, l := range lengths {
+		if l < 0 {
+			return nil, fmt.Errorf("invalid length of the block %d: %d", i, l)
+		}
+		totalLength += int(l)
+	}
+
+	if input == nil && totalLength > 0 {
+		return nil, errors.New("the blocks are missing")
+	}
+
+	data := unsafe.Slice((*byte)(unsafe.Pointer(input)), totalLength)
+	rlpBlocks := make([][]byte, nbBlocks)
+	for i, l := range lengths {
+		rlpBlocks[i], data = data[:l:l], data[l:]
+	}
+
+	return rlpBlocks, nil
+}
+
+func rejectionCode(res blob_v1.WriteResult, err error) C.int {
+	if err != nil {
+		lastError = err
+		return -1
+	}
+	switch res.Reason {
+	case blob_v1.NotRejected:
+		return C.REJECTION_NONE
+	case blob_v1.RejectedBlobFull:
+		return C.REJECTION_BLOB_FULL
+	case blob_v1.RejectedDecompressorLimit:
+		return C.REJECTION_DECOMPRESSOR_LIMIT
+	case blob_v1.RejectedTooManyBatches:
+		return C.REJECTION_TOO_MANY_BATCHES
+	default:
+		lastError = fmt.Errorf("unexpected rejection reason: %v", res.Reason)
+		return -1
+	}
+}
+
+// RemainingBytes returns the number of bytes left in the blob, that is the data limit minus Len().
+//
+//export RemainingBytes
+func RemainingBytes() int {
+	lock.Lock()
+	defer lock.Unlock()
+	return compressor.Remaining().Bytes
+}
+
+// RemainingUncompressedBytes returns the number of uncompressed bytes the decompression circuit
+// can still accept in the blob, the header included.
+//
+//export RemainingUncompressedBytes
+func RemainingUncompressedBytes() int {
+	lock.Lock()
+	defer lock.Unlock()
+	return compressor.Remaining().UncompressedBytes
+}
+
+// RemainingBatches returns the number of batches that can still be started in the blob.
+//
+//export RemainingBatches
+func RemainingBatches() int {
+	lock.Lock()
+	defer lock.Unlock()
+	return compressor.Remaining().Batches
+}
@@ -19,6 +19,18 @@ typedef struct { const char *p; ptrdiff_t n; } _GoString_;
 /* Start of preamble from import "C" comments.  */
 
 
+#line 3 "libcompressor.go"
+
+// Codes returned by WriteWithReason, CanWriteWithReason, WriteBatch and CanWriteBatch.
+// A negative code means that an error occurred, see Error().
+enum RejectionReason {
+	REJECTION_NONE = 0,               // the block(s) were, or could have been, appended
+	REJECTION_BLOB_FULL = 1,          // the compressed data would exceed the data limit
+	REJECTION_DECOMPRESSOR_LIMIT = 2, // the uncompressed data would exceed what the decompression circuit handles
+	REJECTION_TOO_MANY_BATCHES = 3,   // the blob already holds the max number of batches of the decompression circuit
+};
+
+#line 1 "cgo-generated-wrapper"
 
 
 /* End of preamble from import "C" comments.  */
@@ -156,6 +168,46 @@ extern int WorstCompressedTxSize(char* input, int inputLength);
 //
 extern int RawCompressedSize(char* input, int inputLength);
 
+// WriteWithReason behaves as Write, but tells why the block was discarded.
+// Returns REJECTION_NONE if the block was appended, the reason why it was discarded otherwise,
+// or -1 if an error occurred. User must call Error() to get the error message.
+//
+extern int WriteWithReason(char* input, int inputLength);
+
+// CanWriteWithReason behaves as WriteWithReason, except that it doesn't append the input to the compressed data
+// (but returns REJECTION_NONE if it could)
+//
+extern int CanWriteWithReason(char* input, int inputLength);
+
+// WriteBatch appends the given blocks to the compressed data as a new batch, atomically:
+// either all the blocks are appended or the compressed data is left unchanged.
+// The batch is sealed once appended; there is no need to call StartNewBatch.
+// The input is the concatenation of the nbBlocks RLP encoded blocks, the length of each being given by blockLengths.
+// The Go code doesn't keep a pointer to the inputs and the caller is free to modify them.
+// nbBlocks must be positive and the lengths non-negative, otherwise the inputs are rejected as an error.
+// Returns REJECTION_NONE if the batch was appended, the reason why it was discarded otherwise,
+// or -1 if an error occurred. User must call Error() to get the error message.
+//
+extern int WriteBatch(char* input, int* blockLengths, int nbBlocks);
+
+// CanWriteBatch behaves as WriteBatch, except that it doesn't append the blocks to the compressed data
+// (but returns REJECTION_NONE if it could)
+//
+extern int CanWriteBatch(char* input, int* blockLengths, int nbBlocks);
+
+// RemainingBytes returns the number of bytes left in the blob, that is the data limit minus Len().
+//
+extern GoInt RemainingBytes();
+
+// RemainingUncompressedBytes returns the number of uncompressed bytes the decompression circuit
+// can still accept in the blob, the header included.
+//
+extern GoInt RemainingUncompressedBytes();
+
+// RemainingBatches returns the number of batches that can still be started in the blob.
+//
+extern GoInt RemainingBatches();
+
 #ifdef __cplusplus
 }
 #endif
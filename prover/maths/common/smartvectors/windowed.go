@@ -47,6 +47,22 @@ func (p *PaddedCircularWindow) Len() int {
 	return p.totLen
 }
 
+// Window returns the non-padded part of the vector. The caller must not
+// mutate it.
+func (p *PaddedCircularWindow) Window() []field.Element {
+	return p.window
+}
+
+// PaddingVal returns the value of the padding
+func (p *PaddedCircularWindow) PaddingVal() field.Element {
+	return p.paddingVal
+}
+
+// Offset returns the position of the first entry of the window
+func (p *PaddedCircularWindow) Offset() int {
+	return p.offset
+}
+
 // Returns a queries position
 func (p *PaddedCircularWindow) GetBase(n int) (field.Element, error) {
 	// Check if the queried index is in the window
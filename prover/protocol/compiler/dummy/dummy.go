@@ -1,14 +1,10 @@
 package dummy
 
 import (
-	"fmt"
-	"sync"
-
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,6 +15,10 @@ and ask the verifier to manually verify all queries.
 Primary use-case is testing.
 */
 func Compile(comp *wizard.CompiledIOP) {
+	compile(comp, ReportSettings{})
+}
+
+func compile(comp *wizard.CompiledIOP, settings ReportSettings) {
 
 	comp.DummyCompiled = true
 
@@ -88,47 +88,7 @@ func Compile(comp *wizard.CompiledIOP) {
 
 		logrus.Infof("started to run the dummy verifier")
 
-		var finalErr error
-		lock := sync.Mutex{}
-
-		/*
-			Test all the query with parameters
-		*/
-		parallel.Execute(len(queriesParamsToCompile), func(start, stop int) {
-			for i := start; i < stop; i++ {
-				name := queriesParamsToCompile[i]
-				lock.Lock()
-				q := comp.QueriesParams.Data(name)
-				lock.Unlock()
-				if err := q.Check(run); err != nil {
-					lock.Lock()
-					finalErr = fmt.Errorf("%v\nfailed %v - %v", finalErr, name, err)
-					lock.Unlock()
-					logrus.Debugf("query %v failed\n", name)
-				} else {
-					logrus.Debugf("query %v passed\n", name)
-				}
-			}
-		})
-
-		/*
-			Test the queries without parameters
-		*/
-		parallel.Execute(len(queriesNoParamsToCompile), func(start, stop int) {
-			for i := start; i < stop; i++ {
-				name := queriesNoParamsToCompile[i]
-				lock.Lock()
-				q := comp.QueriesNoParams.Data(name)
-				lock.Unlock()
-				if err := q.Check(run); err != nil {
-					lock.Lock()
-					finalErr = fmt.Errorf("%v\nfailed %v - %v", finalErr, name, err)
-					lock.Unlock()
-				} else {
-					logrus.Debugf("query %v passed\n", name)
-				}
-			}
-		})
+		finalErr := checkAll(comp, run, queriesParamsToCompile, queriesNoParamsToCompile, settings)
 
 		/*
 			Nil to indicate all checks passed
@@ -1,12 +1,8 @@
 package dummy
 
 import (
-	"fmt"
-	"sync"
-
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +16,10 @@ import (
 // at prover level, the "errors" result in panics. This makes it not very
 // suitable for established unit-tests where we want to analyze the errors.
 func CompileAtProverLvl(comp *wizard.CompiledIOP) {
+	compileAtProverLvl(comp, ReportSettings{})
+}
+
+func compileAtProverLvl(comp *wizard.CompiledIOP, settings ReportSettings) {
 
 	/*
 		Registers all declared commitments and query parameters
@@ -45,47 +45,7 @@ func CompileAtProverLvl(comp *wizard.CompiledIOP) {
 
 		logrus.Infof("started to run the dummy verifier")
 
-		var finalErr error
-		lock := sync.Mutex{}
-
-		/*
-			Test all the query with parameters
-		*/
-		parallel.Execute(len(queriesParamsToCompile), func(start, stop int) {
-			for i := start; i < stop; i++ {
-				name := queriesParamsToCompile[i]
-				lock.Lock()
-				q := comp.QueriesParams.Data(name)
-				lock.Unlock()
-				if err := q.Check(run); err != nil {
-					lock.Lock()
-					finalErr = fmt.Errorf("%v\nfailed %v - %v", finalErr, name, err)
-					lock.Unlock()
-					logrus.Debugf("query %v failed\n", name)
-				} else {
-					logrus.Debugf("query %v passed\n", name)
-				}
-			}
-		})
-
-		/*
-			Test the queries without parameters
-		*/
-		parallel.Execute(len(queriesNoParamsToCompile), func(start, stop int) {
-			for i := start; i < stop; i++ {
-				name := queriesNoParamsToCompile[i]
-				lock.Lock()
-				q := comp.QueriesNoParams.Data(name)
-				lock.Unlock()
-				if err := q.Check(run); err != nil {
-					lock.Lock()
-					finalErr = fmt.Errorf("%v\nfailed %v - %v", finalErr, name, err)
-					lock.Unlock()
-				} else {
-					logrus.Debugf("query %v passed\n", name)
-				}
-			}
-		})
+		finalErr := checkAll(comp, run, queriesParamsToCompile, queriesNoParamsToCompile, settings)
 
 		if finalErr != nil {
 			utils.Panic("dummy.Compile brought errors: %v", finalErr.Error())
@@ -0,0 +1,202 @@
+package dummy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxReportedRows is the default number of offending rows reported
+	// per table of a violation.
+	DefaultMaxReportedRows = 100
+	// ReportFile is the name of the JSON report in the report directory
+	ReportFile = "violations.json"
+	// RowColumn is the column holding the index of the offending rows in the
+	// CSV files of the report.
+	RowColumn = "ROW"
+)
+
+// ReportSettings tunes how the dummy verifier reports the unsatisfied queries
+type ReportSettings struct {
+	// Dir is the directory where the report is written when a query is not
+	// satisfied. It holds a JSON file listing all the violations and, for
+	// every table of offending rows, a CSV file readable with
+	// [csvtraces.NewCsvTrace]. Leave empty to only report in the error.
+	Dir string
+	// MaxRows is the number of offending rows reported per table. Zero means
+	// [DefaultMaxReportedRows] and a negative value means no limit.
+	MaxRows int
+}
+
+// Report lists the queries the dummy verifier found unsatisfied
+type Report struct {
+	Violations []query.Violation `json:"violations"`
+}
+
+// CompileWithReport returns [Compile], with the verifier reporting the
+// unsatisfied queries according to the settings.
+func CompileWithReport(settings ReportSettings) func(*wizard.CompiledIOP) {
+	return func(comp *wizard.CompiledIOP) {
+		compile(comp, settings)
+	}
+}
+
+// CompileAtProverLvlWithReport returns [CompileAtProverLvl], with the prover
+// reporting the unsatisfied queries according to the settings.
+func CompileAtProverLvlWithReport(settings ReportSettings) func(*wizard.CompiledIOP) {
+	return func(comp *wizard.CompiledIOP) {
+		compileAtProverLvl(comp, settings)
+	}
+}
+
+// checkAll checks all the queries, without stopping at the first unsatisfied
+// one. The returned error lists the violations of all of them, and the report
+// is written if the settings require it.
+func checkAll(comp *wizard.CompiledIOP, run ifaces.Runtime, queriesParams, queriesNoParams []ifaces.QueryID, settings ReportSettings) error {
+
+	var (
+		report Report
+		lock   = sync.Mutex{}
+	)
+
+	maxRows := settings.MaxRows
+	if maxRows == 0 {
+		maxRows = DefaultMaxReportedRows
+	}
+
+	check := func(names []ifaces.QueryID, get func(ifaces.QueryID) ifaces.Query) {
+		parallel.Execute(len(names), func(start, stop int) {
+			for i := start; i < stop; i++ {
+				name := names[i]
+				lock.Lock()
+				q := get(name)
+				lock.Unlock()
+				err := q.Check(run)
+				if err == nil {
+					logrus.Debugf("query %v passed\n", name)
+					continue
+				}
+				logrus.Debugf("query %v failed\n", name)
+				v := query.ViolationOf(q, run, err, maxRows)
+				lock.Lock()
+				report.Violations = append(report.Violations, v)
+				lock.Unlock()
+			}
+		})
+	}
+
+	/*
+		Test all the query with parameters
+	*/
+	check(queriesParams, func(name ifaces.QueryID) ifaces.Query {
+		return comp.QueriesParams.Data(name)
+	})
+
+	/*
+		Test the queries without parameters
+	*/
+	check(queriesNoParams, func(name ifaces.QueryID) ifaces.Query {
+		return comp.QueriesNoParams.Data(name)
+	})
+
+	if len(report.Violations) == 0 {
+		return nil
+	}
+
+	sort.Slice(report.Violations, func(i, j int) bool {
+		return report.Violations[i].Query < report.Violations[j].Query
+	})
+
+	var finalErr error
+	for _, v := range report.Violations {
+		finalErr = errors.Join(finalErr, fmt.Errorf("failed %v - %v", v.Query, v.String()))
+	}
+
+	if len(settings.Dir) > 0 {
+		if err := report.Write(settings.Dir); err != nil {
+			logrus.Errorf("could not write the report of the violations: %v", err)
+		} else {
+			finalErr = errors.Join(finalErr, fmt.Errorf("the violations are reported in %v", settings.Dir))
+		}
+	}
+
+	return finalErr
+}
+
+// Write writes the report in dir, creating it if needed. The CSV files are
+// named after the query and the table; the index of the offending rows is
+// given in the ROW column.
+func (r *Report) Write(dir string) error {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ReportFile), b, 0644); err != nil {
+		return err
+	}
+
+	for _, v := range r.Violations {
+		for _, t := range v.Tables {
+
+			name := string(v.Query)
+			if len(t.Name) > 0 {
+				name += "." + t.Name
+			}
+
+			if err := writeTableCsv(filepath.Join(dir, fileName(name)+".csv"), t); err != nil {
+				return fmt.Errorf("query %v: %w", v.Query, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeTableCsv(path string, t query.ViolationTable) error {
+
+	cols := make([][]field.Element, len(t.Columns)+1)
+	for i := range cols {
+		cols[i] = make([]field.Element, len(t.Rows))
+	}
+	for i := range t.Rows {
+		cols[0][i].SetUint64(uint64(t.Rows[i]))
+		for j := range t.Columns {
+			cols[j+1][i] = t.Values[i][j]
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	csvtraces.WriteExplicit(f, append([]string{RowColumn}, t.Columns...), cols, false)
+	return f.Close()
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.\-\[\]]`)
+
+// fileName replaces the characters of the query names that are unsafe in a
+// file name.
+func fileName(name string) string {
+	return unsafeFileNameChars.ReplaceAllString(name, "_")
+}
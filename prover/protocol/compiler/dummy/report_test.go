@@ -0,0 +1,61 @@
+package dummy_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks that all the unsatisfied queries are reported, and that the CSV
+// files can be read back
+func TestReport(t *testing.T) {
+
+	dir := t.TempDir()
+
+	define := func(b *wizard.Builder) {
+		x := b.RegisterCommit("X", 4)
+		y := b.RegisterCommit("Y", 4)
+		b.GlobalConstraint("EQUAL", ifaces.ColumnAsVariable(x).Sub(ifaces.ColumnAsVariable(y)))
+		b.Inclusion("LOOKUP", []ifaces.Column{x}, []ifaces.Column{y})
+		b.Range("RANGE", x, 16)
+	}
+
+	prover := func(pr *wizard.ProverRuntime) {
+		pr.AssignColumn("X", smartvectors.ForTest(1, 2, 3, 4))
+		pr.AssignColumn("Y", smartvectors.ForTest(1, 2, 5, 6))
+	}
+
+	comp := wizard.Compile(define, dummy.CompileWithReport(dummy.ReportSettings{Dir: dir}))
+	proof := wizard.Prove(comp, prover)
+	err := wizard.Verify(comp, proof)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed EQUAL")
+	assert.Contains(t, err.Error(), "failed LOOKUP")
+	assert.NotContains(t, err.Error(), "failed RANGE")
+
+	b, err := os.ReadFile(filepath.Join(dir, dummy.ReportFile))
+	require.NoError(t, err)
+	var report dummy.Report
+	require.NoError(t, json.Unmarshal(b, &report))
+	require.Len(t, report.Violations, 2)
+	assert.Equal(t, ifaces.QueryID("EQUAL"), report.Violations[0].Query)
+	assert.Equal(t, ifaces.QueryID("LOOKUP"), report.Violations[1].Query)
+
+	ct := csvtraces.MustOpenCsvFile(filepath.Join(dir, "LOOKUP.csv"))
+	assert.Equal(t, []field.Element{field.NewElement(2), field.NewElement(3)}, ct.Get(dummy.RowColumn))
+	assert.Equal(t, []field.Element{field.NewElement(5), field.NewElement(6)}, ct.Get("Y"))
+
+	ct = csvtraces.MustOpenCsvFile(filepath.Join(dir, "EQUAL.csv"))
+	assert.Equal(t, 2, ct.Len())
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc/gkrmimc"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
 	"github.com/consensys/linea-monorepo/prover/protocol/query"
@@ -69,10 +68,10 @@ func (c *gnarkCircuit) Define(api frontend.API) error {
 	w := c.WizardVerifier
 
 	if c.withoutGkr {
-		w.FS = fiatshamir.NewGnarkFiatShamir(api, nil)
+		w.FS = c.comp.NewGnarkFiatShamir(api, nil)
 	} else {
 		w.HasherFactory = gkrmimc.NewHasherFactory(api)
-		w.FS = fiatshamir.NewGnarkFiatShamir(api, w.HasherFactory)
+		w.FS = c.comp.NewGnarkFiatShamir(api, w.HasherFactory)
 	}
 
 	w.FiatShamirHistory = make([][2][]frontend.Variable, c.comp.NumRounds())
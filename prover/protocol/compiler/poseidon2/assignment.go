@@ -0,0 +1,88 @@
+package poseidon2
+
+import (
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
+)
+
+// assign assigns the intermediate columns to the prover runtime
+func (ctx *poseidon2Ctx) assign(run *wizard.ProverRuntime) {
+
+	var (
+		oldStates = ctx.oldStates.GetColAssignment(run).IntoRegVecSaveAlloc()
+		blocks    = ctx.blocks.GetColAssignment(run).IntoRegVecSaveAlloc()
+		numRows   = len(oldStates)
+		pow4      = make([][][]field.Element, len(ctx.pow4))
+		states    = make([][poseidon2.Width][]field.Element, len(ctx.states))
+	)
+
+	for r := range pow4 {
+		pow4[r] = make([][]field.Element, len(ctx.pow4[r]))
+		for i := range pow4[r] {
+			pow4[r][i] = make([]field.Element, numRows)
+		}
+	}
+
+	for r := range states {
+		for i := range states[r] {
+			states[r][i] = make([]field.Element, numRows)
+		}
+	}
+
+	parallel.Execute(numRows, func(start, stop int) {
+		for k := start; k < stop; k++ {
+
+			state := matMulNative(poseidon2.ExternalMatrix, [poseidon2.Width]field.Element{oldStates[k], blocks[k]})
+
+			for r := range poseidon2.RoundKeys {
+
+				for i := range poseidon2.RoundKeys[r] {
+					var x, x4 field.Element
+					x.Add(&state[i], &poseidon2.RoundKeys[r][i])
+					x4.Square(&x).Square(&x4)
+					pow4[r][i][k] = x4
+					state[i].Square(&x4).Square(&state[i]).Mul(&state[i], &x)
+				}
+
+				if poseidon2.IsPartialRound(r) {
+					state = matMulNative(poseidon2.InternalMatrix, state)
+				} else {
+					state = matMulNative(poseidon2.ExternalMatrix, state)
+				}
+
+				if r < len(states) {
+					for i := range state {
+						states[r][i][k] = state[i]
+					}
+				}
+			}
+		}
+	})
+
+	for r := range ctx.pow4 {
+		for i := range ctx.pow4[r] {
+			run.AssignColumn(ctx.pow4[r][i].GetColID(), smartvectors.NewRegular(pow4[r][i]))
+		}
+	}
+
+	for r := range ctx.states {
+		for i := range ctx.states[r] {
+			run.AssignColumn(ctx.states[r][i].GetColID(), smartvectors.NewRegular(states[r][i]))
+		}
+	}
+}
+
+// matMulNative multiplies the state by a matrix of small integers
+func matMulNative(m [poseidon2.Width][poseidon2.Width]int, state [poseidon2.Width]field.Element) [poseidon2.Width]field.Element {
+	var res [poseidon2.Width]field.Element
+	for i := range m {
+		var a, b field.Element
+		a.SetInt64(int64(m[i][0])).Mul(&a, &state[0])
+		b.SetInt64(int64(m[i][1])).Mul(&b, &state[1])
+		res[i].Add(&a, &b)
+	}
+	return res
+}
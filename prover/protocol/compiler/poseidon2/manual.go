@@ -0,0 +1,131 @@
+package poseidon2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizardutils"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+)
+
+// Internally checks the correctness of the Poseidon2 compression of blocks in
+// parallel. Namely, on every row i of the columns (blocks, oldStates,
+// newStates), we have that poseidon2F(oldState, blocks) == newState.
+//
+// Every s-box x -> x^17 is arithmetized with an intermediate column storing
+// x^4 so that the constraints have degree 5, and the state is committed after
+// every round but the last one.
+func manualCheckPoseidon2Block(comp *wizard.CompiledIOP, blocks, oldStates, newStates ifaces.Column) {
+
+	ctx := poseidon2Ctx{
+		comp:      comp,
+		oldStates: oldStates,
+		blocks:    blocks,
+		newStates: newStates,
+		round:     wizardutils.MaxRound(blocks, oldStates, newStates),
+	}
+
+	// The input of the permutation goes through the external linear layer
+	state := matMul(poseidon2.ExternalMatrix, [poseidon2.Width]*symbolic.Expression{
+		ifaces.ColumnAsVariable(oldStates),
+		ifaces.ColumnAsVariable(blocks),
+	})
+
+	lastRound := len(poseidon2.RoundKeys) - 1
+	for r := range poseidon2.RoundKeys {
+		state = ctx.permRound(state, r)
+		if r < lastRound {
+			state = ctx.commitState(state, r)
+		}
+	}
+
+	// newState = state[1] + block
+	ctx.comp.InsertGlobal(
+		ctx.round,
+		ifaces.QueryID(poseidon2Name(ctx.comp, ctx.newStates.GetColID(), "FINAL")),
+		state[1].Add(ifaces.ColumnAsVariable(blocks)).Sub(ifaces.ColumnAsVariable(newStates)),
+	)
+
+	comp.SubProvers.AppendToInner(ctx.round, ctx.assign)
+}
+
+// Utility struct wrapping all the intermediate values of the Poseidon2 wizard
+type poseidon2Ctx struct {
+	comp      *wizard.CompiledIOP
+	round     int
+	oldStates ifaces.Column
+	blocks    ifaces.Column
+	newStates ifaces.Column
+	// pow4[r][i] stores the 4-th power of the input of the i-th s-box of the
+	// round r. The partial rounds have a single s-box.
+	pow4 [][]ifaces.Column
+	// states[r] stores the state after the round r
+	states [][poseidon2.Width]ifaces.Column
+}
+
+// Applies the round #r of the permutation: the round keys are added, the
+// s-boxes are applied and the result goes through the linear layer.
+func (ctx *poseidon2Ctx) permRound(state [poseidon2.Width]*symbolic.Expression, r int) [poseidon2.Width]*symbolic.Expression {
+
+	pow4 := make([]ifaces.Column, len(poseidon2.RoundKeys[r]))
+
+	for i := range poseidon2.RoundKeys[r] {
+		// s' = (s + ark)^17 = (s + ark) * ((s + ark)^4)^4
+		x := state[i].Add(symbolic.NewConstant(poseidon2.RoundKeys[r][i]))
+		pow4[i] = poseidon2ExprHandle(ctx.comp, ctx.round, x.Pow(4), poseidon2Name(ctx.comp, "POW4", ctx.newStates.GetColID(), r, i))
+		state[i] = x.Mul(ifaces.ColumnAsVariable(pow4[i]).Pow(4))
+	}
+
+	ctx.pow4 = append(ctx.pow4, pow4)
+
+	if poseidon2.IsPartialRound(r) {
+		return matMul(poseidon2.InternalMatrix, state)
+	}
+
+	return matMul(poseidon2.ExternalMatrix, state)
+}
+
+// Commits to the state after the round #r and returns it as variables
+func (ctx *poseidon2Ctx) commitState(state [poseidon2.Width]*symbolic.Expression, r int) [poseidon2.Width]*symbolic.Expression {
+
+	var cols [poseidon2.Width]ifaces.Column
+	for i := range state {
+		cols[i] = poseidon2ExprHandle(ctx.comp, ctx.round, state[i], poseidon2Name(ctx.comp, "STATE", ctx.newStates.GetColID(), r, i))
+		state[i] = ifaces.ColumnAsVariable(cols[i])
+	}
+
+	ctx.states = append(ctx.states, cols)
+	return state
+}
+
+// matMul multiplies the state by a matrix of small integers
+func matMul(m [poseidon2.Width][poseidon2.Width]int, state [poseidon2.Width]*symbolic.Expression) [poseidon2.Width]*symbolic.Expression {
+	var res [poseidon2.Width]*symbolic.Expression
+	for i := range m {
+		res[i] = symbolic.NewConstant(m[i][0]).Mul(state[0]).Add(symbolic.NewConstant(m[i][1]).Mul(state[1]))
+	}
+	return res
+}
+
+func poseidon2Name(comp *wizard.CompiledIOP, args ...interface{}) string {
+	// Format all the arguments independently
+	fmttedArgs := make([]string, len(args))
+	for i := range args {
+		fmttedArgs[i] = fmt.Sprintf("%v", args[i])
+	}
+
+	// Join them with "_" and prefix them with an indicator for POSEIDON2
+	return fmt.Sprintf("POSEIDON2_%v_%s", comp.SelfRecursionCount, strings.Join(fmttedArgs, "_"))
+}
+
+// Create a column constrained to be equal to an expression
+func poseidon2ExprHandle(comp *wizard.CompiledIOP, round int, expr *symbolic.Expression, name string) ifaces.Column {
+	board := expr.Board()
+	length := wizardutils.ExprIsOnSameLengthHandles(&board)
+	res := comp.InsertCommit(round, ifaces.ColID(name), length)
+	comp.InsertGlobal(round, ifaces.QueryID(name), expr.Sub(ifaces.ColumnAsVariable(res)))
+	return res
+}
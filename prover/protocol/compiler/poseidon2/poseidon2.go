@@ -0,0 +1,94 @@
+package poseidon2
+
+import (
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// CompilePoseidon2 compiles the Poseidon2 queries by instantiating a Poseidon2
+// module. It works as the MiMC compiler: if there is a single query, the
+// compression function is arithmetized directly over its columns. Otherwise,
+// the queries are conflated in a single module and looked up into it.
+func CompilePoseidon2(comp *wizard.CompiledIOP) {
+
+	// Scans the compiled IOP, looking for unignored Poseidon2 queries.
+	// And mark them as ignored when encountered.
+	totalLen := 0
+	round := 0
+	poseidon2Queries := []query.Poseidon2{}
+
+	for _, id := range comp.QueriesNoParams.AllUnignoredKeys() {
+
+		q, ok := comp.QueriesNoParams.Data(id).(query.Poseidon2)
+		if !ok {
+			// not a Poseidon2 query, skip it
+			continue
+		}
+
+		comp.QueriesNoParams.MarkAsIgnored(id)
+
+		poseidon2Queries = append(poseidon2Queries, q)
+		totalLen += q.Blocks.Size()
+		round = utils.Max(round, comp.QueriesNoParams.Round(id))
+	}
+
+	if len(poseidon2Queries) == 0 {
+		logrus.Debug("Poseidon2 compiler exited : no Poseidon2 queries to compile")
+		return
+	}
+
+	if len(poseidon2Queries) == 1 {
+		// arithmetize the compression directly over the columns of the query
+		logrus.Debug("Poseidon2 compiler : only one Poseidon2 query to compile, no lookup needed")
+		q := poseidon2Queries[0]
+		manualCheckPoseidon2Block(comp, q.Blocks, q.OldState, q.NewState)
+		return
+	}
+
+	// Else, we conflate every query in a single module and we apply the
+	// Poseidon2 check over it.
+	totalLen = utils.NextPowerOfTwo(totalLen)
+
+	blocks := comp.InsertCommit(round, ifaces.ColID(poseidon2Name(comp, "ALL_BLOCKS")), totalLen)
+	oldStates := comp.InsertCommit(round, ifaces.ColID(poseidon2Name(comp, "ALL_OLD_STATES")), totalLen)
+	newStates := comp.InsertCommit(round, ifaces.ColID(poseidon2Name(comp, "ALL_NEW_STATES")), totalLen)
+
+	comp.SubProvers.AppendToInner(round, func(run *wizard.ProverRuntime) {
+
+		blocksWit := make([]field.Element, 0, totalLen)
+		oldStatesWit := make([]field.Element, 0, totalLen)
+		newStatesWit := make([]field.Element, 0, totalLen)
+
+		for _, q := range poseidon2Queries {
+			blocksWit = append(blocksWit, q.Blocks.GetColAssignment(run).IntoRegVecSaveAlloc()...)
+			oldStatesWit = append(oldStatesWit, q.OldState.GetColAssignment(run).IntoRegVecSaveAlloc()...)
+			newStatesWit = append(newStatesWit, q.NewState.GetColAssignment(run).IntoRegVecSaveAlloc()...)
+		}
+
+		// The module is padded with the compression of (0, 0)
+		dumNew := poseidon2.BlockCompression(field.Zero(), field.Zero())
+
+		run.AssignColumn(blocks.GetColID(), smartvectors.RightZeroPadded(blocksWit, totalLen))
+		run.AssignColumn(oldStates.GetColID(), smartvectors.RightZeroPadded(oldStatesWit, totalLen))
+		run.AssignColumn(newStates.GetColID(), smartvectors.RightPadded(newStatesWit, dumNew, totalLen))
+	})
+
+	// Internal consistency of the new columns
+	manualCheckPoseidon2Block(comp, blocks, oldStates, newStates)
+
+	// And lookupize all the Poseidon2 queries into the central module
+	for _, q := range poseidon2Queries {
+		comp.InsertInclusion(
+			round,
+			ifaces.QueryID(poseidon2Name(comp, "INCLUSION", q.ID)),
+			[]ifaces.Column{blocks, oldStates, newStates},
+			[]ifaces.Column{q.Blocks, q.OldState, q.NewState},
+		)
+	}
+}
@@ -0,0 +1,92 @@
+package poseidon2_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	poseidon2Comp "github.com/consensys/linea-monorepo/prover/protocol/compiler/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// assignPoseidon2 assigns (block, old, new) with block[i] = i, old[i] = i +
+// offset and new[i] = poseidon2(old[i], block[i]). If tamper is set, the last
+// new state is wrong.
+func assignPoseidon2(run *wizard.ProverRuntime, block, old, new ifaces.Column, offset int, tamper bool) {
+
+	size := block.Size()
+	bl := make([]field.Element, size)
+	ol := make([]field.Element, size)
+	ne := make([]field.Element, size)
+
+	for i := 0; i < size; i++ {
+		bl[i] = field.NewElement(uint64(i))
+		ol[i] = field.NewElement(uint64(i + offset))
+		ne[i] = poseidon2.BlockCompression(ol[i], bl[i])
+	}
+
+	if tamper {
+		ne[size-1] = field.NewElement(42)
+	}
+
+	run.AssignColumn(block.GetColID(), smartvectors.NewRegular(bl))
+	run.AssignColumn(old.GetColID(), smartvectors.NewRegular(ol))
+	run.AssignColumn(new.GetColID(), smartvectors.NewRegular(ne))
+}
+
+func TestPoseidon2CompilerSingleQuery(t *testing.T) {
+
+	size := 16
+
+	var block, old, new ifaces.Column
+
+	define := func(b *wizard.Builder) {
+		block = b.RegisterCommit("BLOCK", size)
+		old = b.RegisterCommit("OLD", size)
+		new = b.RegisterCommit("NEW", size)
+		b.InsertPoseidon2(0, "POSEIDON2", block, old, new)
+	}
+
+	comp := wizard.Compile(define, poseidon2Comp.CompilePoseidon2, dummy.Compile)
+
+	proof := wizard.Prove(comp, func(run *wizard.ProverRuntime) { assignPoseidon2(run, block, old, new, size, false) })
+	require.NoError(t, wizard.Verify(comp, proof))
+
+	proof = wizard.Prove(comp, func(run *wizard.ProverRuntime) { assignPoseidon2(run, block, old, new, size, true) })
+	require.Error(t, wizard.Verify(comp, proof))
+}
+
+func TestPoseidon2CompilerTwoQuery(t *testing.T) {
+
+	size1 := 16
+	size2 := 8
+
+	var block1, old1, new1, block2, old2, new2 ifaces.Column
+
+	define := func(b *wizard.Builder) {
+		block1 = b.RegisterCommit("BLOCK1", size1)
+		old1 = b.RegisterCommit("OLD1", size1)
+		new1 = b.RegisterCommit("NEW1", size1)
+		b.InsertPoseidon2(0, "POSEIDON2_1", block1, old1, new1)
+
+		block2 = b.RegisterCommit("BLOCK2", size2)
+		old2 = b.RegisterCommit("OLD2", size2)
+		new2 = b.RegisterCommit("NEW2", size2)
+		b.InsertPoseidon2(0, "POSEIDON2_2", block2, old2, new2)
+	}
+
+	prove := func(tamper bool) wizard.ProverStep {
+		return func(run *wizard.ProverRuntime) {
+			assignPoseidon2(run, block1, old1, new1, size1, false)
+			assignPoseidon2(run, block2, old2, new2, size2, tamper)
+		}
+	}
+
+	comp := wizard.Compile(define, poseidon2Comp.CompilePoseidon2, dummy.Compile)
+	require.NoError(t, wizard.Verify(comp, wizard.Prove(comp, prove(false))))
+	require.Error(t, wizard.Verify(comp, wizard.Prove(comp, prove(true))))
+}
@@ -300,6 +300,13 @@ func assertVortexCompiled(comp *wizard.CompiledIOP) *vortex.Ctx {
 	}
 
 	vortexCtx := ctx.(*vortex.Ctx)
+
+	// The self-recursion arithmetizes the MiMC hashes of the columns and of
+	// the Merkle trees.
+	if vortexCtx.UsePoseidon2 {
+		utils.Panic("the self-recursion does not support the Poseidon2 option of vortex")
+	}
+
 	// Also "stamp" that the compilation context has been cancelled
 	// this means that the verifier part of vortex will be ignored
 	// (and will be replaced by what is declared in the self-recursion)
@@ -1,10 +1,12 @@
 package vortex
 
 import (
+	"hash"
 	"math"
 
 	"github.com/consensys/linea-monorepo/prover/crypto"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
 	"github.com/consensys/linea-monorepo/prover/crypto/ringsis"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
@@ -104,6 +106,9 @@ type Ctx struct {
 
 	// Flag indicating that we want to replace SIS by MiMC
 	ReplaceSisByMimc bool
+	// Flag indicating that we want to use Poseidon2 in place of MiMC to hash
+	// the columns and the Merkle trees.
+	UsePoseidon2 bool
 
 	// The (verifiedly) unique polynomial query
 	Query                        query.UnivariateEval
@@ -419,14 +424,24 @@ func (ctx *Ctx) generateVortexParams() {
 		}
 		sisParams = &ringsis.StdParams
 	}
-	ctx.VortexParams = vortex.NewParams(ctx.BlowUpFactor, ctx.NumCols, ctx.CommittedRowsCount, *sisParams, mimc.NewMiMC)
+	ctx.VortexParams = vortex.NewParams(ctx.BlowUpFactor, ctx.NumCols, ctx.CommittedRowsCount, *sisParams, ctx.hashFunc)
 
 	// And replace SIS by MiMC if this is deemed useful
 	if ctx.ReplaceSisByMimc {
-		ctx.VortexParams.RemoveSis(mimc.NewMiMC)
+		ctx.VortexParams.RemoveSis(ctx.hashFunc)
 	}
 }
 
+// hashFunc returns the hasher used to hash the columns when SIS is replaced
+// and to build the Merkle trees. This is MiMC unless the [WithPoseidon2]
+// option is set.
+func (ctx *Ctx) hashFunc() hash.Hash {
+	if ctx.UsePoseidon2 {
+		return poseidon2.NewPoseidon2()
+	}
+	return mimc.NewMiMC()
+}
+
 // return the number of columns to open
 func (ctx *Ctx) NbColsToOpen() int {
 
@@ -4,6 +4,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
 	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
 	"github.com/consensys/linea-monorepo/prover/maths/fft/fastpoly"
@@ -65,6 +66,14 @@ func (ctx *Ctx) GnarkVerify(api frontend.API, vr *wizard.WizardVerifierCircuit)
 		return h, nil
 	}
 
+	// Poseidon2 is not supported by the gkr hasher factory, so it is hashed
+	// directly in the circuit.
+	if ctx.UsePoseidon2 {
+		factoryHasherFunc = func(api frontend.API) (hash.FieldHasher, error) {
+			return poseidon2.NewGnarkHasher(api), nil
+		}
+	}
+
 	packedMProofs := vr.GetColumn(ctx.MerkleProofName())
 	proof.MerkleProofs = ctx.unpackMerkleProofsGnark(packedMProofs, entryList)
 
@@ -44,6 +44,18 @@ func assignTestCircuit(comp *wizard.CompiledIOP, proof wizard.Proof) *VortexTest
 }
 
 func TestVortexGnarkVerifier(t *testing.T) {
+	testVortexGnarkVerifier(t, nil, vortex.ReplaceSisByMimc())
+}
+
+// Poseidon2 is used both for the Merkle trees and for the Fiat-Shamir
+// transcript.
+func TestVortexGnarkVerifierPoseidon2(t *testing.T) {
+	testVortexGnarkVerifier(t, []func(*wizard.CompiledIOP){wizard.UsePoseidon2FiatShamir}, vortex.ReplaceSisByMimc(), vortex.WithPoseidon2())
+}
+
+// testVortexGnarkVerifier runs the native and the gnark verifier of a wizard
+// compiled with the provided steps followed by Vortex.
+func testVortexGnarkVerifier(t *testing.T, steps []func(*wizard.CompiledIOP), options ...vortex.VortexOp) {
 
 	polSize := 1 << 4
 	nPols := 16
@@ -105,13 +117,7 @@ func TestVortexGnarkVerifier(t *testing.T) {
 		pr.AssignUnivariate("EVAL", x, ys...)
 	}
 
-	compiled := wizard.Compile(
-		define,
-		vortex.Compile(
-			4,
-			vortex.ReplaceSisByMimc(),
-		),
-	)
+	compiled := wizard.Compile(define, append(steps, vortex.Compile(4, options...))...)
 	proof := wizard.Prove(compiled, prove)
 
 	// Just as a sanity check, do not run the Plonk
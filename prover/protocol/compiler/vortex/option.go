@@ -36,3 +36,12 @@ func ReplaceSisByMimc() VortexOp {
 		ctx.SisParams = nil
 	}
 }
+
+// Use Poseidon2 in place of MiMC to hash the columns (when SIS is replaced)
+// and to build the Merkle trees. The resulting compiled IOP cannot be
+// self-recursed as the self-recursion arithmetizes MiMC.
+func WithPoseidon2() VortexOp {
+	return func(ctx *Ctx) {
+		ctx.UsePoseidon2 = true
+	}
+}
@@ -161,6 +161,15 @@ func TestVortexOneFullOneDryMerkle(t *testing.T) {
 }
 
 func TestVortexSingleRoundMerkleNoSis(t *testing.T) {
+	testVortexSingleRoundMerkle(t, vortex.ReplaceSisByMimc())
+}
+
+func TestVortexSingleRoundMerklePoseidon2(t *testing.T) {
+	testVortexSingleRoundMerkle(t, vortex.WithPoseidon2())
+	testVortexSingleRoundMerkle(t, vortex.ReplaceSisByMimc(), vortex.WithPoseidon2())
+}
+
+func testVortexSingleRoundMerkle(t *testing.T, options ...vortex.VortexOp) {
 
 	polSize := 1 << 4
 	nPols := 16
@@ -187,7 +196,7 @@ func TestVortexSingleRoundMerkleNoSis(t *testing.T) {
 		pr.AssignUnivariate("EVAL", x, ys...)
 	}
 
-	compiled := wizard.Compile(define, vortex.Compile(4, vortex.ReplaceSisByMimc()))
+	compiled := wizard.Compile(define, vortex.Compile(4, options...))
 	proof := wizard.Prove(compiled, prove)
 	valid := wizard.Verify(compiled, proof)
 
@@ -0,0 +1,150 @@
+package distributed
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+const (
+	// randomnessRound is the round of the segments at which the shared
+	// randomness is available, the columns of the original wizard being all
+	// committed at round 0.
+	randomnessRound = 1
+
+	// DigestPublicInput is the name of the public input of the segments
+	// exposing the digest of their transcript after round 0.
+	DigestPublicInput = "DISTRIBUTED_DIGEST"
+	// AlphaPublicInput and GammaPublicInput are the names of the public
+	// inputs of the segments exposing the shared randomness they used.
+	AlphaPublicInput = "DISTRIBUTED_ALPHA"
+	GammaPublicInput = "DISTRIBUTED_GAMMA"
+
+	// beaconStateKey is the key of the [Beacon] in the state of the prover
+	// runtime of the segments.
+	beaconStateKey = "DISTRIBUTED_BEACON"
+)
+
+// Beacon lets the provers of the segments exchange the digests of their
+// transcripts. The shared randomness of the cross-segment arguments is
+// derived from all the digests so that no segment can choose its columns
+// after seeing it.
+type Beacon interface {
+	// Exchange publishes the digest of a segment and returns the digests of
+	// all the segments. It blocks until all of them are published.
+	Exchange(segment ModuleName, digest field.Element) map[ModuleName]field.Element
+}
+
+// LocalBeacon is a [Beacon] for segments proven in the same process.
+type LocalBeacon struct {
+	lock     sync.Mutex
+	segments int
+	digests  map[ModuleName]field.Element
+	done     chan struct{}
+}
+
+// NewLocalBeacon returns a [LocalBeacon] for the number of segments
+func NewLocalBeacon(numSegments int) *LocalBeacon {
+	return &LocalBeacon{
+		segments: numSegments,
+		digests:  map[ModuleName]field.Element{},
+		done:     make(chan struct{}),
+	}
+}
+
+// Exchange implements [Beacon]
+func (b *LocalBeacon) Exchange(segment ModuleName, digest field.Element) map[ModuleName]field.Element {
+
+	b.lock.Lock()
+	if _, ok := b.digests[segment]; ok {
+		b.lock.Unlock()
+		utils.Panic("the digest of the segment %v was already published", segment)
+	}
+
+	b.digests[segment] = digest
+	if len(b.digests) == b.segments {
+		close(b.done)
+	}
+	b.lock.Unlock()
+
+	<-b.done
+	return b.digests
+}
+
+// sharedRandomness declares the shared randomness in a segment. The digest
+// of the segment is a coin sampled after round 0, and the randomness is
+// sent by the prover in a proof column. The verifier of the
+// [DistributedWizard] checks that it is derived from the digests of all the
+// segments.
+type sharedRandomness struct {
+	// Segment is the name of the segment
+	Segment ModuleName
+	// AllSegments lists the names of all the segments, sorted
+	AllSegments []ModuleName
+	// Digest is the coin standing for the digest of the segment
+	Digest coin.Info
+	// R stores the randomness, alpha and gamma
+	R ifaces.Column
+	// Alpha is used to collapse the tables into a single column and Gamma is
+	// the evaluation point of the log-derivative sums and grand products.
+	Alpha, Gamma ifaces.Accessor
+}
+
+// define declares the randomness in comp
+func (r *sharedRandomness) define(comp *wizard.CompiledIOP) {
+
+	r.Digest = comp.InsertCoin(randomnessRound, coin.Name(DigestPublicInput), coin.Field)
+	r.R = comp.InsertProof(randomnessRound, "DISTRIBUTED_RANDOMNESS", 2)
+	r.Alpha = accessors.NewFromPublicColumn(r.R, 0)
+	r.Gamma = accessors.NewFromPublicColumn(r.R, 1)
+
+	comp.PublicInputs = append(comp.PublicInputs,
+		wizard.PublicInput{Name: DigestPublicInput, Acc: accessors.NewFromCoin(r.Digest)},
+		wizard.PublicInput{Name: AlphaPublicInput, Acc: r.Alpha},
+		wizard.PublicInput{Name: GammaPublicInput, Acc: r.Gamma},
+	)
+
+	comp.RegisterProverAction(randomnessRound, r)
+}
+
+// Run implements [wizard.ProverAction]. It exchanges the digest of the
+// segment through the [Beacon] stored in the state of the runtime and
+// assigns the randomness.
+func (r *sharedRandomness) Run(run *wizard.ProverRuntime) {
+
+	var (
+		beacon  = run.State.MustGet(beaconStateKey).(Beacon)
+		digests = beacon.Exchange(r.Segment, run.GetRandomCoinField(r.Digest.Name))
+	)
+
+	alpha, gamma, err := deriveRandomness(r.AllSegments, digests)
+	if err != nil {
+		utils.Panic("segment %v: %v", r.Segment, err)
+	}
+
+	run.AssignColumn(r.R.GetColID(), smartvectors.NewRegular([]field.Element{alpha, gamma}))
+}
+
+// deriveRandomness hashes the digests of the segments, in the order of the
+// segments, into the shared randomness.
+func deriveRandomness(segments []ModuleName, digests map[ModuleName]field.Element) (alpha, gamma field.Element, err error) {
+
+	fs := fiatshamir.NewMiMCFiatShamir()
+	for _, seg := range segments {
+		digest, ok := digests[seg]
+		if !ok {
+			return alpha, gamma, fmt.Errorf("missing the digest of the segment %v", seg)
+		}
+		fs.Update(digest)
+	}
+
+	return fs.RandomField(), fs.RandomField(), nil
+}
@@ -0,0 +1,298 @@
+// Package distributed splits a wizard into segments that are proven
+// independently, each with its own commitment, so that the proving work can
+// be spread over several machines.
+//
+// The columns of the wizard are attributed to modules by a user-provided
+// [ModuleDiscoverer]. The modules that are bound by a query that cannot be
+// split (global or local constraints, ranges, MiMC, Poseidon2, fixed
+// permutations) are merged into the same segment. The inclusion and
+// permutation queries whose sides lie in different segments are turned into
+// cross-segment arguments: each segment proves the log-derivative sum or the
+// grand product of its share of the query and exposes it as a public input.
+// The verifier checks the segment proofs and then that the shares of every
+// query add up.
+//
+// The cross-segment arguments are sampled from a randomness shared by all the
+// segments. Every segment commits to its columns first and exposes a digest
+// of its transcript, then the provers exchange their digests through a
+// [Beacon] and derive the shared randomness from all of them. The package only
+// provides the in-process [LocalBeacon]: proving the segments on separate
+// machines requires a [Beacon] relaying the digests between them. The prover
+// of a segment only needs the columns returned by [Segment.RequiredColumns].
+//
+// The package only supports single-round wizards: the columns and the queries
+// must all be declared at round 0 and the wizard must not have coins or
+// verifier actions. This is because the prover and verifier actions of the
+// multi-round gadgets cannot be relocated in a segment.
+package distributed
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// ModuleName identifies a module of the wizard, e.g. a module of the
+// arithmetization.
+type ModuleName string
+
+// ModuleDiscoverer returns the module of a column of the wizard. It is only
+// called on the columns that are assigned by the prover, the precomputed
+// columns being copied in every segment that uses them.
+type ModuleDiscoverer func(col ifaces.ColID) ModuleName
+
+// DistributedWizard is a wizard split into segments. It is constructed by
+// [Distribute].
+type DistributedWizard struct {
+	// Original is the wizard that is distributed. It is not compiled and is
+	// only used to run the assignment of the columns.
+	Original *wizard.CompiledIOP
+	// Segments lists the segments of the wizard, sorted by name.
+	Segments []*Segment
+	// Lookups lists the inclusion queries spanning several segments.
+	Lookups []*CrossLookup
+	// Permutations lists the permutation queries spanning several segments.
+	Permutations []*CrossPermutation
+}
+
+// Segment is a part of a [DistributedWizard] that is proven independently.
+type Segment struct {
+	// Name is the name of the segment. This is the first of its modules in
+	// lexicographic order.
+	Name ModuleName
+	// Modules lists the modules of the segment in lexicographic order.
+	Modules []ModuleName
+	// Comp is the compiled wizard of the segment.
+	Comp *wizard.CompiledIOP
+	// Columns lists the columns of the original wizard that are assigned by
+	// the prover of the segment.
+	Columns []ifaces.ColID
+	// Queries lists the queries of the original wizard that are entirely
+	// enforced within the segment.
+	Queries []ifaces.QueryID
+	// LookupParts and PermutationParts are the shares of the segment in the
+	// cross-segment queries.
+	LookupParts      []*LookupPart
+	PermutationParts []*PermutationPart
+	// randomness is the shared randomness of the segment
+	randomness *sharedRandomness
+}
+
+// Distribute splits comp into segments along the modules returned by
+// discover. Each segment is compiled with the compilation suite and its
+// Fiat-Shamir state is bootstrapped from the metadata and the name of the
+// segment. The function panics if comp uses a feature that is not supported,
+// see the package documentation.
+func Distribute(
+	comp *wizard.CompiledIOP,
+	discover ModuleDiscoverer,
+	metadata wizard.VersionMetadata,
+	suite ...func(*wizard.CompiledIOP),
+) *DistributedWizard {
+
+	assertSupported(comp)
+
+	var (
+		part = partition(comp, discover)
+		dw   = &DistributedWizard{Original: comp}
+	)
+
+	for _, seg := range part.segments {
+		if !slices.Contains(dw.Segments, seg) {
+			dw.Segments = append(dw.Segments, seg)
+		}
+	}
+
+	slices.SortFunc(dw.Segments, func(a, b *Segment) int {
+		return compareModules(a.Name, b.Name)
+	})
+
+	names := dw.SegmentNames()
+	for _, seg := range dw.Segments {
+		seg.randomness = &sharedRandomness{Segment: seg.Name, AllSegments: names}
+	}
+
+	for _, qName := range append(comp.QueriesNoParams.AllKeys(), comp.QueriesParams.AllKeys()...) {
+		dw.dispatchQuery(part, qName)
+	}
+
+	for _, seg := range dw.Segments {
+
+		segMetadata := metadata
+		segMetadata.Title = fmt.Sprintf("%v/segment/%v", metadata.Title, seg.Name)
+
+		seg.Comp = wizard.Compile(
+			func(b *wizard.Builder) { seg.define(b.CompiledIOP, comp) },
+			suite...,
+		).BootstrapFiatShamir(segMetadata, nil)
+	}
+
+	return dw
+}
+
+// SegmentNames returns the names of the segments, sorted.
+func (dw *DistributedWizard) SegmentNames() []ModuleName {
+	res := make([]ModuleName, len(dw.Segments))
+	for i := range dw.Segments {
+		res[i] = dw.Segments[i].Name
+	}
+	return res
+}
+
+// Segment returns the segment with the given name or nil if there is none.
+func (dw *DistributedWizard) Segment(name ModuleName) *Segment {
+	for _, seg := range dw.Segments {
+		if seg.Name == name {
+			return seg
+		}
+	}
+	return nil
+}
+
+// SegmentOf returns the segment containing the module, or nil if there is
+// none.
+func (dw *DistributedWizard) SegmentOf(module ModuleName) *Segment {
+	for _, seg := range dw.Segments {
+		if slices.Contains(seg.Modules, module) {
+			return seg
+		}
+	}
+	return nil
+}
+
+// dispatchQuery attributes a query of the original wizard to the segment
+// enforcing it, or registers it as a cross-segment query.
+func (dw *DistributedWizard) dispatchQuery(part *modulePartition, qName ifaces.QueryID) {
+
+	var q ifaces.Query
+	if dw.Original.QueriesNoParams.Exists(qName) {
+		q = dw.Original.QueriesNoParams.Data(qName)
+	} else {
+		q = dw.Original.QueriesParams.Data(qName)
+	}
+
+	switch q := q.(type) {
+
+	case query.Inclusion:
+		sides := append([][]ifaces.Column{q.Included}, q.Including...)
+		sides[0] = appendIfNotNil(sides[0], q.IncludedFilter)
+		for frag := range q.Including {
+			if q.IsFilteredOnIncluding() {
+				sides[frag+1] = appendIfNotNil(sides[frag+1], q.IncludingFilter[frag])
+			}
+		}
+
+		segs := part.segmentsOfSides(qName, sides)
+		if allEqual(segs) {
+			segs[0].Queries = append(segs[0].Queries, qName)
+			return
+		}
+
+		dw.Lookups = append(dw.Lookups, newCrossLookup(q, segs))
+
+	case query.Permutation:
+		sides := append(append([][]ifaces.Column{}, q.A...), q.B...)
+		segs := part.segmentsOfSides(qName, sides)
+		if allEqual(segs) {
+			segs[0].Queries = append(segs[0].Queries, qName)
+			return
+		}
+
+		dw.Permutations = append(dw.Permutations, newCrossPermutation(q, segs))
+
+	default:
+		seg := part.segmentOf(qName, queryColumns(q))
+		seg.Queries = append(seg.Queries, qName)
+	}
+}
+
+// assertSupported panics if comp cannot be distributed
+func assertSupported(comp *wizard.CompiledIOP) {
+
+	if comp.NumRounds() != 1 {
+		utils.Panic("only single-round wizards can be distributed, the wizard has %v rounds", comp.NumRounds())
+	}
+
+	if len(comp.Coins.AllKeys()) > 0 {
+		utils.Panic("the wizard has coins (%v) and cannot be distributed", comp.Coins.AllKeys())
+	}
+
+	for _, steps := range comp.SubVerifiers.Inner() {
+		if len(steps) > 0 {
+			utils.Panic("the wizard has verifier actions and cannot be distributed")
+		}
+	}
+
+	for _, name := range comp.Columns.AllKeysAt(0) {
+		switch status := comp.Columns.Status(name); status {
+		case column.Committed, column.Proof, column.Precomputed, column.VerifyingKey:
+		default:
+			utils.Panic("the column %v has status %v and cannot be distributed", name, status.String())
+		}
+	}
+
+	for _, pub := range comp.PublicInputs {
+		if _, ok := localOpeningOfPublicInput(pub); !ok {
+			utils.Panic("the public input %v is not a local opening (%T) and cannot be distributed", pub.Name, pub.Acc)
+		}
+	}
+}
+
+// queryColumns returns the columns of the queries that are not split by the
+// distribution. It panics on the queries that are not supported.
+func queryColumns(q ifaces.Query) []ifaces.Column {
+
+	switch q := q.(type) {
+	case query.GlobalConstraint:
+		return expressionColumns(q.Expression)
+	case query.LocalConstraint:
+		return expressionColumns(q.Expression)
+	case query.Range:
+		return []ifaces.Column{q.Handle}
+	case query.MiMC:
+		return []ifaces.Column{q.Blocks, q.OldState, q.NewState}
+	case query.Poseidon2:
+		return []ifaces.Column{q.Blocks, q.OldState, q.NewState}
+	case query.FixedPermutation:
+		return append(append([]ifaces.Column{}, q.A...), q.B...)
+	case query.LocalOpening:
+		return []ifaces.Column{q.Pol}
+	}
+
+	utils.Panic("query %v of type %T cannot be distributed", q.Name(), q)
+	return nil
+}
+
+// appendIfNotNil appends col to cols if it is not nil
+func appendIfNotNil(cols []ifaces.Column, col ifaces.Column) []ifaces.Column {
+	if col == nil {
+		return cols
+	}
+	return append(append([]ifaces.Column{}, cols...), col)
+}
+
+// allEqual returns true if all the segments are the same
+func allEqual(segs []*Segment) bool {
+	for i := range segs {
+		if segs[i] != segs[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareModules compares two module names
+func compareModules(a, b ModuleName) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,209 @@
+package distributed_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
+	"github.com/consensys/linea-monorepo/prover/protocol/distributed"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moduleOfTest returns the prefix of the name of the column
+func moduleOfTest(col ifaces.ColID) distributed.ModuleName {
+	module, _, _ := strings.Cut(string(col), ".")
+	return distributed.ModuleName(module)
+}
+
+// defineTest declares a wizard over five modules:
+//   - A.Y = 2 * A.X, and (A.X, A.Y) is looked up in the table (B.T, B.U)
+//   - A.X filtered by A.F is looked up in B.T
+//   - C.P is a permutation of A.X and C.Q is looked up in a precomputed
+//     table
+//   - D.V and E.W are bound by a global constraint
+func defineTest(b *wizard.Builder) {
+
+	var (
+		x = b.RegisterCommit("A.X", 8)
+		y = b.RegisterCommit("A.Y", 8)
+		f = b.RegisterCommit("A.F", 8)
+		t = b.RegisterCommit("B.T", 16)
+		u = b.RegisterCommit("B.U", 16)
+		p = b.RegisterCommit("C.P", 8)
+		q = b.RegisterCommit("C.Q", 8)
+		v = b.RegisterCommit("D.V", 4)
+		w = b.RegisterCommit("E.W", 4)
+		r = b.RegisterPrecomputed("RANGE", smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7))
+	)
+
+	b.GlobalConstraint("A_DOUBLE", symbolic.Sub(y, symbolic.Mul(x, 2)))
+	b.GlobalConstraint("B_DOUBLE", symbolic.Sub(u, symbolic.Mul(t, 2)))
+	b.Inclusion("A_IN_B", []ifaces.Column{t, u}, []ifaces.Column{x, y})
+	b.InclusionConditionalOnIncluded("A_FILTERED_IN_B", []ifaces.Column{t}, []ifaces.Column{x}, f)
+	b.Permutation("A_PERM_C", []ifaces.Column{x}, []ifaces.Column{p})
+	b.Inclusion("C_IN_RANGE", []ifaces.Column{r}, []ifaces.Column{q})
+	b.GlobalConstraint("D_PLUS_E", symbolic.Sub(w, symbolic.Add(v, column.Shift(v, 1))))
+}
+
+// assignmentTest returns the assignment of the wizard of [defineTest]. If
+// tamper is set, A.X is not a permutation of C.P anymore. The offset is added
+// to D.V to obtain another valid assignment.
+func assignmentTest(tamper bool, offset int) map[ifaces.ColID]smartvectors.SmartVector {
+
+	p := smartvectors.ForTest(8, 7, 6, 5, 4, 3, 2, 1)
+	if tamper {
+		p = smartvectors.ForTest(8, 7, 6, 5, 4, 3, 2, 2)
+	}
+
+	return map[ifaces.ColID]smartvectors.SmartVector{
+		"A.X": smartvectors.ForTest(1, 2, 3, 4, 5, 6, 7, 8),
+		"A.Y": smartvectors.ForTest(2, 4, 6, 8, 10, 12, 14, 16),
+		"A.F": smartvectors.ForTest(1, 0, 1, 0, 0, 0, 0, 1),
+		"B.T": smartvectors.ForTest(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15),
+		"B.U": smartvectors.ForTest(0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30),
+		"C.P": p,
+		"C.Q": smartvectors.ForTest(7, 7, 0, 1, 2, 3, 4, 5),
+		"D.V": smartvectors.ForTest(1+offset, 2+offset, 3+offset, 4+offset),
+		"E.W": smartvectors.ForTest(3+2*offset, 5+2*offset, 7+2*offset, 5+2*offset),
+	}
+}
+
+// proveTest assigns the whole wizard of [defineTest], see [assignmentTest]
+func proveTest(tamper bool, offset int) wizard.ProverStep {
+	return func(run *wizard.ProverRuntime) {
+		for name, v := range assignmentTest(tamper, offset) {
+			run.AssignColumn(name, v)
+		}
+	}
+}
+
+// proveSegmentTest only assigns the columns requested for a segment, see
+// [assignmentTest]
+func proveSegmentTest(offset int) distributed.SegmentAssignment {
+	return func(run *wizard.ProverRuntime, columns []ifaces.ColID) {
+		assignment := assignmentTest(false, offset)
+		for _, name := range columns {
+			run.AssignColumn(name, assignment[name])
+		}
+	}
+}
+
+func TestDistribute(t *testing.T) {
+
+	dw := distributed.Distribute(wizard.Compile(defineTest), moduleOfTest, wizard.VersionMetadata{}, dummy.Compile)
+
+	require.Equal(t, []distributed.ModuleName{"A", "B", "C", "D"}, dw.SegmentNames())
+	assert.Equal(t, []distributed.ModuleName{"D", "E"}, dw.Segment("D").Modules)
+	assert.Equal(t, dw.Segment("D"), dw.SegmentOf("E"))
+
+	assert.Equal(t, []ifaces.QueryID{"A_DOUBLE"}, dw.Segment("A").Queries)
+	assert.Equal(t, []ifaces.QueryID{"B_DOUBLE"}, dw.Segment("B").Queries)
+	assert.Equal(t, []ifaces.QueryID{"C_IN_RANGE"}, dw.Segment("C").Queries)
+	assert.Equal(t, []ifaces.QueryID{"D_PLUS_E"}, dw.Segment("D").Queries)
+	assert.Equal(t, []ifaces.ColID{"D.V", "E.W"}, dw.Segment("D").Columns)
+
+	require.Len(t, dw.Lookups, 2)
+	require.Len(t, dw.Permutations, 1)
+	assert.Len(t, dw.Segment("A").LookupParts, 2)
+	assert.Len(t, dw.Segment("B").LookupParts, 2)
+	assert.Len(t, dw.Segment("C").PermutationParts, 1)
+
+	// the segment holding the including side of the lookups also needs the
+	// included side to count the multiplicities
+	assert.ElementsMatch(t, []ifaces.ColID{"A.X", "A.Y", "A.F"}, dw.Segment("A").RequiredColumns(dw))
+	assert.ElementsMatch(t, []ifaces.ColID{"B.T", "B.U", "A.X", "A.Y", "A.F"}, dw.Segment("B").RequiredColumns(dw))
+	assert.ElementsMatch(t, []ifaces.ColID{"C.P", "C.Q"}, dw.Segment("C").RequiredColumns(dw))
+
+	// the precomputed table is only copied in the segment using it
+	assert.True(t, dw.Segment("C").Comp.Columns.Exists("RANGE"))
+	assert.False(t, dw.Segment("A").Comp.Columns.Exists("RANGE"))
+}
+
+func TestDistributedProof(t *testing.T) {
+
+	suites := map[string][]func(*wizard.CompiledIOP){
+		"dummy":  {dummy.Compile},
+		"vortex": {compiler.Arcane(4, 8), vortex.Compile(2)},
+	}
+
+	for name, suite := range suites {
+		t.Run(name, func(t *testing.T) {
+
+			dw := distributed.Distribute(wizard.Compile(defineTest), moduleOfTest, wizard.VersionMetadata{Title: "test"}, suite...)
+
+			proofs := dw.Prove(proveTest(false, 0))
+			require.NoError(t, dw.Verify(proofs))
+
+			// The segments proven on their own with a shared beacon
+			var (
+				beacon = distributed.NewLocalBeacon(len(dw.Segments))
+				mu     = &sync.Mutex{}
+				wg     = &sync.WaitGroup{}
+			)
+
+			proofs = map[distributed.ModuleName]wizard.Proof{}
+			wg.Add(len(dw.Segments))
+			for _, seg := range dw.Segments {
+				go func(seg distributed.ModuleName) {
+					defer wg.Done()
+					proof := dw.ProveSegment(seg, proveSegmentTest(0), beacon)
+					mu.Lock()
+					proofs[seg] = proof
+					mu.Unlock()
+				}(seg.Name)
+			}
+			wg.Wait()
+
+			require.NoError(t, dw.Verify(proofs))
+
+			// The proof of a segment cannot be swapped with a proof using
+			// another randomness. The proofs are regenerated each time as
+			// the verifier inserts the verifying key in them. The dummy
+			// compiler does not update the Fiat-Shamir state with the
+			// columns, so the digest does not depend on them.
+			if name != "dummy" {
+				swapped := dw.Prove(proveTest(false, 0))
+				swapped["D"] = dw.Prove(proveTest(false, 1))["D"]
+				require.Error(t, dw.Verify(swapped))
+			}
+
+			missing := dw.Prove(proveTest(false, 0))
+			delete(missing, "D")
+			require.Error(t, dw.Verify(missing))
+		})
+	}
+}
+
+// Checks that the cross-segment permutation is enforced although each
+// segment is valid on its own
+func TestDistributedProofTampered(t *testing.T) {
+
+	dw := distributed.Distribute(wizard.Compile(defineTest), moduleOfTest, wizard.VersionMetadata{}, dummy.Compile)
+	err := dw.Verify(dw.Prove(proveTest(true, 0)))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "A_PERM_C")
+}
+
+func TestDistributeUnsupported(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		x := b.RegisterCommit("A.X", 8)
+		b.RegisterRandomCoin("COIN", 0)
+		b.RegisterCommit("B.Y", 8)
+		b.GlobalConstraint("A_ZERO", ifaces.ColumnAsVariable(x))
+	}
+
+	require.Panics(t, func() {
+		distributed.Distribute(wizard.Compile(define), moduleOfTest, wizard.VersionMetadata{}, dummy.Compile)
+	})
+}
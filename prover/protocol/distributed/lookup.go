@@ -0,0 +1,282 @@
+package distributed
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/column/verifiercol"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizardutils"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// CrossLookup is an inclusion query of the original wizard whose sides lie
+// in different segments. It is enforced with the log-derivative sums
+//
+//	\sum_{i \in S} filter_S[i] / (gamma + S~[i]) = \sum_{j \in T} M[j] / (gamma + T~[j])
+//
+// where S~ and T~ are the included and the including tables collapsed into a
+// single column using the powers of alpha, and M counts the occurrences of
+// the rows of T in S. Each side of the query is a [LookupPart] computing its
+// share of the sums in its segment.
+//
+// As in the log-derivative lookup compiler, the filter of the including side
+// is handled by prepending it to the including table and prepending a column
+// of ones to the included table.
+type CrossLookup struct {
+	// Query is the inclusion query of the original wizard
+	Query query.Inclusion
+	// Included is the share of the included side and Including the shares
+	// of the fragments of the including side.
+	Included  *LookupPart
+	Including []*LookupPart
+}
+
+// LookupPart is the share of a side of a [CrossLookup] in a segment
+type LookupPart struct {
+	// Segment is the name of the segment of the part
+	Segment ModuleName
+	// Table is the table of the side, in the original wizard
+	Table []ifaces.Column
+	// Filter is the filter of the included side, in the original wizard. It
+	// is nil for the including side or if the included side is not
+	// filtered.
+	Filter ifaces.Column
+	// M is the multiplicity column of an including fragment. It is nil for
+	// the included side.
+	M ifaces.Column
+	// Z accumulates the terms of the sum of the part and ZOpening opens its
+	// last value, which is exposed as a public input of the segment.
+	Z        ifaces.Column
+	ZOpening query.LocalOpening
+
+	name                   string
+	including              bool
+	numerator, denominator symbolic.ExpressionBoard
+}
+
+// newCrossLookup constructs the [CrossLookup] of an inclusion query given the
+// segments of its sides, the included side first, and registers the parts in
+// their segments.
+func newCrossLookup(q query.Inclusion, segs []*Segment) *CrossLookup {
+
+	var (
+		included  = q.Included
+		including = append([][]ifaces.Column{}, q.Including...)
+		cl        = &CrossLookup{Query: q}
+	)
+
+	if q.IsFilteredOnIncluding() {
+		included = append([]ifaces.Column{verifiercol.NewConstantCol(field.One(), included[0].Size())}, included...)
+		for frag := range including {
+			including[frag] = append([]ifaces.Column{q.IncludingFilter[frag]}, including[frag]...)
+		}
+	}
+
+	cl.Included = &LookupPart{
+		Segment: segs[0].Name,
+		Table:   included,
+		Filter:  q.IncludedFilter,
+		name:    wizardutils.DeriveName[string]("DISTRIBUTED_LOOKUP", q.ID, "INCLUDED"),
+	}
+	segs[0].LookupParts = append(segs[0].LookupParts, cl.Included)
+
+	for frag := range including {
+		part := &LookupPart{
+			Segment:   segs[frag+1].Name,
+			Table:     including[frag],
+			name:      wizardutils.DeriveName[string]("DISTRIBUTED_LOOKUP", q.ID, "INCLUDING", frag),
+			including: true,
+		}
+		cl.Including = append(cl.Including, part)
+		segs[frag+1].LookupParts = append(segs[frag+1].LookupParts, part)
+	}
+
+	return cl
+}
+
+// PublicInput returns the name of the public input of the segment exposing
+// the share of the part.
+func (p *LookupPart) PublicInput() string {
+	return p.name
+}
+
+// define declares the columns and the constraints of the part in the wizard
+// of its segment.
+func (p *LookupPart) define(comp *wizard.CompiledIOP, t *translator, r *sharedRandomness) {
+
+	var (
+		table       = t.columns(p.Table)
+		size        = table[0].Size()
+		numerator   = symbolic.NewConstant(1)
+		denominator = symbolic.Add(r.Gamma, collapse(r.Alpha, table))
+	)
+
+	if p.Filter != nil {
+		numerator = symbolic.NewVariable(t.column(p.Filter))
+	}
+
+	if p.including {
+		p.M = comp.InsertCommit(0, ifaces.ColIDf("%v_M", p.name), size)
+		numerator = symbolic.Neg(p.M)
+	}
+
+	p.numerator = numerator.Board()
+	p.denominator = denominator.Board()
+	p.Z = comp.InsertCommit(randomnessRound, ifaces.ColIDf("%v_Z", p.name), size)
+
+	// Z[0] = numerator[0] / denominator[0]
+	comp.InsertLocal(
+		randomnessRound,
+		ifaces.QueryIDf("%v_Z_START", p.name),
+		symbolic.Sub(numerator, symbolic.Mul(p.Z, denominator)),
+	)
+
+	// Z[i] = Z[i-1] + numerator[i] / denominator[i]
+	comp.InsertGlobal(
+		randomnessRound,
+		ifaces.QueryIDf("%v_Z_CONSISTENCY", p.name),
+		symbolic.Sub(
+			numerator,
+			symbolic.Mul(symbolic.Sub(p.Z, column.Shift(p.Z, -1)), denominator),
+		),
+	)
+
+	p.ZOpening = comp.InsertLocalOpening(randomnessRound, ifaces.QueryIDf("%v_Z_FINAL", p.name), column.Shift(p.Z, -1))
+	comp.PublicInputs = append(comp.PublicInputs, wizard.PublicInput{
+		Name: p.name,
+		Acc:  accessors.NewLocalOpeningAccessor(p.ZOpening, randomnessRound),
+	})
+}
+
+// assignZ assigns Z and its opening
+func (p *LookupPart) assignZ(run *wizard.ProverRuntime) {
+
+	var (
+		denominator = wizardutils.EvalExprColumn(run, p.denominator).IntoRegVecSaveAlloc()
+		z           = field.BatchInvert(denominator)
+	)
+
+	// The numerator is the constant 1 when the part is neither filtered nor
+	// including, and it cannot be evaluated as a column in that case.
+	if p.Filter != nil || p.including {
+		numerator := wizardutils.EvalExprColumn(run, p.numerator).IntoRegVecSaveAlloc()
+		for k := range z {
+			z[k].Mul(&z[k], &numerator[k])
+		}
+	}
+
+	for k := range z {
+		if k > 0 {
+			z[k].Add(&z[k], &z[k-1])
+		}
+	}
+
+	run.AssignColumn(p.Z.GetColID(), smartvectors.NewRegular(z))
+	run.AssignLocalPoint(p.ZOpening.ID, z[len(z)-1])
+}
+
+// multiplicities returns the multiplicities of the rows of the fragments of
+// the including table in the included table, as assigned in the runtime of
+// the original wizard. It panics if a row of the included table is missing
+// from the including table.
+//
+// As in the log-derivative lookup compiler, the tables are collapsed with a
+// randomness that is internal to the prover so that the counting is done on
+// single field elements.
+func (cl *CrossLookup) multiplicities(run *wizard.ProverRuntime) [][]field.Element {
+
+	var collapsingRandomness field.Element
+	if _, err := collapsingRandomness.SetRandom(); err != nil {
+		utils.Panic("could not sample the collapsing randomness: %v", err.Error())
+	}
+
+	var (
+		m        = make([][]field.Element, len(cl.Including))
+		position = map[field.Element][2]int{}
+		included = wizardutils.RandLinCombColAssignment(run, collapsingRandomness, cl.Included.Table)
+		filter   []field.Element
+		one      = field.One()
+	)
+
+	for frag, part := range cl.Including {
+		t := wizardutils.RandLinCombColAssignment(run, collapsingRandomness, part.Table)
+		m[frag] = make([]field.Element, t.Len())
+		for k := 0; k < t.Len(); k++ {
+			position[t.Get(k)] = [2]int{frag, k}
+		}
+	}
+
+	if cl.Included.Filter != nil {
+		filter = cl.Included.Filter.GetColAssignment(run).IntoRegVecSaveAlloc()
+	}
+
+	for k := 0; k < included.Len(); k++ {
+
+		if filter != nil && filter[k].IsZero() {
+			continue
+		}
+
+		if filter != nil && !filter[k].IsOne() {
+			utils.Panic(
+				"the filter column `%v` has a non-binary value at position `%v`: (%v)",
+				cl.Included.Filter.GetColID(), k, filter[k].String(),
+			)
+		}
+
+		pos, ok := position[included.Get(k)]
+		if !ok {
+			row := make([]field.Element, len(cl.Included.Table))
+			for j := range row {
+				row[j] = cl.Included.Table[j].GetColAssignmentAt(run, k)
+			}
+			utils.Panic(
+				"entry %v of the included table of %v is not included in the table. tableRow=%v",
+				k, cl.Query.ID, vector.Prettify(row),
+			)
+		}
+
+		m[pos[0]][pos[1]].Add(&m[pos[0]][pos[1]], &one)
+	}
+
+	return m
+}
+
+// check returns an error if the shares of the parts do not sum to zero
+func (cl *CrossLookup) check(shares func(part string, segment ModuleName) field.Element) error {
+
+	sum := shares(cl.Included.name, cl.Included.Segment)
+	for _, part := range cl.Including {
+		share := shares(part.name, part.Segment)
+		sum.Add(&sum, &share)
+	}
+
+	if !sum.IsZero() {
+		return fmt.Errorf("the log-derivative sums of the cross-segment lookup %v do not match", cl.Query.ID)
+	}
+
+	return nil
+}
+
+// collapse returns the linear combination of the columns with the powers of
+// alpha.
+func collapse(alpha ifaces.Accessor, cols []ifaces.Column) *symbolic.Expression {
+
+	if len(cols) == 1 {
+		return symbolic.NewVariable(cols[0])
+	}
+
+	exprs := make([]*symbolic.Expression, len(cols))
+	for i := range cols {
+		exprs[i] = symbolic.NewVariable(cols[i])
+	}
+
+	return symbolic.NewPolyEval(alpha.AsVariable(), exprs)
+}
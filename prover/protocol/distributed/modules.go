@@ -0,0 +1,244 @@
+package distributed
+
+import (
+	"slices"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/column/verifiercol"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// modulePartition stores the attribution of the columns of the original
+// wizard to the segments.
+type modulePartition struct {
+	comp *wizard.CompiledIOP
+	// segments maps every module to its segment
+	segments map[ModuleName]*Segment
+	// columnModule maps the columns assigned by the prover to their module
+	columnModule map[ifaces.ColID]ModuleName
+}
+
+// partition groups the modules of the columns of comp into segments. Two
+// modules are in the same segment if they are bound by a query that cannot
+// be split or by a side of an inclusion or a permutation query.
+func partition(comp *wizard.CompiledIOP, discover ModuleDiscoverer) *modulePartition {
+
+	var (
+		uf = unionFind{}
+		mp = &modulePartition{
+			comp:         comp,
+			segments:     map[ModuleName]*Segment{},
+			columnModule: map[ifaces.ColID]ModuleName{},
+		}
+	)
+
+	for _, name := range comp.Columns.AllKeysAt(0) {
+		if isPrecomputed(comp, name) {
+			continue
+		}
+
+		module := discover(name)
+		mp.columnModule[name] = module
+		uf.add(module)
+	}
+
+	bind := func(cols []ifaces.Column) {
+		modules := mp.modulesOf(cols)
+		for i := 1; i < len(modules); i++ {
+			uf.union(modules[0], modules[i])
+		}
+	}
+
+	for _, qName := range append(comp.QueriesNoParams.AllKeys(), comp.QueriesParams.AllKeys()...) {
+
+		var q ifaces.Query
+		if comp.QueriesNoParams.Exists(qName) {
+			q = comp.QueriesNoParams.Data(qName)
+		} else {
+			q = comp.QueriesParams.Data(qName)
+		}
+
+		switch q := q.(type) {
+		case query.Inclusion:
+			bind(appendIfNotNil(q.Included, q.IncludedFilter))
+			for frag := range q.Including {
+				if q.IsFilteredOnIncluding() {
+					bind(appendIfNotNil(q.Including[frag], q.IncludingFilter[frag]))
+				} else {
+					bind(q.Including[frag])
+				}
+			}
+		case query.Permutation:
+			for _, frag := range append(append([][]ifaces.Column{}, q.A...), q.B...) {
+				bind(frag)
+			}
+		default:
+			bind(queryColumns(q))
+		}
+	}
+
+	groups := map[ModuleName][]ModuleName{}
+	for module := range uf {
+		root := uf.find(module)
+		groups[root] = append(groups[root], module)
+	}
+
+	for _, modules := range groups {
+		slices.Sort(modules)
+		seg := &Segment{Name: modules[0], Modules: modules}
+		for _, module := range modules {
+			mp.segments[module] = seg
+		}
+	}
+
+	// The columns are attributed in the order of the original wizard, so that
+	// the segments declare them in the same order.
+	for _, name := range comp.Columns.AllKeysAt(0) {
+		if module, ok := mp.columnModule[name]; ok {
+			seg := mp.segments[module]
+			seg.Columns = append(seg.Columns, name)
+		}
+	}
+
+	return mp
+}
+
+// modulesOf returns the modules of the columns. The precomputed and the
+// constant columns have no module and are ignored.
+func (mp *modulePartition) modulesOf(cols []ifaces.Column) []ModuleName {
+
+	res := []ModuleName{}
+	for _, col := range cols {
+		nat, ok := rootColumn(col)
+		if !ok {
+			continue
+		}
+
+		if module, ok := mp.columnModule[nat.ID]; ok {
+			res = append(res, module)
+		}
+	}
+
+	return res
+}
+
+// segmentOf returns the segment of a set of columns bound by the query. It
+// panics if the columns only consist of precomputed columns.
+func (mp *modulePartition) segmentOf(qName ifaces.QueryID, cols []ifaces.Column) *Segment {
+
+	modules := mp.modulesOf(cols)
+	if len(modules) == 0 {
+		utils.Panic("the query %v only involves precomputed columns and cannot be attributed to a segment", qName)
+	}
+
+	return mp.segments[modules[0]]
+}
+
+// segmentsOfSides returns the segments of the sides of an inclusion or a
+// permutation query. A side only made of precomputed columns does not belong
+// to any segment as it can be copied anywhere: it is attributed to the
+// segment of the first side.
+func (mp *modulePartition) segmentsOfSides(qName ifaces.QueryID, sides [][]ifaces.Column) []*Segment {
+
+	var (
+		res   = make([]*Segment, len(sides))
+		first *Segment
+	)
+
+	for i := range sides {
+		if modules := mp.modulesOf(sides[i]); len(modules) > 0 {
+			res[i] = mp.segments[modules[0]]
+			if first == nil {
+				first = res[i]
+			}
+		}
+	}
+
+	if first == nil {
+		utils.Panic("the query %v only involves precomputed columns and cannot be attributed to a segment", qName)
+	}
+
+	for i := range res {
+		if res[i] == nil {
+			res[i] = first
+		}
+	}
+
+	return res
+}
+
+// rootColumn returns the natural column underlying col, if any
+func rootColumn(col ifaces.Column) (column.Natural, bool) {
+
+	switch c := col.(type) {
+	case column.Natural:
+		return c, true
+	case column.Shifted:
+		return rootColumn(c.Parent)
+	case verifiercol.ConstCol:
+		return column.Natural{}, false
+	}
+
+	utils.Panic("column %v of type %T cannot be distributed", col.GetColID(), col)
+	return column.Natural{}, false
+}
+
+// expressionColumns returns the columns of an expression. It panics if the
+// expression uses coins or accessors.
+func expressionColumns(expr *symbolic.Expression) []ifaces.Column {
+
+	var (
+		board    = expr.Board()
+		metadata = board.ListVariableMetadata()
+		res      = []ifaces.Column{}
+	)
+
+	for _, m := range metadata {
+		switch m := m.(type) {
+		case ifaces.Column:
+			res = append(res, m)
+		case ifaces.Accessor:
+			utils.Panic("the expression uses the accessor %v and cannot be distributed", m.Name())
+		}
+	}
+
+	return res
+}
+
+// isPrecomputed returns true if the column of comp is precomputed, and can
+// therefore be copied in all the segments.
+func isPrecomputed(comp *wizard.CompiledIOP, name ifaces.ColID) bool {
+	status := comp.Columns.Status(name)
+	return status == column.Precomputed || status == column.VerifyingKey
+}
+
+// unionFind is a minimal union-find over the modules
+type unionFind map[ModuleName]ModuleName
+
+// add registers a module, if it is not already
+func (uf unionFind) add(m ModuleName) {
+	if _, ok := uf[m]; !ok {
+		uf[m] = m
+	}
+}
+
+// find returns the representative of the module
+func (uf unionFind) find(m ModuleName) ModuleName {
+	for uf[m] != m {
+		uf[m] = uf[uf[m]]
+		m = uf[m]
+	}
+	return m
+}
+
+// union merges the groups of two modules
+func (uf unionFind) union(a, b ModuleName) {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra != rb {
+		uf[rb] = ra
+	}
+}
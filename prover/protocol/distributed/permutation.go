@@ -0,0 +1,155 @@
+package distributed
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizardutils"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+)
+
+// CrossPermutation is a permutation query of the original wizard whose
+// fragments lie in different segments. It is enforced with the grand
+// products
+//
+//	\prod_{i \in A} (gamma + A~[i]) = \prod_{j \in B} (gamma + B~[j])
+//
+// where A~ and B~ are the fragments collapsed into a single column using the
+// powers of alpha. Each fragment is a [PermutationPart] computing its share
+// of the products in its segment.
+type CrossPermutation struct {
+	// Query is the permutation query of the original wizard
+	Query query.Permutation
+	// A and B are the shares of the fragments of the two sides
+	A, B []*PermutationPart
+}
+
+// PermutationPart is the share of a fragment of a [CrossPermutation] in a
+// segment.
+type PermutationPart struct {
+	// Segment is the name of the segment of the part
+	Segment ModuleName
+	// Fragment is the fragment, in the original wizard
+	Fragment []ifaces.Column
+	// Z accumulates the factors of the product of the part and ZOpening opens
+	// its last value, which is exposed as a public input of the segment.
+	Z        ifaces.Column
+	ZOpening query.LocalOpening
+
+	name   string
+	factor symbolic.ExpressionBoard
+}
+
+// newCrossPermutation constructs the [CrossPermutation] of a permutation
+// query given the segments of its fragments, those of A first, and registers
+// the parts in their segments.
+func newCrossPermutation(q query.Permutation, segs []*Segment) *CrossPermutation {
+
+	cp := &CrossPermutation{Query: q}
+
+	for k, frag := range append(append([][]ifaces.Column{}, q.A...), q.B...) {
+
+		var (
+			seg  = segs[k]
+			side = "A"
+			num  = k
+		)
+
+		if k >= len(q.A) {
+			side, num = "B", k-len(q.A)
+		}
+
+		part := &PermutationPart{
+			Segment:  seg.Name,
+			Fragment: frag,
+			name:     wizardutils.DeriveName[string]("DISTRIBUTED_PERMUTATION", q.ID, side, num),
+		}
+
+		if side == "A" {
+			cp.A = append(cp.A, part)
+		} else {
+			cp.B = append(cp.B, part)
+		}
+
+		seg.PermutationParts = append(seg.PermutationParts, part)
+	}
+
+	return cp
+}
+
+// PublicInput returns the name of the public input of the segment exposing
+// the share of the part.
+func (p *PermutationPart) PublicInput() string {
+	return p.name
+}
+
+// define declares the columns and the constraints of the part in the wizard
+// of its segment.
+func (p *PermutationPart) define(comp *wizard.CompiledIOP, t *translator, r *sharedRandomness) {
+
+	var (
+		frag   = t.columns(p.Fragment)
+		factor = symbolic.Add(r.Gamma, collapse(r.Alpha, frag))
+	)
+
+	p.factor = factor.Board()
+	p.Z = comp.InsertCommit(randomnessRound, ifaces.ColIDf("%v_Z", p.name), frag[0].Size())
+
+	// Z[0] = factor[0]
+	comp.InsertLocal(
+		randomnessRound,
+		ifaces.QueryIDf("%v_Z_START", p.name),
+		symbolic.Sub(p.Z, factor),
+	)
+
+	// Z[i] = Z[i-1] * factor[i]
+	comp.InsertGlobal(
+		randomnessRound,
+		ifaces.QueryIDf("%v_Z_CONSISTENCY", p.name),
+		symbolic.Sub(p.Z, symbolic.Mul(column.Shift(p.Z, -1), factor)),
+	)
+
+	p.ZOpening = comp.InsertLocalOpening(randomnessRound, ifaces.QueryIDf("%v_Z_FINAL", p.name), column.Shift(p.Z, -1))
+	comp.PublicInputs = append(comp.PublicInputs, wizard.PublicInput{
+		Name: p.name,
+		Acc:  accessors.NewLocalOpeningAccessor(p.ZOpening, randomnessRound),
+	})
+}
+
+// assignZ assigns Z and its opening
+func (p *PermutationPart) assignZ(run *wizard.ProverRuntime) {
+
+	z := wizardutils.EvalExprColumn(run, p.factor).IntoRegVecSaveAlloc()
+	for k := 1; k < len(z); k++ {
+		z[k].Mul(&z[k], &z[k-1])
+	}
+
+	run.AssignColumn(p.Z.GetColID(), smartvectors.NewRegular(z))
+	run.AssignLocalPoint(p.ZOpening.ID, z[len(z)-1])
+}
+
+// check returns an error if the products of the shares of the two sides
+// differ.
+func (cp *CrossPermutation) check(shares func(part string, segment ModuleName) field.Element) error {
+
+	prod := func(parts []*PermutationPart) field.Element {
+		res := field.One()
+		for _, part := range parts {
+			share := shares(part.name, part.Segment)
+			res.Mul(&res, &share)
+		}
+		return res
+	}
+
+	if a, b := prod(cp.A), prod(cp.B); a != b {
+		return fmt.Errorf("the grand products of the cross-segment permutation %v do not match", cp.Query.ID)
+	}
+
+	return nil
+}
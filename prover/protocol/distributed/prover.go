@@ -0,0 +1,186 @@
+package distributed
+
+import (
+	"sync"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// SegmentAssignment assigns the listed columns of the original wizard. The
+// other columns may be left unassigned; if they are assigned, the prover of
+// the segment ignores them.
+type SegmentAssignment func(run *wizard.ProverRuntime, columns []ifaces.ColID)
+
+// ProveSegment runs the prover of a segment. The assignment is only asked for
+// the columns returned by [Segment.RequiredColumns]. The function blocks until
+// the digests of all the segments have been exchanged through the beacon. To
+// prove the segments on several machines, the caller has to provide a beacon
+// relaying the digests between them. The package only implements the
+// in-process [LocalBeacon].
+func (dw *DistributedWizard) ProveSegment(name ModuleName, assign SegmentAssignment, beacon Beacon) wizard.Proof {
+
+	seg := dw.Segment(name)
+	if seg == nil {
+		utils.Panic("unknown segment %v, the segments are %v", name, dw.SegmentNames())
+	}
+
+	var (
+		columns = seg.RequiredColumns(dw)
+		orig    = wizard.RunProver(dw.Original, func(run *wizard.ProverRuntime) { assign(run, columns) })
+	)
+
+	return seg.prove(dw, orig, beacon)
+}
+
+// Prove runs the provers of all the segments in the current process and
+// returns their proofs by segment name. The original wizard is only assigned
+// once.
+func (dw *DistributedWizard) Prove(prover wizard.ProverStep) map[ModuleName]wizard.Proof {
+
+	var (
+		orig   = wizard.RunProver(dw.Original, prover)
+		beacon = NewLocalBeacon(len(dw.Segments))
+		proofs = make([]wizard.Proof, len(dw.Segments))
+		wg     = &sync.WaitGroup{}
+	)
+
+	wg.Add(len(dw.Segments))
+	for i := range dw.Segments {
+		go func(i int) {
+			defer wg.Done()
+			proofs[i] = dw.Segments[i].prove(dw, orig, beacon)
+		}(i)
+	}
+	wg.Wait()
+
+	res := make(map[ModuleName]wizard.Proof, len(dw.Segments))
+	for i := range dw.Segments {
+		res[dw.Segments[i].Name] = proofs[i]
+	}
+
+	return res
+}
+
+// prove runs the prover of the segment given the assigned runtime of the
+// original wizard
+func (seg *Segment) prove(dw *DistributedWizard, orig *wizard.ProverRuntime, beacon Beacon) wizard.Proof {
+	return wizard.Prove(seg.Comp, func(run *wizard.ProverRuntime) {
+
+		run.State.InsertNew(beaconStateKey, beacon)
+
+		for _, name := range seg.Columns {
+			run.AssignColumn(name, orig.GetColumn(name))
+		}
+
+		// The local openings are evaluated from the columns rather than read
+		// from the runtime of the original wizard as the assignment of a
+		// segment only assigns columns.
+		for _, qName := range seg.Queries {
+			if !orig.Spec.QueriesParams.Exists(qName) {
+				continue
+			}
+			if lo, ok := orig.Spec.QueriesParams.Data(qName).(query.LocalOpening); ok {
+				run.AssignLocalPoint(qName, lo.Pol.GetColAssignmentAt(orig, 0))
+			}
+		}
+
+		for _, cl := range dw.Lookups {
+			var m [][]field.Element
+			for frag, part := range cl.Including {
+				if part.Segment != seg.Name {
+					continue
+				}
+				if m == nil {
+					m = cl.multiplicities(orig)
+				}
+				run.AssignColumn(part.M.GetColID(), smartvectors.NewRegular(m[frag]))
+			}
+		}
+	})
+}
+
+// RequiredColumns returns the columns of the original wizard that the prover
+// of the segment reads: the columns of the segment and, for the cross-segment
+// lookups where the segment holds a fragment of the including side, the
+// columns of the included side, which are needed to count the multiplicities.
+// The precomputed columns are left out as they are not assigned by the
+// prover.
+func (seg *Segment) RequiredColumns(dw *DistributedWizard) []ifaces.ColID {
+
+	var (
+		res  = append([]ifaces.ColID{}, seg.Columns...)
+		seen = make(map[ifaces.ColID]struct{}, len(seg.Columns))
+	)
+
+	for _, name := range seg.Columns {
+		seen[name] = struct{}{}
+	}
+
+	addRoots := func(cols []ifaces.Column) {
+		for _, col := range cols {
+			for _, root := range column.RootParents(col) {
+				name := root.GetColID()
+				if _, ok := seen[name]; ok || !dw.Original.Columns.Exists(name) {
+					continue
+				}
+				if status := dw.Original.Columns.Status(name); status != column.Committed && status != column.Proof {
+					continue
+				}
+				seen[name] = struct{}{}
+				res = append(res, name)
+			}
+		}
+	}
+
+	for _, cl := range dw.Lookups {
+		for _, part := range cl.Including {
+			if part.Segment != seg.Name {
+				continue
+			}
+			addRoots(part.Table)
+			addRoots(cl.Included.Table)
+			if cl.Included.Filter != nil {
+				addRoots([]ifaces.Column{cl.Included.Filter})
+			}
+			break
+		}
+	}
+
+	return res
+}
+
+// partsAssignment implements [wizard.ProverAction] and assigns the shares of
+// a segment in the cross-segment queries once the shared randomness is
+// available.
+type partsAssignment struct {
+	Segment *Segment
+}
+
+// Run implements [wizard.ProverAction]
+func (a partsAssignment) Run(run *wizard.ProverRuntime) {
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(a.Segment.LookupParts) + len(a.Segment.PermutationParts))
+
+	for _, part := range a.Segment.LookupParts {
+		go func(part *LookupPart) {
+			defer wg.Done()
+			part.assignZ(run)
+		}(part)
+	}
+
+	for _, part := range a.Segment.PermutationParts {
+		go func(part *PermutationPart) {
+			defer wg.Done()
+			part.assignZ(run)
+		}(part)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,192 @@
+package distributed
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/accessors"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/column/verifiercol"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/variables"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// define declares the segment in comp: the columns and the queries of the
+// original wizard that are attributed to the segment, the shared randomness
+// and the shares of the segment in the cross-segment queries.
+func (seg *Segment) define(comp, orig *wizard.CompiledIOP) {
+
+	t := &translator{orig: orig, comp: comp}
+
+	for _, name := range seg.Columns {
+		size := orig.Columns.GetSize(name)
+		switch orig.Columns.Status(name) {
+		case column.Committed:
+			comp.InsertCommit(0, name, size)
+		case column.Proof:
+			comp.InsertProof(0, name, size)
+		}
+	}
+
+	for _, qName := range seg.Queries {
+		t.insertQuery(qName)
+	}
+
+	for _, pub := range orig.PublicInputs {
+		lo, _ := localOpeningOfPublicInput(pub)
+		if comp.QueriesParams.Exists(lo.ID) {
+			comp.PublicInputs = append(comp.PublicInputs, wizard.PublicInput{
+				Name: pub.Name,
+				Acc:  accessors.NewLocalOpeningAccessor(comp.QueriesParams.Data(lo.ID).(query.LocalOpening), 0),
+			})
+		}
+	}
+
+	seg.randomness.define(comp)
+
+	for _, part := range seg.LookupParts {
+		part.define(comp, t, seg.randomness)
+	}
+
+	for _, part := range seg.PermutationParts {
+		part.define(comp, t, seg.randomness)
+	}
+
+	if len(seg.LookupParts)+len(seg.PermutationParts) > 0 {
+		comp.RegisterProverAction(randomnessRound, partsAssignment{Segment: seg})
+	}
+}
+
+// translator rebuilds the handles of the original wizard in the wizard of a
+// segment. The precomputed columns are imported in the segment the first time
+// they are encountered.
+type translator struct {
+	orig, comp *wizard.CompiledIOP
+}
+
+// column returns the handle of the segment corresponding to col. It returns
+// nil if col is nil.
+func (t *translator) column(col ifaces.Column) ifaces.Column {
+
+	switch c := col.(type) {
+	case nil:
+		return nil
+	case column.Natural:
+		if !t.comp.Columns.Exists(c.ID) {
+			t.importPrecomputed(c.ID)
+		}
+		return t.comp.Columns.GetHandle(c.ID)
+	case column.Shifted:
+		return column.Shift(t.column(c.Parent), c.Offset)
+	case verifiercol.ConstCol:
+		return c
+	}
+
+	utils.Panic("column %v of type %T cannot be distributed", col.GetColID(), col)
+	return nil
+}
+
+// columns returns the handles of the segment corresponding to cols
+func (t *translator) columns(cols []ifaces.Column) []ifaces.Column {
+	res := make([]ifaces.Column, len(cols))
+	for i := range cols {
+		res[i] = t.column(cols[i])
+	}
+	return res
+}
+
+// fragments returns the handles of the segment corresponding to the fragments
+// of a table
+func (t *translator) fragments(frags [][]ifaces.Column) [][]ifaces.Column {
+	res := make([][]ifaces.Column, len(frags))
+	for i := range frags {
+		res[i] = t.columns(frags[i])
+	}
+	return res
+}
+
+// importPrecomputed copies a precomputed column of the original wizard in the
+// segment. It panics if the column is not precomputed as the other columns
+// must have been declared by the segment they belong to.
+func (t *translator) importPrecomputed(name ifaces.ColID) {
+
+	switch t.orig.Columns.Status(name) {
+	case column.Precomputed:
+		t.comp.InsertPrecomputed(name, t.orig.Precomputed.MustGet(name))
+	case column.VerifyingKey:
+		t.comp.RegisterVerifyingKey(name, t.orig.Precomputed.MustGet(name))
+	default:
+		utils.Panic("the column %v is used by the segment but belongs to another segment", name)
+	}
+}
+
+// expression rebuilds an expression of the original wizard with the handles
+// of the segment.
+func (t *translator) expression(expr *symbolic.Expression) *symbolic.Expression {
+
+	if v, ok := expr.Operator.(symbolic.Variable); ok {
+		switch m := v.Metadata.(type) {
+		case ifaces.Column:
+			return symbolic.NewVariable(t.column(m))
+		case variables.X, variables.PeriodicSample:
+			return expr
+		}
+		utils.Panic("the variable %v of type %T cannot be distributed", v.Metadata.String(), v.Metadata)
+	}
+
+	children := make([]*symbolic.Expression, len(expr.Children))
+	for i := range expr.Children {
+		children[i] = t.expression(expr.Children[i])
+	}
+
+	return expr.SameWithNewChildren(children)
+}
+
+// insertQuery declares a query of the original wizard in the segment
+func (t *translator) insertQuery(qName ifaces.QueryID) {
+
+	if t.orig.QueriesParams.Exists(qName) {
+		q := t.orig.QueriesParams.Data(qName).(query.LocalOpening)
+		t.comp.InsertLocalOpening(0, q.ID, t.column(q.Pol))
+		return
+	}
+
+	switch q := t.orig.QueriesNoParams.Data(qName).(type) {
+	case query.GlobalConstraint:
+		t.comp.InsertGlobal(0, q.ID, t.expression(q.Expression), q.NoBoundCancel)
+	case query.LocalConstraint:
+		t.comp.InsertLocal(0, q.ID, t.expression(q.Expression))
+	case query.Range:
+		t.comp.InsertRange(0, q.ID, t.column(q.Handle), q.B)
+	case query.MiMC:
+		t.comp.InsertMiMC(0, q.ID, t.column(q.Blocks), t.column(q.OldState), t.column(q.NewState))
+	case query.Poseidon2:
+		t.comp.InsertPoseidon2(0, q.ID, t.column(q.Blocks), t.column(q.OldState), t.column(q.NewState))
+	case query.FixedPermutation:
+		t.comp.InsertFixedPermutation(0, q.ID, q.S, t.columns(q.A), t.columns(q.B))
+	case query.Inclusion:
+		var includingFilter []ifaces.Column
+		if q.IsFilteredOnIncluding() {
+			includingFilter = t.columns(q.IncludingFilter)
+		}
+		t.comp.GenericFragmentedConditionalInclusion(
+			0, q.ID,
+			t.fragments(q.Including), t.columns(q.Included),
+			includingFilter, t.column(q.IncludedFilter),
+		)
+	case query.Permutation:
+		t.comp.InsertFragmentedPermutation(0, q.ID, t.fragments(q.A), t.fragments(q.B))
+	default:
+		utils.Panic("query %v of type %T cannot be distributed", qName, q)
+	}
+}
+
+// localOpeningOfPublicInput returns the local opening query of a public input
+func localOpeningOfPublicInput(pub wizard.PublicInput) (query.LocalOpening, bool) {
+	acc, ok := pub.Acc.(*accessors.FromLocalOpeningYAccessor)
+	if !ok {
+		return query.LocalOpening{}, false
+	}
+	return acc.Q, true
+}
@@ -0,0 +1,72 @@
+package distributed
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Verify verifies the proofs of all the segments, given by segment name. On
+// top of verifying every segment proof, it checks that all the segments used
+// the randomness derived from their digests and that the shares of the
+// cross-segment queries match.
+func (dw *DistributedWizard) Verify(proofs map[ModuleName]wizard.Proof) error {
+
+	var (
+		runtimes = make(map[ModuleName]*wizard.VerifierRuntime, len(dw.Segments))
+		digests  = make(map[ModuleName]field.Element, len(dw.Segments))
+		errs     = []error{}
+	)
+
+	for _, seg := range dw.Segments {
+
+		proof, ok := proofs[seg.Name]
+		if !ok {
+			return fmt.Errorf("missing the proof of the segment %v", seg.Name)
+		}
+
+		run, err := wizard.VerifyWithRuntime(seg.Comp, proof)
+		if err != nil {
+			return fmt.Errorf("segment %v: %w", seg.Name, err)
+		}
+
+		runtimes[seg.Name] = run
+		digests[seg.Name] = run.GetPublicInput(DigestPublicInput)
+	}
+
+	alpha, gamma, err := deriveRandomness(dw.SegmentNames(), digests)
+	if err != nil {
+		return err
+	}
+
+	for _, seg := range dw.Segments {
+		run := runtimes[seg.Name]
+		if run.GetPublicInput(AlphaPublicInput) != alpha || run.GetPublicInput(GammaPublicInput) != gamma {
+			errs = append(errs, fmt.Errorf("the segment %v does not use the shared randomness", seg.Name))
+		}
+	}
+
+	shares := func(part string, segment ModuleName) field.Element {
+		return runtimes[segment].GetPublicInput(part)
+	}
+
+	for _, cl := range dw.Lookups {
+		if err := cl.check(shares); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, cp := range dw.Permutations {
+		if err := cp.check(shares); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return utils.WrapErrsAlphabetically(errs)
+	}
+
+	return nil
+}
@@ -110,11 +110,6 @@ func (cs GlobalConstraint) Check(run ifaces.Runtime) error {
 		}
 	}
 
-	/*
-		Collects the relevant datas into a slice for the evaluation
-	*/
-	evalInputs := make([]sv.SmartVector, len(metadatas))
-
 	/*
 		Omega is a root of unity which generates the domain of evaluation
 		of the constraint. Its size coincide with the size of the domain
@@ -123,47 +118,15 @@ func (cs GlobalConstraint) Check(run ifaces.Runtime) error {
 	omega := fft.GetOmega(cs.DomainSize)
 	omegaI := field.One()
 
-	// precomputations of the powers of omega, can be optimized if useful
-	omegas := make([]field.Element, cs.DomainSize)
-	for i := 0; i < cs.DomainSize; i++ {
-		omegas[i] = omegaI
-		omegaI.Mul(&omegaI, &omega)
-	}
-
 	/*
 		Collect the relevants inputs for evaluating the constraint
 	*/
-	for k, metadataInterface := range metadatas {
-		switch meta := metadataInterface.(type) {
-		case ifaces.Column:
-			w := meta.GetColAssignment(run)
-			evalInputs[k] = w
-		case coin.Info:
-			evalInputs[k] = sv.NewConstant(run.GetRandomCoinField(meta.Name), cs.DomainSize)
-		case variables.X:
-			evalInputs[k] = meta.EvalCoset(cs.DomainSize, 0, 1, false)
-		case variables.PeriodicSample:
-			evalInputs[k] = meta.EvalCoset(cs.DomainSize, 0, 1, false)
-		case ifaces.Accessor:
-			evalInputs[k] = sv.NewConstant(meta.GetVal(run), cs.DomainSize)
-		default:
-			utils.Panic("Not a variable type %v in query %v", reflect.TypeOf(metadataInterface), cs.ID)
-		}
-	}
+	evalInputs := cs.evalInputs(run, metadatas)
 
 	// This panics if the global constraints doesn't use any commitment
 	res := boarded.Evaluate(evalInputs)
 
-	offsetRange := cs.MinMaxOffset()
-
-	start, stop := 0, res.Len()
-	if !cs.NoBoundCancel {
-		start -= offsetRange.Min
-		stop -= offsetRange.Max
-	}
-
-	start = max(start, 0)
-	stop = min(stop, cs.DomainSize)
+	start, stop := cs.checkedRange(res.Len())
 
 	for i := start; i < stop; i++ {
 
@@ -198,6 +161,48 @@ func (cs GlobalConstraint) Check(run ifaces.Runtime) error {
 	return nil
 }
 
+// evalInputs returns the assignments of the variables of the expression, in
+// the order of metadatas, over the domain of the constraint.
+func (cs GlobalConstraint) evalInputs(run ifaces.Runtime, metadatas []symbolic.Metadata) []sv.SmartVector {
+
+	evalInputs := make([]sv.SmartVector, len(metadatas))
+
+	for k, metadataInterface := range metadatas {
+		switch meta := metadataInterface.(type) {
+		case ifaces.Column:
+			w := meta.GetColAssignment(run)
+			evalInputs[k] = w
+		case coin.Info:
+			evalInputs[k] = sv.NewConstant(run.GetRandomCoinField(meta.Name), cs.DomainSize)
+		case variables.X:
+			evalInputs[k] = meta.EvalCoset(cs.DomainSize, 0, 1, false)
+		case variables.PeriodicSample:
+			evalInputs[k] = meta.EvalCoset(cs.DomainSize, 0, 1, false)
+		case ifaces.Accessor:
+			evalInputs[k] = sv.NewConstant(meta.GetVal(run), cs.DomainSize)
+		default:
+			utils.Panic("Not a variable type %v in query %v", reflect.TypeOf(metadataInterface), cs.ID)
+		}
+	}
+
+	return evalInputs
+}
+
+// checkedRange returns the range of rows [start, stop) on which the
+// constraint is enforced, given the length of the evaluated expression.
+func (cs GlobalConstraint) checkedRange(n int) (start, stop int) {
+
+	offsetRange := cs.MinMaxOffset()
+
+	start, stop = 0, n
+	if !cs.NoBoundCancel {
+		start -= offsetRange.Min
+		stop -= offsetRange.Max
+	}
+
+	return max(start, 0), min(stop, cs.DomainSize)
+}
+
 // validatedDomainSize scans the expression of the global constraints and more
 // specifically its inputs and looks for the followings:
 //   - the expression must use at least one [ifaces.Column] as input variable
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+var _ ifaces.Query = Poseidon2{}
+
+/*
+A Poseidon2 query over a set of 3 columns (block, oldState, newState) enforces
+that newState is the result of applying the Poseidon2 compression function to
+block and oldState. It is the analogue of the [MiMC] query and the two can be
+swapped.
+
+We use the Poseidon2 permutation as specified in the following paper.
+https://eprint.iacr.org/2023/323.pdf
+
+And the compression function is described in [poseidon2.BlockCompression].
+*/
+type Poseidon2 struct {
+	// The columns on which the query applies
+	Blocks, OldState, NewState ifaces.Column
+	// The name of the query
+	ID ifaces.QueryID
+}
+
+// Name implements the [ifaces.Query] interface
+func (p Poseidon2) Name() ifaces.QueryID {
+	return p.ID
+}
+
+/*
+Constructs a new Poseidon2 query
+*/
+func NewPoseidon2(id ifaces.QueryID, block, oldState, newState ifaces.Column) Poseidon2 {
+
+	/*
+		Sanity-check : the querie's ifaces.QueryID cannot be empty or nil
+	*/
+	if len(id) <= 0 {
+		utils.Panic("Given an empty ifaces.QueryID for poseidon2 query")
+	}
+
+	/*
+		Sanity-check : All columns must have the same length
+	*/
+	if block.Size() != oldState.Size() || block.Size() != newState.Size() {
+		utils.Panic("block, oldState and newState must have the same length %v %v %v", block.Size(), oldState.Size(), newState.Size())
+	}
+
+	return Poseidon2{
+		OldState: oldState,
+		NewState: newState,
+		Blocks:   block,
+		ID:       id,
+	}
+}
+
+/*
+The verifier checks that the compression function was applied correctly
+*/
+func (p Poseidon2) Check(run ifaces.Runtime) error {
+
+	blocks := p.Blocks.GetColAssignment(run)
+	oldStates := p.OldState.GetColAssignment(run)
+	newStates := p.NewState.GetColAssignment(run)
+
+	for i := 0; i < newStates.Len(); i++ {
+
+		block := blocks.Get(i)
+		oldState := oldStates.Get(i)
+		newState := newStates.Get(i)
+
+		recomputed := poseidon2.BlockCompression(oldState, block)
+		if recomputed != newState {
+			return fmt.Errorf(
+				"Poseidon2 compression check failed for row #%v : block %v, oldState %v, newState %v",
+				i, block.String(), oldState.String(), newState.String(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// Check the poseidon2 relation in a gnark circuit
+func (p Poseidon2) CheckGnark(api frontend.API, run ifaces.GnarkRuntime) {
+
+	blocks := p.Blocks.GetColAssignmentGnark(run)
+	oldStates := p.OldState.GetColAssignmentGnark(run)
+	newStates := p.NewState.GetColAssignmentGnark(run)
+
+	for i := 0; i < len(newStates); i++ {
+		recomputed := poseidon2.GnarkBlockCompression(api, oldStates[i], blocks[i])
+		api.AssertIsEqual(newStates[i], recomputed)
+	}
+}
@@ -0,0 +1,391 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	sv "github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/variables"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// ResultColumn is the name of the column holding the value of the expression
+// in the violations of global and local constraints.
+const ResultColumn = "RESULT"
+
+// ViolationLocator is implemented by the queries able to tell on which rows of
+// an assignment they are not satisfied. This is meant for debugging traces,
+// where knowing that a query fails is not enough to fix the trace.
+type ViolationLocator interface {
+	ifaces.Query
+	// Violation returns nil if the query is satisfied. Otherwise, it lists at
+	// most maxRows offending rows per table, or all of them if maxRows <= 0.
+	Violation(run ifaces.Runtime, maxRows int) *Violation
+}
+
+// Violation details why a query is not satisfied by an assignment
+type Violation struct {
+	Query ifaces.QueryID `json:"query"`
+	// Type is the type of the query, e.g. GlobalConstraint
+	Type string `json:"type"`
+	// NbRows is the total number of offending rows, including the ones
+	// dropped from the tables.
+	NbRows int `json:"nbRows"`
+	// Tables holds the offending rows. Queries spanning several tables, such
+	// as permutations, report one table per side.
+	Tables []ViolationTable `json:"tables,omitempty"`
+	// Err is the error returned by the Check method of the queries that are
+	// not ViolationLocator.
+	Err string `json:"error,omitempty"`
+}
+
+// ViolationTable lists offending rows and the values of the relevant columns
+// on these rows.
+type ViolationTable struct {
+	// Name is empty when the query reports a single table
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	// Rows are the indices of the offending rows in the assignment
+	Rows []int `json:"rows"`
+	// Values[i][j] is the value of Columns[j] on Rows[i]
+	Values [][]field.Element `json:"values"`
+}
+
+// addRow appends a row to the table unless it already holds maxRows rows
+func (t *ViolationTable) addRow(maxRows, row int, values []field.Element) {
+	if maxRows > 0 && len(t.Rows) >= maxRows {
+		return
+	}
+	t.Rows = append(t.Rows, row)
+	t.Values = append(t.Values, values)
+}
+
+// ViolationOf returns the violation of q, given the error returned by its
+// Check method. Queries that are not a ViolationLocator are reported with the
+// error only.
+func ViolationOf(q ifaces.Query, run ifaces.Runtime, checkErr error, maxRows int) Violation {
+
+	if l, ok := q.(ViolationLocator); ok {
+		if v := l.Violation(run, maxRows); v != nil {
+			return *v
+		}
+		// Randomized checks may fail where the exact search does not
+	}
+
+	v := Violation{Query: q.Name(), Type: queryType(q)}
+	if checkErr != nil {
+		v.Err = checkErr.Error()
+	}
+	return v
+}
+
+// String summarizes the violation, showing the first few offending rows of
+// every table.
+func (v Violation) String() string {
+
+	const maxShownRows = 4
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v %v", v.Type, v.Query)
+
+	if len(v.Tables) == 0 {
+		fmt.Fprintf(&sb, ": %v", v.Err)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, " fails on %v row(s)", v.NbRows)
+	for _, t := range v.Tables {
+		if len(t.Name) > 0 {
+			fmt.Fprintf(&sb, "\n\t%v:", t.Name)
+		}
+		for i := 0; i < len(t.Rows) && i < maxShownRows; i++ {
+			fmt.Fprintf(&sb, "\n\trow %v:", t.Rows[i])
+			for j := range t.Columns {
+				fmt.Fprintf(&sb, " %v=%v", t.Columns[j], t.Values[i][j].String())
+			}
+		}
+		if len(t.Rows) > maxShownRows {
+			fmt.Fprintf(&sb, "\n\t... and %v more", len(t.Rows)-maxShownRows)
+		}
+	}
+
+	return sb.String()
+}
+
+// Violation implements the [ViolationLocator] interface. The table lists the
+// values of every variable of the expression, shifted columns included, and of
+// the expression itself on the offending rows.
+func (cs GlobalConstraint) Violation(run ifaces.Runtime, maxRows int) *Violation {
+
+	var (
+		boarded    = cs.Board()
+		metadatas  = boarded.ListVariableMetadata()
+		evalInputs = cs.evalInputs(run, metadatas)
+		res        = boarded.Evaluate(evalInputs)
+		start, end = cs.checkedRange(res.Len())
+		v          = &Violation{Query: cs.ID, Type: queryType(cs)}
+		table      = ViolationTable{Columns: append(variableNames(metadatas), ResultColumn)}
+	)
+
+	for i := start; i < end; i++ {
+		resx := res.Get(i)
+		if resx.IsZero() {
+			continue
+		}
+		v.NbRows++
+		table.addRow(maxRows, i, append(rowValues(evalInputs, i), resx))
+	}
+
+	if v.NbRows == 0 {
+		return nil
+	}
+
+	v.Tables = []ViolationTable{table}
+	return v
+}
+
+// Violation implements the [ViolationLocator] interface. The constraint being
+// evaluated at the first row only, the offending row is always 0.
+func (cs LocalConstraint) Violation(run ifaces.Runtime, maxRows int) *Violation {
+
+	var (
+		board     = cs.Board()
+		metadatas = board.ListVariableMetadata()
+		inputs    = make([]sv.SmartVector, len(metadatas))
+	)
+
+	for i, metadataInterface := range metadatas {
+		switch metadata := metadataInterface.(type) {
+		case ifaces.Column:
+			inputs[i] = sv.NewConstant(metadata.GetColAssignmentAt(run, 0), 1)
+		case coin.Info:
+			inputs[i] = sv.NewConstant(run.GetRandomCoinField(metadata.Name), 1)
+		case variables.PeriodicSample:
+			inputs[i] = sv.NewConstant(metadata.EvalAtOnDomain(0), 1)
+		case ifaces.Accessor:
+			inputs[i] = sv.NewConstant(metadata.GetVal(run), 1)
+		default:
+			utils.Panic("Unsupported variable type %v in local constraint %v", reflect.TypeOf(metadataInterface), cs.ID)
+		}
+	}
+
+	res := board.Evaluate(inputs).Get(0)
+	if res.IsZero() {
+		return nil
+	}
+
+	table := ViolationTable{Columns: append(variableNames(metadatas), ResultColumn)}
+	table.addRow(maxRows, 0, append(rowValues(inputs, 0), res))
+
+	return &Violation{Query: cs.ID, Type: queryType(cs), NbRows: 1, Tables: []ViolationTable{table}}
+}
+
+// Violation implements the [ViolationLocator] interface. The table lists the
+// rows of the included table, filter excepted, whose tuple is missing from the
+// including table. Unlike [Inclusion.Check], the tuples are compared exactly.
+func (r Inclusion) Violation(run ifaces.Runtime, maxRows int) *Violation {
+
+	inclusionSet := make(map[string]struct{})
+	for frag := range r.Including {
+
+		var (
+			including = assignments(run, r.Including[frag])
+			filter    sv.SmartVector
+		)
+
+		if r.IsFilteredOnIncluding() {
+			filter = r.IncludingFilter[frag].GetColAssignment(run)
+		}
+
+		for row := 0; row < r.Including[frag][0].Size(); row++ {
+			if filter == nil || filter.Get(row) == field.One() {
+				inclusionSet[tupleKey(rowValues(including, row))] = struct{}{}
+			}
+		}
+	}
+
+	var (
+		included = assignments(run, r.Included)
+		filter   sv.SmartVector
+		v        = &Violation{Query: r.ID, Type: queryType(r)}
+		table    = ViolationTable{Columns: columnNames(r.Included)}
+	)
+
+	if r.IsFilteredOnIncluded() {
+		filter = r.IncludedFilter.GetColAssignment(run)
+	}
+
+	for row := 0; row < r.Included[0].Size(); row++ {
+		if filter != nil && filter.Get(row) == field.Zero() {
+			continue
+		}
+		tuple := rowValues(included, row)
+		if _, ok := inclusionSet[tupleKey(tuple)]; !ok {
+			v.NbRows++
+			table.addRow(maxRows, row, tuple)
+		}
+	}
+
+	if v.NbRows == 0 {
+		return nil
+	}
+
+	v.Tables = []ViolationTable{table}
+	return v
+}
+
+// Violation implements the [ViolationLocator] interface. The tables list the
+// rows of each side whose tuple is not matched by a row of the other side, one
+// table per fragment with offending rows. They are named after the side, A or
+// B, and the index of the fragment when the permutation is fractioned.
+func (r Permutation) Violation(run ifaces.Runtime, maxRows int) *Violation {
+
+	type position struct{ frag, row int }
+
+	var (
+		sides     = [2][][]ifaces.Column{r.A, r.B}
+		unmatched = [2][]position{}
+		// positions of the rows of A not matched so far, by tuple
+		pending = make(map[string][]position)
+		tuples  = [2][][][]field.Element{}
+	)
+
+	for k, aOrB := range sides {
+		tuples[k] = make([][][]field.Element, len(aOrB))
+		for frag := range aOrB {
+			tab := assignments(run, aOrB[frag])
+			tuples[k][frag] = make([][]field.Element, aOrB[frag][0].Size())
+			for row := range tuples[k][frag] {
+				tuple := rowValues(tab, row)
+				tuples[k][frag][row] = tuple
+				key := tupleKey(tuple)
+
+				if k == 0 {
+					pending[key] = append(pending[key], position{frag, row})
+					continue
+				}
+
+				if len(pending[key]) == 0 {
+					unmatched[1] = append(unmatched[1], position{frag, row})
+					continue
+				}
+				pending[key] = pending[key][1:]
+			}
+		}
+	}
+
+	for _, positions := range pending {
+		unmatched[0] = append(unmatched[0], positions...)
+	}
+
+	sort.Slice(unmatched[0], func(i, j int) bool {
+		pi, pj := unmatched[0][i], unmatched[0][j]
+		return pi.frag < pj.frag || (pi.frag == pj.frag && pi.row < pj.row)
+	})
+
+	v := &Violation{Query: r.ID, Type: queryType(r), NbRows: len(unmatched[0]) + len(unmatched[1])}
+	if v.NbRows == 0 {
+		return nil
+	}
+
+	for k, aOrB := range sides {
+		for frag := range aOrB {
+			name := string("AB"[k])
+			if len(aOrB) > 1 {
+				name = fmt.Sprintf("%v[%v]", name, frag)
+			}
+
+			table := ViolationTable{Name: name, Columns: columnNames(aOrB[frag])}
+			for _, pos := range unmatched[k] {
+				if pos.frag == frag {
+					table.addRow(maxRows, pos.row, tuples[k][frag][pos.row])
+				}
+			}
+
+			if len(table.Rows) > 0 {
+				v.Tables = append(v.Tables, table)
+			}
+		}
+	}
+
+	return v
+}
+
+// Violation implements the [ViolationLocator] interface. The table lists the
+// rows holding a value out of the range.
+func (r Range) Violation(run ifaces.Runtime, maxRows int) *Violation {
+
+	var (
+		b     = field.NewElement(uint64(r.B))
+		wit   = r.Handle.GetColAssignment(run)
+		v     = &Violation{Query: r.ID, Type: queryType(r)}
+		table = ViolationTable{Columns: columnNames([]ifaces.Column{r.Handle})}
+	)
+
+	for i := 0; i < wit.Len(); i++ {
+		x := wit.Get(i)
+		if x.Cmp(&b) >= 0 {
+			v.NbRows++
+			table.addRow(maxRows, i, []field.Element{x})
+		}
+	}
+
+	if v.NbRows == 0 {
+		return nil
+	}
+
+	v.Tables = []ViolationTable{table}
+	return v
+}
+
+func queryType(q ifaces.Query) string {
+	return reflect.TypeOf(q).Name()
+}
+
+func variableNames(metadatas []symbolic.Metadata) []string {
+	names := make([]string, len(metadatas))
+	for i := range metadatas {
+		names[i] = metadatas[i].String()
+	}
+	return names
+}
+
+func columnNames(cols []ifaces.Column) []string {
+	names := make([]string, len(cols))
+	for i := range cols {
+		names[i] = string(cols[i].GetColID())
+	}
+	return names
+}
+
+func assignments(run ifaces.Runtime, cols []ifaces.Column) []sv.SmartVector {
+	res := make([]sv.SmartVector, len(cols))
+	for i := range cols {
+		res[i] = cols[i].GetColAssignment(run)
+	}
+	return res
+}
+
+func rowValues(vecs []sv.SmartVector, row int) []field.Element {
+	res := make([]field.Element, len(vecs))
+	for i := range vecs {
+		res[i] = vecs[i].Get(row)
+	}
+	return res
+}
+
+// tupleKey returns a map key identifying the tuple exactly
+func tupleKey(tuple []field.Element) string {
+	var sb strings.Builder
+	for i := range tuple {
+		b := tuple[i].Bytes()
+		sb.Write(b[:])
+	}
+	return sb.String()
+}
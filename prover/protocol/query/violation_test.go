@@ -0,0 +1,110 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViolation(t *testing.T) {
+
+	var (
+		runS                                 *wizard.ProverRuntime
+		global, inclusion, permutation, rnge query.ViolationLocator
+		passing                              query.ViolationLocator
+	)
+
+	define := func(b *wizard.Builder) {
+		x := b.RegisterCommit("X", 8)
+		// X[i-1] + X[i-2] - X[i] = 0
+		global = b.GlobalConstraint("FIBONACCI", ifaces.ColumnAsVariable(column.Shift(x, -1)).
+			Add(ifaces.ColumnAsVariable(column.Shift(x, -2))).
+			Sub(ifaces.ColumnAsVariable(x)))
+
+		including := b.RegisterCommit("T", 4)
+		included := b.RegisterCommit("S", 4)
+		b.Inclusion("INCLUSION", []ifaces.Column{including}, []ifaces.Column{included})
+		inclusion = b.QueriesNoParams.Data("INCLUSION").(query.ViolationLocator)
+
+		a, c := b.RegisterCommit("A", 4), b.RegisterCommit("B", 4)
+		permutation = b.CompiledIOP.InsertPermutation(0, "PERMUTATION", []ifaces.Column{a}, []ifaces.Column{c})
+		passing = b.CompiledIOP.InsertPermutation(0, "PASSING", []ifaces.Column{a}, []ifaces.Column{a})
+
+		b.Range("RANGE", b.RegisterCommit("R", 4), 8)
+		rnge = b.QueriesNoParams.Data("RANGE").(query.ViolationLocator)
+	}
+
+	prove := func(run *wizard.ProverRuntime) {
+		run.AssignColumn("X", smartvectors.ForTest(1, 1, 2, 3, 5, 9, 13, 21))
+		run.AssignColumn("T", smartvectors.ForTest(1, 2, 3, 4))
+		run.AssignColumn("S", smartvectors.ForTest(1, 5, 3, 7))
+		run.AssignColumn("A", smartvectors.ForTest(1, 2, 3, 4))
+		run.AssignColumn("B", smartvectors.ForTest(4, 3, 2, 2))
+		run.AssignColumn("R", smartvectors.ForTest(0, 3, 8, 9))
+		runS = run
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	_ = wizard.Prove(comp, prove)
+
+	assert.Nil(t, passing.Violation(runS, 0))
+
+	// the global constraint fails on the 3 last rows
+	v := global.Violation(runS, 0)
+	require.NotNil(t, v)
+	assert.Equal(t, "GlobalConstraint", v.Type)
+	assert.Equal(t, 3, v.NbRows)
+	require.Len(t, v.Tables, 1)
+	table := v.Tables[0]
+	assert.Equal(t, []int{5, 6, 7}, table.Rows)
+	assert.Len(t, table.Columns, 4)
+	assert.Contains(t, table.Columns, "X")
+	assert.Equal(t, query.ResultColumn, table.Columns[3])
+
+	// the values of the columns and shifts are those of the offending row
+	for k, row := range table.Rows {
+		sum := field.Zero()
+		for j, name := range table.Columns[:3] {
+			val := table.Values[k][j]
+			if name == "X" {
+				val.Neg(&val)
+			}
+			sum.Add(&sum, &val)
+		}
+		assert.Equal(t, sum, table.Values[k][3], "row %v", row)
+	}
+
+	// the number of reported rows is bounded, but not the count
+	v = global.Violation(runS, 1)
+	assert.Equal(t, 3, v.NbRows)
+	assert.Equal(t, []int{5}, v.Tables[0].Rows)
+
+	// the tuples missing from the including table
+	v = inclusion.Violation(runS, 0)
+	require.NotNil(t, v)
+	assert.Equal(t, []string{"S"}, v.Tables[0].Columns)
+	assert.Equal(t, []int{1, 3}, v.Tables[0].Rows)
+	assert.Equal(t, [][]field.Element{{field.NewElement(5)}, {field.NewElement(7)}}, v.Tables[0].Values)
+
+	// the unmatched rows of each side
+	v = permutation.Violation(runS, 0)
+	require.NotNil(t, v)
+	assert.Equal(t, 2, v.NbRows)
+	require.Len(t, v.Tables, 2)
+	assert.Equal(t, "A", v.Tables[0].Name)
+	assert.Equal(t, []int{0}, v.Tables[0].Rows)
+	assert.Equal(t, "B", v.Tables[1].Name)
+	assert.Equal(t, []int{3}, v.Tables[1].Rows)
+
+	v = rnge.Violation(runS, 0)
+	require.NotNil(t, v)
+	assert.Equal(t, []int{2, 3}, v.Tables[0].Rows)
+}
@@ -0,0 +1,555 @@
+package serialization
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/crypto/vortex"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// checkpointFormatVersion is stored in the header of every checkpoint. It must
+// be bumped whenever the encoding of the checkpoints changes.
+const checkpointFormatVersion = "prover-checkpoint/v1"
+
+const (
+	checkpointPayloadFile = "checkpoint.cbor"
+	checkpointHeaderFile  = "checkpoint.json"
+)
+
+// checkpointHeader is stored next to the payload of the checkpoint. It is
+// written last so that a checkpoint without header is treated as missing.
+type checkpointHeader struct {
+	Format          string `json:"format"`
+	Fingerprint     string `json:"fingerprint"`
+	Round           int    `json:"round"`
+	PayloadChecksum string `json:"payloadChecksum"`
+}
+
+// checkpointMeta is the first item of the payload. It is followed by the
+// columns, the query parameters, the coins and the state entries, one CBOR
+// item each, so that the payload can be streamed.
+type checkpointMeta struct {
+	Round              int         `cbor:"round"`
+	FSState            []byte      `cbor:"fsState"`
+	FSTranscriptSize   int         `cbor:"fsTranscriptSize"`
+	FSNumCoinGenerated int         `cbor:"fsNumCoinGenerated"`
+	FSHistory          [][2][]byte `cbor:"fsHistory"`
+	NumColumns         int         `cbor:"numColumns"`
+	NumQueriesParams   int         `cbor:"numQueriesParams"`
+	NumCoins           int         `cbor:"numCoins"`
+	NumStateEntries    int         `cbor:"numStateEntries"`
+}
+
+type serializableCoin struct {
+	Name     coin.Name `cbor:"name"`
+	Field    []byte    `cbor:"field,omitempty"`
+	Integers []int     `cbor:"integers,omitempty"`
+}
+
+type serializableStateEntry struct {
+	Key   string `cbor:"key"`
+	Codec string `cbor:"codec"`
+	Data  []byte `cbor:"data"`
+}
+
+// ProverCheckpointDir is a [wizard.ProverCheckpointer] storing the checkpoint
+// of a single proof in a directory. Every checkpoint overwrites the previous
+// one. The checkpoints are tied to a fingerprint of the compiled IOP, as for
+// [SerializeProof].
+//
+// The checkpoint stores the assigned columns, the query parameters, the coins,
+// the Fiat-Shamir state and the entries of [wizard.ProverRuntime.State] that
+// have a codec registered with [RegisterProverStateCodec]. A round at which
+// the state holds other entries is not checkpointed.
+type ProverCheckpointDir struct {
+	Dir         string
+	Fingerprint string
+}
+
+func init() {
+	RegisterProverStateCodec("field-elements", encodeFieldElements, decodeFieldElements)
+	RegisterProverStateCodec("vortex-encoded-matrix", encodeEncodedMatrix, decodeEncodedMatrix)
+	RegisterProverStateCodec("smt-tree", encodeSmtTree, decodeSmtTree)
+}
+
+// Save implements [wizard.ProverCheckpointer]
+func (d ProverCheckpointDir) Save(run *wizard.ProverRuntime) error {
+
+	stateKeys := run.State.ListAllKeys()
+	sort.Strings(stateKeys)
+
+	for _, key := range stateKeys {
+		if _, ok := stateCodecsByType[reflect.TypeOf(run.State.MustGet(key))]; !ok {
+			return fmt.Errorf("%w: the state entry %v has type %T", wizard.ErrNotCheckpointable, key, run.State.MustGet(key))
+		}
+	}
+
+	if err := os.MkdirAll(d.Dir, 0755); err != nil {
+		return fmt.Errorf("could not create the checkpoint directory: %w", err)
+	}
+
+	// The previous checkpoint is invalidated before the payload is replaced
+	if err := os.Remove(d.headerPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove the previous checkpoint: %w", err)
+	}
+
+	var checksum string
+	err := writeFileAtomicFunc(d.payloadPath(), func(w io.Writer) error {
+		h := sha256.New()
+		if err := d.writePayload(io.MultiWriter(w, h), run, stateKeys); err != nil {
+			return err
+		}
+		checksum = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(checkpointHeader{
+		Format:          checkpointFormatVersion,
+		Fingerprint:     d.Fingerprint,
+		Round:           run.Round(),
+		PayloadChecksum: checksum,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomicFunc(d.headerPath(), func(w io.Writer) error {
+		_, err := w.Write(header)
+		return err
+	})
+}
+
+func (d ProverCheckpointDir) writePayload(w io.Writer, run *wizard.ProverRuntime, stateKeys []string) error {
+
+	em, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return err
+	}
+
+	var (
+		bw  = bufio.NewWriter(w)
+		enc = em.NewEncoder(bw)
+	)
+
+	// The precomputed columns are part of the compiled IOP, they are not
+	// stored.
+	colIDs := []ifaces.ColID{}
+	for _, id := range run.Columns.ListAllKeys() {
+		if !run.Spec.Precomputed.Exists(id) {
+			colIDs = append(colIDs, id)
+		}
+	}
+	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+
+	queryIDs := run.QueriesParams.ListAllKeys()
+	sort.Slice(queryIDs, func(i, j int) bool { return queryIDs[i] < queryIDs[j] })
+
+	coinNames := run.Coins.ListAllKeys()
+	sort.Slice(coinNames, func(i, j int) bool { return coinNames[i] < coinNames[j] })
+
+	meta := checkpointMeta{
+		Round:              run.Round(),
+		FSState:            encodeElements(run.FS.State()),
+		FSTranscriptSize:   run.FS.TranscriptSize,
+		FSNumCoinGenerated: run.FS.NumCoinGenerated,
+		NumColumns:         len(colIDs),
+		NumQueriesParams:   len(queryIDs),
+		NumCoins:           len(coinNames),
+		NumStateEntries:    len(stateKeys),
+	}
+
+	for _, h := range run.FiatShamirHistory {
+		meta.FSHistory = append(meta.FSHistory, [2][]byte{encodeElements(h[0]), encodeElements(h[1])})
+	}
+
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+
+	for _, id := range colIDs {
+		if err := enc.Encode(intoSerializableMessage(id, run.Columns.MustGet(id))); err != nil {
+			return fmt.Errorf("column %v: %w", id, err)
+		}
+	}
+
+	for _, id := range queryIDs {
+		params, err := intoSerializableQueryParams(id, run.QueriesParams.MustGet(id))
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(params); err != nil {
+			return fmt.Errorf("query %v: %w", id, err)
+		}
+	}
+
+	for _, name := range coinNames {
+		c := serializableCoin{Name: name}
+		switch v := run.Coins.MustGet(name).(type) {
+		case field.Element:
+			c.Field = encodeElements([]field.Element{v})
+		case []int:
+			c.Integers = v
+		default:
+			return fmt.Errorf("coin %v has unexpected type %T", name, v)
+		}
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("coin %v: %w", name, err)
+		}
+	}
+
+	for _, key := range stateKeys {
+		value := run.State.MustGet(key)
+		codec := stateCodecsByType[reflect.TypeOf(value)]
+		data, err := codec.encode(value)
+		if err != nil {
+			return fmt.Errorf("state entry %v: %w", key, err)
+		}
+		if err := enc.Encode(serializableStateEntry{Key: key, Codec: codec.name, Data: data}); err != nil {
+			return fmt.Errorf("state entry %v: %w", key, err)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Load implements [wizard.ProverCheckpointer]. It returns nil if the
+// directory holds no checkpoint.
+func (d ProverCheckpointDir) Load(c *wizard.CompiledIOP) (*wizard.ProverRuntime, error) {
+
+	headerBytes, err := os.ReadFile(d.headerPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the header of the checkpoint: %w", err)
+	}
+
+	var header checkpointHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("could not decode the header of the checkpoint: %w", err)
+	}
+
+	switch {
+	case header.Format != checkpointFormatVersion:
+		return nil, fmt.Errorf("unsupported checkpoint format %q, expected %q", header.Format, checkpointFormatVersion)
+	case header.Fingerprint != d.Fingerprint:
+		return nil, fmt.Errorf("the checkpoint was generated for another compiled IOP: got %v, expected %v", header.Fingerprint, d.Fingerprint)
+	case header.Round < 0 || header.Round+1 >= c.NumRounds():
+		return nil, fmt.Errorf("the checkpoint is at round %v but the compiled IOP has %v rounds", header.Round, c.NumRounds())
+	}
+
+	if err := checkFileSha256(d.payloadPath(), header.PayloadChecksum); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(d.payloadPath())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	run, err := readPayload(bufio.NewReader(f), c)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the checkpoint: %w", err)
+	}
+
+	if run.Round() != header.Round {
+		return nil, fmt.Errorf("the payload of the checkpoint is at round %v but its header at round %v", run.Round(), header.Round)
+	}
+
+	return run, nil
+}
+
+func readPayload(r io.Reader, c *wizard.CompiledIOP) (*wizard.ProverRuntime, error) {
+
+	dec := cbor.NewDecoder(r)
+
+	var meta checkpointMeta
+	if err := dec.Decode(&meta); err != nil {
+		return nil, err
+	}
+
+	fsState, err := decodeElements(meta.FSState)
+	if err != nil {
+		return nil, fmt.Errorf("FS state: %w", err)
+	}
+
+	if len(fsState) != 1 || len(meta.FSHistory) != c.NumRounds() {
+		return nil, fmt.Errorf("invalid FS state or history (%v rounds)", len(meta.FSHistory))
+	}
+
+	fsHistory := make([][2][]field.Element, len(meta.FSHistory))
+	for i := range meta.FSHistory {
+		for j := range meta.FSHistory[i] {
+			if fsHistory[i][j], err = decodeElements(meta.FSHistory[i][j]); err != nil {
+				return nil, fmt.Errorf("FS history: %w", err)
+			}
+		}
+	}
+
+	fs := c.NewFiatShamirState()
+	fs.SetState(fsState)
+	fs.TranscriptSize = meta.FSTranscriptSize
+	fs.NumCoinGenerated = meta.FSNumCoinGenerated
+
+	run := wizard.NewProverRuntimeAt(c, meta.Round, fs, fsHistory)
+
+	for i := 0; i < meta.NumColumns; i++ {
+		var m serializableMessage
+		if err := dec.Decode(&m); err != nil {
+			return nil, err
+		}
+		v, err := m.intoSmartVector()
+		if err != nil {
+			return nil, fmt.Errorf("column %v: %w", m.ID, err)
+		}
+		if !c.Columns.Exists(m.ID) || run.Columns.Exists(m.ID) {
+			return nil, fmt.Errorf("column %v is unknown or appears twice", m.ID)
+		}
+		run.Columns.InsertNew(m.ID, v)
+	}
+
+	for i := 0; i < meta.NumQueriesParams; i++ {
+		var q serializableQueryParams
+		if err := dec.Decode(&q); err != nil {
+			return nil, err
+		}
+		params, err := q.intoQueryParams()
+		if err != nil {
+			return nil, fmt.Errorf("query %v: %w", q.ID, err)
+		}
+		if !c.QueriesParams.Exists(q.ID) || run.QueriesParams.Exists(q.ID) {
+			return nil, fmt.Errorf("query %v is unknown or appears twice", q.ID)
+		}
+		run.QueriesParams.InsertNew(q.ID, params)
+	}
+
+	for i := 0; i < meta.NumCoins; i++ {
+		var sc serializableCoin
+		if err := dec.Decode(&sc); err != nil {
+			return nil, err
+		}
+		if !c.Coins.Exists(sc.Name) || run.Coins.Exists(sc.Name) {
+			return nil, fmt.Errorf("coin %v is unknown or appears twice", sc.Name)
+		}
+		switch c.Coins.Data(sc.Name).Type {
+		case coin.Field:
+			v, err := decodeElements(sc.Field)
+			if err != nil || len(v) != 1 {
+				return nil, fmt.Errorf("coin %v: invalid field element", sc.Name)
+			}
+			run.Coins.InsertNew(sc.Name, v[0])
+		case coin.IntegerVec:
+			run.Coins.InsertNew(sc.Name, sc.Integers)
+		}
+	}
+
+	for i := 0; i < meta.NumStateEntries; i++ {
+		var e serializableStateEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, err
+		}
+		codec, ok := stateCodecsByName[e.Codec]
+		if !ok {
+			return nil, fmt.Errorf("state entry %v: unknown codec %q", e.Key, e.Codec)
+		}
+		v, err := codec.decode(e.Data)
+		if err != nil {
+			return nil, fmt.Errorf("state entry %v: %w", e.Key, err)
+		}
+		run.State.InsertNew(e.Key, v)
+	}
+
+	return run, nil
+}
+
+// Clear removes the checkpoint from the directory. It is meant to be called
+// once the proof is complete.
+func (d ProverCheckpointDir) Clear() error {
+	return errors.Join(
+		removeIfExists(d.headerPath()),
+		removeIfExists(d.payloadPath()),
+	)
+}
+
+func (d ProverCheckpointDir) headerPath() string {
+	return filepath.Join(d.Dir, checkpointHeaderFile)
+}
+
+func (d ProverCheckpointDir) payloadPath() string {
+	return filepath.Join(d.Dir, checkpointPayloadFile)
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Writes the file through a temporary file and a rename, streaming the
+// content from the write function.
+func writeFileAtomicFunc(path string, write func(w io.Writer) error) error {
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("could not create %v: %w", tmp, err)
+	}
+
+	if err := errors.Join(write(f), f.Close()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not write %v: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not rename %v into %v: %w", tmp, path, err)
+	}
+
+	return nil
+}
+
+// Checks the SHA256 of a file without loading it in memory
+func checkFileSha256(path, checksum string) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); got != checksum {
+		return fmt.Errorf("checksum mismatch for %v, expected %v got %v", path, checksum, got)
+	}
+
+	return nil
+}
+
+// stateCodec encodes the entries of a given type of the prover state
+type stateCodec struct {
+	name   string
+	encode func(any) ([]byte, error)
+	decode func([]byte) (any, error)
+}
+
+var (
+	stateCodecsByType = map[reflect.Type]stateCodec{}
+	stateCodecsByName = map[string]stateCodec{}
+)
+
+// RegisterProverStateCodec registers how the entries of type T of the
+// [wizard.ProverRuntime.State] are stored in the checkpoints of a
+// [ProverCheckpointDir]. The name identifies the codec in the checkpoints and
+// must not change. Registering the same name twice panics.
+func RegisterProverStateCodec[T any](name string, encode func(T) ([]byte, error), decode func([]byte) (T, error)) {
+
+	if _, ok := stateCodecsByName[name]; ok {
+		panic(fmt.Sprintf("a prover state codec is already registered as %q", name))
+	}
+
+	codec := stateCodec{
+		name:   name,
+		encode: func(v any) ([]byte, error) { return encode(v.(T)) },
+		decode: func(b []byte) (any, error) { return decode(b) },
+	}
+
+	stateCodecsByType[reflect.TypeOf((*T)(nil)).Elem()] = codec
+	stateCodecsByName[name] = codec
+}
+
+func encodeFieldElements(v []field.Element) ([]byte, error) {
+	return encodeElements(v), nil
+}
+
+func decodeFieldElements(b []byte) ([]field.Element, error) {
+	return decodeElements(b)
+}
+
+func encodeEncodedMatrix(m vortex.EncodedMatrix) ([]byte, error) {
+	rows := make([]serializableVector, len(m))
+	for i := range m {
+		rows[i] = intoSerializableVector(m[i])
+	}
+	return cbor.Marshal(rows)
+}
+
+func decodeEncodedMatrix(b []byte) (vortex.EncodedMatrix, error) {
+	var rows []serializableVector
+	if err := cbor.Unmarshal(b, &rows); err != nil {
+		return nil, err
+	}
+	res := make(vortex.EncodedMatrix, len(rows))
+	for i := range rows {
+		v, err := rows[i].intoSmartVector()
+		if err != nil {
+			return nil, fmt.Errorf("row %v: %w", i, err)
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// serializableSmtTree stores the nodes of a [smt.Tree]. The hash function of
+// the tree is not stored.
+type serializableSmtTree struct {
+	Depth          int               `cbor:"depth"`
+	Root           types.Bytes32     `cbor:"root"`
+	OccupiedLeaves []types.Bytes32   `cbor:"occupiedLeaves"`
+	OccupiedNodes  [][]types.Bytes32 `cbor:"occupiedNodes"`
+	EmptyNodes     []types.Bytes32   `cbor:"emptyNodes"`
+}
+
+func encodeSmtTree(t *smt.Tree) ([]byte, error) {
+	if t.Storage != nil {
+		return nil, fmt.Errorf("%w: the tree is held by a storage", wizard.ErrNotCheckpointable)
+	}
+	return cbor.Marshal(serializableSmtTree{
+		Depth:          t.Config.Depth,
+		Root:           t.Root,
+		OccupiedLeaves: t.OccupiedLeaves,
+		OccupiedNodes:  t.OccupiedNodes,
+		EmptyNodes:     t.EmptyNodes,
+	})
+}
+
+// decodeSmtTree restores a tree without its hash function. The prover only
+// uses the trees it keeps in its state to extract Merkle proofs, which does
+// not require hashing.
+func decodeSmtTree(b []byte) (*smt.Tree, error) {
+	var t serializableSmtTree
+	if err := cbor.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	return &smt.Tree{
+		Config:         &smt.Config{Depth: t.Depth},
+		Root:           t.Root,
+		OccupiedLeaves: t.OccupiedLeaves,
+		OccupiedNodes:  t.OccupiedNodes,
+		EmptyNodes:     t.EmptyNodes,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package serialization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes"
+	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crashingCheckpointer saves the checkpoints and panics after having saved
+// the one of the given round, simulating an interrupted prover.
+type crashingCheckpointer struct {
+	ProverCheckpointDir
+	crashAt int
+}
+
+var errCrash = errors.New("crash")
+
+func (c crashingCheckpointer) Save(run *wizard.ProverRuntime) error {
+	err := c.ProverCheckpointDir.Save(run)
+	if run.Round() == c.crashAt {
+		panic(errCrash)
+	}
+	return err
+}
+
+func TestProverCheckpoint(t *testing.T) {
+
+	var (
+		P, Q   ifaces.ColID   = "P", "Q"
+		U      ifaces.QueryID = "U"
+		R1, R2 coin.Name      = "R1", "R2"
+		stored                = []field.Element{field.NewElement(42)}
+	)
+
+	define := func(b *wizard.Builder) {
+		p := b.RegisterCommit(P, 8)
+		b.RegisterRandomCoin(R1, coin.Field)
+		q := b.RegisterCommit(Q, 8)
+		b.RegisterRandomCoin(R2, coin.IntegerVec, 4, 8)
+		b.UnivariateEval(U, p, q)
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	require.Equal(t, 3, comp.NumRounds())
+
+	// The high-level prover only assigns the first round, the next ones are
+	// assigned by prover steps so that the prover can be checkpointed in
+	// between. The state entry stored in the first round is read at the last
+	// one, after the resumption.
+	prover := func(run *wizard.ProverRuntime) {
+		run.AssignColumn(P, smartvectors.ForTest(1, 2, 3, 4, 5, 6, 7, 8))
+		run.State.InsertNew("stored", stored)
+	}
+
+	comp.SubProvers.AppendToInner(1, func(run *wizard.ProverRuntime) {
+		r1 := run.GetRandomCoinField(R1)
+		run.AssignColumn(Q, smartvectors.NewPaddedCircularWindow([]field.Element{r1, r1}, field.One(), 3, 8))
+	})
+
+	comp.SubProvers.AppendToInner(2, func(run *wizard.ProverRuntime) {
+		assert.Equal(t, stored, run.State.MustGet("stored"))
+		_ = run.GetRandomCoinIntegerVec(R2)
+		x := field.NewElement(3)
+		run.AssignUnivariate(U, x,
+			smartvectors.Interpolate(run.GetColumn(P), x),
+			smartvectors.Interpolate(run.GetColumn(Q), x),
+		)
+	})
+
+	expected, err := SerializeProof(wizard.Prove(comp, prover), "")
+	require.NoError(t, err)
+
+	dir := ProverCheckpointDir{Dir: t.TempDir(), Fingerprint: "fingerprint"}
+
+	for crashAt := 0; crashAt < 2; crashAt++ {
+
+		require.NoError(t, dir.Clear())
+
+		require.PanicsWithValue(t, errCrash, func() {
+			wizard.ProveWithCheckpoints(comp, prover, crashingCheckpointer{dir, crashAt})
+		})
+
+		// The resumed prover does not run the high-level prover
+		notCalled := func(*wizard.ProverRuntime) { t.Fatal("the high-level prover was called") }
+		proof := wizard.ProveWithCheckpoints(comp, notCalled, dir)
+		require.NoError(t, wizard.Verify(comp, proof))
+
+		got, err := SerializeProof(proof, "")
+		require.NoError(t, err)
+		assert.Equal(t, expected, got, "crash at round %v", crashAt)
+	}
+
+	t.Run("fingerprint", func(t *testing.T) {
+		other := ProverCheckpointDir{Dir: dir.Dir, Fingerprint: "other"}
+		_, err := other.Load(comp)
+		assert.Error(t, err)
+
+		// The checkpoint is ignored and the proof is generated from scratch
+		proof := wizard.ProveWithCheckpoints(comp, prover, other)
+		require.NoError(t, wizard.Verify(comp, proof))
+	})
+
+	t.Run("not-checkpointable", func(t *testing.T) {
+		run := wizard.NewProverRuntimeAt(comp, 0, nil, nil)
+		run.State.InsertNew("channel", make(chan int))
+		err := dir.Save(run)
+		assert.ErrorIs(t, err, wizard.ErrNotCheckpointable)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, dir.Clear())
+		run, err := dir.Load(comp)
+		assert.NoError(t, err)
+		assert.Nil(t, run)
+	})
+}
+
+func TestSmtTreeCodec(t *testing.T) {
+
+	leaves := make([]types.Bytes32, 8)
+	for i := range leaves {
+		leaves[i][31] = byte(i + 1)
+	}
+
+	tree := smt.BuildComplete(leaves, hashtypes.MiMC)
+
+	b, err := encodeSmtTree(tree)
+	require.NoError(t, err)
+
+	decoded, err := decodeSmtTree(b)
+	require.NoError(t, err)
+
+	for i := range leaves {
+		assert.Equal(t, tree.MustProve(i), decoded.MustProve(i))
+	}
+}
@@ -19,6 +19,8 @@ type rawCompiledIOP struct {
 	QueriesNoParams [][]json.RawMessage `json:"queriesNoParams"`
 	Coins           [][]json.RawMessage `json:"coins"`
 	DummyCompiled   bool                `json:"dummyCompiled"`
+	// Poseidon2FiatShamir mirrors [wizard.CompiledIOP.Poseidon2FiatShamir]
+	Poseidon2FiatShamir bool `json:"poseidon2FiatShamir"`
 }
 
 // SerializeCompiledIOP marshals a [wizard.CompiledIOP] object into JSON. This is
@@ -34,7 +36,7 @@ type rawCompiledIOP struct {
 //		}
 func SerializeCompiledIOP(comp *wizard.CompiledIOP) ([]byte, error) {
 
-	raw := &rawCompiledIOP{}
+	raw := &rawCompiledIOP{Poseidon2FiatShamir: comp.Poseidon2FiatShamir}
 	numRounds := comp.NumRounds()
 
 	for round := 0; round < numRounds; round++ {
@@ -115,6 +117,7 @@ func DeserializeCompiledIOP(data []byte) (*wizard.CompiledIOP, error) {
 	}
 
 	numRounds := len(raw.Columns)
+	comp.Poseidon2FiatShamir = raw.Poseidon2FiatShamir
 
 	// It is crucial that we first deserialize the columns and the coins before
 	// we attempt
@@ -44,6 +44,7 @@ func init() {
 	RegisterImplementation(query.LocalOpening{})
 	RegisterImplementation(query.MiMC{})
 	RegisterImplementation(query.Permutation{})
+	RegisterImplementation(query.Poseidon2{})
 	RegisterImplementation(query.Range{})
 	RegisterImplementation(query.UnivariateEval{})
 	RegisterImplementation(symbolic.Variable{})
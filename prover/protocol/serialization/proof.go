@@ -0,0 +1,345 @@
+package serialization
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// proofFormatVersion is stored in every serialized proof. It must be bumped
+// whenever the encoding of the proof changes so that the proofs written by
+// older binaries are rejected instead of being misread.
+const proofFormatVersion = "wizard-proof/v1"
+
+var (
+	// ErrProofFormat is returned when the proof was serialized with another
+	// version of the format.
+	ErrProofFormat = errors.New("unsupported wizard proof format")
+	// ErrProofFingerprint is returned when the proof was generated for another
+	// compiled IOP than the one of the caller.
+	ErrProofFingerprint = errors.New("the wizard proof was generated for another compiled IOP")
+	// ErrProofCorrupted is returned when the payload of the proof does not
+	// match its checksum or cannot be decoded.
+	ErrProofCorrupted = errors.New("the wizard proof is corrupted")
+)
+
+// Kinds of query parameters in a serialized proof
+const (
+	innerProductParamsKind   = "inner-product"
+	localOpeningParamsKind   = "local-opening"
+	univariateEvalParamsKind = "univariate-eval"
+)
+
+// proofEnvelope is the top-level object of a serialized proof. The payload is
+// kept as an opaque CBOR blob so that the checksum can be verified before it
+// is decoded.
+type proofEnvelope struct {
+	Format          string `cbor:"format"`
+	Fingerprint     string `cbor:"fingerprint"`
+	PayloadChecksum string `cbor:"payloadChecksum"`
+	Payload         []byte `cbor:"payload"`
+}
+
+type serializableProof struct {
+	Messages      []serializableMessage     `cbor:"messages"`
+	QueriesParams []serializableQueryParams `cbor:"queriesParams"`
+}
+
+// Kinds of smart-vectors in a serialized proof or checkpoint. The vectors of
+// the other kinds are stored as regular vectors.
+const (
+	regularVectorKind  = ""
+	constantVectorKind = "constant"
+	windowVectorKind   = "window"
+)
+
+// serializableVector stores a smart-vector. Constant vectors are stored as a
+// single value and windows as their window, padding and offset.
+type serializableVector struct {
+	Kind    string `cbor:"kind,omitempty"`
+	Len     int    `cbor:"len"`
+	Values  []byte `cbor:"values"`
+	Padding []byte `cbor:"padding,omitempty"`
+	Offset  int    `cbor:"offset,omitempty"`
+}
+
+// serializableMessage stores a column of the proof
+type serializableMessage struct {
+	ID ifaces.ColID `cbor:"id"`
+	serializableVector
+}
+
+// serializableQueryParams stores the parameters of a query. The fields that
+// are used depend on the kind of the query.
+type serializableQueryParams struct {
+	ID   ifaces.QueryID `cbor:"id"`
+	Kind string         `cbor:"kind"`
+	X    []byte         `cbor:"x,omitempty"`
+	Ys   []byte         `cbor:"ys,omitempty"`
+}
+
+// SerializeProof encodes the proof in a versioned binary format. The
+// fingerprint identifies the compiled IOP the proof was generated for and
+// [DeserializeProof] refuses to decode the proof for any other fingerprint.
+// The fingerprint is an arbitrary string chosen by the caller, typically the
+// digest of the [CacheKey] of the compiled IOP.
+//
+// The field elements are stored in canonical big-endian form, so the encoding
+// does not depend on the internal representation of the field.
+func SerializeProof(proof wizard.Proof, fingerprint string) ([]byte, error) {
+
+	ser := serializableProof{}
+
+	colIDs := proof.Messages.ListAllKeys()
+	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+
+	for _, id := range colIDs {
+		ser.Messages = append(ser.Messages, intoSerializableMessage(id, proof.Messages.MustGet(id)))
+	}
+
+	queryIDs := proof.QueriesParams.ListAllKeys()
+	sort.Slice(queryIDs, func(i, j int) bool { return queryIDs[i] < queryIDs[j] })
+
+	for _, id := range queryIDs {
+		params, err := intoSerializableQueryParams(id, proof.QueriesParams.MustGet(id))
+		if err != nil {
+			return nil, err
+		}
+		ser.QueriesParams = append(ser.QueriesParams, params)
+	}
+
+	em, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := em.Marshal(ser)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode the proof: %w", err)
+	}
+
+	return em.Marshal(proofEnvelope{
+		Format:          proofFormatVersion,
+		Fingerprint:     fingerprint,
+		PayloadChecksum: sha256Hex(payload),
+		Payload:         payload,
+	})
+}
+
+// DeserializeProof decodes a proof encoded by [SerializeProof]. The returned
+// error wraps [ErrProofFormat], [ErrProofFingerprint] or [ErrProofCorrupted]
+// when the proof cannot be used for the compiled IOP identified by the
+// fingerprint.
+func DeserializeProof(data []byte, fingerprint string) (wizard.Proof, error) {
+
+	var env proofEnvelope
+	if err := cbor.Unmarshal(data, &env); err != nil {
+		return wizard.Proof{}, fmt.Errorf("%w: could not decode the envelope: %v", ErrProofCorrupted, err)
+	}
+
+	if env.Format != proofFormatVersion {
+		return wizard.Proof{}, fmt.Errorf("%w: got %q, expected %q", ErrProofFormat, env.Format, proofFormatVersion)
+	}
+
+	if env.Fingerprint != fingerprint {
+		return wizard.Proof{}, fmt.Errorf("%w: got %v, expected %v", ErrProofFingerprint, env.Fingerprint, fingerprint)
+	}
+
+	if checksum := sha256Hex(env.Payload); checksum != env.PayloadChecksum {
+		return wizard.Proof{}, fmt.Errorf("%w: checksum mismatch, expected %v got %v", ErrProofCorrupted, env.PayloadChecksum, checksum)
+	}
+
+	var ser serializableProof
+	if err := cbor.Unmarshal(env.Payload, &ser); err != nil {
+		return wizard.Proof{}, fmt.Errorf("%w: could not decode the payload: %v", ErrProofCorrupted, err)
+	}
+
+	proof := wizard.Proof{
+		Messages:      collection.NewMapping[ifaces.ColID, ifaces.ColAssignment](),
+		QueriesParams: collection.NewMapping[ifaces.QueryID, ifaces.QueryParams](),
+	}
+
+	for _, m := range ser.Messages {
+		v, err := m.intoSmartVector()
+		if err != nil {
+			return wizard.Proof{}, fmt.Errorf("%w: column %v: %v", ErrProofCorrupted, m.ID, err)
+		}
+		if proof.Messages.Exists(m.ID) {
+			return wizard.Proof{}, fmt.Errorf("%w: column %v appears twice", ErrProofCorrupted, m.ID)
+		}
+		proof.Messages.InsertNew(m.ID, v)
+	}
+
+	for _, q := range ser.QueriesParams {
+		params, err := q.intoQueryParams()
+		if err != nil {
+			return wizard.Proof{}, fmt.Errorf("%w: query %v: %v", ErrProofCorrupted, q.ID, err)
+		}
+		if proof.QueriesParams.Exists(q.ID) {
+			return wizard.Proof{}, fmt.Errorf("%w: query %v appears twice", ErrProofCorrupted, q.ID)
+		}
+		proof.QueriesParams.InsertNew(q.ID, params)
+	}
+
+	return proof, nil
+}
+
+func intoSerializableMessage(id ifaces.ColID, v ifaces.ColAssignment) serializableMessage {
+	return serializableMessage{ID: id, serializableVector: intoSerializableVector(v)}
+}
+
+func intoSerializableVector(v smartvectors.SmartVector) serializableVector {
+	switch w := v.(type) {
+	case *smartvectors.Constant:
+		return serializableVector{
+			Kind:   constantVectorKind,
+			Len:    w.Len(),
+			Values: encodeElements([]field.Element{w.Val()}),
+		}
+	case *smartvectors.PaddedCircularWindow:
+		padding := w.PaddingVal()
+		return serializableVector{
+			Kind:    windowVectorKind,
+			Len:     w.Len(),
+			Values:  encodeElements(w.Window()),
+			Padding: encodeElements([]field.Element{padding}),
+			Offset:  w.Offset(),
+		}
+	default:
+		return serializableVector{
+			Len:    v.Len(),
+			Values: encodeElements(v.IntoRegVecSaveAlloc()),
+		}
+	}
+}
+
+func (m serializableVector) intoSmartVector() (smartvectors.SmartVector, error) {
+
+	values, err := decodeElements(m.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Len <= 0 {
+		return nil, fmt.Errorf("invalid length %v", m.Len)
+	}
+
+	switch m.Kind {
+	case constantVectorKind:
+		if len(values) != 1 {
+			return nil, fmt.Errorf("a constant vector has %v values", len(values))
+		}
+		return smartvectors.NewConstant(values[0], m.Len), nil
+
+	case windowVectorKind:
+		padding, err := decodeElements(m.Padding)
+		if err != nil {
+			return nil, err
+		}
+		if len(padding) != 1 || len(values) == 0 || len(values) >= m.Len || m.Offset < 0 || m.Offset >= m.Len {
+			return nil, fmt.Errorf("invalid window: len=%v window=%v offset=%v", m.Len, len(values), m.Offset)
+		}
+		return smartvectors.NewPaddedCircularWindow(values, padding[0], m.Offset, m.Len), nil
+
+	case regularVectorKind:
+		if len(values) != m.Len {
+			return nil, fmt.Errorf("the vector has %v values but its length is %v", len(values), m.Len)
+		}
+		return smartvectors.NewRegular(values), nil
+
+	default:
+		return nil, fmt.Errorf("unknown kind of vector %q", m.Kind)
+	}
+}
+
+func intoSerializableQueryParams(id ifaces.QueryID, params ifaces.QueryParams) (serializableQueryParams, error) {
+	switch p := params.(type) {
+	case query.InnerProductParams:
+		return serializableQueryParams{ID: id, Kind: innerProductParamsKind, Ys: encodeElements(p.Ys)}, nil
+	case query.LocalOpeningParams:
+		return serializableQueryParams{ID: id, Kind: localOpeningParamsKind, Ys: encodeElements([]field.Element{p.Y})}, nil
+	case query.UnivariateEvalParams:
+		return serializableQueryParams{
+			ID:   id,
+			Kind: univariateEvalParamsKind,
+			X:    encodeElements([]field.Element{p.X}),
+			Ys:   encodeElements(p.Ys),
+		}, nil
+	default:
+		return serializableQueryParams{}, fmt.Errorf("cannot serialize the parameters of query %v: unsupported type %T", id, params)
+	}
+}
+
+func (q serializableQueryParams) intoQueryParams() (ifaces.QueryParams, error) {
+
+	ys, err := decodeElements(q.Ys)
+	if err != nil {
+		return nil, err
+	}
+
+	switch q.Kind {
+	case innerProductParamsKind:
+		return query.NewInnerProductParams(ys...), nil
+	case localOpeningParamsKind:
+		if len(ys) != 1 {
+			return nil, fmt.Errorf("a local opening has %v values", len(ys))
+		}
+		return query.NewLocalOpeningParams(ys[0]), nil
+	case univariateEvalParamsKind:
+		x, err := decodeElements(q.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 1 {
+			return nil, fmt.Errorf("a univariate evaluation has %v evaluation points", len(x))
+		}
+		return query.NewUnivariateEvalParams(x[0], ys...), nil
+	default:
+		return nil, fmt.Errorf("unknown kind of query %q", q.Kind)
+	}
+}
+
+// encodeElements concatenates the canonical big-endian encodings of the
+// elements.
+func encodeElements(v []field.Element) []byte {
+	res := make([]byte, 0, len(v)*field.Bytes)
+	for i := range v {
+		b := v[i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+// decodeElements is the inverse of encodeElements. It rejects non-canonical
+// encodings.
+func decodeElements(b []byte) ([]field.Element, error) {
+
+	if len(b)%field.Bytes != 0 {
+		return nil, fmt.Errorf("the length of the encoding (%v) is not a multiple of %v", len(b), field.Bytes)
+	}
+
+	res := make([]field.Element, len(b)/field.Bytes)
+	for i := range res {
+		if err := res[i].SetBytesCanonical(b[i*field.Bytes : (i+1)*field.Bytes]); err != nil {
+			return nil, fmt.Errorf("element #%v: %w", i, err)
+		}
+	}
+
+	return res, nil
+}
+
+// sha256Hex returns the hex-encoded SHA256 of b
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,83 @@
+package serialization
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/query"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofRoundTrip(t *testing.T) {
+
+	const fingerprint = "test-fingerprint"
+
+	var (
+		P, Q     ifaces.ColID   = "P", "Q"
+		U, L, IP ifaces.QueryID = "U", "L", "IP"
+		R        coin.Name      = "R"
+	)
+
+	define := func(b *wizard.Builder) {
+		p := b.RegisterCommit(P, 4)
+		q := b.RegisterCommit(Q, 4)
+		b.RegisterRandomCoin(R, coin.Field)
+		b.UnivariateEval(U, p)
+		b.LocalOpening(L, p)
+		b.InnerProduct(IP, p, q)
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		p := smartvectors.ForTest(1, 2, 3, 4)
+		q := smartvectors.NewConstant(field.NewElement(7), 4)
+		run.AssignColumn(P, p)
+		run.AssignColumn(Q, q)
+		r := run.GetRandomCoinField(R)
+		run.AssignLocalPoint(L, p.Get(0))
+		run.AssignInnerProduct(IP, field.NewElement(70))
+		run.AssignUnivariate(U, r, smartvectors.Interpolate(p, r))
+	}
+
+	comp := wizard.Compile(define, dummy.Compile)
+	proof := wizard.Prove(comp, prover)
+
+	encoded, err := SerializeProof(proof, fingerprint)
+	require.NoError(t, err)
+
+	decoded, err := DeserializeProof(encoded, fingerprint)
+	require.NoError(t, err)
+	require.NoError(t, wizard.Verify(comp, decoded))
+
+	assert.IsType(t, &smartvectors.Constant{}, decoded.Messages.MustGet(Q))
+	assert.Equal(t, proof.QueriesParams.MustGet(U), decoded.QueriesParams.MustGet(U))
+
+	// The encoding is deterministic
+	reencoded, err := SerializeProof(decoded, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+
+	t.Run("fingerprint", func(t *testing.T) {
+		_, err := DeserializeProof(encoded, "another-fingerprint")
+		assert.ErrorIs(t, err, ErrProofFingerprint)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		corrupted := append([]byte{}, encoded...)
+		corrupted[len(corrupted)-1] ^= 1
+		_, err := DeserializeProof(corrupted, fingerprint)
+		assert.ErrorIs(t, err, ErrProofCorrupted)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered, err := DeserializeProof(encoded, fingerprint)
+		require.NoError(t, err)
+		tampered.QueriesParams.Update(IP, query.NewInnerProductParams(field.NewElement(71)))
+		assert.Error(t, wizard.Verify(comp, tampered))
+	})
+}
@@ -0,0 +1,109 @@
+package wizard
+
+import (
+	"errors"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotCheckpointable is returned by a [ProverCheckpointer] when the state of
+// the runtime cannot be persisted at the current round, typically because a
+// [ProverStep] stored an entry in [ProverRuntime.State] that cannot be
+// serialized (e.g. channels shared with goroutines spanning several rounds).
+// The prover then moves on and tries again at the next round.
+var ErrNotCheckpointable = errors.New("the prover runtime cannot be checkpointed at this round")
+
+// ProverCheckpointer persists the [ProverRuntime] at the round boundaries so
+// that a proof can be resumed after the prover was interrupted. It is used by
+// [ProveWithCheckpoints].
+type ProverCheckpointer interface {
+	// Save persists the runtime once all the prover steps of its current
+	// round have been run. An error does not interrupt the prover.
+	Save(run *ProverRuntime) error
+	// Load returns the runtime saved for the compiled IOP or nil if there is
+	// none. The checkpointer is responsible for checking that the saved
+	// runtime was generated for the same compiled IOP. The returned runtime
+	// should be created with [NewProverRuntimeAt].
+	Load(c *CompiledIOP) (*ProverRuntime, error)
+}
+
+// ProveWithCheckpoints works as [Prove] but saves the runtime using the
+// checkpointer at the end of every round but the last one. If the
+// checkpointer holds a runtime for the compiled IOP, the proof is resumed
+// from it and `highLevelprover` is not run. A checkpoint that cannot be
+// loaded is ignored and the proof is generated from scratch.
+//
+// Passing a nil checkpointer is equivalent to calling [Prove].
+func ProveWithCheckpoints(c *CompiledIOP, highLevelprover ProverStep, cp ProverCheckpointer) Proof {
+
+	if cp == nil {
+		return Prove(c, highLevelprover)
+	}
+
+	runtime, err := cp.Load(c)
+	if err != nil {
+		logrus.Warnf("ignoring the prover checkpoint: %v", err)
+		runtime = nil
+	}
+
+	if runtime != nil {
+		logrus.Infof("resuming the prover from the checkpoint of round %v/%v", runtime.currRound, runtime.NumRounds())
+	} else {
+		fresh := c.createProver()
+		runtime = &fresh
+		highLevelprover(runtime)
+		runtime.runProverSteps()
+		runtime.saveCheckpoint(cp)
+	}
+
+	for runtime.currRound+1 < runtime.NumRounds() {
+		runtime.goNextRound()
+		runtime.runProverSteps()
+		runtime.saveCheckpoint(cp)
+	}
+
+	return runtime.extractProof()
+}
+
+// saveCheckpoint calls the checkpointer unless the runtime is at the last
+// round, which would be pointless. The failures are logged.
+func (run *ProverRuntime) saveCheckpoint(cp ProverCheckpointer) {
+
+	if run.currRound+1 >= run.NumRounds() {
+		return
+	}
+
+	start := time.Now()
+	err := cp.Save(run)
+
+	switch {
+	case errors.Is(err, ErrNotCheckpointable):
+		logrus.Infof("skipping the prover checkpoint of round %v: %v", run.currRound, err)
+	case err != nil:
+		logrus.Errorf("could not save the prover checkpoint of round %v: %v", run.currRound, err)
+	default:
+		logrus.Infof("saved the prover checkpoint of round %v in %v", run.currRound, time.Since(start))
+	}
+}
+
+// NewProverRuntimeAt returns a runtime positioned at the end of the given
+// round, with the Fiat-Shamir state and history of the checkpoint it is
+// restored from. Only the precomputed columns are assigned: the caller is
+// responsible for restoring the columns, the query parameters, the coins and
+// the state of the runtime. It is meant to be used by the implementations of
+// [ProverCheckpointer].
+func NewProverRuntimeAt(c *CompiledIOP, round int, fs *fiatshamir.State, fsHistory [][2][]field.Element) *ProverRuntime {
+	run := c.createProver()
+	run.currRound = round
+	run.FS = fs
+	copy(run.FiatShamirHistory, fsHistory)
+	return &run
+}
+
+// Round returns the round the runtime is currently at
+func (run *ProverRuntime) Round() int {
+	return run.currRound
+}
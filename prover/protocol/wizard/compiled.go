@@ -101,6 +101,11 @@ type CompiledIOP struct {
 	// amount of constraints and the flag
 	DummyCompiled bool
 
+	// Poseidon2FiatShamir is set by the [UsePoseidon2FiatShamir] compilation
+	// step. When set, the Fiat-Shamir transcripts of the prover and of the
+	// verifier are instantiated with Poseidon2 in place of MiMC.
+	Poseidon2FiatShamir bool
+
 	// SelfRecursionCount counts the number of self-recursions induced in the protocol. Used to
 	// derive unique names for when the self-recursion is called several time.
 	SelfRecursionCount int
@@ -609,6 +614,23 @@ func (c *CompiledIOP) InsertMiMC(round int, id ifaces.QueryID, block, old, new i
 	return q
 }
 
+// InsertPoseidon2 declares a Poseidon2 constraints query; a constraint that
+// all the entries of new are obtained by running the compression function of
+// Poseidon2 over the entries of block and old, row-by-row. It is the analogue
+// of [CompiledIOP.InsertMiMC].
+//
+// The function returns the registered [query.Poseidon2] object and will panic
+// if
+//   - the columns do not share the same size
+//   - the declaration round is anterior to the declaration round of the
+//     provided input columns.
+func (c *CompiledIOP) InsertPoseidon2(round int, id ifaces.QueryID, block, old, new ifaces.Column) query.Poseidon2 {
+	c.assertConsistentRound(round)
+	q := query.NewPoseidon2(id, block, old, new)
+	c.QueriesNoParams.AddToRound(round, id, q)
+	return q
+}
+
 // RegistersVerifyingKey registers a column as part of the verifying key of the
 // protocol; meaning a column whose assignment is static and which is visible
 // to the verifier.
@@ -2,7 +2,13 @@ package wizard
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover/crypto/mimc/gkrmimc"
 )
 
 // CompiledIOPSerializer is a function capable of serializing a Compiled-IOP
@@ -15,6 +21,10 @@ type VersionMetadata struct {
 	Title string
 	// Version number is a version string
 	Version string
+	// ConstraintsHash identifies the constraints of the wizard when they are
+	// not fixed at build time, e.g. the hash of the zkevm.bin file of the
+	// arithmetization. Empty otherwise.
+	ConstraintsHash string
 }
 
 // BootstrapFiatShamir hashes the description of the struct to bootstrap the
@@ -25,6 +35,7 @@ func (comp *CompiledIOP) BootstrapFiatShamir(vm VersionMetadata, ser CompiledIOP
 
 	io.WriteString(hasher, vm.Title)
 	io.WriteString(hasher, vm.Version)
+	io.WriteString(hasher, vm.ConstraintsHash)
 
 	// compBlob, err := ser(comp)
 	// if err != nil {
@@ -38,3 +49,67 @@ func (comp *CompiledIOP) BootstrapFiatShamir(vm VersionMetadata, ser CompiledIOP
 
 	return comp
 }
+
+// Fingerprint returns a hex-encoded digest identifying the compiled IOP. It
+// covers the Fiat-Shamir setup, the hash function of the transcript and the
+// declarations of every round: the columns with their sizes and statuses, the
+// queries with their types and the coins. The prover and verifier actions are
+// not covered, the constraints being identified by the metadata passed to
+// [CompiledIOP.BootstrapFiatShamir].
+func (comp *CompiledIOP) Fingerprint() string {
+
+	hasher := sha256.New()
+	setup := comp.fiatShamirSetup.Bytes()
+	hasher.Write(setup[:])
+	fmt.Fprintf(hasher, "poseidon2=%v;dummy=%v;rounds=%v\n", comp.Poseidon2FiatShamir, comp.DummyCompiled, comp.NumRounds())
+
+	for round := 0; round < comp.NumRounds(); round++ {
+
+		for _, col := range comp.Columns.AllHandlesAtRound(round) {
+			id := col.GetColID()
+			fmt.Fprintf(hasher, "column %v %v %v %v\n", round, id, comp.Columns.GetSize(id), comp.Columns.Status(id))
+		}
+
+		for _, id := range comp.QueriesParams.AllKeysAt(round) {
+			fmt.Fprintf(hasher, "query-params %v %v %T\n", round, id, comp.QueriesParams.Data(id))
+		}
+
+		for _, id := range comp.QueriesNoParams.AllKeysAt(round) {
+			fmt.Fprintf(hasher, "query %v %v %T\n", round, id, comp.QueriesNoParams.Data(id))
+		}
+
+		for _, name := range comp.Coins.AllKeysAt(round) {
+			info := comp.Coins.Data(name)
+			fmt.Fprintf(hasher, "coin %v %v %v %v %v\n", round, name, info.Type, info.Size, info.UpperBound)
+		}
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// UsePoseidon2FiatShamir is a compilation step instantiating the Fiat-Shamir
+// transcript of the wizard with Poseidon2 in place of MiMC. It can be placed
+// anywhere in the compilation suite passed to [Compile] and is honoured by
+// [Prove], [Verify] and the verifier circuit.
+func UsePoseidon2FiatShamir(comp *CompiledIOP) {
+	comp.Poseidon2FiatShamir = true
+}
+
+// NewFiatShamirState returns a fresh Fiat-Shamir state using the hash function
+// selected for comp. The state is not bootstrapped.
+func (comp *CompiledIOP) NewFiatShamirState() *fiatshamir.State {
+	if comp.Poseidon2FiatShamir {
+		return fiatshamir.NewPoseidon2FiatShamir()
+	}
+	return fiatshamir.NewMiMCFiatShamir()
+}
+
+// NewGnarkFiatShamir returns a fresh Fiat-Shamir state in a circuit, mirroring
+// [CompiledIOP.NewFiatShamirState]. The factory is optional and only used for
+// MiMC, see [fiatshamir.NewGnarkFiatShamir].
+func (comp *CompiledIOP) NewGnarkFiatShamir(api frontend.API, factory *gkrmimc.HasherFactory) *fiatshamir.GnarkFiatShamir {
+	if comp.Poseidon2FiatShamir {
+		return fiatshamir.NewGnarkFiatShamirPoseidon2(api)
+	}
+	return fiatshamir.NewGnarkFiatShamir(api, factory)
+}
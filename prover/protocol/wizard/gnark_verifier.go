@@ -150,7 +150,7 @@ func AllocateWizardCircuit(comp *CompiledIOP) (*WizardVerifierCircuit, error) {
 // [frontend.Define] function. Its work mirrors the [Verify] function.
 func (c *WizardVerifierCircuit) Verify(api frontend.API) {
 	c.HasherFactory = gkrmimc.NewHasherFactory(api)
-	c.FS = fiatshamir.NewGnarkFiatShamir(api, c.HasherFactory)
+	c.FS = c.Spec.NewGnarkFiatShamir(api, c.HasherFactory)
 	c.FS.Update(c.Spec.fiatShamirSetup)
 	c.FiatShamirHistory = make([][2][]frontend.Variable, c.Spec.NumRounds())
 	c.generateAllRandomCoins(api)
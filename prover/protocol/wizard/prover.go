@@ -147,6 +147,14 @@ type ProverRuntime struct {
 // when the specified protocol is complicated and involves multiple multi-rounds
 // sub-protocols that runs independently.
 func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
+	return RunProver(c, highLevelprover).extractProof()
+}
+
+// RunProver works as [Prove] but returns the prover runtime once all the
+// rounds have been run instead of the proof. This is useful when the caller
+// needs to access the assignment of the columns afterwards, for instance to
+// dispatch them to other wizards.
+func RunProver(c *CompiledIOP, highLevelprover ProverStep) *ProverRuntime {
 	runtime := c.createProver()
 	/*
 		Run the user provided assignment function. We can't expect it
@@ -164,25 +172,32 @@ func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
 		runtime.runProverSteps()
 	}
 
+	return &runtime
+}
+
+// extractProof collects the messages of the prover to the verifier once all
+// the rounds have been run.
+func (run *ProverRuntime) extractProof() Proof {
+
 	/*
 		Pass all the prover message columns as part of the proof
 	*/
 	messages := collection.NewMapping[ifaces.ColID, ifaces.ColAssignment]()
 
-	for _, name := range runtime.Spec.Columns.AllKeysProof() {
-		messageValue := runtime.Columns.MustGet(name)
+	for _, name := range run.Spec.Columns.AllKeysProof() {
+		messageValue := run.Columns.MustGet(name)
 		messages.InsertNew(name, messageValue)
 	}
 
 	// And also the public inputs
-	for _, name := range runtime.Spec.Columns.AllKeysPublicInput() {
-		messageValue := runtime.Columns.MustGet(name)
+	for _, name := range run.Spec.Columns.AllKeysPublicInput() {
+		messageValue := run.Columns.MustGet(name)
 		messages.InsertNew(name, messageValue)
 	}
 
 	return Proof{
 		Messages:      messages,
-		QueriesParams: runtime.QueriesParams,
+		QueriesParams: run.QueriesParams,
 	}
 }
 
@@ -203,7 +218,7 @@ func (run *ProverRuntime) NumRounds() int {
 func (c *CompiledIOP) createProver() ProverRuntime {
 
 	// Create a new fresh FS state and bootstrap it
-	fs := fiatshamir.NewMiMCFiatShamir()
+	fs := c.NewFiatShamirState()
 	fs.Update(c.fiatShamirSetup)
 
 	// Instantiates an empty Assignment (but link it to the CompiledIOP)
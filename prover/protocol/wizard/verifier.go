@@ -80,6 +80,15 @@ type VerifierRuntime struct {
 // `nil` to indicate that the proof passed and an error to indicate the proof
 // was invalid.
 func Verify(c *CompiledIOP, proof Proof) error {
+	_, err := VerifyWithRuntime(c, proof)
+	return err
+}
+
+// VerifyWithRuntime works as [Verify] but also returns the verifier runtime
+// so that the caller can read the public inputs of the proof with
+// [VerifierRuntime.GetPublicInput]. The public inputs should not be trusted
+// if the returned error is non-nil.
+func VerifyWithRuntime(c *CompiledIOP, proof Proof) (*VerifierRuntime, error) {
 
 	runtime := c.createVerifier(proof)
 
@@ -106,10 +115,10 @@ func Verify(c *CompiledIOP, proof Proof) error {
 	}
 
 	if len(errs) > 0 {
-		return utils.WrapErrsAlphabetically(errs)
+		return &runtime, utils.WrapErrsAlphabetically(errs)
 	}
 
-	return nil
+	return &runtime, nil
 }
 
 // createVerifier is an internal constructor for a new empty [VerifierRuntime] runtime. It
@@ -127,7 +136,7 @@ func (c *CompiledIOP) createVerifier(proof Proof) VerifierRuntime {
 		Coins:             collection.NewMapping[coin.Name, interface{}](),
 		Columns:           proof.Messages,
 		QueriesParams:     proof.QueriesParams,
-		FS:                fiatshamir.NewMiMCFiatShamir(),
+		FS:                c.NewFiatShamirState(),
 		FiatShamirHistory: make([][2][]field.Element, c.NumRounds()),
 	}
 
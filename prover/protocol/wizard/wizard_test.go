@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/coin"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
@@ -57,3 +58,42 @@ func TestCompiler(t *testing.T) {
 	err := wizard.Verify(compiled, proof)
 	require.NoError(t, err)
 }
+
+func TestPoseidon2FiatShamir(t *testing.T) {
+
+	var (
+		P    ifaces.ColID   = "P"
+		U    ifaces.QueryID = "U"
+		COIN coin.Name      = "R"
+	)
+
+	define := func(build *wizard.Builder) {
+		P := build.RegisterCommit(P, SIZE)
+		build.RegisterRandomCoin(COIN, coin.Field)
+		build.UnivariateEval(U, P)
+	}
+
+	// proveAndSample runs the prover and returns the sampled coin
+	proveAndSample := func(compiled *wizard.CompiledIOP) (wizard.Proof, field.Element) {
+		var u field.Element
+		proof := wizard.Prove(compiled, func(run *wizard.ProverRuntime) {
+			p := smartvectors.ForTest(1, 2, 3, 3)
+			run.AssignColumn(P, p)
+			u = run.GetRandomCoinField(COIN)
+			run.AssignUnivariate(U, u, smartvectors.Interpolate(p, u))
+		})
+		return proof, u
+	}
+
+	var (
+		mimcComp                   = wizard.Compile(define, dummy.Compile)
+		poseidon2Comp              = wizard.Compile(define, wizard.UsePoseidon2FiatShamir, dummy.Compile)
+		mimcProof, uMiMC           = proveAndSample(mimcComp)
+		poseidon2Proof, uPoseidon2 = proveAndSample(poseidon2Comp)
+	)
+
+	require.True(t, poseidon2Comp.Poseidon2FiatShamir)
+	require.NotEqual(t, uMiMC, uPoseidon2)
+	require.NoError(t, wizard.Verify(mimcComp, mimcProof))
+	require.NoError(t, wizard.Verify(poseidon2Comp, poseidon2Proof))
+}
@@ -0,0 +1,198 @@
+// Package exit defines the taxonomy of the failures of the prover. The
+// failures are represented as typed errors, each kind of failure being mapped
+// to a documented exit code of the prover process. The controller relies on
+// the same taxonomy to name the done files and to decide whether a job should
+// be retried.
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// List of the exit codes of the prover. Some of these codes are generated by
+// the controller and not the prover process itself: CodeFatal and
+// CodeCantRunCommand. CodeOom is not returned by the prover either; this is
+// what the controller observes when the process is killed by the OOM killer.
+const (
+	CodeSuccess         int = 0   // Success code
+	CodeUnclassified    int = 1   // The error is not part of the taxonomy
+	CodePanic           int = 2   // The process panicked (set by the Go runtime)
+	CodeFatal           int = 14  // When the process could not start
+	CodeCantRunCommand  int = 15  // When the controller could not run the command
+	CodeTraceLimit      int = 77  // The traces are overflown
+	CodeInvalidRequest  int = 78  // The request is malformed or inconsistent
+	CodeStateMismatch   int = 79  // The state-manager proofs do not match the request
+	CodeSetupMismatch   int = 80  // The setup is missing or does not match the config
+	CodeOomRisk         int = 81  // The machine does not have enough memory for the job
+	CodeTraceLimitLarge int = 82  // The traces are overflown even for the large limits
+	CodeOom             int = 137 // When the process exits on OOM
+)
+
+// Kind classifies a failure of the prover
+type Kind string
+
+const (
+	// KindTraceLimit indicates that a module of the zkEVM has more rows than
+	// its limit. The job may pass with the large limits.
+	KindTraceLimit Kind = "trace-limit"
+	// KindTraceLimitLarge indicates that the traces overflow the large limits
+	// as well. Retrying cannot help.
+	KindTraceLimitLarge Kind = "trace-limit-large"
+	// KindInvalidRequest indicates that the request cannot be parsed or is
+	// self-inconsistent. Retrying cannot help.
+	KindInvalidRequest Kind = "invalid-request"
+	// KindStateMismatch indicates that the state-manager traces are invalid or
+	// do not match the parent state root hash of the request. Retrying cannot
+	// help.
+	KindStateMismatch Kind = "state-mismatch"
+	// KindSetupMismatch indicates that the setup of the circuit cannot be
+	// loaded or was generated for another configuration. This is an issue
+	// with the deployment and not with the request.
+	KindSetupMismatch Kind = "setup-mismatch"
+	// KindOomRisk indicates that the prover refused to start the job because
+	// the machine does not have the memory the job requires.
+	KindOomRisk Kind = "oom-risk"
+	// KindOom indicates that the process was killed by the OOM killer
+	KindOom Kind = "oom"
+	// KindUnclassified is used for all the errors that are not covered by the
+	// taxonomy.
+	KindUnclassified Kind = "unclassified"
+)
+
+var kindCodes = map[Kind]int{
+	KindTraceLimit:      CodeTraceLimit,
+	KindTraceLimitLarge: CodeTraceLimitLarge,
+	KindInvalidRequest:  CodeInvalidRequest,
+	KindStateMismatch:   CodeStateMismatch,
+	KindSetupMismatch:   CodeSetupMismatch,
+	KindOomRisk:         CodeOomRisk,
+	KindOom:             CodeOom,
+	KindUnclassified:    CodeUnclassified,
+}
+
+// Code returns the exit code corresponding to the kind
+func (k Kind) Code() int {
+	if code, ok := kindCodes[k]; ok {
+		return code
+	}
+	return CodeUnclassified
+}
+
+// IsDeterministic returns true if the failure is expected to happen again
+// whatever the machine the job is run on. Such failures are never retried.
+func (k Kind) IsDeterministic() bool {
+	return k == KindInvalidRequest || k == KindStateMismatch || k == KindTraceLimitLarge
+}
+
+// Suffix returns the suffix identifying the kind in the name of the done
+// files. It is empty for unclassified failures.
+func (k Kind) Suffix() string {
+	if k == KindUnclassified || len(k) == 0 {
+		return ""
+	}
+	return "_" + strings.ToLower(string(k))
+}
+
+// KindOf returns the kind of failure corresponding to an exit code. It returns
+// [KindUnclassified] for the codes that are not part of the taxonomy,
+// including [CodeSuccess].
+func KindOf(code int) Kind {
+	for kind, c := range kindCodes {
+		if c == code {
+			return kind
+		}
+	}
+	return KindUnclassified
+}
+
+// Error is a failure of the prover classified by the taxonomy
+type Error struct {
+	Kind Kind
+	// Module names the part of the prover where the failure happened. For
+	// trace-limit overflows, this is the module whose limit overflowed.
+	Module string
+	// Count and Limit are the number of rows (or of instances) and the limit
+	// of the module. They are only set for trace-limit overflows.
+	Count, Limit int
+	// Err is the underlying error
+	Err error
+}
+
+// Errorf returns an [Error] of the given kind with a formatted message. The
+// format supports the %w verb.
+func Errorf(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// TraceLimitOverflow returns an error reporting that the module has more rows
+// (or instances) than its limit.
+func TraceLimitOverflow(module string, count, limit int) *Error {
+	return &Error{
+		Kind:   KindTraceLimit,
+		Module: module,
+		Count:  count,
+		Limit:  limit,
+		Err:    fmt.Errorf("limit overflow: module '%v' count=%v limit=%v", module, count, limit),
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// As returns the first [Error] in the chain of err
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// CodeOf returns the exit code the prover should return for err
+func CodeOf(err error) int {
+	if err == nil {
+		return CodeSuccess
+	}
+	if e, ok := As(err); ok {
+		return e.Kind.Code()
+	}
+	return CodeUnclassified
+}
+
+// Panic panics with an [Error] of the given kind. It is meant to replace
+// [utils.Panic] in the code paths where the failure can be classified. The
+// panic is converted back into an error by [Recover].
+func Panic(kind Kind, format string, args ...any) {
+	panic(Errorf(kind, format, args...))
+}
+
+// Recover converts a panic carrying an [Error] into an error. It must be
+// deferred directly. Other panics are propagated unchanged so that they keep
+// their stack trace.
+func Recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if rerr, ok := r.(error); ok {
+		if _, isTyped := As(rerr); isTyped {
+			*err = rerr
+			return
+		}
+	}
+	panic(r)
+}
+
+// PanicOnLimitOverflow panics with a [TraceLimitOverflow] error if count >
+// limit. It is used in the places where the error cannot be returned, for
+// instance when a limit overflows while the wizard prover is running.
+func PanicOnLimitOverflow(module string, count, limit int) {
+	if count > limit {
+		panic(TraceLimitOverflow(module, count, limit))
+	}
+}
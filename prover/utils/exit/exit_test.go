@@ -0,0 +1,83 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindCodes(t *testing.T) {
+
+	for kind, code := range kindCodes {
+		assert.Equalf(t, code, kind.Code(), "kind %v", kind)
+		assert.Equalf(t, kind, KindOf(code), "code %v", code)
+	}
+
+	assert.Equal(t, KindUnclassified, KindOf(CodeSuccess))
+	assert.Equal(t, KindUnclassified, KindOf(CodePanic))
+	assert.Equal(t, "_trace-limit", KindTraceLimit.Suffix())
+	assert.Equal(t, "", KindUnclassified.Suffix())
+	assert.True(t, KindInvalidRequest.IsDeterministic())
+	assert.False(t, KindTraceLimit.IsDeterministic())
+	assert.True(t, KindTraceLimitLarge.IsDeterministic())
+}
+
+func TestCodeOf(t *testing.T) {
+
+	var (
+		typed   = TraceLimitOverflow("MODEXP_256_BITS", 33, 32)
+		wrapped = fmt.Errorf("could not prove the execution: %w", typed)
+	)
+
+	assert.Equal(t, CodeSuccess, CodeOf(nil))
+	assert.Equal(t, CodeUnclassified, CodeOf(errors.New("untyped")))
+	assert.Equal(t, CodeTraceLimit, CodeOf(typed))
+	assert.Equal(t, CodeTraceLimit, CodeOf(wrapped))
+	assert.Equal(t, CodeSetupMismatch, CodeOf(Errorf(KindSetupMismatch, "no setup")))
+}
+
+func TestRecover(t *testing.T) {
+
+	run := func(f func()) (err error) {
+		defer Recover(&err)
+		f()
+		return nil
+	}
+
+	err := run(func() { Panic(KindStateMismatch, "root hash mismatch") })
+	assert.Equal(t, CodeStateMismatch, CodeOf(err))
+
+	err = run(func() { PanicOnLimitOverflow("KECCAKF", 9, 8) })
+	assert.Equal(t, CodeTraceLimit, CodeOf(err))
+	assert.NoError(t, run(func() { PanicOnLimitOverflow("KECCAKF", 8, 8) }))
+
+	// Untyped panics are not converted into errors
+	assert.Panics(t, func() {
+		_ = run(func() { panic("untyped") })
+	})
+}
+
+func TestFailureRoundTrip(t *testing.T) {
+
+	var (
+		path = filepath.Join(t.TempDir(), FailureFile("response.json"))
+		err  = fmt.Errorf("wrapped: %w", TraceLimitOverflow("KECCAKF", 10, 8))
+	)
+
+	require.NoError(t, WriteFailure(path, err))
+
+	f, rerr := ReadFailure(path)
+	require.NoError(t, rerr)
+	assert.Equal(t, &Failure{
+		Kind:     KindTraceLimit,
+		ExitCode: CodeTraceLimit,
+		Module:   "KECCAKF",
+		Count:    10,
+		Limit:    8,
+		Message:  err.Error(),
+	}, f)
+}
@@ -0,0 +1,74 @@
+package exit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Failure is the machine-readable report of a failure written by the prover
+// next to the response file.
+type Failure struct {
+	Kind     Kind   `json:"kind"`
+	ExitCode int    `json:"exitCode"`
+	Module   string `json:"module,omitempty"`
+	Count    int    `json:"count,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+	Message  string `json:"message"`
+}
+
+// FailureFile returns the path of the failure report corresponding to a
+// response file.
+func FailureFile(responseFile string) string {
+	return responseFile + ".failure.json"
+}
+
+// NewFailure returns the failure report of an error
+func NewFailure(err error) *Failure {
+
+	f := &Failure{
+		Kind:     KindUnclassified,
+		ExitCode: CodeOf(err),
+		Message:  err.Error(),
+	}
+
+	if e, ok := As(err); ok {
+		f.Kind = e.Kind
+		f.Module = e.Module
+		f.Count = e.Count
+		f.Limit = e.Limit
+	}
+
+	return f
+}
+
+// WriteFailure writes the failure report of err at path
+func WriteFailure(path string, err error) error {
+
+	b, jerr := json.MarshalIndent(NewFailure(err), "", "  ")
+	if jerr != nil {
+		return fmt.Errorf("could not marshal the failure report: %w", jerr)
+	}
+
+	if werr := os.WriteFile(path, b, 0600); werr != nil {
+		return fmt.Errorf("could not write the failure report: %w", werr)
+	}
+
+	return nil
+}
+
+// ReadFailure reads the failure report at path
+func ReadFailure(path string) (*Failure, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Failure{}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("could not parse the failure report %v: %w", path, err)
+	}
+
+	return f, nil
+}
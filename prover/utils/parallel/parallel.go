@@ -1,6 +1,7 @@
 package parallel
 
 import (
+	"fmt"
 	"runtime"
 	"runtime/debug"
 	"sync"
@@ -69,6 +70,11 @@ func Execute(nbIterations int, work func(int, int), maxCpus ...int) {
 	wg.Wait()
 
 	if len(panicTrace) > 0 {
+		// The errors are wrapped so that the typed failures (see the exit
+		// package) can still be recovered by the caller.
+		if err, ok := panicMsg.(error); ok {
+			panic(fmt.Errorf("had a panic: %w\nStack: %v", err, string(panicTrace)))
+		}
 		utils.Panic("Had a panic: %v\nStack: %v\n", panicMsg, string(panicTrace))
 	}
 }
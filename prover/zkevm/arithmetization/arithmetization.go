@@ -11,7 +11,9 @@ import (
 	"github.com/consensys/go-corset/pkg/util/collection/typed"
 	"github.com/consensys/linea-monorepo/prover/backend/files"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,6 +29,9 @@ type Settings struct {
 	// apply when compiling the zkevm.bin file to AIR constraints.  If in doubt,
 	// use mir.DEFAULT_OPTIMISATION_LEVEL.
 	OptimisationLevel *mir.OptimisationConfig
+	// ZkevmBin is the constraint binary compiled into the arithmetization. If
+	// nil, the binary embedded in the prover is used.
+	ZkevmBin *ZkevmBin
 }
 
 // Arithmetization exposes all the methods relevant for the user to interact
@@ -46,9 +51,16 @@ type Arithmetization struct {
 // NewArithmetization is the function that declares all the columns and the constraints of
 // the zkEVM in the input builder object.
 func NewArithmetization(builder *wizard.Builder, settings Settings) *Arithmetization {
-	schema, metadata, errS := ReadZkevmBin(settings.OptimisationLevel)
+	bin := settings.ZkevmBin
+	if bin == nil {
+		bin = EmbeddedZkevmBin()
+	}
+
+	// A binary the prover cannot handle is a deployment issue: it is reported
+	// before anything is compiled.
+	schema, metadata, errS := bin.Compile(settings.OptimisationLevel)
 	if errS != nil {
-		panic(errS)
+		exit.Panic(exit.KindSetupMismatch, "%w", errS)
 	}
 
 	Define(builder.CompiledIOP, schema, settings.Limits)
@@ -67,6 +79,13 @@ func NewArithmetization(builder *wizard.Builder, settings Settings) *Arithmetiza
 // computed columns with concrete values, such for determining multiplicative
 // inverses, etc.
 func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) {
+	a.AssignColumns(run, traceFile, nil)
+}
+
+// AssignColumns is as [Arithmetization.Assign] but only assigns the listed
+// columns, or all of them if columns is nil. The trace file is still parsed
+// and expanded in full and the limits of all the modules are checked.
+func (a *Arithmetization) AssignColumns(run *wizard.ProverRuntime, traceFile string, columns []ifaces.ColID) {
 	traceF := files.MustRead(traceFile)
 	// Parse trace file and extract raw column data.
 	rawColumns, metadata, errT := ReadLtTraces(traceF, a.Schema)
@@ -112,5 +131,5 @@ func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) {
 		logrus.Warnf("corset expansion gave the following errors: %v", errors.Join(errs...).Error())
 	}
 	// Passed
-	AssignFromLtTraces(run, a.Schema, expandedTrace, a.Settings.Limits)
+	assignFromLtTraces(run, a.Schema, expandedTrace, mapModuleLimits(a.Settings.Limits), columns)
 }
@@ -3,7 +3,6 @@ package arithmetization
 import (
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/consensys/go-corset/pkg/air"
 	"github.com/consensys/go-corset/pkg/trace"
@@ -12,20 +11,30 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/sirupsen/logrus"
 )
 
 // ReadExpandedTraces parses the provided trace file, expands it and returns the
 // corset object holding the expanded traces.
 func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) {
+	assignFromLtTraces(run, schema, expTraces, mapModuleLimits(limits), nil)
+}
+
+// assignFromLtTraces assigns the columns given the limits of the modules,
+// keyed by their corset name. If columns is not nil, only the listed columns
+// are assigned along with the sources of the interleaved ones. The limits of
+// all the modules are checked in any case.
+func assignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace, moduleLimits map[string]int, columns []ifaces.ColID) {
 
 	// This loops checks the module assignment to see if we have created a 77
 	// error.
 	var (
-		modules      = expTraces.Modules().Collect()
-		moduleLimits = mapModuleLimits(limits)
-		err77        error
-		numCols      = expTraces.Width()
+		modules    = expTraces.Modules().Collect()
+		err77      error
+		worst      *exit.Error
+		worstRatio float64
+		numCols    = expTraces.Width()
 	)
 
 	for _, module := range modules {
@@ -41,14 +50,37 @@ func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces
 		if uint(limit) < height {
 			level = logrus.ErrorLevel
 			err77 = errors.Join(err77, fmt.Errorf("limit overflow: module '%s' overflows its limit height=%v limit=%v ratio=%v", name, height, limit, ratio))
+
+			// The failure report only names the module with the largest
+			// overflow, the message lists all of them.
+			if worst == nil || ratio > worstRatio {
+				worst = exit.TraceLimitOverflow(name, int(height), limit)
+				worstRatio = ratio
+			}
 		}
 
 		logrus.StandardLogger().Logf(level, "module utilization module=%v height=%v limit=%v ratio=%v", name, height, limit, ratio)
 	}
 
 	if err77 != nil {
-		logrus.Errorf("Error code 77 %v", err77)
-		os.Exit(TraceOverflowExitCode)
+		// The panic is converted back into an error by [exit.Recover]
+		worst.Err = err77
+		panic(worst)
+	}
+
+	// The interleaved columns are computed from their sources as assigned in
+	// the wizard, see [schemaScanner.addInterleavingInComp].
+	interleavings := interleavedSources(schema)
+
+	var keep map[ifaces.ColID]struct{}
+	if columns != nil {
+		keep = make(map[ifaces.ColID]struct{}, len(columns))
+		for _, name := range columns {
+			keep[name] = struct{}{}
+			for _, source := range interleavings[name] {
+				keep[source] = struct{}{}
+			}
+		}
 	}
 
 	for id := uint(0); id < numCols; id++ {
@@ -66,6 +98,15 @@ func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces
 			continue
 		}
 
+		if _, ok := keep[name]; keep != nil && !ok {
+			continue
+		}
+
+		if sources, ok := interleavings[name]; ok {
+			assignInterleaved(run, name, sources)
+			continue
+		}
+
 		for i := range plain {
 			plain[i] = data.Get(uint(i))
 		}
@@ -0,0 +1,327 @@
+package arithmetization
+
+import (
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+)
+
+// countPrecompiles adds to res the counts of the precompile calls found in
+// the trace, keyed by the name of their limit.
+func countPrecompiles(cols traceColumns, res map[string]int) error {
+
+	counters := []struct {
+		names []string
+		count func(cols traceColumns) ([]int, error)
+	}{
+		{
+			names: []string{"PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS"},
+			count: countCallStarts("ecdata.CIRCUIT_SELECTOR_ECRECOVER", "ecdata.IS_ECRECOVER_DATA"),
+		},
+		{
+			names: []string{"PRECOMPILE_ECADD_EFFECTIVE_CALLS"},
+			count: countCallStarts("ecdata.CIRCUIT_SELECTOR_ECADD", "ecdata.IS_ECADD_DATA"),
+		},
+		{
+			names: []string{"PRECOMPILE_ECMUL_EFFECTIVE_CALLS"},
+			count: countCallStarts("ecdata.CIRCUIT_SELECTOR_ECMUL", "ecdata.IS_ECMUL_DATA"),
+		},
+		{
+			names: []string{
+				"PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS",
+				"PRECOMPILE_ECPAIRING_MILLER_LOOPS",
+				"PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS",
+			},
+			count: countEcPairing,
+		},
+		{
+			names: []string{"PRECOMPILE_SHA2_BLOCKS"},
+			count: countMd4LikeBlocks("shakiradata.IS_SHA2_DATA", generic.Sha2Usecase),
+		},
+		{
+			names: []string{"PRECOMPILE_RIPEMD_BLOCKS"},
+			count: countMd4LikeBlocks("shakiradata.IS_RIPEMD_DATA", generic.RipemdUsecase),
+		},
+		{
+			names: []string{"PRECOMPILE_MODEXP_EFFECTIVE_CALLS"},
+			count: countModexp,
+		},
+		{
+			names: []string{"PRECOMPILE_BLAKE_EFFECTIVE_CALLS", "PRECOMPILE_BLAKE_ROUNDS"},
+			count: countBlake,
+		},
+		{
+			names: []string{"BLOCK_KECCAK"},
+			count: countKeccakF,
+		},
+	}
+
+	for _, c := range counters {
+		counts, err := c.count(cols)
+		if err != nil {
+			return err
+		}
+		for i := range c.names {
+			res[c.names[i]] = counts[i]
+		}
+	}
+
+	return nil
+}
+
+// countCallStarts returns a counter for the number of calls of an elliptic
+// curve precompile that are sent to the circuit. A call is counted on its
+// first data row.
+func countCallStarts(selector, isData string) func(cols traceColumns) ([]int, error) {
+	return func(cols traceColumns) ([]int, error) {
+
+		c, err := cols.getAll(selector, isData, "ecdata.INDEX")
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			cs, data, index = c[0], c[1], c[2]
+			count           = 0
+		)
+
+		for i := range cs {
+			if cs[i].IsOne() && data[i].IsOne() && index[i].IsZero() {
+				count++
+			}
+		}
+
+		return []int{count}, nil
+	}
+}
+
+// countEcPairing returns the number of final exponentiations, Miller loops and
+// G2 membership checks. This follows the assignment of the ecpair module: the
+// trivial pairs are skipped and the last non-trivial pair of each call is
+// processed by the final exponentiation circuit.
+func countEcPairing(cols traceColumns) ([]int, error) {
+
+	c, err := cols.getAll(
+		"ecdata.CIRCUIT_SELECTOR_ECPAIRING",
+		"ecdata.IS_ECPAIRING_RESULT",
+		"ecdata.CIRCUIT_SELECTOR_G2_MEMBERSHIP",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		csPairing, isRes, csG2     = c[0], c[1], c[2]
+		nbG1Limbs, nbG2Limbs       = ecpair.PointLimbs()
+		nbPairLimbs                = nbG1Limbs + nbG2Limbs
+		finalExps, millerLoops, g2 = 0, 0, 0
+	)
+
+	for curr := 0; curr < len(csPairing); {
+
+		if csPairing[curr].IsZero() {
+			curr++
+			continue
+		}
+
+		nbInputs, nbActual := 1, 1
+		for curr+nbInputs*nbPairLimbs < len(isRes) && !isRes[curr+nbInputs*nbPairLimbs].IsOne() {
+			if csPairing[curr+nbInputs*nbPairLimbs].IsOne() {
+				nbActual++
+			}
+			nbInputs++
+		}
+
+		finalExps++
+		millerLoops += nbActual - 1
+		curr += nbInputs*nbPairLimbs + 2
+	}
+
+	for curr := 0; curr < len(csG2); {
+
+		if csG2[curr].IsZero() {
+			curr++
+			continue
+		}
+
+		g2++
+		curr += nbG2Limbs
+	}
+
+	return []int{finalExps, millerLoops, g2}, nil
+}
+
+// countMd4LikeBlocks returns a counter for the number of blocks processed
+// by SHA2 or RIPEMD-160 for the hashes flagged by isData in shakiradata. Both
+// hash functions append at least 9 bytes of padding to the message.
+func countMd4LikeBlocks(isData string, usecase generic.HashingUsecase) func(cols traceColumns) ([]int, error) {
+	return func(cols traceColumns) ([]int, error) {
+
+		lens, err := streamLengths(cols, isData, "shakiradata.INDEX", "shakiradata.nBYTES")
+		if err != nil {
+			return nil, err
+		}
+
+		count := 0
+		for _, n := range lens {
+			count += 1 + (n+8)/usecase.BlockSizeBytes()
+		}
+
+		return []int{count}, nil
+	}
+}
+
+// countModexp returns the number of modexp calls whose operands fit in 256
+// bits. The larger calls are checked against a limit that is not part of the
+// configuration.
+func countModexp(cols traceColumns) ([]int, error) {
+
+	c, err := cols.getAll(
+		"blake2fmodexpdata.IS_MODEXP_BASE",
+		"blake2fmodexpdata.IS_MODEXP_EXPONENT",
+		"blake2fmodexpdata.IS_MODEXP_MODULUS",
+		"blake2fmodexpdata.IS_MODEXP_RESULT",
+		"blake2fmodexpdata.LIMB",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		limbs                                = c[4]
+		count                                = 0
+		nbRows, nbLimbsPerOp, nbLimbsSmallOp = modexp.InstanceLayout()
+	)
+
+	// An instance is large if any of its operands has a non-zero limb before
+	// its last nbLimbsSmallOp limbs.
+	isLarge := func(start int) bool {
+		for k := 0; k < nbRows; k++ {
+			if k%nbLimbsPerOp < nbLimbsPerOp-nbLimbsSmallOp && !limbs[start+k].IsZero() {
+				return true
+			}
+		}
+		return false
+	}
+
+	isModexp := func(row int) bool {
+		return !c[0][row].IsZero() || !c[1][row].IsZero() || !c[2][row].IsZero() || !c[3][row].IsZero()
+	}
+
+	for curr := 0; curr+nbRows <= len(limbs); {
+
+		if !isModexp(curr) {
+			curr++
+			continue
+		}
+
+		if !isLarge(curr) {
+			count++
+		}
+
+		curr += nbRows
+	}
+
+	return []int{count}, nil
+}
+
+// countBlake returns the number of BLAKE2f calls and the total number of
+// rounds. A call with zero rounds still counts for one round.
+func countBlake(cols traceColumns) ([]int, error) {
+
+	c, err := cols.getAll("blake2fmodexpdata.IS_BLAKE_PARAMS", "blake2fmodexpdata.LIMB")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		isParams, limbs = c[0], c[1]
+		calls, rounds   = 0, 0
+	)
+
+	// The parameters of a call span two rows: the number of rounds and the
+	// final block flag.
+	for i := range isParams {
+		if isParams[i].IsOne() && (i == 0 || isParams[i-1].IsZero()) {
+			calls++
+			rounds += max(int(limbs[i].Uint64()), 1)
+		}
+	}
+
+	return []int{calls, rounds}, nil
+}
+
+// countKeccakF returns the number of keccakf permutations. It accounts for
+// all the providers of the keccak module: shakiradata, rlpaddr, the hashes of
+// the transactions and the hashes of the public keys recovered by ecrecover
+// and by the transaction signatures.
+func countKeccakF(cols traceColumns) ([]int, error) {
+
+	var (
+		count   = 0
+		sources = [][3]string{
+			{"shakiradata.IS_KECCAK_DATA", "shakiradata.INDEX", "shakiradata.nBYTES"},
+			{"rlpaddr.LC", "rlpaddr.INDEX", "rlpaddr.nBYTES"},
+			{"rlptxn.TO_HASH_BY_PROVER", "rlptxn.INDEX_LX", "rlptxn.nBYTES"},
+		}
+		nbTx int
+	)
+
+	for i, s := range sources {
+
+		lens, err := streamLengths(cols, s[0], s[1], s[2])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range lens {
+			count += 1 + n/generic.KeccakUsecase.BlockSizeBytes()
+		}
+
+		if i == len(sources)-1 {
+			nbTx = len(lens)
+		}
+	}
+
+	ecrecover, err := countCallStarts("ecdata.CIRCUIT_SELECTOR_ECRECOVER", "ecdata.IS_ECRECOVER_DATA")(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	// A public key is 64 bytes long and is thus hashed in a single
+	// permutation.
+	count += ecrecover[0] + nbTx
+
+	return []int{count}, nil
+}
+
+// streamLengths returns the length in bytes of the streams hashed by a
+// provider of the arithmetization. The streams are delimited in the same way
+// as in [generic.GenDataModule.ScanStreams].
+func streamLengths(cols traceColumns, toHash, index, nBytes string) ([]int, error) {
+
+	c, err := cols.getAll(toHash, index, nBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		isHashed, idx, nb = c[0], c[1], c[2]
+		res               = []int{}
+	)
+
+	for row := range isHashed {
+
+		if isHashed[row].IsZero() {
+			continue
+		}
+
+		if idx[row].IsZero() || len(res) == 0 {
+			res = append(res, 0)
+		}
+
+		res[len(res)-1] += int(nb[row].Uint64())
+	}
+
+	return res, nil
+}
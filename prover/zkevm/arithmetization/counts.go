@@ -0,0 +1,148 @@
+package arithmetization
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/sirupsen/logrus"
+)
+
+// CountLtTraces reads and expands the provided trace file and counts what is
+// checked against the [config.TracesLimits] when proving it: the number of
+// rows of each module and the number of precompile calls (or blocks, rounds,
+// etc.) that are sent to the dedicated circuits, including the number of
+// keccakf permutations.
+//
+// The counts are keyed by the name of the corresponding limit in the
+// configuration file (e.g. "HUB" or "PRECOMPILE_SHA2_BLOCKS"). A module that
+// has no corresponding limit is keyed by its corset name in upper-case.
+//
+// The limits that depend on the request rather than on the trace file (e.g.
+// "BLOCK_TRANSACTIONS" or "SHOMEI_MERKLE_PROOFS") are not counted.
+func CountLtTraces(sch *air.Schema, traceFile string) (map[string]int, error) {
+
+	f, err := os.Open(traceFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the trace file: %w", err)
+	}
+
+	rawColumns, _, err := ReadLtTraces(f, sch)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the trace file %v: %w", traceFile, err)
+	}
+
+	expTraces, errs := schema.NewTraceBuilder(sch).Build(rawColumns)
+	if len(errs) > 0 {
+		logrus.Warnf("corset expansion gave the following errors: %v", errors.Join(errs...).Error())
+	}
+
+	var (
+		res        = make(map[string]int)
+		limitNames = moduleLimitNames()
+		modules    = expTraces.Modules().Collect()
+		cols       = newTraceColumns(sch, expTraces)
+	)
+
+	for _, module := range modules {
+		name, ok := limitNames[module.Name()]
+		if !ok {
+			name = strings.ToUpper(module.Name())
+		}
+		res[name] = int(module.Height())
+	}
+
+	if err := countPrecompiles(cols, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// moduleLimitNames maps the name of the corset modules to the name of their
+// limit in the configuration file. It follows the same convention as
+// [mapModuleLimits].
+func moduleLimitNames() map[string]string {
+
+	var (
+		res = make(map[string]string, 100)
+		typ = reflect.TypeOf(config.TracesLimits{})
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		var (
+			field     = typ.Field(i)
+			corsetTag = field.Tag.Get("corset")
+		)
+
+		if len(corsetTag) == 0 {
+			corsetTag = strings.ToLower(field.Name)
+		}
+
+		res[corsetTag] = field.Tag.Get("mapstructure")
+	}
+
+	return res
+}
+
+// traceColumns gives access to the columns of an expanded trace by their
+// wizard name (e.g. "ecdata.LIMB").
+type traceColumns map[string]trace.Column
+
+func newTraceColumns(sch *air.Schema, expTraces trace.Trace) traceColumns {
+
+	res := make(traceColumns, expTraces.Width())
+
+	for id := uint(0); id < expTraces.Width(); id++ {
+		col := expTraces.Column(id)
+		res[wizardName(getModuleName(sch, col), col.Name())] = col
+	}
+
+	return res
+}
+
+// get returns the data of the column without the padding. It returns an error
+// if the column does not exist, which means that the constraint system is not
+// the one expected by the counting functions.
+func (tc traceColumns) get(name string) ([]field.Element, error) {
+
+	col, ok := tc[name]
+	if !ok {
+		return nil, fmt.Errorf("column %v is missing from the trace", name)
+	}
+
+	var (
+		data = col.Data()
+		res  = make([]field.Element, data.Len())
+	)
+
+	for i := range res {
+		res[i] = data.Get(uint(i))
+	}
+
+	return res, nil
+}
+
+// getAll returns the data of several columns of the same module
+func (tc traceColumns) getAll(names ...string) ([][]field.Element, error) {
+
+	res := make([][]field.Element, len(names))
+
+	for i := range names {
+		col, err := tc.get(names[i])
+		if err != nil {
+			return nil, err
+		}
+		res[i] = col
+	}
+
+	return res, nil
+}
@@ -0,0 +1,36 @@
+package arithmetization
+
+import (
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCountPrecompilesColumns checks that all the columns read by the
+// precompile counters exist in the constraint system and that an empty trace
+// counts no call.
+func TestCountPrecompilesColumns(t *testing.T) {
+
+	sch, _, err := ReadZkevmBin(&mir.DEFAULT_OPTIMISATION_LEVEL)
+	require.NoError(t, err)
+
+	// The expansion errors are expected as the trace is empty
+	expTraces, _ := schema.NewTraceBuilder(sch).Build([]trace.RawColumn{})
+
+	var (
+		cols = newTraceColumns(sch, expTraces)
+		res  = make(map[string]int)
+	)
+
+	require.NoError(t, countPrecompiles(cols, res))
+
+	for name, count := range res {
+		require.Zerof(t, count, "limit %v", name)
+	}
+
+	require.Contains(t, res, "BLOCK_KECCAK")
+	require.Contains(t, res, "PRECOMPILE_ECPAIRING_MILLER_LOOPS")
+}
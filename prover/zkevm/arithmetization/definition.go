@@ -1,6 +1,7 @@
 package arithmetization
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/consensys/go-corset/pkg/trace"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/distributed"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/symbolic"
@@ -32,9 +34,15 @@ type schemaScanner struct {
 // Define registers the arithmetization from a corset air.Schema and trace limits
 // from config.
 func Define(comp *wizard.CompiledIOP, schema *air.Schema, limits *config.TracesLimits) {
+	define(comp, schema, mapModuleLimits(limits))
+}
+
+// define registers the arithmetization given the limits of the modules, keyed
+// by their corset name.
+func define(comp *wizard.CompiledIOP, schema *air.Schema, limitMap map[string]int) {
 
 	scanner := &schemaScanner{
-		LimitMap:           mapModuleLimits(limits),
+		LimitMap:           limitMap,
 		Comp:               comp,
 		Schema:             schema,
 		Modules:            schema.Modules().Collect(),
@@ -42,9 +50,52 @@ func Define(comp *wizard.CompiledIOP, schema *air.Schema, limits *config.TracesL
 	}
 
 	scanner.scanColumns()
+	scanner.scanInterleavings()
 	scanner.scanConstraints()
 }
 
+// CheckSupported returns an error if the schema uses a kind of constraint or
+// of assignment that the arithmetization cannot translate into the wizard. It
+// is meant to reject a constraint binary when it is loaded, before anything is
+// compiled or proven.
+//
+// The sorted constraints, sorted permutations, lexicographic sorts and byte
+// decompositions never reach AIR as such: corset lowers them into vanishing,
+// range and permutation constraints over computed columns. The columns of all
+// the assignments are then assigned from the expanded traces.
+func CheckSupported(schema *air.Schema) error {
+
+	var errs []error
+
+	for it := schema.Assignments(); it.HasNext(); {
+		switch a := it.Next().(type) {
+		case *assignment.Interleaving,
+			*assignment.SortedPermutation,
+			*assignment.LexicographicSort,
+			*assignment.ComputedColumn,
+			*assignment.ByteDecomposition,
+			*assignment.Computation:
+		default:
+			errs = append(errs, fmt.Errorf("unsupported assignment type: %T", a))
+		}
+	}
+
+	for it := schema.Constraints(); it.HasNext(); {
+		switch cs := it.Next().(type) {
+		case air.LookupConstraint,
+			air.PermutationConstraint,
+			air.VanishingConstraint,
+			air.RangeConstraint:
+		case *constraint.SortedConstraint[air.Expr]:
+			errs = append(errs, fmt.Errorf("the sorted constraint %v was not lowered to AIR", cs.Handle))
+		default:
+			errs = append(errs, fmt.Errorf("unsupported constraint type: %T", cs))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // scanColumns scans the column declaration of the corset [air.Schema] into the
 // [wizard.CompiledIOP] object.
 func (s *schemaScanner) scanColumns() {
@@ -116,10 +167,9 @@ func (s *schemaScanner) addConstraintInComp(name string, corsetCS schema.Constra
 			wTargets = make([]ifaces.Column, numCol)
 		)
 
-		// this will panic over interleaved columns, we can debug that later
 		for i := 0; i < numCol; i++ {
-			wSources[i] = s.compColumnByCorsetID(cSources[i].Column)
-			wTargets[i] = s.compColumnByCorsetID(cTargets[i].Column)
+			wSources[i] = s.compColumnAccess(cSources[i])
+			wTargets[i] = s.compColumnAccess(cTargets[i])
 		}
 
 		s.Comp.InsertInclusion(0, ifaces.QueryID(name), wTargets, wSources)
@@ -134,7 +184,6 @@ func (s *schemaScanner) addConstraintInComp(name string, corsetCS schema.Constra
 			wTargets = make([]ifaces.Column, numCol)
 		)
 
-		// this will panic over interleaved columns, we can debug that later
 		for i := 0; i < numCol; i++ {
 			wSources[i] = s.compColumnByCorsetID(cSources[i])
 			wTargets[i] = s.compColumnByCorsetID(cTargets[i])
@@ -186,10 +235,11 @@ func (s *schemaScanner) addConstraintInComp(name string, corsetCS schema.Constra
 
 		bound := cs.Bound
 		// #nosec G115 -- this bound will not overflow
-		s.Comp.InsertRange(0, ifaces.QueryID(name), s.compColumnByCorsetID(cs.Expr.Column), int(bound.Uint64()))
+		s.Comp.InsertRange(0, ifaces.QueryID(name), s.compColumnAccess(cs.Expr), int(bound.Uint64()))
 
 	default:
 
+		// Rejected beforehand by [CheckSupported]
 		utils.Panic("unexpected constraint type: %T", cs)
 	}
 }
@@ -230,11 +280,7 @@ func (s *schemaScanner) castExpression(expr air.Term) *symbolic.Expression {
 
 	case *air.ColumnAccess:
 
-		c := s.compColumnByCorsetID(e.Column)
-		if e.Shift != 0 {
-			c = column.Shift(c, e.Shift)
-		}
-		return symbolic.NewVariable(c)
+		return symbolic.NewVariable(s.compColumnAccess(e))
 
 	default:
 		eStr := fmt.Sprintf("%v", e)
@@ -270,16 +316,35 @@ func wizardName(moduleName, objectName string) string {
 	return moduleName + "." + objectName
 }
 
+// ModuleOf returns the name of the corset module of a column declared by the
+// arithmetization, as given by the prefix of its wizard name. It is meant to
+// be used as the [distributed.ModuleDiscoverer] of the arithmetization.
+func ModuleOf(col ifaces.ColID) distributed.ModuleName {
+	module, _, _ := strings.Cut(string(col), ".")
+	return distributed.ModuleName(module)
+}
+
 // compColumnByCorsetID returns an [ifaces.Column] that has already been
 // registered inside of the [wizard.CompiledIOP] from its index in the corset
 // [air.Schema].
 func (s *schemaScanner) compColumnByCorsetID(corsetID uint) ifaces.Column {
-	var (
-		cCol  = s.Schema.Columns().Nth(corsetID)
-		cName = ifaces.ColID(wizardName(getModuleNameFromColumn(s.Schema, cCol), cCol.Name))
-		wCol  = s.Comp.Columns.GetHandle(cName)
-	)
-	return wCol
+	return s.compColumn(s.Schema.Columns().Nth(corsetID))
+}
+
+// compColumn returns the [ifaces.Column] registered for a corset column
+func (s *schemaScanner) compColumn(cCol schema.Column) ifaces.Column {
+	cName := ifaces.ColID(wizardName(getModuleNameFromColumn(s.Schema, cCol), cCol.Name))
+	return s.Comp.Columns.GetHandle(cName)
+}
+
+// compColumnAccess returns the [ifaces.Column] registered for the column
+// accessed by a corset term, shifted as in the access.
+func (s *schemaScanner) compColumnAccess(access *air.ColumnAccess) ifaces.Column {
+	c := s.compColumnByCorsetID(access.Column)
+	if access.Shift != 0 {
+		c = column.Shift(c, access.Shift)
+	}
+	return c
 }
 
 // mapModuleLimits returns a map of the module by limit in lower-case.
@@ -0,0 +1,90 @@
+package arithmetization
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/distributed"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistributedArithmetization(t *testing.T) {
+
+	var (
+		schema = compileTestSchema(t, `(defcolumns (X :i16) (Y :i16))
+			(defconstraint c1 () (vanishes! (- Y (* 2 X))))
+			(module other)
+			(defcolumns (T :i16) (U :i16))
+			(defconstraint c2 () (vanishes! (- U (* 2 T))))
+			(deflookup l1 (other.T other.U) (test.X test.Y))`)
+		traces = expandTestTraces(t, schema, map[string][]int64{
+			"X":       {1, 2, 3},
+			"Y":       {2, 4, 6},
+			"other.T": {3, 2, 1, 0, 4},
+			"other.U": {6, 4, 2, 0, 8},
+		})
+		comp = wizard.Compile(func(b *wizard.Builder) { define(b.CompiledIOP, schema, testLimits) })
+		dw   = distributed.Distribute(comp, ModuleOf, wizard.VersionMetadata{}, dummy.Compile)
+	)
+
+	require.Equal(t, []distributed.ModuleName{"other", "test"}, dw.SegmentNames())
+	assert.Len(t, dw.Lookups, 1)
+
+	proofs := dw.Prove(func(run *wizard.ProverRuntime) { assignFromLtTraces(run, schema, traces, testLimits, nil) })
+	require.NoError(t, dw.Verify(proofs))
+
+	// Every segment only assigns the columns it requires
+	var (
+		beacon = distributed.NewLocalBeacon(len(dw.Segments))
+		mu     = &sync.Mutex{}
+		wg     = &sync.WaitGroup{}
+	)
+
+	proofs = map[distributed.ModuleName]wizard.Proof{}
+	wg.Add(len(dw.Segments))
+	for _, seg := range dw.Segments {
+		go func(seg distributed.ModuleName) {
+			defer wg.Done()
+			proof := dw.ProveSegment(seg, func(run *wizard.ProverRuntime, columns []ifaces.ColID) {
+				assignFromLtTraces(run, schema, traces, testLimits, columns)
+			}, beacon)
+			mu.Lock()
+			proofs[seg] = proof
+			mu.Unlock()
+		}(seg.Name)
+	}
+	wg.Wait()
+
+	require.NoError(t, dw.Verify(proofs))
+}
+
+// Checks that the constraints of the zkevm.bin can be split in segments
+func TestDistributeZkevmBin(t *testing.T) {
+
+	var (
+		schema, _, errBin = ReadZkevmBin(&mir.DEFAULT_OPTIMISATION_LEVEL)
+		limits            = &config.TracesLimits{}
+		limitRefl         = reflect.ValueOf(limits).Elem()
+	)
+
+	for i := 0; i < limitRefl.NumField(); i++ {
+		limitRefl.Field(i).SetInt(1 << 10)
+	}
+
+	require.NoError(t, errBin)
+
+	var (
+		comp = wizard.Compile(func(b *wizard.Builder) { Define(b.CompiledIOP, schema, limits) })
+		dw   = distributed.Distribute(comp, ModuleOf, wizard.VersionMetadata{}, dummy.Compile)
+	)
+
+	assert.Greater(t, len(dw.Segments), 1)
+	assert.NotEmpty(t, dw.Lookups)
+}
@@ -2,63 +2,33 @@ package arithmetization
 
 import (
 	_ "embed"
-	"encoding/gob"
 	"errors"
 	"fmt"
 	"io"
 
 	"github.com/consensys/go-corset/pkg/air"
-	"github.com/consensys/go-corset/pkg/binfile"
-	"github.com/consensys/go-corset/pkg/corset"
 	"github.com/consensys/go-corset/pkg/mir"
 	"github.com/consensys/go-corset/pkg/trace"
 	"github.com/consensys/go-corset/pkg/trace/lt"
 	"github.com/consensys/go-corset/pkg/util/collection/typed"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 )
 
-const TraceOverflowExitCode = 77
+// TraceOverflowExitCode is the exit code of the prover when a module overflows
+// its limit.
+const TraceOverflowExitCode = exit.CodeTraceLimit
 
 // Embed the whole constraint system at compile time, so no
-// more need to keep it in sync
+// more need to keep it in sync. It can be overridden at runtime, see
+// [SelectZkevmBin].
 //
 //go:embed zkevm.bin
 var zkevmStr string
 
-// ReadZkevmBin parses and compiles a "zkevm.bin" file into an air.Schema,
-// whilst applying whatever optimisations are requested. Optimisations can
-// impact the size of the generated schema and, consequently, the size of the
-// expanded trace.  For example, certain optimisations eliminate unnecessary
-// columns creates for multiplicative inverses.  However, optimisations do not
-// always improve overall performance, as they can increase the complexity of
-// other constraints.  The DEFAULT_OPTIMISATION_LEVEL is the recommended level
-// to use in general, whilst others are intended for testing purposes (i.e. to
-// try out new optimisations to see whether they help or hinder, etc).
-//
-// This additionally extracts the metadata map from the zkevm.bin file.  This
-// contains information which can be used to cross-check the zkevm.bin file,
-// such as the git commit of the enclosing repository when it was built.
+// ReadZkevmBin parses and compiles the "zkevm.bin" file embedded in the
+// prover into an air.Schema. See [ZkevmBin.Compile].
 func ReadZkevmBin(optConfig *mir.OptimisationConfig) (schema *air.Schema, metadata typed.Map, err error) {
-	var (
-		binf binfile.BinaryFile
-		buf  []byte = []byte(zkevmStr)
-	)
-	// TODO: why is only this one needed??
-	gob.Register(binfile.Attribute(&corset.SourceMap{}))
-	// Parse zkbinary file
-	err = binf.UnmarshalBinary(buf)
-	// Sanity check for errors
-	if err != nil {
-		return nil, metadata, fmt.Errorf("could not parse the read bytes of the 'zkevm.bin' file into an hir.Schema: %w", err)
-	}
-	// Extract schema
-	hirSchema := &binf.Schema
-	// Attempt to extract metadata from bin file, and sanity check constraints
-	// commit information is available.
-	if metadata, err = binf.Header.GetMetaData(); metadata.IsEmpty() {
-		return nil, metadata, errors.New("missing metatdata from 'zkevm.bin' file")
-	}
-	// This performs the corset compilation
-	return hirSchema.LowerToMir().LowerToAir(*optConfig), metadata, err
+	return EmbeddedZkevmBin().Compile(optConfig)
 }
 
 // ReadLtTraces reads a given LT trace file which contains (unexpanded) column
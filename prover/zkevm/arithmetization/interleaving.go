@@ -0,0 +1,158 @@
+package arithmetization
+
+import (
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/schema/assignment"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// scanInterleavings constrains the interleaved columns of the schema. Corset
+// does not emit any constraint for them as it computes them itself during the
+// expansion; but on the wizard side they are committed like any other column
+// and must be tied to their sources.
+func (s *schemaScanner) scanInterleavings() {
+	for it := s.Schema.Assignments(); it.HasNext(); {
+		if il, isIL := it.Next().(*assignment.Interleaving); isIL {
+			s.addInterleavingInComp(il)
+		}
+	}
+}
+
+// addInterleavingInComp constrains the interleaved column Z of k sources X_i.
+// Z being right-aligned like the other columns, we have
+//
+//	Z[offset + j*k + i] = X_i[j]	with offset = |Z| - k*|X_i|
+//
+// which is enforced by looking up the rows (offset + j*k + i, X_i[j]) in the
+// table (r, Z[r]). As the row indices are unique in the table, the lookups
+// pin the value of every row of Z past the offset. The rows before the offset,
+// which exist when k is not a power of two, are the padding of Z. Corset pads
+// the interleaved columns like the first column of the trace, an input column
+// padded with zeroes, so a global constraint sets them to zero using a
+// precomputed selector of the padding rows. The constraints only use round-0
+// queries so that they stay within the module of Z when the wizard is
+// segmented by modules.
+func (s *schemaScanner) addInterleavingInComp(il *assignment.Interleaving) {
+
+	var (
+		target  = s.compColumn(il.Target)
+		sources = make([]ifaces.Column, len(il.Sources))
+	)
+
+	for i := range il.Sources {
+		sources[i] = s.compColumnByCorsetID(il.Sources[i])
+	}
+
+	var (
+		k = len(sources)
+		n = ifaces.AssertSameLength(sources...)
+		m = target.Size()
+	)
+
+	if k*n > m {
+		utils.Panic("cannot interleave %v columns of size %v into a column of size %v", k, n, m)
+	}
+
+	targetIdx := s.interleavingIndex(1, m, m, 0)
+	for i := range sources {
+		s.Comp.InsertInclusion(
+			0,
+			ifaces.QueryIDf("%v_INTERLEAVING_%v", target.GetColID(), i),
+			[]ifaces.Column{targetIdx, target},
+			[]ifaces.Column{s.interleavingIndex(k, n, m, i), sources[i]},
+		)
+	}
+
+	if offset := m - k*n; offset > 0 {
+		s.Comp.InsertGlobal(
+			0,
+			ifaces.QueryIDf("%v_INTERLEAVING_PADDING", target.GetColID()),
+			symbolic.Mul(s.interleavingPadding(m, offset), target),
+		)
+	}
+}
+
+// interleavingIndex returns the precomputed column of size n storing the rows
+// offset + j*k + i, for j < n, of an interleaving of k columns of size n into
+// a column of size m. With k = 1 and n = m, this is the identity. The columns
+// are shared by the interleavings of the same shape.
+func (s *schemaScanner) interleavingIndex(k, n, m, i int) ifaces.Column {
+
+	name := ifaces.ColIDf("ARITHMETIZATION_INTERLEAVING_INDEX_%v_%v_%v_%v", k, n, m, i)
+	if s.Comp.Columns.Exists(name) {
+		return s.Comp.Columns.GetHandle(name)
+	}
+
+	var (
+		offset = m - k*n
+		index  = make([]field.Element, n)
+	)
+
+	for j := range index {
+		index[j].SetInt64(int64(offset + j*k + i))
+	}
+
+	return s.Comp.InsertPrecomputed(name, smartvectors.NewRegular(index))
+}
+
+// interleavingPadding returns the precomputed column of size m selecting its
+// first offset rows, i.e. the padding rows of an interleaved column. The
+// columns are shared by the interleavings of the same shape.
+func (s *schemaScanner) interleavingPadding(m, offset int) ifaces.Column {
+
+	name := ifaces.ColIDf("ARITHMETIZATION_INTERLEAVING_PADDING_%v_%v", m, offset)
+	if s.Comp.Columns.Exists(name) {
+		return s.Comp.Columns.GetHandle(name)
+	}
+
+	return s.Comp.InsertPrecomputed(name, smartvectors.RightZeroPadded(vector.Repeat(field.One(), offset), m))
+}
+
+// assignInterleaved assigns the interleaved column from its sources, which
+// must already be assigned. The leading rows not covered by the interleaving
+// are the padding of the column and are zero, see
+// [schemaScanner.addInterleavingInComp].
+func assignInterleaved(run *wizard.ProverRuntime, name ifaces.ColID, sources []ifaces.ColID) {
+
+	var (
+		size = run.Spec.Columns.GetHandle(name).Size()
+		vecs = make([][]field.Element, len(sources))
+	)
+
+	for i := range sources {
+		vecs[i] = run.GetColumn(sources[i]).IntoRegVecSaveAlloc()
+	}
+
+	run.AssignColumn(name, smartvectors.LeftZeroPadded(vector.Interleave(vecs...), size))
+}
+
+// interleavedSources maps the interleaved columns of the schema to their
+// sources, by wizard names.
+func interleavedSources(sch *air.Schema) map[ifaces.ColID][]ifaces.ColID {
+
+	res := map[ifaces.ColID][]ifaces.ColID{}
+
+	for it := sch.Assignments(); it.HasNext(); {
+
+		il, isIL := it.Next().(*assignment.Interleaving)
+		if !isIL {
+			continue
+		}
+
+		sources := make([]ifaces.ColID, len(il.Sources))
+		for i := range il.Sources {
+			col := sch.Columns().Nth(il.Sources[i])
+			sources[i] = ifaces.ColID(wizardName(getModuleNameFromColumn(sch, col), col.Name))
+		}
+
+		res[ifaces.ColID(wizardName(getModuleNameFromColumn(sch, il.Target), il.Target.Name))] = sources
+	}
+
+	return res
+}
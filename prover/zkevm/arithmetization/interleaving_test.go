@@ -0,0 +1,172 @@
+package arithmetization
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/corset"
+	"github.com/consensys/go-corset/pkg/mir"
+	sch "github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	cfield "github.com/consensys/go-corset/pkg/util/field"
+	"github.com/consensys/go-corset/pkg/util/sexp"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// the limits of the modules of the test schemas
+var testLimits = map[string]int{"test": 8, "other": 8}
+
+func TestInterleavedColumns(t *testing.T) {
+
+	testCases := []struct {
+		Name    string
+		Source  string
+		Columns map[string][]int64
+	}{
+		{
+			Name: "two-sources",
+			Source: `(defcolumns (X :i16) (Y :i16))
+				(definterleaved Z (X Y))
+				(defconstraint c1 () (vanishes! (* Z (- Z 1) (- Z 2) (- Z 3))))`,
+			Columns: map[string][]int64{"X": {0, 2, 1}, "Y": {3, 1, 0}},
+		},
+		{
+			Name: "lookup-and-sort",
+			Source: `(defcolumns (X :i16) (Y :i16) (W :i16))
+				(definterleaved Z (X Y W))
+				(defpermutation (S) ((+ Z)))
+				(deflookup l1 (Z) (X))`,
+			Columns: map[string][]int64{"X": {1, 2, 3}, "Y": {9, 5, 6}, "W": {7, 1, 2}},
+		},
+		{
+			Name: "computed",
+			Source: `(defcolumns (X :i16) (Y :i16))
+				(defcomputed (C) (id X))
+				(definterleaved Z (C Y))
+				(deflookup l1 (Z) (X))`,
+			Columns: map[string][]int64{"X": {1, 2, 3, 4}, "Y": {5, 1, 2, 3}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			var (
+				schema = compileTestSchema(t, tc.Source)
+				traces = expandTestTraces(t, schema, tc.Columns)
+				comp   = wizard.Compile(func(b *wizard.Builder) { define(b.CompiledIOP, schema, testLimits) }, dummy.Compile)
+				proof  = wizard.Prove(comp, func(run *wizard.ProverRuntime) { assignFromLtTraces(run, schema, traces, testLimits, nil) })
+			)
+
+			require.NoError(t, wizard.Verify(comp, proof))
+		})
+	}
+}
+
+// Checks that the interleaved column cannot be committed independently of its
+// sources.
+func TestInterleavedColumnsTampered(t *testing.T) {
+
+	var (
+		schema = compileTestSchema(t, `(defcolumns (X :i16) (Y :i16)) (definterleaved Z (X Y))`)
+		comp   = wizard.Compile(func(b *wizard.Builder) { define(b.CompiledIOP, schema, testLimits) }, dummy.Compile)
+	)
+
+	prove := func(z ...int) func(run *wizard.ProverRuntime) {
+		return func(run *wizard.ProverRuntime) {
+			run.AssignColumn("test.X", smartvectors.ForTest(0, 0, 0, 0, 0, 0, 1, 2))
+			run.AssignColumn("test.Y", smartvectors.ForTest(0, 0, 0, 0, 0, 0, 3, 4))
+			run.AssignColumn("test.Z", smartvectors.ForTest(z...))
+		}
+	}
+
+	var (
+		honest   = prove(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 3, 2, 4)
+		swapped  = prove(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4)
+		shifted  = prove(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 3, 2, 4, 0)
+		modified = prove(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 3, 2, 5)
+	)
+
+	require.NoError(t, wizard.Verify(comp, wizard.Prove(comp, honest)))
+	require.Error(t, wizard.Verify(comp, wizard.Prove(comp, swapped)))
+	require.Error(t, wizard.Verify(comp, wizard.Prove(comp, shifted)))
+	require.Error(t, wizard.Verify(comp, wizard.Prove(comp, modified)))
+}
+
+// Checks that the padding rows of an interleaved column are constrained when
+// the number of sources is not a power of two.
+func TestInterleavedColumnsPaddingTampered(t *testing.T) {
+
+	var (
+		schema = compileTestSchema(t, `(defcolumns (X :i16) (Y :i16) (W :i16)) (definterleaved Z (X Y W))`)
+		comp   = wizard.Compile(func(b *wizard.Builder) { define(b.CompiledIOP, schema, testLimits) }, dummy.Compile)
+		size   = comp.Columns.GetHandle("test.Z").Size()
+		x      = []field.Element{field.NewElement(1), field.NewElement(2)}
+		y      = []field.Element{field.NewElement(3), field.NewElement(4)}
+		w      = []field.Element{field.NewElement(5), field.NewElement(6)}
+	)
+
+	require.Greater(t, size, 3*testLimits["test"])
+
+	prove := func(padding field.Element) func(run *wizard.ProverRuntime) {
+		return func(run *wizard.ProverRuntime) {
+			run.AssignColumn("test.X", smartvectors.LeftZeroPadded(x, testLimits["test"]))
+			run.AssignColumn("test.Y", smartvectors.LeftZeroPadded(y, testLimits["test"]))
+			run.AssignColumn("test.W", smartvectors.LeftZeroPadded(w, testLimits["test"]))
+			z := smartvectors.LeftZeroPadded(vector.Interleave(
+				smartvectors.LeftZeroPadded(x, testLimits["test"]).IntoRegVecSaveAlloc(),
+				smartvectors.LeftZeroPadded(y, testLimits["test"]).IntoRegVecSaveAlloc(),
+				smartvectors.LeftZeroPadded(w, testLimits["test"]).IntoRegVecSaveAlloc(),
+			), size).IntoRegVecSaveAlloc()
+			z[0] = padding
+			run.AssignColumn("test.Z", smartvectors.NewRegular(z))
+		}
+	}
+
+	require.NoError(t, wizard.Verify(comp, wizard.Prove(comp, prove(field.Zero()))))
+	require.Error(t, wizard.Verify(comp, wizard.Prove(comp, prove(field.NewElement(7)))))
+}
+
+// compileTestSchema compiles a corset source whose columns are declared in a
+// module named "test", unless the source opens another module, and lowers it
+// to AIR.
+func compileTestSchema(t *testing.T, src string) *air.Schema {
+
+	src = "(module test)\n(defpurefun ((vanishes! :@loob) x) x)\n" + src
+
+	binf, errs := corset.CompileSourceFile(true, false, sexp.NewSourceFile("test.lisp", []byte(src)))
+	require.Empty(t, errs)
+
+	return binf.Schema.LowerToMir().LowerToAir(mir.DEFAULT_OPTIMISATION_LEVEL)
+}
+
+// expandTestTraces builds the expanded trace of the test schema from the
+// values of its input columns. The columns are in the "test" module unless
+// their name is prefixed by another module as in "other.T".
+func expandTestTraces(t *testing.T, schema *air.Schema, columns map[string][]int64) trace.Trace {
+
+	raw := make([]trace.RawColumn, 0, len(columns))
+	for name, vals := range columns {
+		module, colName, ok := strings.Cut(name, ".")
+		if !ok {
+			module, colName = "test", name
+		}
+		ints := make([]*big.Int, len(vals))
+		for i := range vals {
+			ints[i] = big.NewInt(vals[i])
+		}
+		raw = append(raw, trace.RawColumn{Module: module, Name: colName, Data: cfield.FrArrayFromBigInts(256, ints)})
+	}
+
+	traces, errs := sch.NewTraceBuilder(schema).Build(raw)
+	require.Empty(t, errs)
+
+	return traces
+}
@@ -0,0 +1,57 @@
+package arithmetization
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks that the sorting constraints and the computed columns they introduce
+// are carried over to the wizard.
+func TestSortedColumns(t *testing.T) {
+
+	testCases := []struct {
+		Name    string
+		Source  string
+		Columns map[string][]int64
+	}{
+		{
+			Name:    "sorted-single",
+			Source:  `(defcolumns (X :i16)) (defsorted s1 ((+ X)))`,
+			Columns: map[string][]int64{"X": {1, 2, 2, 7}},
+		},
+		{
+			Name:    "sorted-lexicographic",
+			Source:  `(defcolumns (X :i16) (Y :i16)) (defsorted s1 ((+ X) (- Y)))`,
+			Columns: map[string][]int64{"X": {1, 1, 2, 2}, "Y": {5, 3, 9, 9}},
+		},
+		{
+			Name:    "permutation-lexicographic",
+			Source:  `(defcolumns (X :i16) (Y :i16)) (defpermutation (A B) ((+ X) (- Y)))`,
+			Columns: map[string][]int64{"X": {1, 3, 2, 3}, "Y": {5, 3, 9, 1}},
+		},
+		{
+			Name:    "byte-decomposition",
+			Source:  `(defcolumns (X :i64) (Y :i64)) (defpermutation (A B) ((+ X) (+ Y)))`,
+			Columns: map[string][]int64{"X": {1 << 40, 3, 1 << 40, 3}, "Y": {5, 1 << 50, 9, 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			schema := compileTestSchema(t, tc.Source)
+			require.NoError(t, CheckSupported(schema))
+
+			var (
+				traces = expandTestTraces(t, schema, tc.Columns)
+				comp   = wizard.Compile(func(b *wizard.Builder) { define(b.CompiledIOP, schema, testLimits) }, dummy.Compile)
+				proof  = wizard.Prove(comp, func(run *wizard.ProverRuntime) { assignFromLtTraces(run, schema, traces, testLimits, nil) })
+			)
+
+			require.NoError(t, wizard.Verify(comp, proof))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package arithmetization
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/binfile"
+	"github.com/consensys/go-corset/pkg/corset"
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/go-corset/pkg/util/collection/typed"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+)
+
+// ZkevmBinFileName is the name of the constraint binaries in the versioned
+// directory of [config.Execution.ZkevmBinDir].
+const ZkevmBinFileName = "zkevm.bin"
+
+// ZkevmBin is a constraint binary of the arithmetization, i.e. the content of
+// a "zkevm.bin" file, along with its hash. The hash identifies the constraint
+// system: it is folded in the Fiat-Shamir state of the zkEVM and recorded in
+// its setup so that a proof is never generated or checked against the wrong
+// constraints.
+type ZkevmBin struct {
+	// Source describes where the binary was read from
+	Source string
+	// Version is the traces engine version the binary was selected for in
+	// [config.Execution.ZkevmBinDir]. It is empty for the other binaries. The
+	// setups of the execution circuits are stored per version, see
+	// [config.Config.PathForVersionedSetup].
+	Version string
+	data    []byte
+	hash    string
+}
+
+// newZkevmBin returns the binary with the content and computes its hash
+func newZkevmBin(source string, data []byte) *ZkevmBin {
+	digest := sha256.Sum256(data)
+	return &ZkevmBin{
+		Source: source,
+		data:   data,
+		hash:   hex.EncodeToString(digest[:]),
+	}
+}
+
+// EmbeddedZkevmBin returns the constraint binary embedded in the prover at
+// build time.
+func EmbeddedZkevmBin() *ZkevmBin {
+	return newZkevmBin("embedded", []byte(zkevmStr))
+}
+
+// ReadZkevmBinFile reads a constraint binary from a file
+func ReadZkevmBinFile(path string) (*ZkevmBin, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the constraint binary: %w", err)
+	}
+	return newZkevmBin(path, data), nil
+}
+
+// SelectZkevmBin returns the constraint binary to use for a request of the
+// given traces engine version. In order of precedence, it is the binary of
+// the version in [config.Execution.ZkevmBinDir], the one of
+// [config.Execution.ZkevmBin] and the embedded one. The version is ignored if
+// empty or if the configuration has no versioned directory.
+//
+// The returned errors are classified with [exit.Kind]: a missing binary is a
+// [exit.KindSetupMismatch] as the prover is not set up for the version.
+func SelectZkevmBin(cfg *config.Execution, tracesEngineVersion string) (*ZkevmBin, error) {
+
+	var path, version string
+
+	switch {
+	case len(cfg.ZkevmBinDir) > 0 && len(tracesEngineVersion) > 0:
+		if !filepath.IsLocal(tracesEngineVersion) || filepath.Base(tracesEngineVersion) != tracesEngineVersion {
+			return nil, exit.Errorf(exit.KindInvalidRequest, "invalid traces engine version %q", tracesEngineVersion)
+		}
+		path = filepath.Join(cfg.ZkevmBinDir, tracesEngineVersion, ZkevmBinFileName)
+		version = tracesEngineVersion
+	case len(cfg.ZkevmBin) > 0:
+		path = cfg.ZkevmBin
+	default:
+		return EmbeddedZkevmBin(), nil
+	}
+
+	bin, err := ReadZkevmBinFile(path)
+	if err != nil {
+		return nil, exit.Errorf(exit.KindSetupMismatch, "no constraints for the traces engine version %q: %w", tracesEngineVersion, err)
+	}
+
+	bin.Version = version
+	return bin, nil
+}
+
+// Hash returns the hex-encoded SHA256 hash of the binary
+func (b *ZkevmBin) Hash() string {
+	return b.hash
+}
+
+// Compile parses and compiles the binary into an air.Schema, whilst applying
+// whatever optimisations are requested. Optimisations can impact the size of
+// the generated schema and, consequently, the size of the expanded trace.  For
+// example, certain optimisations eliminate unnecessary columns creates for
+// multiplicative inverses.  However, optimisations do not always improve
+// overall performance, as they can increase the complexity of other
+// constraints.  The DEFAULT_OPTIMISATION_LEVEL is the recommended level to use
+// in general, whilst others are intended for testing purposes (i.e. to try out
+// new optimisations to see whether they help or hinder, etc).
+//
+// This additionally extracts the metadata map from the binary.  This contains
+// information which can be used to cross-check the binary, such as the git
+// commit of the enclosing repository when it was built.
+func (b *ZkevmBin) Compile(optConfig *mir.OptimisationConfig) (schema *air.Schema, metadata typed.Map, err error) {
+	var binf binfile.BinaryFile
+	// TODO: why is only this one needed??
+	gob.Register(binfile.Attribute(&corset.SourceMap{}))
+	// Parse zkbinary file
+	err = binf.UnmarshalBinary(b.data)
+	// Sanity check for errors
+	if err != nil {
+		return nil, metadata, fmt.Errorf("could not parse the read bytes of the 'zkevm.bin' file (%v) into an hir.Schema: %w", b.Source, err)
+	}
+	// Extract schema
+	hirSchema := &binf.Schema
+	// Attempt to extract metadata from bin file, and sanity check constraints
+	// commit information is available.
+	if metadata, err = binf.Header.GetMetaData(); metadata.IsEmpty() {
+		return nil, metadata, fmt.Errorf("missing metatdata from 'zkevm.bin' file (%v)", b.Source)
+	}
+	// This performs the corset compilation
+	schema = hirSchema.LowerToMir().LowerToAir(*optConfig)
+	if errS := CheckSupported(schema); errS != nil {
+		return nil, metadata, fmt.Errorf("the constraints of the 'zkevm.bin' file (%v) are not supported by the prover: %w", b.Source, errS)
+	}
+	return schema, metadata, err
+}
@@ -0,0 +1,70 @@
+package arithmetization
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/mir"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectZkevmBin(t *testing.T) {
+
+	var (
+		dir      = t.TempDir()
+		embedded = EmbeddedZkevmBin()
+		path     = filepath.Join(dir, "v1.0.0", ZkevmBinFileName)
+		other    = filepath.Join(dir, "other.bin")
+		cfg      = &config.Execution{}
+	)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(zkevmStr), 0644))
+	require.NoError(t, os.WriteFile(other, []byte("not a zkevm.bin"), 0644))
+
+	// without configuration, the version is ignored
+	bin, err := SelectZkevmBin(cfg, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, embedded.Hash(), bin.Hash())
+
+	cfg.ZkevmBin = other
+	bin, err = SelectZkevmBin(cfg, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, other, bin.Source)
+	assert.Empty(t, bin.Version)
+	assert.NotEqual(t, embedded.Hash(), bin.Hash())
+	_, _, err = bin.Compile(&mir.DEFAULT_OPTIMISATION_LEVEL)
+	assert.Error(t, err)
+
+	// the versioned directory takes precedence for the requests specifying
+	// the version
+	cfg.ZkevmBinDir = dir
+	bin, err = SelectZkevmBin(cfg, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, path, bin.Source)
+	assert.Equal(t, "v1.0.0", bin.Version)
+	assert.Equal(t, embedded.Hash(), bin.Hash())
+	_, _, err = bin.Compile(&mir.DEFAULT_OPTIMISATION_LEVEL)
+	assert.NoError(t, err)
+
+	bin, err = SelectZkevmBin(cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, other, bin.Source)
+	assert.Empty(t, bin.Version)
+
+	_, err = SelectZkevmBin(cfg, "v2.0.0")
+	e, ok := exit.As(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, exit.KindSetupMismatch, e.Kind)
+
+	for _, version := range []string{"..", "../v1.0.0", "v1.0.0/../v1.0.0", "/v1.0.0"} {
+		_, err = SelectZkevmBin(cfg, version)
+		e, ok = exit.As(err)
+		require.True(t, ok, "unexpected error for %q: %v", version, err)
+		assert.Equal(t, exit.KindInvalidRequest, e.Kind, version)
+	}
+}
@@ -15,21 +15,28 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/accumulator"
+	"github.com/sirupsen/logrus"
 )
 
+// fullFirstVortexBlowUp is the Reed-Solomon blow-up factor of the first Vortex
+// step of the [fullCompilationSuite], the one committing to the columns of the
+// zkEVM.
+const fullFirstVortexBlowUp = 2
+
 var (
-	fullZkEvm              *ZkEvm
-	fullZkEvmCheckOnly     *ZkEvm
-	onceFullZkEvm          = sync.Once{}
-	onceFullZkEvmCheckOnly = sync.Once{}
+	// The full zkEVMs compiled so far, by limits and constraint binary
+	fullZkEvms          = zkEvmMemo{}
+	fullZkEvmsCheckOnly = zkEvmMemo{}
 
 	// This is the SIS instance, that has been found to minimize the overhead of
 	// recursion. It is changed w.r.t to the estimated because the estimated one
@@ -47,7 +54,7 @@ var (
 		mimc.CompileMiMC,
 		compiler.Arcane(1<<10, 1<<19, false),
 		vortex.Compile(
-			2,
+			fullFirstVortexBlowUp,
 			vortex.ForceNumOpenedColumns(256),
 			vortex.WithSISParams(&sisInstance),
 		),
@@ -94,34 +101,35 @@ var (
 	}
 )
 
-// FullZkEvm compiles the full prover zkEVM. It memoizes the results and
-// returns it for all the subsequent calls. That is, it should not be called
-// twice with different configuration parameters as it will always return the
-// instance compiled with the parameters it received the first time. This
-// behavior is motivated by the fact that the compilation process takes time
-// and we don't want to spend the compilation time twice, plus in practice we
-// won't need to call it with different configuration parameters.
-func FullZkEvm(tl *config.TracesLimits, cfg *config.Config) *ZkEvm {
+// FullZkEvm compiles the full prover zkEVM for the limits and the constraint
+// binary. It memoizes the results and returns it for all the subsequent calls
+// with the same limits and binary. The other configuration parameters are
+// only read by the first call as the compilation process takes time and we
+// don't want to spend the compilation time twice, plus in practice we won't
+// need to call it with different configuration parameters.
+func FullZkEvm(tl *config.TracesLimits, cfg *config.Config, bin *arithmetization.ZkevmBin) *ZkEvm {
 
-	onceFullZkEvm.Do(func() {
+	return fullZkEvms.get(tl, bin, func() *ZkEvm {
 		// Initialize the Full zkEVM arithmetization
-		fullZkEvm = fullZKEVMWithSuite(tl, fullCompilationSuite, cfg)
+		return fullZKEVMWithSuite(tl, fullCompilationSuite, cfg, bin)
 	})
-
-	return fullZkEvm
 }
 
-func FullZkEVMCheckOnly(tl *config.TracesLimits, cfg *config.Config) *ZkEvm {
+func FullZkEVMCheckOnly(tl *config.TracesLimits, cfg *config.Config, bin *arithmetization.ZkevmBin) *ZkEvm {
 
-	onceFullZkEvmCheckOnly.Do(func() {
+	return fullZkEvmsCheckOnly.get(tl, bin, func() *ZkEvm {
+		suite := dummyCompilationSuite
+		if len(cfg.Execution.ViolationReportDir) > 0 {
+			suite = compilationSuite{dummy.CompileAtProverLvlWithReport(dummy.ReportSettings{
+				Dir: cfg.Execution.ViolationReportDir,
+			})}
+		}
 		// Initialize the Full zkEVM arithmetization
-		fullZkEvmCheckOnly = fullZKEVMWithSuite(tl, dummyCompilationSuite, cfg)
+		return fullZKEVMWithSuite(tl, suite, cfg, bin)
 	})
-
-	return fullZkEvmCheckOnly
 }
 
-func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite, cfg *config.Config) *ZkEvm {
+func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite, cfg *config.Config, bin *arithmetization.ZkevmBin) *ZkEvm {
 
 	// @Alex: only set mandatory parameters here. aka, the one that are not
 	// actually feature-gated.
@@ -131,6 +139,7 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite, cfg *co
 			Limits:                   tl,
 			OptimisationLevel:        &mir.DEFAULT_OPTIMISATION_LEVEL,
 			IgnoreCompatibilityCheck: &cfg.Execution.IgnoreCompatibilityCheck,
+			ZkevmBin:                 bin,
 		},
 		Statemanager: statemanager.Settings{
 			AccSettings: accumulator.Settings{
@@ -143,6 +152,9 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite, cfg *co
 		Metadata: wizard.VersionMetadata{
 			Title:   "linea/evm-execution/full",
 			Version: "beta-v1",
+			// The constraints are loaded at runtime so they must be part of
+			// the Fiat-Shamir state.
+			ConstraintsHash: bin.Hash(),
 		},
 		Keccak: keccak.Settings{
 			MaxNumKeccakf: tl.BlockKeccak,
@@ -178,9 +190,113 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite, cfg *co
 		Sha2: sha2.Settings{
 			MaxNumSha2F: tl.PrecompileSha2Blocks,
 		},
+		Ripemd: ripemd.Settings{
+			MaxNumRipemdF: tl.PrecompileRipemdBlocks,
+		},
+		Blake2f: blake2f.Settings{
+			MaxNbRounds: tl.PrecompileBlakeRounds,
+		},
 	}
 
 	// Initialize the Full zkEVM arithmetization
-	fullZkEvm = NewZkEVM(settings)
-	return fullZkEvm
+	return NewZkEVM(settings)
+}
+
+// CommitmentStats describes the columns committed by the first round of
+// Vortex of the full prover. These are what drives the memory of the prover.
+type CommitmentStats struct {
+	NbColumns, NbCells                       int
+	NbPrecomputedColumns, NbPrecomputedCells int
+	// VortexBlowUp is the Reed-Solomon blow-up factor of the first round of
+	// Vortex. The prover holds the committed columns along with their
+	// encoding.
+	VortexBlowUp int
+}
+
+// FullZkEvmCommitmentStats compiles the full zkEVM with the provided limits and
+// constraint binary up to the first Vortex step of the full compilation suite and returns the size
+// of what this step commits to. This is much faster than the full compilation
+// and is meant for estimating the cost of a change of the limits.
+func FullZkEvmCommitmentStats(tl *config.TracesLimits, cfg *config.Config, bin *arithmetization.ZkevmBin) CommitmentStats {
+
+	var (
+		// The suite starts with MiMC and Arcane, which are what sets the
+		// columns committed by the first Vortex step.
+		suite = fullCompilationSuite[:2]
+		comp  = fullZKEVMWithSuite(tl, suite, cfg, bin).WizardIOP
+		res   = CommitmentStats{VortexBlowUp: fullFirstVortexBlowUp}
+	)
+
+	for _, name := range comp.Columns.AllKeysCommitted() {
+		res.NbColumns++
+		res.NbCells += comp.Columns.GetSize(name)
+	}
+
+	for _, name := range comp.Columns.AllPrecomputed() {
+		res.NbPrecomputedColumns++
+		res.NbPrecomputedCells += comp.Columns.GetSize(name)
+	}
+
+	return res
+}
+
+// maxMemoizedZkEvms bounds the number of zkEVMs kept by a [zkEvmMemo]. A
+// compiled zkEVM takes several GB of memory, so a long-running server proving
+// for many versions of the constraints only keeps the most recently used ones.
+// The evicted zkEVMs are compiled again when they are requested again.
+const maxMemoizedZkEvms = 2
+
+// zkEvmMemo memoizes the zkEVMs compiled for the limits and the constraint
+// binaries. It keeps at most [maxMemoizedZkEvms] of them.
+type zkEvmMemo struct {
+	lock   sync.Mutex
+	zkEvms map[string]*ZkEvm
+	// recent lists the keys of the memoized zkEVMs, from the most recently
+	// used to the least recently used.
+	recent []string
+}
+
+// get returns the zkEVM memoized for the limits and the binary, compiling it
+// if needed. The compilations are serialized. Memoizing a new zkEVM evicts the
+// least recently used one if the memo is full.
+func (m *zkEvmMemo) get(tl *config.TracesLimits, bin *arithmetization.ZkevmBin, compile func() *ZkEvm) *ZkEvm {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := tl.Checksum() + "/" + bin.Hash()
+	if z, ok := m.zkEvms[key]; ok {
+		m.touch(key)
+		return z
+	}
+
+	if m.zkEvms == nil {
+		m.zkEvms = map[string]*ZkEvm{}
+	}
+
+	// The evicted zkEVM is released before compiling the new one so that the
+	// two are never in memory at the same time, unless a proof still uses it.
+	if len(m.recent) >= maxMemoizedZkEvms {
+		evicted := m.recent[len(m.recent)-1]
+		m.recent = m.recent[:len(m.recent)-1]
+		delete(m.zkEvms, evicted)
+		logrus.Infof("evicting the zkEVM %v from the memo", evicted)
+	}
+
+	logrus.Infof("compiling the zkEVM for the constraints %v (%v)", bin.Hash(), bin.Source)
+	z := compile()
+	m.zkEvms[key] = z
+	m.recent = append([]string{key}, m.recent...)
+	return z
+}
+
+// touch marks the key as the most recently used
+func (m *zkEvmMemo) touch(key string) {
+	for i := range m.recent {
+		if m.recent[i] == key {
+			copy(m.recent[1:i+1], m.recent[:i])
+			m.recent[0] = key
+			return
+		}
+	}
 }
@@ -0,0 +1,243 @@
+package blake2f
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/linea-monorepo/prover/crypto/blake2f"
+)
+
+// blake2fCircuit is the gnark circuit (compiled as Plonk) used to check the
+// rounds of the BLAKE2f compression function.
+type blake2fCircuit struct {
+	Instances []roundInstance `gnark:",public"`
+}
+
+func allocateCircuit(nbInstances int) *blake2fCircuit {
+	return &blake2fCircuit{
+		Instances: make([]roundInstance, nbInstances),
+	}
+}
+
+// Define implements the [frontend.Circuit] interface
+func (c *blake2fCircuit) Define(api frontend.API) error {
+
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		panic(fmt.Sprintf("unexpected error when instantiating `uapi`: %v", err.Error()))
+	}
+
+	for i := range c.Instances {
+		c.Instances[i].checkRound(api, uapi)
+	}
+	return nil
+}
+
+// roundInstance represents a round of the compression function. The fields
+// are listed in the same order as the rows of an instance in [Module.Limbs].
+// The state, the message block, the counter and the working vectors are
+// given as 16 bytes limbs (see [Input]).
+//
+// An instance where all the fields are zero is accepted by the circuit so that
+// the unused instances can be zero-padded.
+type roundInstance struct {
+	H        [4]frontend.Variable
+	M        [8]frontend.Variable
+	T        frontend.Variable
+	R        frontend.Variable
+	F        frontend.Variable
+	HOut     [4]frontend.Variable
+	RoundIdx frontend.Variable
+	IsFirst  frontend.Variable
+	IsLast   frontend.Variable
+	VIn      [nbLimbsPerState]frontend.Variable
+	VOut     [nbLimbsPerState]frontend.Variable
+}
+
+// checkRound adds the constraints ensuring the correctness of the instance.
+func (ri *roundInstance) checkRound(api frontend.API, uapi *uints.BinaryField[uints.U64]) {
+
+	var (
+		h    = limbsToWords(api, ri.H[:])
+		m    = limbsToWords(api, ri.M[:])
+		t    = limbsToWords(api, []frontend.Variable{ri.T})
+		hOut = limbsToWords(api, ri.HOut[:])
+		vIn  = limbsToWords(api, ri.VIn[:])
+		vOut = limbsToWords(api, ri.VOut[:])
+
+		isZeroR = api.IsZero(ri.R)
+		isEnd   = api.IsZero(api.Sub(api.Add(ri.RoundIdx, 1), ri.R))
+	)
+
+	api.AssertIsBoolean(ri.F)
+	api.AssertIsBoolean(ri.IsFirst)
+
+	// A call with zero rounds spans a single instance. Otherwise, the last
+	// round is the one with index R-1.
+	api.AssertIsEqual(ri.IsLast, api.Select(isZeroR, ri.IsFirst, isEnd))
+
+	var (
+		vInit = initCircuit(api, uapi, h, t, ri.F)
+		v     = selectWords(api, ri.IsFirst, vInit, vIn)
+		vNext = roundCircuit(uapi, v, permuteMessage(api, m, ri.RoundIdx))
+		res   = selectWords(api, isZeroR, v, vNext)
+	)
+
+	for i := range res {
+		uapi.AssertEq(res[i], vOut[i])
+	}
+
+	// The finalization is only checked on the last round. The check is
+	// expressed per byte as it is conditional.
+	for i := range hOut {
+		expected := uapi.Xor(h[i], res[i], res[i+8])
+		for j := range expected {
+			api.AssertIsEqual(
+				api.Mul(ri.IsLast, api.Sub(hOut[i][j].Val, expected[j].Val)),
+				0,
+			)
+		}
+	}
+}
+
+// initCircuit returns the working vector before the first round
+func initCircuit(api frontend.API, uapi *uints.BinaryField[uints.U64], h, t []uints.U64, f frontend.Variable) []uints.U64 {
+
+	v := make([]uints.U64, 16)
+	copy(v, h)
+	for i := range blake2f.IV {
+		v[8+i] = uints.NewU64(blake2f.IV[i])
+	}
+
+	v[12] = uapi.Xor(v[12], t[0])
+	v[13] = uapi.Xor(v[13], t[1])
+	v[14] = selectWords(api, f, []uints.U64{uints.NewU64(^blake2f.IV[6])}, v[14:15])[0]
+
+	return v
+}
+
+// roundCircuit applies a round of the compression function on the working
+// vector with an already permuted message.
+func roundCircuit(uapi *uints.BinaryField[uints.U64], v, s []uints.U64) []uints.U64 {
+
+	v = append([]uints.U64{}, v...)
+
+	g := func(a, b, c, d int, x, y uints.U64) {
+		v[a] = uapi.Add(v[a], v[b], x)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -32)
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -24)
+		v[a] = uapi.Add(v[a], v[b], y)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -16)
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -63)
+	}
+
+	g(0, 4, 8, 12, s[0], s[1])
+	g(1, 5, 9, 13, s[2], s[3])
+	g(2, 6, 10, 14, s[4], s[5])
+	g(3, 7, 11, 15, s[6], s[7])
+	g(0, 5, 10, 15, s[8], s[9])
+	g(1, 6, 11, 12, s[10], s[11])
+	g(2, 7, 8, 13, s[12], s[13])
+	g(3, 4, 9, 14, s[14], s[15])
+
+	return v
+}
+
+// permuteMessage returns the message words in the order in which they are
+// consumed by the round of index roundIdx. The permutation is selected by
+// roundIdx mod 10 which is computed by the prover and checked by the circuit.
+func permuteMessage(api frontend.API, m []uints.U64, roundIdx frontend.Variable) []uints.U64 {
+
+	qr, err := api.Compiler().NewHint(divModTenHint, 2, roundIdx)
+	if err != nil {
+		panic(err)
+	}
+
+	// The round index is at most 2^32 - 2, so the quotient fits on 32 bits.
+	// The remainder is implicitly checked to be in [0, 10) by the decoder.
+	rangecheck.New(api).Check(qr[0], 32)
+	api.AssertIsEqual(roundIdx, api.Add(api.Mul(qr[0], 10), qr[1]))
+
+	var (
+		indicators = selector.Decoder(api, len(blake2f.Sigma), qr[1])
+		res        = make([]uints.U64, len(m))
+	)
+
+	// Since the indicators are boolean and sum to one, the bytes of the
+	// result are bytes without requiring an extra range-check.
+	for pos := range res {
+		for b := range res[pos] {
+			acc := frontend.Variable(0)
+			for s := range blake2f.Sigma {
+				acc = api.MulAcc(acc, indicators[s], m[blake2f.Sigma[s][pos]][b].Val)
+			}
+			res[pos][b] = uints.U8{Val: acc}
+		}
+	}
+
+	return res
+}
+
+// selectWords returns a if sel == 1 and b if sel == 0. The selection is done
+// per byte so that the result does not need to be range-checked.
+func selectWords(api frontend.API, sel frontend.Variable, a, b []uints.U64) []uints.U64 {
+	res := make([]uints.U64, len(a))
+	for i := range res {
+		for j := range res[i] {
+			res[i][j] = uints.U8{Val: api.Select(sel, a[i][j].Val, b[i][j].Val)}
+		}
+	}
+	return res
+}
+
+// limbsToWords decomposes 16 bytes limbs into the 64 bits words they encode.
+// The limbs store the serialized words in big-endian order while the words
+// are serialized in little-endian order.
+func limbsToWords(api frontend.API, limbs []frontend.Variable) []uints.U64 {
+
+	res := make([]uints.U64, 0, 2*len(limbs))
+
+	for _, limb := range limbs {
+
+		// The bytes are range-checked by toNBytes
+		bytes := toNBytes(api, limb, 16)
+
+		for k := 0; k < 2; k++ {
+			var word uints.U64
+			for j := range word {
+				word[j] = uints.U8{Val: bytes[8*k+j]}
+			}
+			res = append(res, word)
+		}
+	}
+
+	return res
+}
+
+// divModTenHint returns the quotient and the remainder of the division by 10
+func divModTenHint(_ *big.Int, ins, outs []*big.Int) error {
+	if len(ins) != 1 || len(outs) != 2 {
+		return errors.New("expected 1 input and 2 outputs")
+	}
+	outs[0].DivMod(ins[0], big.NewInt(10), outs[1])
+	return nil
+}
+
+var onceRegisterGnarkHint = sync.Once{}
+
+// registerGnarkHint registers the circuit specific hints needed to assign to
+// the circuit
+func registerGnarkHint() {
+	onceRegisterGnarkHint.Do(func() {
+		solver.RegisterHint(decomposeIntoBytesHint, divModTenHint)
+	})
+}
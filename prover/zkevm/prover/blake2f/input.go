@@ -0,0 +1,86 @@
+package blake2f
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+)
+
+// Input collects references to the columns of the arithmetization containing
+// the BLAKE2f statements. These columns are constrained via a projection query
+// to describe the same statement as what is being stated in the module. They
+// are also used as a data source to assign the columns of the module.
+//
+// The columns provided here are columns from the BLAKE_MODEXP_DATA module. A
+// call to the precompile spans 19 rows of 16 bytes limbs which are laid out in
+// the same order as the precompile calldata:
+//
+//   - 13 rows flagged with IS_BLAKE_DATA: the hasher state h (4 limbs), the
+//     message block m (8 limbs) and the offset counter t (1 limb)
+//   - 2 rows flagged with IS_BLAKE_PARAMS: the number of rounds r and the
+//     final block indicator f
+//   - 4 rows flagged with IS_BLAKE_RESULT: the updated hasher state
+//
+// Every limb of h, m, t and of the result stores 16 bytes of the calldata (or
+// of the returndata) as a big-endian integer.
+type Input struct {
+	Settings Settings
+	// Binary column indicating if we have h, m or t limbs
+	IsBlakeData ifaces.Column
+	// Binary column indicating if we have the r or the f parameter
+	IsBlakeParams ifaces.Column
+	// Binary column indicating if we have result limbs
+	IsBlakeResult ifaces.Column
+	// isBlake is a constructed column constrained to be equal to the sum of the
+	// 3 above columns.
+	isBlake ifaces.Column
+	// Multiplexed column containing the limbs of the data, params and result
+	Limbs ifaces.Column
+}
+
+type Settings struct {
+	// MaxNbRounds is the maximal total number of rounds that can be proven.
+	// A call with r rounds consumes max(r, 1) rounds of the budget.
+	MaxNbRounds int
+}
+
+func newZkEVMInput(comp *wizard.CompiledIOP, settings Settings) Input {
+	return Input{
+		Settings:      settings,
+		IsBlakeData:   comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_DATA"),
+		IsBlakeParams: comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_PARAMS"),
+		IsBlakeResult: comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_RESULT"),
+		Limbs:         comp.Columns.GetHandle("blake2fmodexpdata.LIMB"),
+	}
+}
+
+// setIsBlake constructs, constraints and set the [isBlake] column
+func (i *Input) setIsBlake(comp *wizard.CompiledIOP) {
+
+	i.isBlake = comp.InsertCommit(0, "BLAKE2F_INPUT_IS_BLAKE", i.IsBlakeData.Size())
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_BLAKE_WELL_CONSTRUCTED",
+		sym.Sub(
+			i.isBlake,
+			i.IsBlakeData,
+			i.IsBlakeParams,
+			i.IsBlakeResult,
+		),
+	)
+}
+
+// assignIsBlake evaluates and assigns the isBlake column
+func (i *Input) assignIsBlake(run *wizard.ProverRuntime) {
+
+	var (
+		isData   = i.IsBlakeData.GetColAssignment(run)
+		isParams = i.IsBlakeParams.GetColAssignment(run)
+		isResult = i.IsBlakeResult.GetColAssignment(run)
+		isBlake  = smartvectors.Add(isData, isParams, isResult)
+	)
+
+	run.AssignColumn(i.isBlake.GetColID(), isBlake)
+}
@@ -0,0 +1,405 @@
+package blake2f
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/variables"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Layout of a round instance in the [Module.Limbs] column. The first rows
+// replicate the call as provided by the arithmetization, they are followed by
+// the data specific to the round. Every row up to nbRowsToCircuit is a public
+// input of the round circuit and the remaining rows are zero.
+const (
+	offsetH        = 0
+	offsetM        = 4
+	offsetT        = 12
+	offsetR        = 13
+	offsetF        = 14
+	offsetHOut     = 15
+	offsetRoundIdx = 19
+	offsetIsFirst  = 20
+	offsetIsLast   = 21
+	offsetVIn      = 22
+	offsetVOut     = 30
+
+	// nbRowsPerCall is the number of rows of the arithmetization describing a
+	// call to the precompile.
+	nbRowsPerCall = offsetRoundIdx
+	// nbRowsToCircuit is the number of rows of an instance sent to the circuit
+	nbRowsToCircuit = offsetVOut + nbLimbsPerState
+	// nbRowsPerInstance is the number of rows used to represent a round. It is
+	// a power of two so that the layout can be described by periodic samples.
+	nbRowsPerInstance = 64
+
+	// nbLimbsPerState is the number of 16 bytes limbs needed to represent the
+	// working vector of the compression function.
+	nbLimbsPerState = 8
+
+	// nbRoundsPerCircuit is the number of rounds verified by a single instance
+	// of the gnark circuit.
+	nbRoundsPerCircuit = 16
+)
+
+// Module implements the wizard part responsible for checking the BLAKE2f
+// claims coming from the BLAKE_MODEXP_DATA module of the arithmetization.
+//
+// The module represents every round of the compression function as an
+// instance of [nbRowsPerInstance] rows. A call with r rounds thus spans
+// max(r, 1) instances: the call data is replicated in every instance and the
+// working vector is passed from an instance to the next one. The gnark circuit
+// checks the rounds independently of each other.
+type Module struct {
+	// MaxNbRounds corresponds to the maximum number of rounds that we want to
+	// support.
+	MaxNbRounds int
+	// Input stores the columns used as a source for the module.
+	Input Input
+	// IsActive is a binary indicator column marking with a 1, the rows of the
+	// module corresponding "active" rows: e.g. NOT padding rows.
+	IsActive ifaces.Column
+	// IsFirstRound and IsLastRound are indicator columns that are constant per
+	// instance and mark the instances corresponding to the first and the last
+	// round of a call.
+	IsFirstRound, IsLastRound ifaces.Column
+	// Limbs contains the round instances. The call rows of the first round of
+	// every call are subjected to a projection constraint from the
+	// BLAKE_MODEXP_DATA module. It is constrained to zero when IsActive = 0.
+	Limbs ifaces.Column
+	// IsProjected marks the call rows of the first instance of every call and
+	// is used as filter for the projection query.
+	IsProjected ifaces.Column
+	// ToCircuit marks the rows of the active instances that are public inputs
+	// of the circuit.
+	ToCircuit ifaces.Column
+	// IsCallRow, IsVInRow and IsCircuitRow are precomputed columns marking with
+	// a 1 (respectively) the call rows, the input working vector rows and the
+	// rows that are sent to the circuit in every instance.
+	IsCallRow, IsVInRow, IsCircuitRow ifaces.Column
+	// connection logic of the round circuit
+	GnarkCircuitConnector *plonk.Alignment
+	// hasCircuit indicates whether the circuit has been set in the module. In
+	// production, it will be always set to true. But for convenience we omit
+	// the circuit in some of the test as this is CPU intensive.
+	hasCircuit bool
+}
+
+// NewModuleZkEvm constructs an instance of the BLAKE2f module. It should be
+// called only once.
+func NewModuleZkEvm(comp *wizard.CompiledIOP, settings Settings) *Module {
+	return newModule(comp, newZkEVMInput(comp, settings)).
+		WithCircuit(comp, plonk.WithRangecheck(16, 6, false))
+}
+
+func newModule(comp *wizard.CompiledIOP, input Input) *Module {
+
+	var (
+		settings = input.Settings
+		size     = utils.NextPowerOfTwo(settings.MaxNbRounds * nbRowsPerInstance)
+		mod      = &Module{
+			Input:        input,
+			MaxNbRounds:  settings.MaxNbRounds,
+			IsActive:     comp.InsertCommit(0, "BLAKE2F_IS_ACTIVE", size),
+			IsFirstRound: comp.InsertCommit(0, "BLAKE2F_IS_FIRST_ROUND", size),
+			IsLastRound:  comp.InsertCommit(0, "BLAKE2F_IS_LAST_ROUND", size),
+			Limbs:        comp.InsertCommit(0, "BLAKE2F_LIMBS", size),
+			IsProjected:  comp.InsertCommit(0, "BLAKE2F_IS_PROJECTED", size),
+			ToCircuit:    comp.InsertCommit(0, "BLAKE2F_TO_CIRCUIT", size),
+			IsCallRow:    comp.InsertPrecomputed("BLAKE2F_IS_CALL_ROW", rowIndicatorValue(size, offsetH, nbRowsPerCall)),
+			IsVInRow:     comp.InsertPrecomputed("BLAKE2F_IS_V_IN_ROW", rowIndicatorValue(size, offsetVIn, offsetVIn+nbLimbsPerState)),
+			IsCircuitRow: comp.InsertPrecomputed("BLAKE2F_IS_CIRCUIT_ROW", rowIndicatorValue(size, 0, nbRowsToCircuit)),
+		}
+	)
+
+	mod.Input.setIsBlake(comp)
+
+	mod.csIsActive(comp)
+	mod.csFirstAndLastRound(comp)
+	mod.csRoundChaining(comp)
+	mod.csRoundRows(comp)
+	mod.csFilters(comp)
+
+	projection.InsertProjection(
+		comp,
+		"BLAKE2F_BLKMDXP_PROJECTION",
+		[]ifaces.Column{mod.Input.Limbs},
+		[]ifaces.Column{mod.Limbs},
+		mod.Input.isBlake,
+		mod.IsProjected,
+	)
+
+	return mod
+}
+
+// WithCircuit adds the Plonk-in-Wizard circuit verification to complete
+// the module.
+func (mod *Module) WithCircuit(comp *wizard.CompiledIOP, options ...plonk.Option) *Module {
+
+	mod.hasCircuit = true
+
+	mod.GnarkCircuitConnector = plonk.DefineAlignment(
+		comp,
+		&plonk.CircuitAlignmentInput{
+			Name:               "BLAKE2F_ROUNDS",
+			DataToCircuit:      mod.Limbs,
+			DataToCircuitMask:  mod.ToCircuit,
+			Circuit:            allocateCircuit(nbRoundsPerCircuit),
+			NbCircuitInstances: utils.DivCeil(mod.MaxNbRounds, nbRoundsPerCircuit),
+			PlonkOptions:       options,
+		},
+	)
+
+	return mod
+}
+
+// rowIndicatorValue returns a smartvector of the given size marking with a 1
+// the rows whose offset in their instance is in [from, to).
+func rowIndicatorValue(size int, from, to int) smartvectors.SmartVector {
+	resSlice := make([]field.Element, size)
+	for i := range resSlice {
+		if k := i % nbRowsPerInstance; from <= k && k < to {
+			resSlice[i].SetOne()
+		}
+	}
+	return smartvectors.NewRegular(resSlice)
+}
+
+// isInstanceStart returns an expression evaluating to 1 on the first row of
+// every instance and to 0 elsewhere.
+func isInstanceStart() *sym.Expression {
+	return variables.NewPeriodicSample(nbRowsPerInstance, 0)
+}
+
+// csIsActive ensures that the IsActive column is well constructed and that
+// all the other columns are zero when it is zero.
+func (mod *Module) csIsActive(comp *wizard.CompiledIOP) {
+
+	mustBeBinary(comp, mod.IsActive)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_ACTIVE_DOES_NOT_INCREASE",
+		sym.Mul(
+			mod.IsActive,
+			sym.Sub(mod.IsActive, column.Shift(mod.IsActive, -1)),
+		),
+	)
+
+	mustBeConstantPerInstance(comp, mod.IsActive)
+	mustCancelWhenBinCancel(comp, mod.IsActive, mod.Limbs)
+	mustCancelWhenBinCancel(comp, mod.IsActive, mod.IsFirstRound)
+	mustCancelWhenBinCancel(comp, mod.IsActive, mod.IsLastRound)
+
+	// The rows that are not sent to the circuit are unused
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_UNUSED_ROWS_ARE_ZERO",
+		sym.Mul(
+			sym.Sub(1, mod.IsCircuitRow),
+			mod.Limbs,
+		),
+	)
+}
+
+// csFirstAndLastRound constrains IsFirstRound and IsLastRound. The instance
+// following the last round of a call (or the first instance of the module) is
+// the first round of a call. The last active instance must be the last round
+// of a call so that a call cannot be cut in the middle.
+func (mod *Module) csFirstAndLastRound(comp *wizard.CompiledIOP) {
+
+	mustBeBinary(comp, mod.IsFirstRound)
+	mustBeBinary(comp, mod.IsLastRound)
+	mustBeConstantPerInstance(comp, mod.IsFirstRound)
+	mustBeConstantPerInstance(comp, mod.IsLastRound)
+
+	var (
+		prevIsActive = column.Shift(mod.IsActive, -1)
+		prevIsLast   = column.Shift(mod.IsLastRound, -1)
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_FIRST_ROUND_FOLLOWS_LAST_ROUND",
+		sym.Mul(
+			isInstanceStart(),
+			sym.Sub(
+				mod.IsFirstRound,
+				sym.Mul(
+					mod.IsActive,
+					sym.Add(prevIsLast, sym.Sub(1, prevIsActive)),
+				),
+			),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_NO_INACTIVE_INSTANCE_AFTER_NON_LAST_ROUND",
+		sym.Mul(
+			isInstanceStart(),
+			prevIsActive,
+			sym.Sub(1, prevIsLast),
+			sym.Sub(1, mod.IsActive),
+		),
+	)
+
+	comp.InsertLocal(
+		0,
+		"BLAKE2F_IS_FIRST_ROUND_AT_BEGINNING",
+		sym.Sub(mod.IsFirstRound, mod.IsActive),
+	)
+
+	comp.InsertLocal(
+		0,
+		"BLAKE2F_IS_LAST_ROUND_AT_END",
+		sym.Sub(
+			column.Shift(mod.IsLastRound, -1),
+			column.Shift(mod.IsActive, -1),
+		),
+	)
+}
+
+// csRoundChaining ensures that the call data is replicated from an instance to
+// the next one within a call and that the input working vector of a round is
+// the output working vector of the previous round.
+func (mod *Module) csRoundChaining(comp *wizard.CompiledIOP) {
+
+	isChained := sym.Sub(mod.IsActive, mod.IsFirstRound)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_CALL_IS_REPLICATED",
+		sym.Mul(
+			mod.IsCallRow,
+			isChained,
+			sym.Sub(mod.Limbs, column.Shift(mod.Limbs, -nbRowsPerInstance)),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_V_IN_IS_PREVIOUS_V_OUT",
+		sym.Mul(
+			mod.IsVInRow,
+			isChained,
+			sym.Sub(
+				mod.Limbs,
+				column.Shift(mod.Limbs, offsetVOut-offsetVIn-nbRowsPerInstance),
+			),
+		),
+	)
+
+	// The input working vector is computed from the call by the circuit for
+	// the first round. The corresponding rows are set to zero.
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_V_IN_IS_ZERO_FOR_FIRST_ROUND",
+		sym.Mul(mod.IsVInRow, mod.IsFirstRound, mod.Limbs),
+	)
+}
+
+// csRoundRows constrains the rows of the instances storing the round index and
+// the first/last round flags.
+func (mod *Module) csRoundRows(comp *wizard.CompiledIOP) {
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_ROUND_INDEX_INCREMENTS",
+		sym.Mul(
+			variables.NewPeriodicSample(nbRowsPerInstance, offsetRoundIdx),
+			sym.Sub(
+				mod.Limbs,
+				sym.Mul(
+					sym.Sub(mod.IsActive, mod.IsFirstRound),
+					sym.Add(column.Shift(mod.Limbs, -nbRowsPerInstance), 1),
+				),
+			),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_FIRST_ROW",
+		sym.Mul(
+			variables.NewPeriodicSample(nbRowsPerInstance, offsetIsFirst),
+			sym.Sub(mod.Limbs, mod.IsFirstRound),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_LAST_ROW",
+		sym.Mul(
+			variables.NewPeriodicSample(nbRowsPerInstance, offsetIsLast),
+			sym.Sub(mod.Limbs, mod.IsLastRound),
+		),
+	)
+}
+
+// csFilters ensures the well-construction of IsProjected and ToCircuit
+func (mod *Module) csFilters(comp *wizard.CompiledIOP) {
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_PROJECTED_VAL",
+		sym.Sub(
+			mod.IsProjected,
+			sym.Mul(mod.IsFirstRound, mod.IsCallRow),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_TO_CIRCUIT_VAL",
+		sym.Sub(
+			mod.ToCircuit,
+			sym.Mul(mod.IsActive, mod.IsCircuitRow),
+		),
+	)
+}
+
+// mustBeBinary constraints c to be binary
+func mustBeBinary(comp *wizard.CompiledIOP, c ifaces.Column) {
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("%v_CANCEL_IS_BINARY", c.GetColID()),
+		sym.Mul(c, sym.Sub(c, 1)),
+	)
+}
+
+// mustCancelWhenBinCancel enforces to 'c' to be zero when the binary column
+// `bin` is zero. The constraint does not work if bin is not constrained to be
+// binary.
+func mustCancelWhenBinCancel(comp *wizard.CompiledIOP, bin, c ifaces.Column) {
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("%v_CANCEL_WHEN_NOT_%v", c.GetColID(), bin.GetColID()),
+		sym.Mul(
+			sym.Sub(1, bin),
+			c,
+		),
+	)
+}
+
+// mustBeConstantPerInstance enforces 'c' to only change value at the beginning
+// of an instance.
+func mustBeConstantPerInstance(comp *wizard.CompiledIOP, c ifaces.Column) {
+
+	comp.InsertGlobal(
+		0,
+		ifaces.QueryIDf("%v_CONSTANT_PER_INSTANCE", c.GetColID()),
+		sym.Mul(
+			sym.Sub(1, isInstanceStart()),
+			sym.Sub(c, column.Shift(c, -1)),
+		),
+	)
+}
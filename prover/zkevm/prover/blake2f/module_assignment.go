@@ -0,0 +1,200 @@
+package blake2f
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/blake2f"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+)
+
+// moduleAssignment is a builder structure used to incrementally compute the
+// assignment of the column of the [Module] module.
+type moduleAssignment struct {
+	isActive     *common.VectorBuilder
+	isFirstRound *common.VectorBuilder
+	isLastRound  *common.VectorBuilder
+	limbs        *common.VectorBuilder
+	isProjected  *common.VectorBuilder
+	toCircuit    *common.VectorBuilder
+}
+
+// Assign assigns the BLAKE2f module
+func (mod *Module) Assign(run *wizard.ProverRuntime) {
+
+	mod.Input.assignIsBlake(run)
+
+	var (
+		roundCount int = 0
+		isBlake        = mod.Input.isBlake.GetColAssignment(run).IntoRegVecSaveAlloc()
+		limbs          = mod.Input.Limbs.GetColAssignment(run).IntoRegVecSaveAlloc()
+		builder        = moduleAssignment{
+			isActive:     common.NewVectorBuilder(mod.IsActive),
+			isFirstRound: common.NewVectorBuilder(mod.IsFirstRound),
+			isLastRound:  common.NewVectorBuilder(mod.IsLastRound),
+			limbs:        common.NewVectorBuilder(mod.Limbs),
+			isProjected:  common.NewVectorBuilder(mod.IsProjected),
+			toCircuit:    common.NewVectorBuilder(mod.ToCircuit),
+		}
+	)
+
+	for currPosition := 0; currPosition < len(limbs); {
+
+		if isBlake[currPosition].IsZero() {
+			currPosition++
+			continue
+		}
+
+		// This sanity-check is purely defensive and will indicate that we
+		// missed the start of a BLAKE2f call
+		if len(limbs)-currPosition < nbRowsPerCall {
+			utils.Panic("A new blake2f call is starting but there is not enough rows (currPosition=%v len(limbs)=%v)", currPosition, len(limbs))
+		}
+
+		var (
+			call        = limbs[currPosition : currPosition+nbRowsPerCall]
+			in          = parseCall(call)
+			traces      = make([]blake2f.State, 0, in.Rounds)
+			nbInstances = max(int(in.Rounds), 1)
+		)
+
+		// The check is done before running the compression function because
+		// the number of rounds of a call can be as large as 2^32 - 1.
+		exit.PanicOnLimitOverflow("BLAKE2F_ROUNDS", roundCount+nbInstances, mod.MaxNbRounds)
+
+		roundCount += nbInstances
+		blake2f.Compress(in, &traces)
+
+		for k := 0; k < nbInstances; k++ {
+
+			var (
+				isFirst = k == 0
+				isLast  = k == nbInstances-1
+				vIn     = [nbLimbsPerState]field.Element{}
+				vOut    = [nbLimbsPerState]field.Element{}
+			)
+
+			if !isFirst {
+				vIn = encodeState(traces[k-1])
+			}
+
+			if in.Rounds == 0 {
+				vOut = encodeState(blake2f.Init(in.H, in.T, in.F))
+			} else {
+				vOut = encodeState(traces[k])
+			}
+
+			for row := 0; row < nbRowsPerInstance; row++ {
+
+				var limb field.Element
+
+				switch {
+				case row < nbRowsPerCall:
+					limb = call[row]
+				case row == offsetRoundIdx:
+					limb.SetInt64(int64(k))
+				case row == offsetIsFirst:
+					limb = boolToField(isFirst)
+				case row == offsetIsLast:
+					limb = boolToField(isLast)
+				case row >= offsetVIn && row < offsetVIn+nbLimbsPerState:
+					limb = vIn[row-offsetVIn]
+				case row >= offsetVOut && row < offsetVOut+nbLimbsPerState:
+					limb = vOut[row-offsetVOut]
+				}
+
+				builder.isActive.PushOne()
+				builder.isFirstRound.PushBoolean(isFirst)
+				builder.isLastRound.PushBoolean(isLast)
+				builder.limbs.PushField(limb)
+				builder.isProjected.PushBoolean(isFirst && row < nbRowsPerCall)
+				builder.toCircuit.PushBoolean(row < nbRowsToCircuit)
+			}
+		}
+
+		currPosition += nbRowsPerCall
+	}
+
+	builder.isActive.PadAndAssign(run, field.Zero())
+	builder.isFirstRound.PadAndAssign(run, field.Zero())
+	builder.isLastRound.PadAndAssign(run, field.Zero())
+	builder.limbs.PadAndAssign(run, field.Zero())
+	builder.isProjected.PadAndAssign(run, field.Zero())
+	builder.toCircuit.PadAndAssign(run, field.Zero())
+
+	// It is possible to not declare the circuit (for testing purpose) in that
+	// case we skip the corresponding assignment part.
+	if mod.hasCircuit {
+		registerGnarkHint()
+		mod.GnarkCircuitConnector.Assign(run)
+	}
+}
+
+// parseCall reconstructs the precompile input from the limbs of a call as laid
+// out in the arithmetization.
+func parseCall(call []field.Element) blake2f.Input {
+
+	var (
+		res   = blake2f.Input{}
+		bytes = make([]byte, 0, (offsetR-offsetH)*16)
+	)
+
+	for i := offsetH; i < offsetR; i++ {
+		bytes = append(bytes, limbBytes(call[i])...)
+	}
+
+	for i := range res.H {
+		res.H[i] = binary.LittleEndian.Uint64(bytes[8*i:])
+	}
+
+	for i := range res.M {
+		res.M[i] = binary.LittleEndian.Uint64(bytes[(offsetM-offsetH)*16+8*i:])
+	}
+
+	res.T[0] = binary.LittleEndian.Uint64(bytes[(offsetT-offsetH)*16:])
+	res.T[1] = binary.LittleEndian.Uint64(bytes[(offsetT-offsetH)*16+8:])
+
+	rounds := call[offsetR].Uint64()
+	if rounds >= 1<<32 {
+		utils.Panic("the number of rounds does not fit on 4 bytes: %v", rounds)
+	}
+
+	res.Rounds = uint32(rounds)
+	res.F = !call[offsetF].IsZero()
+
+	return res
+}
+
+// encodeState returns the limbs representing a working vector. The limbs are
+// encoded in the same way as the hasher state in the arithmetization: the
+// words are serialized in little-endian order and the result is chunked in 16
+// bytes big-endian limbs.
+func encodeState(v blake2f.State) (res [nbLimbsPerState]field.Element) {
+
+	var bytes [16 * nbLimbsPerState]byte
+	for i := range v {
+		binary.LittleEndian.PutUint64(bytes[8*i:], v[i])
+	}
+
+	for i := range res {
+		res[i].SetBytes(bytes[16*i : 16*i+16])
+	}
+
+	return res
+}
+
+// limbBytes returns the 16 bytes big-endian representation of a limb
+func limbBytes(limb field.Element) []byte {
+	b := limb.Bytes()
+	return b[32-16:]
+}
+
+func boolToField(b bool) field.Element {
+	if b {
+		return field.One()
+	}
+	return field.Zero()
+}
@@ -0,0 +1,79 @@
+package blake2f
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestBlake2fModule(t *testing.T) {
+
+	testCases := []struct {
+		InputFName, ModuleFName string
+		MaxNbRounds             int
+	}{
+		{
+			InputFName:  "testdata/single_12_rounds_input.csv",
+			ModuleFName: "testdata/single_12_rounds_module.csv",
+			MaxNbRounds: 16,
+		},
+		{
+			InputFName:  "testdata/multi_calls_input.csv",
+			ModuleFName: "testdata/multi_calls_module.csv",
+			MaxNbRounds: 16,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.InputFName, func(t *testing.T) {
+
+			var (
+				inp   Input
+				mod   *Module
+				inpCt = csvtraces.MustOpenCsvFile(tc.InputFName)
+				modCt = csvtraces.MustOpenCsvFile(tc.ModuleFName)
+			)
+
+			cmp := wizard.Compile(func(build *wizard.Builder) {
+				inp = Input{
+					IsBlakeData:   inpCt.GetCommit(build, "IS_BLAKE_DATA"),
+					IsBlakeParams: inpCt.GetCommit(build, "IS_BLAKE_PARAMS"),
+					IsBlakeResult: inpCt.GetCommit(build, "IS_BLAKE_RESULT"),
+					Limbs:         inpCt.GetCommit(build, "LIMBS"),
+					Settings:      Settings{MaxNbRounds: tc.MaxNbRounds},
+				}
+
+				mod = newModule(build.CompiledIOP, inp)
+			}, dummy.Compile)
+
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+
+				inpCt.Assign(run,
+					"LIMBS",
+					"IS_BLAKE_DATA",
+					"IS_BLAKE_PARAMS",
+					"IS_BLAKE_RESULT",
+				)
+
+				mod.Assign(run)
+
+				modCt.CheckAssignment(run,
+					"BLAKE2F_LIMBS",
+					"BLAKE2F_IS_ACTIVE",
+					"BLAKE2F_IS_FIRST_ROUND",
+					"BLAKE2F_IS_LAST_ROUND",
+					"BLAKE2F_IS_PROJECTED",
+					"BLAKE2F_TO_CIRCUIT",
+				)
+			})
+
+			if err := wizard.Verify(cmp, proof); err != nil {
+				t.Fatal("proof failed", err)
+			}
+
+			t.Log("proof succeeded")
+		})
+	}
+}
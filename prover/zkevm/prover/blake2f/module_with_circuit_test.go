@@ -0,0 +1,73 @@
+//go:build !fuzzlight
+
+package blake2f
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestBlake2fWithCircuit(t *testing.T) {
+
+	testCases := []struct {
+		InputFName  string
+		MaxNbRounds int
+	}{
+		{
+			InputFName:  "testdata/single_12_rounds_input.csv",
+			MaxNbRounds: 16,
+		},
+		{
+			// The limit is chosen so that the last circuit instance is
+			// partially zero-padded.
+			InputFName:  "testdata/multi_calls_input.csv",
+			MaxNbRounds: 20,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.InputFName, func(t *testing.T) {
+
+			var (
+				inp   Input
+				mod   *Module
+				inpCt = csvtraces.MustOpenCsvFile(tc.InputFName)
+			)
+
+			cmp := wizard.Compile(func(build *wizard.Builder) {
+				inp = Input{
+					IsBlakeData:   inpCt.GetCommit(build, "IS_BLAKE_DATA"),
+					IsBlakeParams: inpCt.GetCommit(build, "IS_BLAKE_PARAMS"),
+					IsBlakeResult: inpCt.GetCommit(build, "IS_BLAKE_RESULT"),
+					Limbs:         inpCt.GetCommit(build, "LIMBS"),
+					Settings:      Settings{MaxNbRounds: tc.MaxNbRounds},
+				}
+
+				mod = newModule(build.CompiledIOP, inp).
+					WithCircuit(build.CompiledIOP, plonk.WithRangecheck(16, 6, false))
+			}, dummy.Compile)
+
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+
+				inpCt.Assign(run,
+					"LIMBS",
+					"IS_BLAKE_DATA",
+					"IS_BLAKE_PARAMS",
+					"IS_BLAKE_RESULT",
+				)
+
+				mod.Assign(run)
+			})
+
+			if err := wizard.Verify(cmp, proof); err != nil {
+				t.Fatal("proof failed", err)
+			}
+
+			t.Log("proof succeeded")
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand/v2"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/crypto/blake2f"
+)
+
+func main() {
+
+	for _, tcase := range testCases {
+		f := files.MustOverwrite("./" + tcase.name + "_input.csv")
+		dumpAsCsv(f, tcase.tab)
+		f.Close()
+	}
+}
+
+var testCases = []struct {
+	name string
+	tab  [][]*big.Int
+}{
+	{
+		name: "single_12_rounds",
+		tab: func() [][]*big.Int {
+
+			var (
+				tab  = make([][]*big.Int, 4)
+				rng  = rand.New(rand.NewChaCha8([32]byte{}))
+				inst = createRandomCall(rng, 12, true)
+			)
+
+			pushCallToInput(inst, tab)
+			return tab
+		}(),
+	},
+	{
+		name: "multi_calls",
+		tab: func() [][]*big.Int {
+
+			var (
+				tab = make([][]*big.Int, 4)
+				rng = rand.New(rand.NewChaCha8([32]byte{}))
+			)
+
+			pushFillerToInput(tab, rng, 3)
+			pushCallToInput(createRandomCall(rng, 0, true), tab)
+			pushCallToInput(createRandomCall(rng, 1, false), tab)
+			pushFillerToInput(tab, rng, 5)
+			pushCallToInput(createRandomCall(rng, 11, false), tab)
+			return tab
+		}(),
+	},
+}
+
+func createRandomCall(rng *rand.Rand, rounds uint32, final bool) blake2f.Input {
+
+	res := blake2f.Input{Rounds: rounds, F: final}
+
+	for i := range res.H {
+		res.H[i] = rng.Uint64()
+	}
+
+	for i := range res.M {
+		res.M[i] = rng.Uint64()
+	}
+
+	res.T[0] = rng.Uint64()
+	res.T[1] = rng.Uint64()
+
+	return res
+}
+
+func dumpAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "LIMBS,IS_BLAKE_DATA,IS_BLAKE_PARAMS,IS_BLAKE_RESULT\n")
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "0x%v,%v,%v,%v\n", tab[0][i].Text(16), tab[1][i].String(), tab[2][i].String(), tab[3][i].String())
+	}
+}
+
+func pushFillerToInput(tab [][]*big.Int, rng *rand.Rand, numRow int) {
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], new(big.Int).SetUint64(rng.Uint64()))
+		tab[1] = append(tab[1], &big.Int{})
+		tab[2] = append(tab[2], &big.Int{})
+		tab[3] = append(tab[3], &big.Int{})
+	}
+}
+
+func pushCallToInput(inst blake2f.Input, tab [][]*big.Int) {
+
+	var (
+		data  = make([]byte, 0, 208)
+		out   = blake2f.EncodeOutput(blake2f.Compress(inst, nil))
+		zero  = &big.Int{}
+		one   = big.NewInt(1)
+		final = &big.Int{}
+	)
+
+	for i := range inst.H {
+		data = binary.LittleEndian.AppendUint64(data, inst.H[i])
+	}
+
+	for i := range inst.M {
+		data = binary.LittleEndian.AppendUint64(data, inst.M[i])
+	}
+
+	data = binary.LittleEndian.AppendUint64(data, inst.T[0])
+	data = binary.LittleEndian.AppendUint64(data, inst.T[1])
+
+	for _, limb := range splitInLimbsOf128Bits(data) {
+		tab[0] = append(tab[0], limb)
+		tab[1] = append(tab[1], one)
+		tab[2] = append(tab[2], zero)
+		tab[3] = append(tab[3], zero)
+	}
+
+	if inst.F {
+		final.SetInt64(1)
+	}
+
+	for _, param := range []*big.Int{big.NewInt(int64(inst.Rounds)), final} {
+		tab[0] = append(tab[0], param)
+		tab[1] = append(tab[1], zero)
+		tab[2] = append(tab[2], one)
+		tab[3] = append(tab[3], zero)
+	}
+
+	for _, limb := range splitInLimbsOf128Bits(out[:]) {
+		tab[0] = append(tab[0], limb)
+		tab[1] = append(tab[1], zero)
+		tab[2] = append(tab[2], zero)
+		tab[3] = append(tab[3], one)
+	}
+}
+
+func splitInLimbsOf128Bits(b []byte) []*big.Int {
+
+	res := make([]*big.Int, len(b)/16)
+
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(b[16*i : 16*i+16])
+	}
+
+	return res
+}
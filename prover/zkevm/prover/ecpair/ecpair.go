@@ -28,6 +28,12 @@ const (
 	nbGtLimbs = 24
 )
 
+// PointLimbs returns the number of limbs of a G1 and of a G2 point in the
+// ecdata module of the arithmetization.
+func PointLimbs() (nbG1, nbG2 int) {
+	return nbG1Limbs, nbG2Limbs
+}
+
 // ECPair represents the constraints for proving the ECPAIR precompile. It is composed of:
 // - ECPairSource: the source columns from arithmetization
 // - UnalignedPairingData: the unaligned columns for the pairing data
@@ -28,6 +28,15 @@ var (
 		laneSizeBytes:     4,
 		nbOfLanesPerBlock: 16,
 	}
+
+	// RipemdUsecase represents using the RIPEMD-160 hash function. The lanes
+	// are packed in big-endian order like for Sha2; the conversion into the
+	// little-endian words of RIPEMD-160 is done by the compression module.
+	RipemdUsecase = HashingUsecase{
+		paddingStrat:      ripemdPadding,
+		laneSizeBytes:     4,
+		nbOfLanesPerBlock: 16,
+	}
 )
 
 type paddingStrat int
@@ -36,6 +45,7 @@ const (
 	zeroPadding paddingStrat = iota
 	keccakPadding
 	sha2Padding
+	ripemdPadding
 )
 
 type HashingUsecase struct {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/keccak"
+	"github.com/consensys/linea-monorepo/prover/crypto/ripemd"
 	"github.com/consensys/linea-monorepo/prover/crypto/sha2"
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
@@ -31,6 +32,12 @@ var testCases = []struct {
 		UseCase:     generic.Sha2Usecase,
 		PaddingFunc: sha2.PadStream,
 	},
+	{
+		Name:        "Ripemd",
+		ModFilePath: "testdata/mod_ripemd.csv",
+		UseCase:     generic.RipemdUsecase,
+		PaddingFunc: ripemd.PadStream,
+	},
 	{
 		Name:        "MiMC",
 		ModFilePath: "testdata/mod_mimc.csv",
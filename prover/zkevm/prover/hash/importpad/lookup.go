@@ -34,3 +34,23 @@ func getLookupForSize(comp *wizard.CompiledIOP, size int) ifaces.Column {
 		smartvectors.RightPadded(res, field.One(), utils.NextPowerOfTwo(size)),
 	)
 }
+
+// getByteLookup returns a lookup table storing all the values in the range
+// 0..255. As for [getLookupForSize], the table is only created once.
+func getByteLookup(comp *wizard.CompiledIOP) ifaces.Column {
+
+	var (
+		res  = make([]field.Element, 256)
+		name = ifaces.ColID("LOOKUP_TABLE_RANGE_0_255")
+	)
+
+	if comp.Columns.Exists(name) {
+		return comp.Columns.GetHandle(name)
+	}
+
+	for i := range res {
+		res[i].SetInt64(int64(i))
+	}
+
+	return comp.InsertPrecomputed(name, smartvectors.NewRegular(res))
+}
@@ -0,0 +1,280 @@
+package importpad
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+)
+
+// ripemdNbLengthBytes is the number of bytes of the bit-length of the stream
+// that we allow to be non-zero. The remaining 4 bytes of the 64 bits length
+// field are constrained to be zero, which limits the size of the hashed
+// streams to 512MiB. This is way beyond what the limits of the zkEVM allow.
+const ripemdNbLengthBytes = 4
+
+// ripemdPadder implements the [padder] interface for the RIPEMD-160 hash
+// function. The padding is identical to the one of Sha2 except that the
+// bit-length of the stream is appended in little-endian order. Since the
+// byte-reversal cannot be expressed as a low-degree polynomial, the bit-length
+// is decomposed in bytes in the LengthBytes columns.
+type ripemdPadder struct {
+	AccInsertedBytes ifaces.Column
+	LengthBytes      [ripemdNbLengthBytes]ifaces.Column
+}
+
+// ripemdPaddingAssignmentBuilder is a utility serving during the assignment of
+// the ripemdPadder module
+type ripemdPaddingAssignmentBuilder struct {
+	AccInsertedBytes *common.VectorBuilder
+	LengthBytes      [ripemdNbLengthBytes]*common.VectorBuilder
+}
+
+// newRipemdPadder declares all the constraints ensuring the imported byte
+// strings are properly padded following the specification of RIPEMD-160.
+func (ipad *importation) newRipemdPadder(comp *wizard.CompiledIOP) padder {
+
+	// The padding structure is
+	//
+	// 	=> xxxxxxx 		|| 	size n 	bytes	||	isPadded:false || 	isLastPadded:false
+	// 	=> 		1 		|| 	size 1 	bytes	||	isPadded:true  || 	isLastPadded:false
+	// 	=>		0 		||	size n0	bytes	||	isPadded:true  || 	isLastPadded:false
+	// 	=>		..		|| 	size .. bytes	||	isPadded:true  || 	isLastPadded:false
+	// 	=> LE([msgSize])||	size 8	bytes	||	isPadded:true  || 	isLastPadded:true
+	//
+	// The constraints are the same as for Sha2 except for the value of the
+	// last padded limb which is constrained as:
+	//
+	//		if isLastPadded[i] == 1:
+	//			accInsertedBytes[i] * 8 == sum_k lengthBytes_k[i] * 256^k
+	//			limbs[i] == sum_k lengthBytes_k[i] * 2^(120 - 8k)
+	//
+	// and the lengthBytes columns are range-checked to be bytes.
+
+	var (
+		numRows = ipad.Limbs.Size()
+		pad     = &ripemdPadder{
+			AccInsertedBytes: comp.InsertCommit(0,
+				ifaces.ColIDf("%v_RIPEMD_ACC_INSERTED_BYTES", ipad.Inputs.Name),
+				numRows,
+			),
+		}
+	)
+
+	for k := range pad.LengthBytes {
+		pad.LengthBytes[k] = comp.InsertCommit(0,
+			ifaces.ColIDf("%v_RIPEMD_LENGTH_BYTE_%v", ipad.Inputs.Name, k),
+			numRows,
+		)
+	}
+
+	var (
+		isInsertedPrev       = column.Shift(ipad.IsInserted, -1)
+		isInserted           = ipad.IsInserted
+		isPaddedPrev         = column.Shift(ipad.IsPadded, -1)
+		isPadded             = ipad.IsPadded
+		isPaddedNext         = column.Shift(ipad.IsPadded, 1)
+		isLastPadded         = sym.Mul(isPadded, sym.Sub(1, isPaddedNext))
+		nbBytes              = ipad.NBytes
+		accInsertedBytesPrev = column.Shift(pad.AccInsertedBytes, -1)
+		accInsertedBytes     = pad.AccInsertedBytes
+		isBinary             = func(x any) *sym.Expression {
+			return sym.Sub(
+				sym.Mul(x, x),
+				x,
+			)
+		}
+		lengthFromBytes = []any{}
+		limbFromBytes   = []any{}
+	)
+
+	for k := range pad.LengthBytes {
+		lengthFromBytes = append(lengthFromBytes, sym.Mul(pad.LengthBytes[k], 1<<(8*k)))
+		limbFromBytes = append(limbFromBytes, sym.Mul(pad.LengthBytes[k], leftAlign(1<<(56-8*k), 8)))
+	}
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_PADDING_AT_LEAST_TWO_LIMBS", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			isBinary(sym.Add(isPaddedPrev, isPaddedNext, -1)),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_FIRST_PADDING_HAS_1_BYTE", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			sym.Sub(1, isPaddedPrev),
+			sym.Sub(nbBytes, 1),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_LAST_PADDING_HAS_8_BYTE", ipad.Inputs.Name),
+		sym.Mul(
+			isLastPadded,
+			sym.Sub(nbBytes, 8),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_INTERMEDIATE_PADDING_BYTES_ARE_ZEROES", ipad.Inputs.Name),
+		sym.Mul(
+			isPaddedPrev,
+			isPadded,
+			isPaddedNext,
+			ipad.Limbs,
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_ACC_INSERTED_BYTES_CORRECTLY_SET", ipad.Inputs.Name),
+		sym.Sub(
+			accInsertedBytes,
+			sym.Mul(isPadded, accInsertedBytesPrev),
+			sym.Mul(isInserted, sym.Add(sym.Mul(isInsertedPrev, accInsertedBytesPrev), nbBytes)),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_FIRST_PADDING_VALUE", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			sym.Sub(1, isPaddedPrev),
+			sym.Sub(ipad.Limbs, leftAlign(0x80, 1)), // The domain separation byte 0b10000000
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_LENGTH_BYTES_DECOMPOSITION", ipad.Inputs.Name),
+		sym.Mul(
+			isLastPadded,
+			sym.Sub(
+				sym.Mul(accInsertedBytes, 8),
+				sym.Add(lengthFromBytes...),
+			),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_LAST_PADDING_VALUE", ipad.Inputs.Name),
+		sym.Mul(
+			isLastPadded,
+			sym.Sub(ipad.Limbs, sym.Add(limbFromBytes...)),
+		),
+	)
+
+	for k := range pad.LengthBytes {
+		comp.InsertInclusionConditionalOnIncluded(0,
+			ifaces.QueryIDf("%v_RIPEMD_LENGTH_BYTE_%v_RANGE", ipad.Inputs.Name, k),
+			[]ifaces.Column{getByteLookup(comp)},
+			[]ifaces.Column{pad.LengthBytes[k]},
+			ipad.IsPadded,
+		)
+	}
+
+	// See the Sha2 padder for the rationale behind the +8.
+	comp.InsertInclusionConditionalOnIncluded(0,
+		ifaces.QueryIDf("%v_LOOKUP_NB_PADDED_BYTES", ipad.Inputs.Name),
+		[]ifaces.Column{getLookupForSize(comp, 8+generic.RipemdUsecase.BlockSizeBytes())},
+		[]ifaces.Column{ipad.AccPaddedBytes},
+		ipad.IsPadded,
+	)
+
+	return pad
+}
+
+func newRipemdPaddingAssignmentBuilder(rp *ripemdPadder) *ripemdPaddingAssignmentBuilder {
+	res := &ripemdPaddingAssignmentBuilder{
+		AccInsertedBytes: common.NewVectorBuilder(rp.AccInsertedBytes),
+	}
+	for k := range res.LengthBytes {
+		res.LengthBytes[k] = common.NewVectorBuilder(rp.LengthBytes[k])
+	}
+	return res
+}
+
+func (rp *ripemdPadder) pushPaddingRows(byteStringSize int, ipad *importationAssignmentBuilder) {
+
+	var (
+		blocksize      = generic.RipemdUsecase.BlockSizeBytes()
+		remainToPad    = blocksize - (byteStringSize % blocksize)
+		rpa            = ipad.Padder.(*ripemdPaddingAssignmentBuilder)
+		accPaddedBytes = 0
+		bitLength      = uint64(byteStringSize) * 8
+		leLength       = uint64(0)
+	)
+
+	if bitLength >= 1<<(8*ripemdNbLengthBytes) {
+		utils.Panic("the stream is too large to be hashed with RIPEMD-160: %v bytes", byteStringSize)
+	}
+
+	if remainToPad < 9 {
+		remainToPad += 64
+	}
+
+	accPaddedBytes++
+	remainToPad--
+
+	ipad.pushPaddingCommonColumns()
+	ipad.Limbs.PushField(leftAlign(0x80, 1))
+	ipad.NBytes.PushOne()
+	ipad.AccPaddedBytes.PushOne()
+	rpa.pushLengthBytes(0)
+	rpa.AccInsertedBytes.PushInt(byteStringSize)
+
+	for remainToPad > 8 {
+		currNbBytes := utils.Min(remainToPad-8, 16)
+		accPaddedBytes += currNbBytes
+		remainToPad -= currNbBytes
+
+		ipad.pushPaddingCommonColumns()
+		ipad.Limbs.PushZero()
+		ipad.NBytes.PushInt(currNbBytes)
+		ipad.AccPaddedBytes.PushInt(accPaddedBytes)
+		rpa.pushLengthBytes(0)
+		rpa.AccInsertedBytes.PushInt(byteStringSize)
+	}
+
+	accPaddedBytes += 8
+
+	for k := 0; k < 8; k++ {
+		leLength = leLength<<8 | (bitLength>>(8*k))&0xff
+	}
+
+	ipad.pushPaddingCommonColumns()
+	ipad.Limbs.PushField(leftAlign(leLength, 8))
+	ipad.NBytes.PushInt(8)
+	ipad.AccPaddedBytes.PushInt(accPaddedBytes)
+	rpa.pushLengthBytes(bitLength)
+	rpa.AccInsertedBytes.PushInt(byteStringSize)
+}
+
+// pushLengthBytes pushes the little-endian decomposition of the bit-length on
+// the LengthBytes columns.
+func (rpa *ripemdPaddingAssignmentBuilder) pushLengthBytes(bitLength uint64) {
+	for k := range rpa.LengthBytes {
+		rpa.LengthBytes[k].PushInt(int((bitLength >> (8 * k)) & 0xff))
+	}
+}
+
+func (rpa *ripemdPaddingAssignmentBuilder) pushInsertingRow(nbBytes int, isNewHash bool) {
+	rpa.pushLengthBytes(0)
+	if isNewHash {
+		rpa.AccInsertedBytes.PushInt(nbBytes)
+	} else {
+		rpa.AccInsertedBytes.PushIncBy(nbBytes)
+	}
+}
+
+func (rpa *ripemdPaddingAssignmentBuilder) padAndAssign(run *wizard.ProverRuntime) {
+	rpa.AccInsertedBytes.PadAndAssign(run, field.Zero())
+	for k := range rpa.LengthBytes {
+		rpa.LengthBytes[k].PadAndAssign(run, field.Zero())
+	}
+}
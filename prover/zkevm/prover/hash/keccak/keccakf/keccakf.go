@@ -11,6 +11,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 )
 
@@ -126,9 +127,7 @@ func (mod *Module) Assign(
 
 	// If the number of keccakf constraints is larger than what the module
 	// is sized for, then, we cannot prove everything.
-	if numKeccakf > mod.MaxNumKeccakf {
-		utils.Panic("Too many keccakf %v > %v", numKeccakf, mod.MaxNumKeccakf)
-	}
+	exit.PanicOnLimitOverflow("KECCAKF", numKeccakf, mod.MaxNumKeccakf)
 
 	lu := mod.lookups
 	mod.assignStateAndBlocks(run, traces, numKeccakf)
@@ -9,6 +9,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	sym "github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 	commonconstraints "github.com/consensys/linea-monorepo/prover/zkevm/prover/common/common_constraints"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/packing/dedicated"
@@ -287,9 +288,7 @@ func (l *laneRepacking) getBlocks(run *wizard.ProverRuntime, inp PackingInput) (
 				utils.Panic("The stream-length should be zero before launching a new hash/batch len(stream) = %v", len(stream))
 			}
 		}
-		if ctr > inp.MaxNumBlocks {
-			utils.Panic("the number of the blocks %v passes the limit %v", ctr, inp.MaxNumBlocks)
-		}
+		exit.PanicOnLimitOverflow(inp.Name, ctr, inp.MaxNumBlocks)
 	}
 
 	// This corresponds to the edge-case were no blocks are being processed. In
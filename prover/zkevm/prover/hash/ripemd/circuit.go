@@ -0,0 +1,206 @@
+package ripemd
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Indices of the message words and rotation amounts used at each step of the
+// left and the right lines of the RIPEMD-160 compression function.
+var (
+	circR = [80]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	circRPrime = [80]int{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+	circS = [80]int{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	circSPrime = [80]int{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+	circK      = [5]uint32{0x00000000, 0x5A827999, 0x6ED9EBA1, 0x8F1BBCDC, 0xA953FD4E}
+	circKPrime = [5]uint32{0x50A28BE6, 0x5C4DD124, 0x6D703EF3, 0x7A6D76E9, 0x00000000}
+)
+
+// ripemdCircuit is the gnark circuit (compiled as Plonk) used to check the
+// RIPEMD-160 compression function.
+type ripemdCircuit struct {
+	Instances []ripemdBlockPermutationInstance `gnark:",public"`
+}
+
+func allocateRipemdCircuit(nbInstances int) *ripemdCircuit {
+	return &ripemdCircuit{
+		Instances: make([]ripemdBlockPermutationInstance, nbInstances),
+	}
+}
+
+// Define implements the [frontend.Circuit] interface
+func (rc *ripemdCircuit) Define(api frontend.API) error {
+	for i := range rc.Instances {
+		rc.Instances[i].checkRipemdPermutation(api)
+	}
+	return nil
+}
+
+// ripemdBlockPermutationInstance represents a instance of the ripemd block
+// permutation.
+type ripemdBlockPermutationInstance struct {
+	// prevDigest is the previous digest formatted as (4 bytes, 16 bytes)
+	PrevDigest [2]frontend.Variable
+	// the block formatted as [16]uint32 packed in big endian order
+	Block [16]frontend.Variable
+	// the current digest formatted as (4 bytes, 16 bytes)
+	NewDigest [2]frontend.Variable
+}
+
+// checkRipemdPermutation adds the constraints ensuring the correctness of the
+// instance.
+func (rbpi *ripemdBlockPermutationInstance) checkRipemdPermutation(api frontend.API) {
+
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		panic(fmt.Sprintf("unexpected error when instantiating `uapi`: %v", err.Error()))
+	}
+
+	var (
+		// If the new digest is zero, then the block check is skipped as this is
+		// considered a padding instance. The wizard should externally check that
+		// NewDigest = 0x0 is forbidden.
+		inpIsZero = api.Add(
+			api.IsZero(rbpi.NewDigest[0]),
+			api.IsZero(rbpi.NewDigest[1]),
+		)
+	)
+
+	var (
+		prevDigest = castDigestTo5xU32s(api, rbpi.PrevDigest)
+		newDigest  = castDigestTo5xU32s(api, rbpi.NewDigest)
+		words      = [16]uints.U32{}
+	)
+
+	// The lanes are packed in big endian order while RIPEMD-160 reads the
+	// message words in little endian order.
+	for i := range rbpi.Block {
+		blockU32 := uapi.ValueOf(rbpi.Block[i])
+		words[i] = uapi.PackLSB(uapi.UnpackMSB(blockU32)...)
+	}
+
+	recomputedNewDigest := ripemdCompressCircuit(uapi, prevDigest, words)
+
+	for i := range recomputedNewDigest {
+		// This checks that newDigest == recomputedDigest unless inpIsZero == 2
+		api.AssertIsEqual(
+			api.Mul(
+				api.Sub(inpIsZero, 2),
+				api.Sub(
+					uapi.ToValue(recomputedNewDigest[i]),
+					uapi.ToValue(newDigest[i]),
+				),
+			),
+			0,
+		)
+	}
+}
+
+// ripemdCompressCircuit implements the compression function of RIPEMD-160 over
+// uints
+func ripemdCompressCircuit(uapi *uints.BinaryField[uints.U32], dig [5]uints.U32, x [16]uints.U32) [5]uints.U32 {
+
+	boolFn := func(round int, x, y, z uints.U32) uints.U32 {
+		switch round {
+		case 0:
+			return uapi.Xor(x, y, z)
+		case 1:
+			return uapi.Or(uapi.And(x, y), uapi.And(uapi.Not(x), z))
+		case 2:
+			return uapi.Xor(uapi.Or(x, uapi.Not(y)), z)
+		case 3:
+			return uapi.Or(uapi.And(x, z), uapi.And(y, uapi.Not(z)))
+		default:
+			return uapi.Xor(x, uapi.Or(y, uapi.Not(z)))
+		}
+	}
+
+	var (
+		al, bl, cl, dl, el = dig[0], dig[1], dig[2], dig[3], dig[4]
+		ar, br, cr, dr, er = dig[0], dig[1], dig[2], dig[3], dig[4]
+	)
+
+	for j := 0; j < 80; j++ {
+
+		round := j / 16
+
+		t := uapi.Add(
+			uapi.Lrot(
+				uapi.Add(al, boolFn(round, bl, cl, dl), x[circR[j]], uints.NewU32(circK[round])),
+				circS[j],
+			),
+			el,
+		)
+		al, el, dl, cl, bl = el, dl, uapi.Lrot(cl, 10), bl, t
+
+		t = uapi.Add(
+			uapi.Lrot(
+				uapi.Add(ar, boolFn(4-round, br, cr, dr), x[circRPrime[j]], uints.NewU32(circKPrime[round])),
+				circSPrime[j],
+			),
+			er,
+		)
+		ar, er, dr, cr, br = er, dr, uapi.Lrot(cr, 10), br, t
+	}
+
+	return [5]uints.U32{
+		uapi.Add(dig[1], cl, dr),
+		uapi.Add(dig[2], dl, er),
+		uapi.Add(dig[3], el, ar),
+		uapi.Add(dig[4], al, br),
+		uapi.Add(dig[0], bl, cr),
+	}
+}
+
+// castDigestTo5xU32s converts a digest in the (4 bytes, 16 bytes) format into
+// the 5 little-endian words forming the state of RIPEMD-160.
+func castDigestTo5xU32s(api frontend.API, v [2]frontend.Variable) [5]uints.U32 {
+
+	var (
+		u8Vars = append(
+			toNBytes(api, v[0], 4),
+			toNBytes(api, v[1], 16)...,
+		)
+		u8s     = make([]uints.U8, 20)
+		u32s    = [5]uints.U32{}
+		uapi, _ = uints.New[uints.U32](api)
+	)
+
+	for i := range u8Vars {
+		// Converting this way instead of using the uapi constructor saves a
+		// rangecheck.
+		u8s[i] = uints.U8{Val: u8Vars[i]}
+	}
+
+	for i := range u32s {
+		u32s[i] = uapi.PackLSB(u8s[4*i : 4*i+4]...)
+	}
+
+	return u32s
+}
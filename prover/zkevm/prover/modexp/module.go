@@ -25,6 +25,14 @@ const (
 	nbInstancePerCircuit256, nbInstancePerCircuit4096 = 10, 1
 )
 
+// InstanceLayout returns how an instance is laid out in the blake2fmodexpdata
+// module of the arithmetization: its number of rows, the number of 16-bytes
+// limbs of each of its 4 operands and the number of trailing limbs of an
+// operand that fits in 256 bits.
+func InstanceLayout() (nbRows, nbLimbsPerOperand, nbLimbsSmallOperand int) {
+	return modexpNumRowsPerInstance, 32, 2
+}
+
 // Module implements the wizard part responsible for checking the MODEXP
 // claims coming from the BLKMDXP module of the arithmetization.
 type Module struct {
@@ -1,13 +1,11 @@
 package modexp
 
 import (
-	"os"
-
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
-	"github.com/sirupsen/logrus"
 )
 
 // antichamberAssignment is a builder structure used to incrementally compute
@@ -86,15 +84,8 @@ func (mod *Module) Assign(run *wizard.ProverRuntime) {
 		currPosition += modexpNumRowsPerInstance
 	}
 
-	if modexpCountSmall > mod.MaxNb256BitsInstances {
-		logrus.Errorf("limit overflow: the modexp (256 bits) count is %v and the limit is %v\n", modexpCountSmall, mod.MaxNb256BitsInstances)
-		os.Exit(77)
-	}
-
-	if modexpCountLarge > mod.MaxNb4096BitsInstances {
-		logrus.Errorf("limit overflow: the modexp (4096 bits) count is %v and the limit is %v\n", modexpCountSmall, mod.MaxNb4096BitsInstances)
-		os.Exit(77)
-	}
+	exit.PanicOnLimitOverflow("MODEXP_256_BITS", modexpCountSmall, mod.MaxNb256BitsInstances)
+	exit.PanicOnLimitOverflow("MODEXP_4096_BITS", modexpCountLarge, mod.MaxNb4096BitsInstances)
 
 	builder.isActive.PadAndAssign(run, field.Zero())
 	builder.isSmall.PadAndAssign(run, field.Zero())
@@ -157,6 +157,26 @@ func newAssignmentBuilder(s Settings) *assignmentBuilder {
 	return &amb
 }
 
+// NumProofsOf returns the number of Merkle proofs that [Module.Assign]
+// registers for the provided traces. This is what is checked against
+// [Settings.MaxNumProofs].
+func NumProofsOf(traces []statemanager.DecodedTrace) int {
+
+	res := 0
+
+	for _, trace := range traces {
+		switch trace.Underlying.(type) {
+		case statemanager.InsertionTraceST, statemanager.InsertionTraceWS,
+			statemanager.DeletionTraceST, statemanager.DeletionTraceWS:
+			res += 6
+		default:
+			res += 2
+		}
+	}
+
+	return res
+}
+
 // Assign is a high level function which is used to arithmetize the columns
 // of the Accumulator module from a slice of decoded traces
 func (am *Module) Assign(
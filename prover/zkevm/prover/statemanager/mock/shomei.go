@@ -606,21 +606,42 @@ func sortedKeysOf[T sortable, U any](m map[T]U) []T {
 	return res
 }
 
-// asDecodedTrace converts a shomei trace
+// asDecodedTrace converts a shomei trace. The type of the trace is also set in
+// the underlying trace as this is what is serialized in JSON.
 func asDecodedTrace(location string, trace accumulator.Trace) statemanager.DecodedTrace {
 
-	var new = statemanager.DecodedTrace{Underlying: trace}
-	switch trace.(type) {
-	case statemanager.ReadZeroTraceST, statemanager.ReadZeroTraceWS:
-		new.Type = statemanager.READ_ZERO_TRACE_CODE
-	case statemanager.ReadNonZeroTraceST, statemanager.ReadNonZeroTraceWS:
-		new.Type = statemanager.READ_TRACE_CODE
-	case statemanager.InsertionTraceST, statemanager.InsertionTraceWS:
-		new.Type = statemanager.INSERTION_TRACE_CODE
-	case statemanager.UpdateTraceST, statemanager.UpdateTraceWS:
-		new.Type = statemanager.UPDATE_TRACE_CODE
-	case statemanager.DeletionTraceST, statemanager.DeletionTraceWS:
-		new.Type = statemanager.DELETION_TRACE_CODE
+	var new = statemanager.DecodedTrace{}
+	switch t := trace.(type) {
+	case statemanager.ReadZeroTraceST:
+		t.Type = statemanager.READ_ZERO_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.ReadZeroTraceWS:
+		t.Type = statemanager.READ_ZERO_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.ReadNonZeroTraceST:
+		t.Type = statemanager.READ_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.ReadNonZeroTraceWS:
+		t.Type = statemanager.READ_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.InsertionTraceST:
+		t.Type = statemanager.INSERTION_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.InsertionTraceWS:
+		t.Type = statemanager.INSERTION_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.UpdateTraceST:
+		t.Type = statemanager.UPDATE_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.UpdateTraceWS:
+		t.Type = statemanager.UPDATE_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.DeletionTraceST:
+		t.Type = statemanager.DELETION_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
+	case statemanager.DeletionTraceWS:
+		t.Type = statemanager.DELETION_TRACE_CODE
+		new.Type, new.Underlying = t.Type, t
 	default:
 		utils.Panic("invalid type: %T", trace)
 	}
@@ -226,6 +226,24 @@ func (b *StateLogBuilder) IncNonce() *StateLogBuilder {
 	return b
 }
 
+// WriteNonce instructs the StateFrameBuilder to generate a frame representing
+// an update of the nonce to an arbitrary value.
+func (b *StateLogBuilder) WriteNonce(nonce int64) *StateLogBuilder {
+	oldNonce := b.currState.GetNonce(b.currAddress)
+	b.currState.SetNonce(b.currAddress, nonce)
+	b.pushFrame(
+		StateAccessLog{
+			Block:    b.currBlock,
+			Address:  b.currAddress,
+			Type:     Nonce,
+			Value:    nonce,
+			OldValue: oldNonce,
+			IsWrite:  true,
+		},
+	)
+	return b
+}
+
 // ReadCodeSize instructs the StateFrameBuilder to generate a frame for reading
 // the codesize of the current contract.
 func (b *StateLogBuilder) ReadCodeSize() *StateLogBuilder {
@@ -0,0 +1,263 @@
+package mock
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// WorldStateSpec is the JSON description of a world state from which the
+// Shomei traces are generated. See [WorldStateSpec.State].
+type WorldStateSpec struct {
+	Accounts []AccountSpec `json:"accounts"`
+}
+
+// AccountSpec is the JSON description of an account of a [WorldStateSpec].
+// The code hashes may be omitted for an account without code, they then
+// default to the ones of the empty code.
+type AccountSpec struct {
+	Address        types.EthAddress  `json:"address"`
+	Nonce          int64             `json:"nonce"`
+	Balance        *hexutil.Big      `json:"balance"`
+	KeccakCodeHash types.FullBytes32 `json:"keccakCodeHash"`
+	MimcCodeHash   types.Bytes32     `json:"mimcCodeHash"`
+	CodeSize       int64             `json:"codeSize"`
+	Storage        []StorageSlot     `json:"storage"`
+}
+
+// StorageSlot is a storage key along with its value
+type StorageSlot struct {
+	Key   types.FullBytes32 `json:"key"`
+	Value types.FullBytes32 `json:"value"`
+}
+
+// CodeSpec describes the code of an account deployed in a [AccountDiff]. A
+// zero code size with zero code hashes stands for an EOA.
+type CodeSpec struct {
+	CodeSize       int64             `json:"codeSize"`
+	KeccakCodeHash types.FullBytes32 `json:"keccakCodeHash"`
+	MimcCodeHash   types.Bytes32     `json:"mimcCodeHash"`
+}
+
+// BlockStateDiff lists the accounts touched by a block. A block touches at
+// least one account, e.g. its coinbase.
+type BlockStateDiff struct {
+	Accounts []AccountDiff `json:"accounts"`
+}
+
+// AccountDiff describes how a block touches an account. An account that is
+// only listed is read. The fields are applied in the following order:
+//
+//   - for an account existing at the beginning of the block: the storage
+//     reads, the deletion if Deleted is set, the (re)deployment if Created is
+//     set and then the writes.
+//   - for a missing account: the deployment if Created is set, the reads, the
+//     writes and then the deletion if Deleted is set, which makes the account
+//     ephemeral.
+//
+// The writes are the new values of the nonce, of the balance and of the
+// storage slots. Writing a zero value into a storage slot clears it.
+type AccountDiff struct {
+	Address       types.EthAddress    `json:"address"`
+	Deleted       bool                `json:"deleted,omitempty"`
+	Created       *CodeSpec           `json:"created,omitempty"`
+	Nonce         *int64              `json:"nonce,omitempty"`
+	Balance       *hexutil.Big        `json:"balance,omitempty"`
+	StorageReads  []types.FullBytes32 `json:"storageReads,omitempty"`
+	StorageWrites []StorageSlot       `json:"storageWrites,omitempty"`
+}
+
+// State returns the [State] described by the spec
+func (spec *WorldStateSpec) State() (State, error) {
+
+	state := State{}
+
+	for _, acc := range spec.Accounts {
+
+		if _, ok := state[acc.Address]; ok {
+			return nil, fmt.Errorf("the account %v is listed twice", acc.Address.Hex())
+		}
+
+		code := CodeSpec{CodeSize: acc.CodeSize, KeccakCodeHash: acc.KeccakCodeHash, MimcCodeHash: acc.MimcCodeHash}.withDefaults()
+		state.InsertContract(acc.Address, code.MimcCodeHash, code.KeccakCodeHash, code.CodeSize)
+		state.SetNonce(acc.Address, acc.Nonce)
+		state.SetBalance(acc.Address, new(big.Int))
+		if acc.Balance != nil {
+			state.SetBalance(acc.Address, acc.Balance.ToInt())
+		}
+
+		for _, slot := range acc.Storage {
+			if slot.Value == (types.FullBytes32{}) {
+				return nil, fmt.Errorf("the account %v has a zero storage value for the key %v", acc.Address.Hex(), slot.Key.Hex())
+			}
+			state.SetStorage(acc.Address, slot.Key, slot.Value)
+		}
+	}
+
+	return state, nil
+}
+
+// withDefaults returns the code with the code hashes of the empty code if the
+// code is empty and the hashes are not set.
+func (c CodeSpec) withDefaults() CodeSpec {
+	if c.CodeSize == 0 && c.KeccakCodeHash == (types.FullBytes32{}) && c.MimcCodeHash == (types.Bytes32{}) {
+		c.KeccakCodeHash = types.AsFullBytes32(statemanager.LEGACY_KECCAK_EMPTY_CODEHASH)
+		c.MimcCodeHash = statemanager.EmptyCodeHash(statemanager.MIMC_CONFIG)
+	}
+	return c
+}
+
+// StateDiffsToLogs converts the state diffs of consecutive blocks, starting
+// from the initial state, into the state access logs of the blocks. It
+// returns an error if the diffs are inconsistent with the state, e.g. when
+// deleting a missing account.
+func StateDiffsToLogs(initialState State, firstBlock int, diffs []BlockStateDiff) ([][]StateAccessLog, error) {
+
+	var (
+		builder = NewStateLogBuilder(firstBlock, initialState)
+		exists  = map[types.EthAddress]bool{}
+	)
+
+	for address := range initialState {
+		exists[address] = true
+	}
+
+	for i, block := range diffs {
+
+		if i > 0 {
+			builder.GoNextBlock()
+		}
+
+		if len(block.Accounts) == 0 {
+			return nil, fmt.Errorf("block #%v: the block does not touch any account", firstBlock+i)
+		}
+
+		touched := map[types.EthAddress]struct{}{}
+
+		for _, diff := range block.Accounts {
+
+			if _, ok := touched[diff.Address]; ok {
+				return nil, fmt.Errorf("block #%v: the account %v is listed twice", firstBlock+i, diff.Address.Hex())
+			}
+			touched[diff.Address] = struct{}{}
+
+			existsAfter, err := applyAccountDiff(builder.WithAddress(diff.Address), exists[diff.Address], diff)
+			if err != nil {
+				return nil, fmt.Errorf("block #%v, account %v: %w", firstBlock+i, diff.Address.Hex(), err)
+			}
+			exists[diff.Address] = existsAfter
+		}
+	}
+
+	return builder.Done(), nil
+}
+
+// applyAccountDiff generates the logs of the diff for the current account of
+// the builder and returns whether the account exists after the diff.
+func applyAccountDiff(b *StateLogBuilder, existed bool, diff AccountDiff) (existsAfter bool, err error) {
+
+	var (
+		hasWrites = diff.Nonce != nil || diff.Balance != nil || len(diff.StorageWrites) > 0
+		created   = diff.Created != nil
+	)
+
+	switch {
+	case existed && created && !diff.Deleted:
+		return false, fmt.Errorf("the account is deployed but already exists")
+	case !existed && !created && diff.Deleted:
+		return false, fmt.Errorf("the account is deleted but does not exist")
+	case !existed && !created && (hasWrites || len(diff.StorageReads) > 0):
+		return false, fmt.Errorf("the account is accessed but does not exist")
+	case existed && diff.Deleted && !created && hasWrites:
+		return false, fmt.Errorf("the account is written but deleted")
+	}
+
+	if existed {
+		readStorage(b, diff)
+		if diff.Deleted {
+			b.EraseAccount()
+		}
+		if created {
+			initAccount(b, *diff.Created)
+		}
+		writeAccount(b, diff)
+	} else {
+		if created {
+			initAccount(b, *diff.Created)
+		}
+		readStorage(b, diff)
+		writeAccount(b, diff)
+		if diff.Deleted {
+			b.EraseAccount()
+		}
+	}
+
+	// An account that is neither written nor deleted nor deployed is read
+	if !hasWrites && !created && !diff.Deleted && len(diff.StorageReads) == 0 {
+		b.ReadBalance()
+	}
+
+	if created {
+		return existed || !diff.Deleted, nil
+	}
+	return existed && !diff.Deleted, nil
+}
+
+func initAccount(b *StateLogBuilder, code CodeSpec) {
+	code = code.withDefaults()
+	b.InitContract(code.CodeSize, code.KeccakCodeHash, code.MimcCodeHash)
+}
+
+func readStorage(b *StateLogBuilder, diff AccountDiff) {
+	for _, key := range diff.StorageReads {
+		b.ReadStorage(key)
+	}
+}
+
+func writeAccount(b *StateLogBuilder, diff AccountDiff) {
+	for _, slot := range diff.StorageWrites {
+		b.WriteStorage(slot.Key, slot.Value)
+	}
+	if diff.Balance != nil {
+		b.WriteBalance(diff.Balance.ToInt())
+	}
+	if diff.Nonce != nil {
+		b.WriteNonce(*diff.Nonce)
+	}
+}
+
+// GenerateShomeiTraces returns the root hash of the initial state and the
+// Shomei traces of the blocks whose diffs are provided, as found in the
+// `zkParentStateRootHash` and `zkStateMerkleProof` fields of an execution
+// request. The traces of every block are checked before being returned.
+func GenerateShomeiTraces(initialState State, firstBlock int, diffs []BlockStateDiff) (parentRoot types.Bytes32, traces [][]statemanager.DecodedTrace, err error) {
+
+	logs, err := StateDiffsToLogs(initialState, firstBlock, diffs)
+	if err != nil {
+		return types.Bytes32{}, nil, err
+	}
+
+	var (
+		shomeiState = InitShomeiState(initialState)
+		root        = shomeiState.AccountTrie.TopRoot()
+	)
+
+	parentRoot = root
+	traces = StateLogsToShomeiTraces(shomeiState, logs)
+
+	for i := range traces {
+		old, new, err := statemanager.CheckTraces(traces[i])
+		if err != nil {
+			return types.Bytes32{}, nil, fmt.Errorf("block #%v: the generated traces do not pass: %w", firstBlock+i, err)
+		}
+		if old != root {
+			return types.Bytes32{}, nil, fmt.Errorf("block #%v: the generated traces start from the root %v, expected %v", firstBlock+i, old.Hex(), root.Hex())
+		}
+		root = new
+	}
+
+	return parentRoot, traces, nil
+}
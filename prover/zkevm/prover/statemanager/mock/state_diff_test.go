@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateShomeiTraces(t *testing.T) {
+
+	var (
+		eoa, contract, newEoa, newContract, missing = types.DummyAddress(1), types.DummyAddress(2), types.DummyAddress(3), types.DummyAddress(4), types.DummyAddress(5)
+		key0, key1                                  = types.DummyFullByte(10), types.DummyFullByte(11)
+		code                                        = CodeSpec{CodeSize: 100, KeccakCodeHash: types.DummyFullByte(20), MimcCodeHash: types.DummyDigest(21)}
+		nonce                                       = int64(8)
+	)
+
+	spec := WorldStateSpec{
+		Accounts: []AccountSpec{
+			{Address: eoa, Nonce: 7, Balance: (*hexutil.Big)(big.NewInt(1000))},
+			{
+				Address: contract, KeccakCodeHash: types.DummyFullByte(30), MimcCodeHash: types.DummyDigest(31), CodeSize: 50,
+				Storage: []StorageSlot{{Key: key0, Value: types.DummyFullByte(40)}},
+			},
+		},
+	}
+
+	diffs := []BlockStateDiff{
+		{
+			// A transfer creating an EOA and a read of a missing account
+			Accounts: []AccountDiff{
+				{Address: eoa, Nonce: &nonce, Balance: (*hexutil.Big)(big.NewInt(900))},
+				{Address: newEoa, Created: &CodeSpec{}, Balance: (*hexutil.Big)(big.NewInt(100))},
+				{Address: missing},
+			},
+		},
+		{
+			// Storage accesses, a deployment and an ephemeral contract
+			Accounts: []AccountDiff{
+				{Address: contract, StorageReads: []types.FullBytes32{key0}, StorageWrites: []StorageSlot{{Key: key1, Value: types.DummyFullByte(41)}}},
+				{Address: newContract, Created: &code, StorageWrites: []StorageSlot{{Key: key0, Value: types.DummyFullByte(42)}}},
+				{Address: missing, Created: &code, Deleted: true},
+				{Address: eoa},
+			},
+		},
+		{
+			// A redeployment and a deletion
+			Accounts: []AccountDiff{
+				{Address: contract, Deleted: true, Created: &code, StorageWrites: []StorageSlot{{Key: key1, Value: types.DummyFullByte(43)}}},
+				{Address: newContract, Deleted: true, StorageReads: []types.FullBytes32{key0}},
+			},
+		},
+	}
+
+	state, err := spec.State()
+	require.NoError(t, err)
+
+	parentRoot, traces, err := GenerateShomeiTraces(state, 10, diffs)
+	require.NoError(t, err)
+	require.Len(t, traces, len(diffs))
+	assert.Equal(t, InitShomeiState(state).AccountTrie.TopRoot(), parentRoot)
+
+	// The traces are parsed back as the ones of an execution request
+	b, err := json.Marshal(traces)
+	require.NoError(t, err)
+
+	var decoded [][]statemanager.DecodedTrace
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	root := parentRoot
+	for i := range decoded {
+		old, new, err := statemanager.CheckTraces(decoded[i])
+		require.NoError(t, err)
+		require.Equal(t, root, old)
+		root = new
+	}
+}
+
+func TestStateDiffsErrors(t *testing.T) {
+
+	var (
+		existing, missing = types.DummyAddress(1), types.DummyAddress(2)
+		nonce             = int64(1)
+		state             = State{}
+	)
+
+	state.InsertEOA(existing, 0, big.NewInt(10))
+
+	testCases := map[string]AccountDiff{
+		"deploy-existing":   {Address: existing, Created: &CodeSpec{}},
+		"delete-missing":    {Address: missing, Deleted: true},
+		"write-missing":     {Address: missing, Nonce: &nonce},
+		"write-deleted":     {Address: existing, Deleted: true, Nonce: &nonce},
+		"read-missing-slot": {Address: missing, StorageReads: []types.FullBytes32{{}}},
+	}
+
+	for name, diff := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := StateDiffsToLogs(state, 0, []BlockStateDiff{{Accounts: []AccountDiff{diff}}})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := StateDiffsToLogs(state, 0, []BlockStateDiff{{}})
+	assert.Error(t, err, "empty block")
+}
@@ -0,0 +1,72 @@
+package zkevm
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/distributed"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+)
+
+// SegmentedArithmetization proves the arithmetization of the zkEVM in
+// segments, one for every group of corset modules sharing constraints. The
+// segments are tied together by the cross-segment lookups and permutations
+// checked by [SegmentedArithmetization.Verify].
+//
+// Its scope is limited:
+//   - only the arithmetization is segmented. The other components of the
+//     zkEVM (keccak, state-manager, precompiles, ...) are multi-round gadgets
+//     and stay proven by the monolithic [ZkEvm];
+//   - the segments can only be proven on separate machines if the caller
+//     provides a [distributed.Beacon] relaying the digests between them, the
+//     repository only has the in-process [distributed.LocalBeacon];
+//   - it is not used by the execution prover, the CLI or the controller.
+type SegmentedArithmetization struct {
+	// arithmetization is the definition of the arithmetization
+	arithmetization *arithmetization.Arithmetization
+	// Wizard is the arithmetization split in segments
+	Wizard *distributed.DistributedWizard
+}
+
+// NewSegmentedArithmetization compiles the arithmetization alone and splits it
+// into segments by corset module. Every segment is compiled with the provided
+// compilation suite.
+func NewSegmentedArithmetization(
+	settings arithmetization.Settings,
+	metadata wizard.VersionMetadata,
+	suite compilationSuite,
+) *SegmentedArithmetization {
+
+	var (
+		res    = &SegmentedArithmetization{}
+		define = func(b *wizard.Builder) {
+			res.arithmetization = arithmetization.NewArithmetization(b, settings)
+		}
+	)
+
+	res.Wizard = distributed.Distribute(wizard.Compile(define), arithmetization.ModuleOf, metadata, suite...)
+	return res
+}
+
+// ProveSegment runs the prover of a segment of the arithmetization. Only the
+// columns required by the segment are assigned, but the trace file of the
+// witness is still expanded in full. The call blocks until every segment has
+// published its digest on the beacon.
+func (s *SegmentedArithmetization) ProveSegment(segment distributed.ModuleName, input *Witness, beacon distributed.Beacon) wizard.Proof {
+	return s.Wizard.ProveSegment(segment, func(run *wizard.ProverRuntime, columns []ifaces.ColID) {
+		s.arithmetization.AssignColumns(run, input.ExecTracesFPath, columns)
+	}, beacon)
+}
+
+// Prove runs the provers of all the segments of the arithmetization in the
+// current process and returns their proofs by segment name. The trace file is
+// only expanded once.
+func (s *SegmentedArithmetization) Prove(input *Witness) map[distributed.ModuleName]wizard.Proof {
+	return s.Wizard.Prove(func(run *wizard.ProverRuntime) {
+		s.arithmetization.Assign(run, input.ExecTracesFPath)
+	})
+}
+
+// Verify verifies the proofs of all the segments of the arithmetization
+func (s *SegmentedArithmetization) Verify(proofs map[distributed.ModuleName]wizard.Proof) error {
+	return s.Wizard.Verify(proofs)
+}
@@ -3,10 +3,12 @@ package zkevm
 import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
@@ -27,6 +29,8 @@ type Settings struct {
 	Ecadd, Ecmul     ecarith.Limits
 	Ecpair           ecpair.Limits
 	Sha2             sha2.Settings
+	Ripemd           ripemd.Settings
+	Blake2f          blake2f.Settings
 	PublicInput      publicInput.Settings
 	CompilationSuite compilationSuite
 	Metadata         wizard.VersionMetadata
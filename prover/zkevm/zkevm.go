@@ -5,10 +5,12 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/serialization"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
@@ -46,6 +48,13 @@ type ZkEvm struct {
 	// sha2 is the module responsible for doing the computation of the sha2
 	// precompile.
 	sha2 *sha2.Sha2SingleProvider
+	// ripemd is the module responsible for doing the computation of the
+	// ripemd160 precompile. It is nil when the limits do not allow any call to
+	// the precompile.
+	ripemd *ripemd.RipemdSingleProvider
+	// blake2f is the module responsible for proving the calls to the blake2f
+	// precompile. It is nil when the limits do not allow any round.
+	blake2f *blake2f.Module
 
 	// Contains the actual wizard-IOP compiled object. This object is called to
 	// generate the inner-proof.
@@ -80,6 +89,13 @@ func (z *ZkEvm) ProveInner(input *Witness) wizard.Proof {
 	return wizard.Prove(z.WizardIOP, z.prove(input))
 }
 
+// ProveInnerWithCheckpoints works as [ZkEvm.ProveInner] but checkpoints the
+// prover at every round using cp and resumes from its checkpoint if it has
+// one. See [wizard.ProveWithCheckpoints].
+func (z *ZkEvm) ProveInnerWithCheckpoints(input *Witness, cp wizard.ProverCheckpointer) wizard.Proof {
+	return wizard.ProveWithCheckpoints(z.WizardIOP, z.prove(input), cp)
+}
+
 // Verify verifies the inner-proof of the zkEVM
 func (z *ZkEvm) VerifyInner(proof wizard.Proof) error {
 	return wizard.Verify(z.WizardIOP, proof)
@@ -103,8 +119,18 @@ func newZkEVM(b *wizard.Builder, s *Settings) *ZkEvm {
 		ecpair       = ecpair.NewECPairZkEvm(comp, &s.Ecpair)
 		sha2         = sha2.NewSha2ZkEvm(comp, s.Sha2)
 		publicInput  = publicInput.NewPublicInputZkEVM(comp, &s.PublicInput, &stateManager.StateSummary)
+		ripemdMod    *ripemd.RipemdSingleProvider
+		blake2fMod   *blake2f.Module
 	)
 
+	if s.Ripemd.MaxNumRipemdF > 0 {
+		ripemdMod = ripemd.NewRipemdZkEvm(comp, s.Ripemd)
+	}
+
+	if s.Blake2f.MaxNbRounds > 0 {
+		blake2fMod = blake2f.NewModuleZkEvm(comp, s.Blake2f)
+	}
+
 	return &ZkEvm{
 		arithmetization: arith,
 		ecdsa:           ecdsa,
@@ -115,6 +141,8 @@ func newZkEVM(b *wizard.Builder, s *Settings) *ZkEvm {
 		ecmul:           ecmul,
 		ecpair:          ecpair,
 		sha2:            sha2,
+		ripemd:          ripemdMod,
+		blake2f:         blake2fMod,
 		PublicInput:     &publicInput,
 	}
 }
@@ -138,6 +166,12 @@ func (z *ZkEvm) prove(input *Witness) (prover wizard.ProverStep) {
 		z.ecmul.Assign(run)
 		z.ecpair.Assign(run)
 		z.sha2.Run(run)
+		if z.ripemd != nil {
+			z.ripemd.Run(run)
+		}
+		if z.blake2f != nil {
+			z.blake2f.Assign(run)
+		}
 		z.PublicInput.Assign(run, input.L2BridgeAddress, input.BlockHashList)
 	}
 }